@@ -3,8 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
@@ -20,7 +24,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -46,21 +51,40 @@ func run() (err error) {
 	if err != nil {
 		return fmt.Errorf("openai new: %w", err)
 	}
-	ctx := context.Background()
+
+	return generate(context.Background(), llm, os.Stdout)
+}
+
+// generate asks the model for the content, printing the response to w, followed by
+// the elapsed time and an approximate tokens-per-second rate of the generation.
+func generate(ctx context.Context, llm llms.Model, w io.Writer) error {
 	content := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeSystem, "You are a fellow Go developer."),
 		llms.TextParts(llms.ChatMessageTypeHuman, "Provide 3 short bullet points explaining why Go is awesome"),
 	}
 
+	timeout := ai.GenerationTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
 	// The response from the model happens when the model finishes processing the input, which it's usually slow.
 	completion, err := llm.GenerateContent(ctx, content)
 	if err != nil {
-		return fmt.Errorf("llm generate content: %w", err)
+		return fmt.Errorf("llm generate content: %w", ai.GenerationError(err, timeout))
 	}
 
+	elapsed := time.Since(start)
+
+	var tokens int
 	for _, choice := range completion.Choices {
-		fmt.Println(choice.Content)
+		fmt.Fprintln(w, choice.Content)
+		// the tokenizer of the model is not known, so the count is an approximation
+		tokens += llms.CountTokens(fqModelName, choice.Content)
 	}
 
+	fmt.Fprintf(w, "\nGenerated ~%d tokens in %s (%.2f tokens/s)\n", tokens, elapsed.Round(time.Millisecond), float64(tokens)/elapsed.Seconds())
+
 	return nil
 }
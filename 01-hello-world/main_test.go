@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/llmtest"
+)
+
+func TestGenerate(t *testing.T) {
+	answer := "Go is simple, fast and has great concurrency primitives."
+
+	model := &llmtest.ScriptedModel{Answers: []string{answer}, Transcript: io.Discard}
+
+	var out bytes.Buffer
+	if err := generate(context.Background(), model, &out); err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if !strings.HasPrefix(out.String(), answer+"\n") {
+		t.Fatalf("expected the answer to be printed first, got %q", out.String())
+	}
+
+	timing := regexp.MustCompile(`\nGenerated ~\d+ tokens in \S+ \(\d+\.\d{2} tokens/s\)\n$`)
+	if !timing.MatchString(out.String()) {
+		t.Fatalf("expected the timing line to be printed, got %q", out.String())
+	}
+}
+
+func TestGenerate_golden(t *testing.T) {
+	var transcript, out bytes.Buffer
+	model := &llmtest.ScriptedModel{
+		Answers:    []string{"- Simple syntax\n- Fast compilation\n- Built-in concurrency"},
+		Transcript: &transcript,
+	}
+
+	if err := generate(context.Background(), model, &out); err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	// The elapsed time and the rate vary on each run
+	timing := regexp.MustCompile(`in \S+ \(\d+\.\d{2} tokens/s\)`)
+	fmt.Fprintf(&transcript, "=== output ===\n%s", timing.ReplaceAll(out.Bytes(), []byte("in <elapsed> (<rate> tokens/s)")))
+
+	llmtest.AssertGolden(t, "generate", transcript.Bytes())
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
@@ -20,7 +25,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -46,18 +52,36 @@ func run() (err error) {
 	if err != nil {
 		return fmt.Errorf("openai new: %w", err)
 	}
-	ctx := context.Background()
+	timeout := ai.GenerationTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Ctrl+C stops the generation, terminating the container instead of killing the process.
+	// A second Ctrl+C kills it, as the default behavior is restored once the first one is received.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	content := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeSystem, "Give me a detailed and long explanation of why Testcontainers for Go is great"),
 	}
 
 	// Streaming is needed because models are usually slow in responding, so showing progress is important.
-	_, err = llm.GenerateContent(ctx, content, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
-		fmt.Print(string(chunk))
+	// The chunks may split multi-byte characters, which are printed once complete.
+	printer := ai.NewStreamPrinter(os.Stdout)
+	_, err = llm.GenerateContent(ctx, content, llms.WithStreamingFunc(printer.Stream))
+	if flushErr := printer.Flush(); err == nil {
+		err = flushErr
+	}
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("\n[generation interrupted]")
 		return nil
-	}))
+	}
 	if err != nil {
-		return fmt.Errorf("llm generate content: %w", err)
+		return fmt.Errorf("llm generate content: %w", ai.GenerationError(err, timeout))
 	}
 
 	return nil
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ask sends the conversation followed by the input to the model, streaming the answer to w, and
+// returns the conversation with the input. An interrupted or failed turn is left out of the
+// conversation, so the next turn doesn't send its question again, unanswered.
+func ask(ctx context.Context, llm llms.Model, conversation []llms.MessageContent, input string, w io.Writer) ([]llms.MessageContent, error) {
+	turn := append(conversation, llms.TextParts(llms.ChatMessageTypeHuman, input))
+
+	printer := ai.NewStreamPrinter(w)
+	_, err := llm.GenerateContent(ctx, turn, llms.WithStreamingFunc(printer.Stream))
+	if flushErr := printer.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return conversation, err
+	}
+
+	return turn, nil
+}
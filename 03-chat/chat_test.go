@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/llmtest"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestAsk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var out strings.Builder
+	model := &llmtest.StreamingModel{
+		Chunks: []string{"Tokyo ", "is ", "the ", "capital."},
+		OnChunk: func(i int) {
+			// Ctrl+C after the first chunk
+			if i == 0 {
+				cancel()
+			}
+		},
+	}
+
+	conversation, err := ask(ctx, model, nil, "What is the capital of Japan?", &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the generation canceled, got %v", err)
+	}
+	if len(conversation) != 0 {
+		t.Fatalf("expected the interrupted turn left out of the conversation, got %d messages", len(conversation))
+	}
+
+	model.OnChunk = nil
+	conversation, err = ask(context.Background(), model, conversation, "And of Spain?", &out)
+	if err != nil {
+		t.Fatalf("ask: %s", err)
+	}
+
+	// The question of the interrupted turn is not sent again
+	request := model.Requests[len(model.Requests)-1]
+	if len(request) != 1 || request[0].Role != llms.ChatMessageTypeHuman || request[0].Parts[0] != llms.TextPart("And of Spain?") {
+		t.Errorf("expected only the new question to be sent, got %v", request)
+	}
+	if len(conversation) != 1 {
+		t.Errorf("expected the answered turn in the conversation, got %d messages", len(conversation))
+	}
+	if out.String() != "Tokyo Tokyo is the capital." {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
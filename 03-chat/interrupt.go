@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// interrupter cancels the in-flight generation on an interrupt signal, so Ctrl+C stops a long
+// response without ending the chat session. Without generation in flight, the signal ends it.
+type interrupter struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// start returns the context of a generation, canceled by the next interrupt. The returned cancel
+// function must be called once the generation completes.
+func (i *interrupter) start(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	i.mu.Lock()
+	i.cancel = cancel
+	i.mu.Unlock()
+
+	return ctx, func() {
+		i.mu.Lock()
+		i.cancel = nil
+		i.mu.Unlock()
+		cancel()
+	}
+}
+
+// interrupt cancels the in-flight generation, returning false if there is none
+func (i *interrupter) interrupt() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.cancel == nil {
+		return false
+	}
+	i.cancel()
+	i.cancel = nil
+
+	return true
+}
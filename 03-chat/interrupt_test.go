@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/llmtest"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestInterrupter(t *testing.T) {
+	var generation interrupter
+	if generation.interrupt() {
+		t.Fatal("expected no generation to interrupt before starting one")
+	}
+
+	var out strings.Builder
+	model := &llmtest.StreamingModel{
+		Chunks: []string{"The ", "capital ", "of ", "Japan ", "is ", "Tokyo."},
+		OnChunk: func(i int) {
+			// Ctrl+C after the second chunk
+			if i == 1 && !generation.interrupt() {
+				t.Error("expected the in-flight generation to be interrupted")
+			}
+		},
+	}
+
+	ctx, cancel := generation.start(context.Background())
+	_, err := model.GenerateContent(ctx, nil, llms.WithStreamingFunc(ai.NewStreamPrinter(&out).Stream))
+	cancel()
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the generation canceled, got %v", err)
+	}
+	if out.String() != "The capital " {
+		t.Errorf("expected no chunk printed after the interrupt, got %q", out.String())
+	}
+
+	// The interrupt is consumed: the next one ends the session
+	if generation.interrupt() {
+		t.Error("expected no generation to interrupt after the canceled one")
+	}
+
+	// A new generation is not affected by the previous interrupt
+	out.Reset()
+	model.OnChunk = nil
+	ctx, cancel = generation.start(context.Background())
+	_, err = model.GenerateContent(ctx, nil, llms.WithStreamingFunc(ai.NewStreamPrinter(&out).Stream))
+	cancel()
+	if err != nil {
+		t.Fatalf("generate content: %s", err)
+	}
+	if out.String() != "The capital of Japan is Tokyo." {
+		t.Errorf("expected the full response, got %q", out.String())
+	}
+	if generation.interrupt() {
+		t.Error("expected no generation to interrupt after the completed one")
+	}
+}
@@ -3,13 +3,15 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
@@ -25,7 +27,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -52,19 +55,27 @@ func run() (err error) {
 		return fmt.Errorf("openai new: %w", err)
 	}
 
-	// listen for interrupt signals to end the chat session gracefully
+	// listen for interrupt signals to stop the in-flight generation, or to end the chat session
+	// gracefully when the model is not generating
+	var generation interrupter
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		<-sigChan
-		fmt.Println("\nInterrupt signal received, ending chat session")
-		os.Exit(0)
+		for sig := range sigChan {
+			if sig == syscall.SIGINT && generation.interrupt() {
+				continue
+			}
+			fmt.Println("\nInterrupt signal received, ending chat session")
+			os.Exit(0)
+		}
 	}()
 
 	var conversation []llms.MessageContent
 
 	reader := bufio.NewReader(os.Stdin)
+	timeout := ai.GenerationTimeout()
+
 	// Enter a conversation loop
 	for {
 		fmt.Print("\nYou: ")
@@ -80,15 +91,17 @@ func run() (err error) {
 			os.Exit(0)
 		}
 
-		conversation = append(conversation, llms.TextParts(llms.ChatMessageTypeHuman, input))
-
-		ctx := context.Background()
-		_, err = llm.GenerateContent(ctx, conversation, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
-			fmt.Print(string(chunk))
-			return nil
-		}))
+		timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := generation.start(timeoutCtx)
+		conversation, err = ask(ctx, llm, conversation, input, os.Stdout)
+		cancel()
+		cancelTimeout()
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("\n[generation interrupted]")
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("llm generate content: %w", err)
+			return fmt.Errorf("llm generate content: %w", ai.GenerationError(err, timeout))
 		}
 	}
 }
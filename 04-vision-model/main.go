@@ -4,8 +4,10 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
-	"log"
+	"os"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	tcollama "github.com/testcontainers/testcontainers-go/modules/ollama"
 	"github.com/tmc/langchaingo/llms"
@@ -17,7 +19,8 @@ var catImage []byte
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -53,13 +56,15 @@ func run() error {
 		},
 	})
 
-	ctx := context.Background()
+	timeout := ai.GenerationTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 	_, err = llm.GenerateContent(ctx, content, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
 		fmt.Print(string(chunk))
 		return nil
 	}))
 	if err != nil {
-		return fmt.Errorf("llm generate content: %w", err)
+		return fmt.Errorf("llm generate content: %w", ai.GenerationError(err, timeout))
 	}
 
 	return nil
@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"os"
 
+	"github.com/mdelapenya/genai-testcontainers-go/rag/rag"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/schema"
 )
 
 const (
@@ -20,7 +25,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -47,55 +53,65 @@ func run() (err error) {
 		return fmt.Errorf("openai new: %w", err)
 	}
 
+	return augment(context.Background(), llm, os.Stdout)
+}
+
+// augment asks the model about the conference, first with the question alone and then augmented
+// with the bullet points about it, printing both completions to w to compare them.
+func augment(ctx context.Context, llm llms.Model, w io.Writer) error {
 	originalMessage := `
 		What is the current topic of the conference?
 	`
 
-	augmentedMessage := fmt.Sprintf(`
-		%s
-
-		Use the following bullet points to answer the question:
-		- The Conference is about how to leverage Testcontainers for building Generative AI applications.
-		- The meeting will explore how Testcontainers can be used to create a seamless development environment for AI projects.
+	// The bullet points are the context passages of a grounded prompt, built as the RAG example does
+	bulletPoints := []schema.Document{
+		{PageContent: "The Conference is about how to leverage Testcontainers for building Generative AI applications."},
+		{PageContent: "The meeting will explore how Testcontainers can be used to create a seamless development environment for AI projects."},
+	}
 
-		Do not indicate that you have been given any additional information.
-		`, originalMessage)
+	augmentedMessage := rag.BuildPrompt(originalMessage, bulletPoints, rag.PromptOptions{})
 
-	ctx := context.Background()
 	originalContent := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeSystem, originalMessage),
 	}
 
+	timeout := ai.GenerationTimeout()
+	originalCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	originalCompletion, err := llm.GenerateContent(
-		ctx, originalContent,
+		originalCtx, originalContent,
 		llms.WithTemperature(0.0001),
 		llms.WithTopK(1),
 	)
 	if err != nil {
-		return fmt.Errorf("llm generate original content: %w", err)
+		return fmt.Errorf("llm generate original content: %w", ai.GenerationError(err, timeout))
 	}
 
-	fmt.Println("\nOriginal completion:")
+	fmt.Fprintln(w, "\nOriginal completion:")
 	for _, choice := range originalCompletion.Choices {
-		fmt.Println(choice.Content)
+		fmt.Fprintln(w, choice.Content)
 	}
 
 	augmentedContent := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeSystem, augmentedMessage),
 	}
 
+	augmentedCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	augmentedCompletion, err := llm.GenerateContent(
-		ctx, augmentedContent,
+		augmentedCtx, augmentedContent,
 		llms.WithTemperature(0.0001),
 		llms.WithTopK(1),
 	)
 	if err != nil {
-		return fmt.Errorf("llm generate original content: %w", err)
+		return fmt.Errorf("llm generate augmented content: %w", ai.GenerationError(err, timeout))
 	}
 
-	fmt.Println("\nAugmented completion:")
+	fmt.Fprintln(w, "\nAugmented completion:")
 	for _, choice := range augmentedCompletion.Choices {
-		fmt.Println(choice.Content)
+		fmt.Fprintln(w, choice.Content)
 	}
 
 	return nil
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/llmtest"
+)
+
+func TestAugment_golden(t *testing.T) {
+	var transcript, out bytes.Buffer
+	model := &llmtest.ScriptedModel{
+		Answers: []string{
+			"I don't have information about a current conference.",
+			"The conference is about leveraging Testcontainers for building Generative AI applications.",
+		},
+		Transcript: &transcript,
+	}
+
+	if err := augment(context.Background(), model, &out); err != nil {
+		t.Fatalf("augment: %s", err)
+	}
+
+	fmt.Fprintf(&transcript, "=== output ===\n%s", out.Bytes())
+
+	llmtest.AssertGolden(t, "augment", transcript.Bytes())
+}
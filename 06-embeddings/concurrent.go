@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// EmbedConcurrently embeds the documents in batches of batchSize, running up to concurrency
+// batches at the same time, so large corpora are not embedded one request at a time.
+// The vectors are returned in the order of the documents. The errors of all the failed
+// batches are returned together, with the vectors of the successful ones.
+func EmbedConcurrently(ctx context.Context, embedder embeddings.Embedder, docs []string, batchSize, concurrency int) ([][]float32, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive, got %d", concurrency)
+	}
+
+	vecs := make([][]float32, len(docs))
+	batches := (len(docs) + batchSize - 1) / batchSize
+	errs := make([]error, batches)
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for b := 0; b < batches; b++ {
+		start := b * batchSize
+		end := min(start+batchSize, len(docs))
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batchVecs, err := embedder.EmbedDocuments(ctx, docs[start:end])
+			if err != nil {
+				errs[b] = fmt.Errorf("embed documents %d-%d: %w", start, end-1, err)
+				return
+			}
+			if len(batchVecs) != end-start {
+				errs[b] = fmt.Errorf("embed documents %d-%d: got %d vectors", start, end-1, len(batchVecs))
+				return
+			}
+
+			// Each batch writes its own range of the result, preserving the order of the documents
+			copy(vecs[start:end], batchVecs)
+		}()
+	}
+
+	wg.Wait()
+
+	return vecs, errors.Join(errs...)
+}
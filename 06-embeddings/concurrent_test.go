@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingEmbedder embeds each document as a vector holding its number, tracking the concurrent calls
+type countingEmbedder struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+	fail        string // Documents containing it fail to embed
+}
+
+func (e *countingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.calls++
+	e.inFlight++
+	e.maxInFlight = max(e.maxInFlight, e.inFlight)
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.inFlight--
+		e.mu.Unlock()
+	}()
+
+	// Give the other batches the chance to run at the same time
+	time.Sleep(10 * time.Millisecond)
+
+	vecs := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		if e.fail != "" && strings.Contains(text, e.fail) {
+			return nil, errors.New("embedding failed")
+		}
+
+		n, err := strconv.Atoi(strings.TrimPrefix(text, "doc-"))
+		if err != nil {
+			return nil, err
+		}
+		vecs = append(vecs, []float32{float32(n)})
+	}
+
+	return vecs, nil
+}
+
+func (e *countingEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+func testDocs(n int) []string {
+	docs := make([]string, n)
+	for i := range docs {
+		docs[i] = "doc-" + strconv.Itoa(i)
+	}
+	return docs
+}
+
+func TestEmbedConcurrently(t *testing.T) {
+	t.Run("order-and-concurrency", func(t *testing.T) {
+		embedder := &countingEmbedder{}
+
+		vecs, err := EmbedConcurrently(context.Background(), embedder, testDocs(23), 4, 3)
+		if err != nil {
+			t.Fatalf("embed concurrently: %s", err)
+		}
+
+		if len(vecs) != 23 {
+			t.Fatalf("expected 23 vectors, got %d", len(vecs))
+		}
+		for i, vec := range vecs {
+			if vec[0] != float32(i) {
+				t.Fatalf("expected the vector of document %d at position %d, got %v", int(vec[0]), i, vec)
+			}
+		}
+
+		if embedder.calls != 6 {
+			t.Errorf("expected 6 batches, got %d", embedder.calls)
+		}
+		if embedder.maxInFlight > 3 {
+			t.Errorf("expected at most 3 concurrent batches, got %d", embedder.maxInFlight)
+		}
+		if embedder.maxInFlight < 2 {
+			t.Errorf("expected the batches to run concurrently, got %d at most", embedder.maxInFlight)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		embedder := &countingEmbedder{fail: "doc-1"} // doc-1 and doc-10..doc-19
+
+		vecs, err := EmbedConcurrently(context.Background(), embedder, testDocs(20), 5, 2)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		// The first, third and fourth batches fail, reporting all of them
+		for _, batch := range []string{"0-4", "10-14", "15-19"} {
+			if !strings.Contains(err.Error(), "embed documents "+batch) {
+				t.Errorf("expected the error of the batch %s, got %s", batch, err)
+			}
+		}
+
+		// The vectors of the successful batch are kept
+		if vecs[5] == nil || vecs[5][0] != 5 {
+			t.Errorf("expected the vectors of the successful batch, got %v", vecs[5])
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := EmbedConcurrently(context.Background(), &countingEmbedder{}, testDocs(2), 0, 1); err == nil {
+			t.Error("expected an error for a zero batch size")
+		}
+		if _, err := EmbedConcurrently(context.Background(), &countingEmbedder{}, testDocs(2), 1, 0); err == nil {
+			t.Error("expected an error for a zero concurrency")
+		}
+	})
+}
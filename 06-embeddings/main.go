@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/chewxy/math32"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/embeddings"
@@ -21,7 +23,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -60,16 +63,35 @@ func run() (err error) {
 		"Docker is a platform designed to help developers build, share, and run container applications. We handle the tedious setup, so you can focus on the code.",
 	}
 
-	vecs, err := embedder.EmbedDocuments(context.Background(), docs)
+	// Embed the documents in batches of 2, running 2 batches at the same time
+	vecs, err := EmbedConcurrently(context.Background(), embedder, docs, 2, 2)
 	if err != nil {
 		return fmt.Errorf("embed query: %w", err)
 	}
 
+	matrix := SimilarityMatrix(vecs)
+	if IsDegenerate(matrix, DegenerateThreshold) {
+		logging.Default().Warn("All the documents are similar, the embedder is likely misconfigured", "threshold", DegenerateThreshold, "model", fqModelName)
+	}
+
+	// Export the matrix for larger corpora, whose pairs are unreadable in the console
+	if path := os.Getenv("EMBEDDINGS_SIMILARITY_CSV"); path != "" {
+		if err := exportSimilarityCSV(path, docs, matrix); err != nil {
+			return fmt.Errorf("export similarities: %w", err)
+		}
+		logging.Default().Info("Similarity matrix exported", "path", path)
+	}
+
+	if len(docs) > maxConsoleDocs {
+		logging.Default().Info("Skipping the similarities in the console, set EMBEDDINGS_SIMILARITY_CSV to export them", "documents", len(docs))
+		return nil
+	}
+
 	fmt.Println("Similarities:")
 	fmt.Println("--------------------------------")
 	for i := range docs {
 		for j := range docs {
-			fmt.Printf("- %6s ~ %6s = %0.2f\n", docs[i], docs[j], cosineSimilarity(vecs[i], vecs[j]))
+			fmt.Printf("- %6s ~ %6s = %0.2f\n", docs[i], docs[j], matrix[i][j])
 		}
 		fmt.Println("--------------------------------")
 	}
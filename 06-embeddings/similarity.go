@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// maxConsoleDocs is the number of documents up to which the similarities are printed to the console:
+// the pairs of larger corpora are unreadable, export them with EMBEDDINGS_SIMILARITY_CSV instead.
+const maxConsoleDocs = 10
+
+// DegenerateThreshold is the similarity above which all the pairs of different documents look like
+// the same text, a sign of a misconfigured embedder: a wrong model, or a truncated output.
+const DegenerateThreshold = 0.99
+
+// maxLabelLength is the length of the document text in the labels of the CSV rows and columns
+const maxLabelLength = 24
+
+// SimilarityMatrix returns the cosine similarity of each pair of vectors, a symmetric matrix with
+// a diagonal of 1.
+func SimilarityMatrix(vecs [][]float32) [][]float32 {
+	matrix := make([][]float32, len(vecs))
+	for i := range vecs {
+		matrix[i] = make([]float32, len(vecs))
+		for j := range vecs {
+			matrix[i][j] = cosineSimilarity(vecs[i], vecs[j])
+		}
+	}
+
+	return matrix
+}
+
+// IsDegenerate reports whether the similarities of all the pairs of different documents are above the
+// threshold, so the embeddings can't tell the documents apart. It needs at least two documents.
+func IsDegenerate(matrix [][]float32, threshold float32) bool {
+	if len(matrix) < 2 {
+		return false
+	}
+
+	for i := range matrix {
+		for j := range matrix[i] {
+			if i != j && matrix[i][j] <= threshold {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// WriteSimilarityCSV writes the similarity matrix of the documents as CSV, with a header row and
+// a first column labeling each document by its position and the beginning of its text, so it
+// can be loaded into a spreadsheet or a heatmap tool.
+func WriteSimilarityCSV(w io.Writer, docs []string, matrix [][]float32) error {
+	if len(matrix) != len(docs) {
+		return fmt.Errorf("got a %d-row matrix for %d documents", len(matrix), len(docs))
+	}
+
+	labels := make([]string, len(docs))
+	for i, doc := range docs {
+		labels[i] = docLabel(i, doc)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{""}, labels...)); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for i, row := range matrix {
+		record := make([]string, 0, len(row)+1)
+		record = append(record, labels[i])
+		for _, similarity := range row {
+			record = append(record, strconv.FormatFloat(float64(similarity), 'f', 4, 32))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+
+	return nil
+}
+
+// exportSimilarityCSV writes the similarity matrix of the documents to the CSV file at path.
+func exportSimilarityCSV(path string, docs []string, matrix [][]float32) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create csv: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("close csv: %w", cerr)
+		}
+	}()
+
+	return WriteSimilarityCSV(f, docs, matrix)
+}
+
+// docLabel identifies the document by its position and the beginning of its text, e.g. "1: A tiger is a large…".
+func docLabel(i int, doc string) string {
+	runes := []rune(doc)
+	if len(runes) > maxLabelLength {
+		doc = string(runes[:maxLabelLength]) + "…"
+	}
+
+	return fmt.Sprintf("%d: %s", i, doc)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestWriteSimilarityCSV(t *testing.T) {
+	docs := []string{
+		"A cat is a small domesticated carnivorous mammal",
+		"A tiger is a large carnivorous feline mammal",
+		"Testcontainers",
+	}
+	vecs := [][]float32{
+		{1, 0, 0},
+		{0.8, 0.6, 0},
+		{0, 0.6, 0.8},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSimilarityCSV(&buf, docs, SimilarityMatrix(vecs)); err != nil {
+		t.Fatalf("write csv: %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %s", err)
+	}
+
+	// A header row and a label column around the 3x3 matrix
+	if len(records) != len(docs)+1 {
+		t.Fatalf("expected %d rows, got %d", len(docs)+1, len(records))
+	}
+	for i, record := range records {
+		if len(record) != len(docs)+1 {
+			t.Fatalf("expected %d columns in row %d, got %d", len(docs)+1, i, len(record))
+		}
+	}
+
+	for i := range docs {
+		label := records[i+1][0]
+		if label != records[0][i+1] {
+			t.Errorf("expected row and column %d to share the label, got %q and %q", i, label, records[0][i+1])
+		}
+		if len([]rune(label)) > maxLabelLength+len("0: …") {
+			t.Errorf("expected a truncated label, got %q", label)
+		}
+
+		if records[i+1][i+1] != "1.0000" {
+			t.Errorf("expected a similarity of 1 on the diagonal, got %s at %d", records[i+1][i+1], i)
+		}
+		for j := range docs {
+			if records[i+1][j+1] != records[j+1][i+1] {
+				t.Errorf("expected a symmetric matrix, got %s at (%d, %d) and %s at (%d, %d)",
+					records[i+1][j+1], i, j, records[j+1][i+1], j, i)
+			}
+		}
+	}
+
+	if !strings.HasPrefix(records[1][0], "0: A cat is a small domest") || !strings.HasSuffix(records[1][0], "…") {
+		t.Errorf("unexpected label: %q", records[1][0])
+	}
+	if records[3][0] != "2: Testcontainers" {
+		t.Errorf("expected the short documents untruncated, got %q", records[3][0])
+	}
+	if records[1][2] != "0.8000" {
+		t.Errorf("expected a similarity of 0.8 between the first documents, got %s", records[1][2])
+	}
+}
+
+func TestIsDegenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		vecs     [][]float32
+		expected bool
+	}{
+		{
+			name:     "degenerate",
+			vecs:     [][]float32{{1, 0.001, 0}, {1, 0, 0.001}, {1, 0.001, 0.001}},
+			expected: true,
+		},
+		{
+			name:     "well-separated",
+			vecs:     [][]float32{{1, 0, 0}, {0.8, 0.6, 0}, {0, 0.6, 0.8}},
+			expected: false,
+		},
+		{
+			// A single pair of different documents is enough to tell the embedder works
+			name:     "one-distinct-pair",
+			vecs:     [][]float32{{1, 0, 0}, {1, 0.001, 0}, {0, 1, 0}},
+			expected: false,
+		},
+		{
+			name:     "single-document",
+			vecs:     [][]float32{{1, 0, 0}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDegenerate(SimilarityMatrix(tt.vecs), DegenerateThreshold); got != tt.expected {
+				t.Errorf("expected degenerate %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"os"
 
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
@@ -14,7 +14,12 @@ import (
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
 
+	"github.com/mdelapenya/genai-testcontainers-go/rag/rag"
+	"github.com/mdelapenya/genai-testcontainers-go/rag/ragmetrics"
+	"github.com/mdelapenya/genai-testcontainers-go/rag/splitter"
 	"github.com/mdelapenya/genai-testcontainers-go/rag/weaviate"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 )
 
 const (
@@ -29,7 +34,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -65,20 +71,45 @@ func run() error {
 		return fmt.Errorf("ingestion: %w", err)
 	}
 
+	if err := evaluateRetrieval(store, embedder); err != nil {
+		return fmt.Errorf("evaluate retrieval: %w", err)
+	}
+
 	optionsVector := []vectorstores.Option{
-		vectorstores.WithScoreThreshold(0.80), // use for precision, when you want to get only the most relevant documents
 		//vectorstores.WithNameSpace(""),            // use for set a namespace in the storage
 		//vectorstores.WithFilters(map[string]interface{}{"language": "en"}), // use for filter the documents
 		vectorstores.WithEmbedder(embedder), // use when you want add documents or doing similarity search
 		//vectorstores.WithDeduplicater(vectorstores.NewSimpleDeduplicater()), //  This is useful to prevent wasting time on creating an embedding
 	}
 
-	relevantDocs, err := store.SimilaritySearch(context.Background(), "What is my favorite sport?", 1, optionsVector...)
+	fallback, err := rag.ParseFallback(os.Getenv("RAG_FALLBACK"))
 	if err != nil {
-		return fmt.Errorf("similarity search: %w", err)
+		return fmt.Errorf("parse RAG_FALLBACK: %w", err)
 	}
 
-	if len(relevantDocs) == 0 {
+	query := "What is my favorite sport?"
+	question := "What is your favourite sport?"
+
+	retrieval, err := rag.Retrieve(context.Background(), store, query, 1, rag.RetrievalConfig{
+		ScoreThreshold:      0.80, // use for precision, when you want to get only the most relevant documents
+		Fallback:            fallback,
+		RetryScoreThreshold: 0.60,
+	}, optionsVector...)
+	if err != nil {
+		return fmt.Errorf("retrieve: %w", err)
+	}
+
+	var response string
+	switch {
+	case len(retrieval.Docs) > 0:
+		if retrieval.Fallback == rag.FallbackRetry {
+			logging.Default().Info("No relevant content found, using the documents of the retry with a lower score threshold")
+		}
+		response = rag.BuildPrompt(question, retrieval.Docs, rag.PromptOptions{AllowUnknown: true})
+	case retrieval.Fallback == rag.FallbackParametric:
+		fmt.Println(rag.ParametricDisclaimer)
+		response = rag.FallbackPrompt(question)
+	default:
 		fmt.Println("No relevant content found")
 		return nil
 	}
@@ -94,16 +125,12 @@ func run() error {
 		}
 	}()
 
-	response := fmt.Sprintf(`
-What is your favourite sport?
-
-Answer the question considering the following relevant content:
-%s
-`, relevantDocs[0].PageContent)
-
 	fmt.Println(response)
 
-	ctx := context.Background()
+	timeout := ai.GenerationTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
 	originalContent := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeHuman, response),
 	}
@@ -118,7 +145,7 @@ Answer the question considering the following relevant content:
 		}),
 	)
 	if err != nil {
-		return fmt.Errorf("llm generate original content: %w", err)
+		return fmt.Errorf("llm generate original content: %w", ai.GenerationError(err, timeout))
 	}
 
 	return nil
@@ -174,13 +201,18 @@ func buildEmbeddingStore(embedder embeddings.Embedder) (vectorstores.VectorStore
 }
 
 func ingestion(store vectorstores.VectorStore) error {
-	docs := []schema.Document{
-		{
-			PageContent: "I like football",
-		},
-		{
-			PageContent: "The weather is good today.",
-		},
+	texts := []string{
+		"I like football",
+		"The weather is good today.",
+	}
+
+	// Split the texts into overlapping chunks, so long documents keep the context
+	// around the chunk boundaries. Short texts are kept as a single chunk.
+	var docs []schema.Document
+	for _, text := range texts {
+		for _, chunk := range (splitter.TextSplitter{}).SplitWithOverlap(text, 1024, 100) {
+			docs = append(docs, schema.Document{PageContent: chunk})
+		}
 	}
 
 	_, err := store.AddDocuments(context.Background(), docs)
@@ -190,3 +222,40 @@ func ingestion(store vectorstores.VectorStore) error {
 
 	return nil
 }
+
+// retrievalTestSet is a labeled set of queries with the documents that are relevant to answer them
+var retrievalTestSet = []struct {
+	query       string
+	relevantIDs map[string]bool
+}{
+	{query: "What is my favorite sport?", relevantIDs: map[string]bool{"I like football": true}},
+	{query: "How is the weather today?", relevantIDs: map[string]bool{"The weather is good today.": true}},
+}
+
+// evaluateRetrieval runs the labeled test set against the store, and prints
+// the precision@k and MRR of the similarity search
+func evaluateRetrieval(store vectorstores.VectorStore, embedder embeddings.Embedder) error {
+	const k = 1
+
+	retrieved := make([][]schema.Document, 0, len(retrievalTestSet))
+	relevant := make([]map[string]bool, 0, len(retrievalTestSet))
+	totalPrecision := 0.0
+
+	for _, tc := range retrievalTestSet {
+		docs, err := store.SimilaritySearch(context.Background(), tc.query, 2, vectorstores.WithEmbedder(embedder))
+		if err != nil {
+			return fmt.Errorf("similarity search: %w", err)
+		}
+
+		precision := ragmetrics.PrecisionAtK(docs, tc.relevantIDs, k)
+		fmt.Printf("precision@%d=%.2f for %q\n", k, precision, tc.query)
+
+		totalPrecision += precision
+		retrieved = append(retrieved, docs)
+		relevant = append(relevant, tc.relevantIDs)
+	}
+
+	fmt.Printf("Retrieval quality: mean precision@%d=%.2f, MRR=%.2f\n", k, totalPrecision/float64(len(retrievalTestSet)), ragmetrics.MRR(retrieved, relevant))
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// Fallback is the behavior when the similarity search doesn't find any relevant document
+type Fallback string
+
+const (
+	// FallbackNone doesn't answer the question, the original behavior
+	FallbackNone Fallback = "none"
+	// FallbackParametric answers from the knowledge of the model, with a disclaimer
+	FallbackParametric Fallback = "parametric"
+	// FallbackRetry retries the search once with a lower score threshold
+	FallbackRetry Fallback = "retry"
+)
+
+// ParseFallback returns the fallback with the given name, FallbackNone for an empty one
+func ParseFallback(name string) (Fallback, error) {
+	switch f := Fallback(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return FallbackNone, nil
+	case FallbackNone, FallbackParametric, FallbackRetry:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported fallback %q: use one of %q, %q or %q", name, FallbackNone, FallbackParametric, FallbackRetry)
+	}
+}
+
+// ParametricDisclaimer warns that the answer doesn't come from the knowledge base
+const ParametricDisclaimer = "Disclaimer: no relevant documents were found in the knowledge base, the answer is based on the general knowledge of the model and could be inaccurate."
+
+// RetrievalConfig configures the similarity search of Retrieve
+type RetrievalConfig struct {
+	// ScoreThreshold is the minimum similarity of the relevant documents
+	ScoreThreshold float32
+	// Fallback is the behavior when no document reaches the score threshold
+	Fallback Fallback
+	// RetryScoreThreshold is the score threshold of the retry of FallbackRetry
+	RetryScoreThreshold float32
+}
+
+// Retrieval is the result of Retrieve
+type Retrieval struct {
+	// Docs are the relevant documents, empty if none was found
+	Docs []schema.Document
+	// Fallback is the fallback applied, empty if the documents were found in the first search
+	Fallback Fallback
+}
+
+// Retrieve searches the documents relevant to the query, applying the configured fallback
+// when none reaches the score threshold. With FallbackParametric, the caller answers without
+// documents, using FallbackPrompt, instead of not answering at all.
+func Retrieve(ctx context.Context, store vectorstores.VectorStore, query string, numDocs int, cfg RetrievalConfig, opts ...vectorstores.Option) (Retrieval, error) {
+	docs, err := search(ctx, store, query, numDocs, cfg.ScoreThreshold, opts)
+	if err != nil {
+		return Retrieval{}, err
+	}
+	if len(docs) > 0 || cfg.Fallback == FallbackNone || cfg.Fallback == "" {
+		return Retrieval{Docs: docs}, nil
+	}
+
+	if cfg.Fallback == FallbackRetry {
+		docs, err = search(ctx, store, query, numDocs, cfg.RetryScoreThreshold, opts)
+		if err != nil {
+			return Retrieval{}, err
+		}
+	}
+
+	return Retrieval{Docs: docs, Fallback: cfg.Fallback}, nil
+}
+
+// search runs a similarity search with the given score threshold
+func search(ctx context.Context, store vectorstores.VectorStore, query string, numDocs int, threshold float32, opts []vectorstores.Option) ([]schema.Document, error) {
+	searchOpts := append([]vectorstores.Option{vectorstores.WithScoreThreshold(threshold)}, opts...)
+
+	docs, err := store.SimilaritySearch(ctx, query, numDocs, searchOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("similarity search: %w", err)
+	}
+
+	return docs, nil
+}
+
+// FallbackPrompt builds the prompt answering the question from the knowledge of the model,
+// when the knowledge base doesn't contain any relevant document
+func FallbackPrompt(question string) string {
+	return fmt.Sprintf(`No documents of the knowledge base are relevant to the question.
+Answer it from your general knowledge, and say %q if you don't know the answer either.
+
+Question: %s
+`, UnknownAnswer, strings.TrimSpace(question))
+}
@@ -0,0 +1,115 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// thresholdStore returns its documents only to the searches with a score threshold up to their score,
+// recording the thresholds of the searches
+type thresholdStore struct {
+	score      float32
+	docs       []schema.Document
+	thresholds []float32
+}
+
+func (s *thresholdStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	s.docs = append(s.docs, docs...)
+	return nil, nil
+}
+
+func (s *thresholdStore) SimilaritySearch(_ context.Context, _ string, _ int, options ...vectorstores.Option) ([]schema.Document, error) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	s.thresholds = append(s.thresholds, opts.ScoreThreshold)
+	if opts.ScoreThreshold > s.score {
+		return nil, nil
+	}
+
+	return s.docs, nil
+}
+
+func TestRetrieve(t *testing.T) {
+	docs := []schema.Document{{PageContent: "I like football"}}
+
+	tests := []struct {
+		name             string
+		score            float32
+		fallback         Fallback
+		expectedDocs     int
+		expectedFallback Fallback
+		expectedSearches int
+	}{
+		{name: "found", score: 0.9, fallback: FallbackRetry, expectedDocs: 1, expectedSearches: 1},
+		{name: "none", score: 0.1, fallback: FallbackNone, expectedDocs: 0, expectedSearches: 1},
+		{name: "parametric", score: 0.1, fallback: FallbackParametric, expectedDocs: 0, expectedFallback: FallbackParametric, expectedSearches: 1},
+		{name: "retry-found", score: 0.7, fallback: FallbackRetry, expectedDocs: 1, expectedFallback: FallbackRetry, expectedSearches: 2},
+		{name: "retry-not-found", score: 0.1, fallback: FallbackRetry, expectedDocs: 0, expectedFallback: FallbackRetry, expectedSearches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &thresholdStore{score: tt.score, docs: docs}
+
+			retrieval, err := Retrieve(context.Background(), store, "What is my favourite sport?", 1, RetrievalConfig{
+				ScoreThreshold:      0.8,
+				Fallback:            tt.fallback,
+				RetryScoreThreshold: 0.6,
+			})
+			if err != nil {
+				t.Fatalf("retrieve: %s", err)
+			}
+
+			if len(retrieval.Docs) != tt.expectedDocs {
+				t.Errorf("expected %d docs, got %d", tt.expectedDocs, len(retrieval.Docs))
+			}
+			if retrieval.Fallback != tt.expectedFallback {
+				t.Errorf("expected fallback %q, got %q", tt.expectedFallback, retrieval.Fallback)
+			}
+
+			if len(store.thresholds) != tt.expectedSearches {
+				t.Fatalf("expected %d searches, got %d", tt.expectedSearches, len(store.thresholds))
+			}
+			if store.thresholds[0] != 0.8 {
+				t.Errorf("expected the first search with the score threshold, got %v", store.thresholds[0])
+			}
+			if tt.expectedSearches == 2 && store.thresholds[1] != 0.6 {
+				t.Errorf("expected the retry with the lower score threshold, got %v", store.thresholds[1])
+			}
+		})
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	for name, expected := range map[string]Fallback{"": FallbackNone, "none": FallbackNone, "Parametric": FallbackParametric, " retry ": FallbackRetry} {
+		got, err := ParseFallback(name)
+		if err != nil {
+			t.Fatalf("parse fallback %q: %s", name, err)
+		}
+		if got != expected {
+			t.Errorf("expected fallback %q for %q, got %q", expected, name, got)
+		}
+	}
+
+	if _, err := ParseFallback("guess"); err == nil {
+		t.Fatal("expected an error for an unsupported fallback")
+	}
+}
+
+func TestFallbackPrompt(t *testing.T) {
+	prompt := FallbackPrompt("What is my favourite sport?")
+
+	if !strings.Contains(prompt, "general knowledge") {
+		t.Errorf("expected the instruction to answer from the general knowledge, got:\n%s", prompt)
+	}
+	if !strings.HasSuffix(prompt, "Question: What is my favourite sport?\n") {
+		t.Errorf("expected the prompt to end with the question, got:\n%s", prompt)
+	}
+}
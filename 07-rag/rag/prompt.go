@@ -0,0 +1,45 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// UnknownAnswer is the answer the model is instructed to give when the context doesn't contain it
+const UnknownAnswer = "I don't know"
+
+// PromptOptions configures the grounded prompt built by BuildPrompt
+type PromptOptions struct {
+	// AllowUnknown instructs the model to answer UnknownAnswer when the answer is not in the context,
+	// instead of making it up
+	AllowUnknown bool
+	// Reference is an optional reference answer, included inline to ground the response
+	Reference string
+}
+
+// BuildPrompt assembles a grounded prompt for the question, with the documents as numbered
+// context passages and the instruction to cite the numbers of the passages used in the answer
+func BuildPrompt(question string, docs []schema.Document, opts PromptOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString("Answer the question using only the information of the following context passages.\n")
+	sb.WriteString("Cite the numbers of the passages supporting your answer, like [1].\n")
+	if opts.AllowUnknown {
+		fmt.Fprintf(&sb, "If the answer is not in the context passages, say %q instead of making up an answer.\n", UnknownAnswer)
+	}
+
+	sb.WriteString("\nContext:\n")
+	for i, doc := range docs {
+		fmt.Fprintf(&sb, "[%d] %s\n", i+1, strings.TrimSpace(doc.PageContent))
+	}
+
+	if opts.Reference != "" {
+		fmt.Fprintf(&sb, "\nReference answer:\n%s\n", strings.TrimSpace(opts.Reference))
+	}
+
+	fmt.Fprintf(&sb, "\nQuestion: %s\n", strings.TrimSpace(question))
+
+	return sb.String()
+}
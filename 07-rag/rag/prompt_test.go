@@ -0,0 +1,58 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestBuildPrompt(t *testing.T) {
+	docs := []schema.Document{
+		{PageContent: "I like football"},
+		{PageContent: "  The weather is good today.\n"},
+	}
+
+	t.Run("numbered-passages", func(t *testing.T) {
+		prompt := BuildPrompt("What is my favourite sport?", docs, PromptOptions{})
+
+		for _, passage := range []string{"[1] I like football\n", "[2] The weather is good today.\n"} {
+			if !strings.Contains(prompt, passage) {
+				t.Errorf("expected the prompt to contain the passage %q, got:\n%s", passage, prompt)
+			}
+		}
+
+		if !strings.Contains(prompt, "Cite the numbers of the passages") {
+			t.Errorf("expected the citation instruction, got:\n%s", prompt)
+		}
+		if !strings.HasSuffix(prompt, "Question: What is my favourite sport?\n") {
+			t.Errorf("expected the prompt to end with the question, got:\n%s", prompt)
+		}
+
+		if strings.Contains(prompt, UnknownAnswer) {
+			t.Errorf("expected no unknown answer instruction by default, got:\n%s", prompt)
+		}
+		if strings.Contains(prompt, "Reference answer") {
+			t.Errorf("expected no reference answer by default, got:\n%s", prompt)
+		}
+	})
+
+	t.Run("allow-unknown", func(t *testing.T) {
+		prompt := BuildPrompt("What is my favourite sport?", docs, PromptOptions{AllowUnknown: true})
+
+		if !strings.Contains(prompt, `say "I don't know" instead of making up an answer`) {
+			t.Errorf("expected the no-hallucination instruction, got:\n%s", prompt)
+		}
+	})
+
+	t.Run("reference", func(t *testing.T) {
+		prompt := BuildPrompt("What is my favourite sport?", docs, PromptOptions{Reference: "Football"})
+
+		if !strings.Contains(prompt, "Reference answer:\nFootball\n") {
+			t.Errorf("expected the reference answer inline, got:\n%s", prompt)
+		}
+		if strings.Index(prompt, "Reference answer") > strings.Index(prompt, "Question:") {
+			t.Errorf("expected the reference answer before the question, got:\n%s", prompt)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+// Package ragmetrics provides retrieval-quality metrics, to measure whether the similarity
+// search surfaced the relevant documents, independently of the final LLM answer.
+package ragmetrics
+
+import (
+	"github.com/tmc/langchaingo/schema"
+)
+
+// IDKey is the metadata key holding the identifier of a document.
+const IDKey = "id"
+
+// DocumentID returns the identifier of a document: the value of the "id" metadata key
+// if present, or the page content otherwise.
+func DocumentID(doc schema.Document) string {
+	if id, ok := doc.Metadata[IDKey].(string); ok && id != "" {
+		return id
+	}
+
+	return doc.PageContent
+}
+
+// PrecisionAtK returns the fraction of the top k retrieved documents that are relevant.
+// Missing results count as non-relevant, so retrieving fewer than k documents lowers the precision.
+func PrecisionAtK(retrieved []schema.Document, relevantIDs map[string]bool, k int) float64 {
+	if k <= 0 {
+		return 0
+	}
+
+	hits := 0
+	for i := 0; i < k && i < len(retrieved); i++ {
+		if relevantIDs[DocumentID(retrieved[i])] {
+			hits++
+		}
+	}
+
+	return float64(hits) / float64(k)
+}
+
+// ReciprocalRank returns 1/rank of the first relevant retrieved document, or 0 if none is relevant.
+func ReciprocalRank(retrieved []schema.Document, relevantIDs map[string]bool) float64 {
+	for i, doc := range retrieved {
+		if relevantIDs[DocumentID(doc)] {
+			return 1 / float64(i+1)
+		}
+	}
+
+	return 0
+}
+
+// MRR returns the Mean Reciprocal Rank over a set of queries, where retrieved[i]
+// are the documents retrieved for the i-th query, and relevantIDs[i] its relevant documents.
+func MRR(retrieved [][]schema.Document, relevantIDs []map[string]bool) float64 {
+	if len(retrieved) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for i, docs := range retrieved {
+		var relevant map[string]bool
+		if i < len(relevantIDs) {
+			relevant = relevantIDs[i]
+		}
+
+		total += ReciprocalRank(docs, relevant)
+	}
+
+	return total / float64(len(retrieved))
+}
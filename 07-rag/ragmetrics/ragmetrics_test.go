@@ -0,0 +1,77 @@
+package ragmetrics
+
+import (
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func docs(ids ...string) []schema.Document {
+	result := make([]schema.Document, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, schema.Document{
+			PageContent: "content of " + id,
+			Metadata:    map[string]any{IDKey: id},
+		})
+	}
+	return result
+}
+
+func TestDocumentID(t *testing.T) {
+	if got := DocumentID(schema.Document{PageContent: "I like football"}); got != "I like football" {
+		t.Errorf("expected page content as ID, got %q", got)
+	}
+
+	if got := DocumentID(docs("sports")[0]); got != "sports" {
+		t.Errorf("expected metadata ID, got %q", got)
+	}
+}
+
+func TestPrecisionAtK(t *testing.T) {
+	relevant := map[string]bool{"a": true, "c": true}
+
+	tests := []struct {
+		name      string
+		retrieved []schema.Document
+		k         int
+		want      float64
+	}{
+		{name: "all-relevant", retrieved: docs("a", "c", "b"), k: 2, want: 1},
+		{name: "half-relevant", retrieved: docs("a", "b", "c"), k: 2, want: 0.5},
+		{name: "none-relevant", retrieved: docs("b", "d"), k: 2, want: 0},
+		{name: "fewer-than-k", retrieved: docs("a"), k: 4, want: 0.25},
+		{name: "zero-k", retrieved: docs("a"), k: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrecisionAtK(tt.retrieved, relevant, tt.k); got != tt.want {
+				t.Fatalf("PrecisionAtK() = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMRR(t *testing.T) {
+	retrieved := [][]schema.Document{
+		docs("a", "b", "c"), // relevant at rank 1
+		docs("b", "a", "c"), // relevant at rank 2
+		docs("b", "c", "d"), // no relevant document
+		docs("d", "b", "a"), // relevant at rank 3
+	}
+	relevant := []map[string]bool{
+		{"a": true},
+		{"a": true},
+		{"a": true},
+		{"a": true},
+	}
+
+	want := (1 + 0.5 + 0 + 1.0/3) / 4
+	if got := MRR(retrieved, relevant); got != want {
+		t.Fatalf("MRR() = %f, want %f", got, want)
+	}
+
+	if got := MRR(nil, nil); got != 0 {
+		t.Fatalf("MRR() of no queries = %f, want 0", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Package splitter splits long texts into overlapping chunks, so that the context
+// around chunk boundaries is preserved when the chunks are embedded separately.
+package splitter
+
+// charsPerToken is the rough number of characters of a token for English text
+const charsPerToken = 4
+
+// TextSplitter splits texts into chunks of a maximum size, measured in characters,
+// or in estimated tokens when ByTokens is true.
+type TextSplitter struct {
+	ByTokens bool
+}
+
+// SplitWithOverlap splits the text into chunks of at most chunkSize units, where
+// consecutive chunks share overlap units. No content is lost: the first chunk
+// followed by every other chunk without its leading overlap reconstructs the text.
+// The overlap is clamped to [0, chunkSize), and a non-positive chunkSize returns
+// the whole text as a single chunk.
+func (s TextSplitter) SplitWithOverlap(text string, chunkSize, overlap int) []string {
+	if text == "" {
+		return nil
+	}
+
+	if s.ByTokens {
+		chunkSize *= charsPerToken
+		overlap *= charsPerToken
+	}
+
+	// work on runes, so multi-byte characters are never split
+	runes := []rune(text)
+	if chunkSize <= 0 || len(runes) <= chunkSize {
+		return []string{text}
+	}
+
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap >= chunkSize {
+		overlap = chunkSize - 1
+	}
+
+	step := chunkSize - overlap
+
+	var chunks []string
+	for start := 0; ; start += step {
+		end := min(start+chunkSize, len(runes))
+		chunks = append(chunks, string(runes[start:end]))
+
+		if end == len(runes) {
+			break
+		}
+	}
+
+	return chunks
+}
@@ -0,0 +1,87 @@
+package splitter
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const text = "Testcontainers for Go is a library to provide lightweight, throwaway instances of common databases, Selenium web browsers, or anything else that can run in a Docker container."
+
+func TestSplitWithOverlap(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		chunkSize int
+		overlap   int
+	}{
+		{name: "overlap", text: text, chunkSize: 40, overlap: 10},
+		{name: "no-overlap", text: text, chunkSize: 40, overlap: 0},
+		{name: "overlap-too-big", text: text, chunkSize: 20, overlap: 50},
+		{name: "multibyte", text: strings.Repeat("añ€😀", 30), chunkSize: 7, overlap: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := TextSplitter{}.SplitWithOverlap(tt.text, tt.chunkSize, tt.overlap)
+			if len(chunks) < 2 {
+				t.Fatalf("expected several chunks, got %d", len(chunks))
+			}
+
+			overlap := min(tt.overlap, tt.chunkSize-1)
+
+			var rebuilt strings.Builder
+			for i, chunk := range chunks {
+				size := utf8.RuneCountInString(chunk)
+				if size > tt.chunkSize {
+					t.Fatalf("chunk %d has %d characters, more than %d", i, size, tt.chunkSize)
+				}
+
+				if i == 0 {
+					rebuilt.WriteString(chunk)
+					continue
+				}
+
+				// the beginning of a chunk must repeat the end of the previous one
+				prev := []rune(chunks[i-1])
+				curr := []rune(chunk)
+				if string(prev[len(prev)-overlap:]) != string(curr[:overlap]) {
+					t.Fatalf("chunk %d does not overlap with the previous one: %q vs %q", i, string(prev), chunk)
+				}
+
+				rebuilt.WriteString(string(curr[overlap:]))
+			}
+
+			if rebuilt.String() != tt.text {
+				t.Fatalf("non-overlapping parts do not reconstruct the input:\n%q\n%q", rebuilt.String(), tt.text)
+			}
+		})
+	}
+}
+
+func TestSplitWithOverlap_shortText(t *testing.T) {
+	chunks := TextSplitter{}.SplitWithOverlap("I like football", 1024, 100)
+	if len(chunks) != 1 || chunks[0] != "I like football" {
+		t.Fatalf("expected the whole text as a single chunk, got %q", chunks)
+	}
+
+	if chunks := (TextSplitter{}).SplitWithOverlap("", 10, 2); len(chunks) != 0 {
+		t.Fatalf("expected no chunks for an empty text, got %q", chunks)
+	}
+}
+
+func TestSplitWithOverlap_byTokens(t *testing.T) {
+	chunks := TextSplitter{ByTokens: true}.SplitWithOverlap(text, 10, 2)
+
+	for i, chunk := range chunks {
+		if size := utf8.RuneCountInString(chunk); size > 10*charsPerToken {
+			t.Fatalf("chunk %d has %d characters, more than %d", i, size, 10*charsPerToken)
+		}
+	}
+
+	// 40 characters per chunk, advancing 32 characters each time
+	want := (len(text)-40+31)/32 + 1
+	if len(chunks) != want {
+		t.Fatalf("expected %d chunks, got %d", want, len(chunks))
+	}
+}
@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// CachingEmbedder is an embeddings.Embedder memoizing the vectors of the inner embedder
+// by the SHA-256 of each text, so identical texts are embedded only once.
+// It's safe for concurrent use.
+type CachingEmbedder struct {
+	inner embeddings.Embedder
+	dir   string
+
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+// CachingEmbedderOption is a functional option for the CachingEmbedder
+type CachingEmbedderOption func(*CachingEmbedder)
+
+// WithCacheDir persists the vectors in the directory, one file per text, so they survive
+// process restarts. Use a different directory per embedding model, as the texts are the only key.
+func WithCacheDir(dir string) CachingEmbedderOption {
+	return func(c *CachingEmbedder) {
+		c.dir = dir
+	}
+}
+
+// NewCachingEmbedder wraps the embedder with an in-memory cache, and an on-disk one if configured
+func NewCachingEmbedder(inner embeddings.Embedder, opts ...CachingEmbedderOption) (*CachingEmbedder, error) {
+	c := &CachingEmbedder{
+		inner:   inner,
+		vectors: make(map[string][]float32),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.dir != "" {
+		if err := os.MkdirAll(c.dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// EmbedDocuments returns the vectors of the texts, embedding only the ones not cached, in a single call
+func (c *CachingEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+
+	// Texts missing in the cache, deduplicated, with the positions where their vectors go
+	var missing []string
+	positions := make(map[string][]int)
+
+	for i, text := range texts {
+		key := cacheKey("document", text)
+
+		if vec, ok := c.get(key); ok {
+			vecs[i] = vec
+			continue
+		}
+
+		if _, ok := positions[key]; !ok {
+			missing = append(missing, text)
+		}
+		positions[key] = append(positions[key], i)
+	}
+
+	if len(missing) == 0 {
+		return vecs, nil
+	}
+
+	embedded, err := c.inner.EmbedDocuments(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missing) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(embedded), len(missing))
+	}
+
+	for i, text := range missing {
+		key := cacheKey("document", text)
+		if err := c.put(key, embedded[i]); err != nil {
+			return nil, err
+		}
+
+		for _, pos := range positions[key] {
+			vecs[pos] = slices.Clone(embedded[i])
+		}
+	}
+
+	return vecs, nil
+}
+
+// EmbedQuery returns the vector of the query text. Queries are cached apart from the documents,
+// as some embedding models embed them differently.
+func (c *CachingEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	key := cacheKey("query", text)
+
+	if vec, ok := c.get(key); ok {
+		return vec, nil
+	}
+
+	vec, err := c.inner.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.put(key, vec); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(vec), nil
+}
+
+// cacheKey returns the hex-encoded SHA-256 of the text, prefixed by its kind
+func cacheKey(kind string, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return kind + "-" + hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the cached vector, loading it from the disk cache if it's not in memory
+func (c *CachingEmbedder) get(key string) ([]float32, bool) {
+	c.mu.RLock()
+	vec, ok := c.vectors[key]
+	c.mu.RUnlock()
+	if ok {
+		return slices.Clone(vec), true
+	}
+
+	if c.dir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	// A corrupted file is a miss, overwritten once the text is embedded again
+	if err := json.Unmarshal(data, &vec); err != nil || len(vec) == 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.vectors[key] = vec
+	c.mu.Unlock()
+
+	return slices.Clone(vec), true
+}
+
+// put caches a copy of the vector, in memory and in the disk cache if configured
+func (c *CachingEmbedder) put(key string, vec []float32) error {
+	vec = slices.Clone(vec)
+
+	c.mu.Lock()
+	c.vectors[key] = vec
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(vec)
+	if err != nil {
+		return fmt.Errorf("marshal vector: %w", err)
+	}
+
+	// Write to a temporary file first, so concurrent readers never see a partial vector
+	tmp, err := os.CreateTemp(c.dir, key+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create cache file: %w", err)
+	}
+
+	_, err = tmp.Write(data)
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file of the disk cache for the key
+func (c *CachingEmbedder) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
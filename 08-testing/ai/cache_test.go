@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// recordingEmbedder embeds each text as a vector of its length, recording the embedded texts
+type recordingEmbedder struct {
+	mu       sync.Mutex
+	embedded []string
+}
+
+func (e *recordingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	vecs := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		e.embedded = append(e.embedded, text)
+		vecs = append(vecs, []float32{float32(len(text)), 1})
+	}
+
+	return vecs, nil
+}
+
+func (e *recordingEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+func TestCachingEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hits-skip-inner", func(t *testing.T) {
+		inner := &recordingEmbedder{}
+		cache, err := NewCachingEmbedder(inner)
+		if err != nil {
+			t.Fatalf("new caching embedder: %s", err)
+		}
+
+		vecs, err := cache.EmbedDocuments(ctx, []string{"cat", "tiger", "cat"})
+		if err != nil {
+			t.Fatalf("embed documents: %s", err)
+		}
+		if !slices.Equal(vecs[0], []float32{3, 1}) || !slices.Equal(vecs[1], []float32{5, 1}) || !slices.Equal(vecs[2], []float32{3, 1}) {
+			t.Fatalf("unexpected vectors: %v", vecs)
+		}
+
+		if _, err := cache.EmbedDocuments(ctx, []string{"tiger", "docker"}); err != nil {
+			t.Fatalf("embed documents: %s", err)
+		}
+
+		if !slices.Equal(inner.embedded, []string{"cat", "tiger", "docker"}) {
+			t.Errorf("expected each text to be embedded once, got %v", inner.embedded)
+		}
+
+		// Mutating a returned vector must not corrupt the cache
+		vecs[0][0] = 42
+		again, err := cache.EmbedDocuments(ctx, []string{"cat"})
+		if err != nil {
+			t.Fatalf("embed documents: %s", err)
+		}
+		if again[0][0] != 3 {
+			t.Errorf("expected the cached vector to be unchanged, got %v", again[0])
+		}
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		inner := &recordingEmbedder{}
+		cache, err := NewCachingEmbedder(inner)
+		if err != nil {
+			t.Fatalf("new caching embedder: %s", err)
+		}
+
+		for range 3 {
+			if _, err := cache.EmbedQuery(ctx, "cat"); err != nil {
+				t.Fatalf("embed query: %s", err)
+			}
+		}
+
+		if len(inner.embedded) != 1 {
+			t.Errorf("expected the query to be embedded once, got %v", inner.embedded)
+		}
+	})
+
+	t.Run("disk-round-trip", func(t *testing.T) {
+		dir := t.TempDir()
+
+		first, err := NewCachingEmbedder(&recordingEmbedder{}, WithCacheDir(dir))
+		if err != nil {
+			t.Fatalf("new caching embedder: %s", err)
+		}
+		expected, err := first.EmbedDocuments(ctx, []string{"cat", "tiger"})
+		if err != nil {
+			t.Fatalf("embed documents: %s", err)
+		}
+
+		// A new embedder, as after a restart, reads the vectors from the disk
+		inner := &recordingEmbedder{}
+		second, err := NewCachingEmbedder(inner, WithCacheDir(dir))
+		if err != nil {
+			t.Fatalf("new caching embedder: %s", err)
+		}
+		got, err := second.EmbedDocuments(ctx, []string{"tiger", "cat"})
+		if err != nil {
+			t.Fatalf("embed documents: %s", err)
+		}
+
+		if !slices.Equal(got[0], expected[1]) || !slices.Equal(got[1], expected[0]) {
+			t.Errorf("expected the vectors from the disk cache, got %v", got)
+		}
+		if len(inner.embedded) != 0 {
+			t.Errorf("expected no texts to be embedded, got %v", inner.embedded)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		cache, err := NewCachingEmbedder(&recordingEmbedder{}, WithCacheDir(t.TempDir()))
+		if err != nil {
+			t.Fatalf("new caching embedder: %s", err)
+		}
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := cache.EmbedDocuments(ctx, []string{"cat", "tiger", "docker"}); err != nil {
+					t.Errorf("embed documents: %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chewxy/math32"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// normTolerance is the maximum distance of the L2 norm to 1.0 for a vector to be considered normalized
+const normTolerance = 1e-3
+
+// EmbeddingCapabilities describes the vectors produced by an embedding model.
+// Vector stores must be configured with the same dimension, and the similarity
+// metric can be simplified to the dot product when vectors are normalized.
+type EmbeddingCapabilities struct {
+	EmbeddingDim int
+	Normalized   bool
+}
+
+// ProbeEmbeddings embeds a sample text once to discover the capabilities of the embedding model
+func ProbeEmbeddings(ctx context.Context, embedder embeddings.Embedder) (EmbeddingCapabilities, error) {
+	vector, err := embedder.EmbedQuery(ctx, "Testcontainers for Go")
+	if err != nil {
+		return EmbeddingCapabilities{}, fmt.Errorf("embed query: %w", err)
+	}
+
+	if len(vector) == 0 {
+		return EmbeddingCapabilities{}, fmt.Errorf("embedding model returned an empty vector")
+	}
+
+	return EmbeddingCapabilities{
+		EmbeddingDim: len(vector),
+		Normalized:   isNormalized(vector),
+	}, nil
+}
+
+// l2Norm returns the euclidean length of the vector
+func l2Norm(v []float32) float32 {
+	var sum float32
+	for _, x := range v {
+		sum += x * x
+	}
+
+	return math32.Sqrt(sum)
+}
+
+// isNormalized checks if the vector has an L2 norm of ~1.0
+func isNormalized(v []float32) bool {
+	return math32.Abs(l2Norm(v)-1) <= normTolerance
+}
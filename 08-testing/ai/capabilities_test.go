@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeEmbedder returns the same vector for any text
+type fakeEmbedder struct {
+	vector []float32
+}
+
+func (f *fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = f.vector
+	}
+	return vectors, nil
+}
+
+func (f *fakeEmbedder) EmbedQuery(_ context.Context, _ string) ([]float32, error) {
+	return f.vector, nil
+}
+
+func TestL2Norm(t *testing.T) {
+	if got := l2Norm([]float32{3, 4}); got != 5 {
+		t.Fatalf("expected norm of 5, got %f", got)
+	}
+
+	if got := l2Norm([]float32{0, 0, 0}); got != 0 {
+		t.Fatalf("expected norm of 0, got %f", got)
+	}
+}
+
+func TestIsNormalized(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector []float32
+		want   bool
+	}{
+		{name: "unit", vector: []float32{0.6, 0.8}, want: true},
+		{name: "axis", vector: []float32{0, 1, 0}, want: true},
+		{name: "not-normalized", vector: []float32{3, 4}, want: false},
+		{name: "zero", vector: []float32{0, 0}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNormalized(tt.vector); got != tt.want {
+				t.Fatalf("isNormalized(%v) = %t, want %t", tt.vector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeEmbeddings(t *testing.T) {
+	caps, err := ProbeEmbeddings(context.Background(), &fakeEmbedder{vector: []float32{0.6, 0, 0.8}})
+	if err != nil {
+		t.Fatalf("probe embeddings: %s", err)
+	}
+
+	if caps.EmbeddingDim != 3 {
+		t.Errorf("expected dimension 3, got %d", caps.EmbeddingDim)
+	}
+	if !caps.Normalized {
+		t.Error("expected normalized vectors")
+	}
+
+	if _, err := ProbeEmbeddings(context.Background(), &fakeEmbedder{}); err == nil {
+		t.Error("expected an error for an empty vector")
+	}
+}
@@ -81,8 +81,8 @@ func (s *ChatService) Chat(userMessage string) (string, error) {
 
 	content = append(content, llms.TextParts(llms.ChatMessageTypeHuman, userMessage))
 
-	completion, err := s.chatModel.GenerateContent(
-		ctx, content,
+	completion, err := generateContent(
+		ctx, s.chatModel, content,
 		llms.WithTemperature(0.00),
 		llms.WithTopK(1),
 		llms.WithSeed(42),
@@ -76,8 +76,8 @@ func (v *EvaluatorAgent) Evaluate(question string, answer string, reference stri
 		llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(v.userMessage, question, answer, reference)),
 	}
 
-	completion, err := v.chatModel.GenerateContent(
-		ctx, content,
+	completion, err := generateContent(
+		ctx, v.chatModel, content,
 		llms.WithTemperature(0.00),
 		llms.WithTopK(1),
 		llms.WithSeed(42),
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"unicode/utf8"
+)
+
+// StreamPrinter prints the streamed chunks of a generation to its writer. The chunks may split the
+// multi-byte UTF-8 characters, e.g. accented letters, CJK characters or emojis, so the bytes of an
+// incomplete character are held back until the next chunk completes it, instead of printing
+// garbled halves.
+type StreamPrinter struct {
+	w       io.Writer
+	pending []byte
+}
+
+// NewStreamPrinter creates a StreamPrinter printing to w
+func NewStreamPrinter(w io.Writer) *StreamPrinter {
+	return &StreamPrinter{w: w}
+}
+
+// Stream is the streaming function of the generation, see [llms.WithStreamingFunc], printing the
+// complete characters of the chunk. It stops the stream with the context error once the generation
+// is canceled, e.g. by Ctrl+C, so no chunk is printed after it.
+func (p *StreamPrinter) Stream(ctx context.Context, chunk []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data := chunk
+	if len(p.pending) > 0 {
+		data = append(p.pending, chunk...)
+	}
+
+	n := len(data) - incompleteSuffixLen(data)
+	p.pending = append(p.pending[:0:0], data[n:]...)
+
+	_, err := p.w.Write(data[:n])
+	return err
+}
+
+// Flush prints the bytes held back when the stream ended in the middle of a character
+func (p *StreamPrinter) Flush() error {
+	rest := p.pending
+	p.pending = nil
+
+	_, err := p.w.Write(rest)
+	return err
+}
+
+// incompleteSuffixLen returns the length of the incomplete UTF-8 character at the end of the data,
+// zero if it ends with a complete one. Invalid bytes are not held back, as no chunk can complete them.
+func incompleteSuffixLen(data []byte) int {
+	// An incomplete character is shorter than the longest one: look for its first byte
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if utf8.FullRune(data[len(data)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+
+	return 0
+}
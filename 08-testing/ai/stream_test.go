@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/llmtest"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// writesRecorder records each write, to verify that no write prints half a character
+type writesRecorder struct {
+	writes []string
+}
+
+func (r *writesRecorder) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+func (r *writesRecorder) String() string {
+	return strings.Join(r.writes, "")
+}
+
+func TestStreamPrinter(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+	}{
+		{name: "ascii", chunks: []string{"Hello, ", "world"}},
+		// "ñ" is 0xC3 0xB1
+		{name: "two-bytes-split", chunks: []string{"Espa\xc3", "\xb1a"}},
+		// "語" is 0xE8 0xAA 0x9E
+		{name: "three-bytes-split-twice", chunks: []string{"日本\xe8", "\xaa", "\x9e"}},
+		// "🚀" is 0xF0 0x9F 0x9A 0x80
+		{name: "four-bytes-split", chunks: []string{"Launch \xf0\x9f", "\x9a\x80!"}},
+		{name: "one-byte-chunks", chunks: []string{"\xf0", "\x9f", "\x9a", "\x80"}},
+		{name: "empty-chunks", chunks: []string{"", "\xc3", "", "\xb1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out writesRecorder
+			printer := NewStreamPrinter(&out)
+			model := &llmtest.StreamingModel{Chunks: tt.chunks}
+
+			if _, err := model.GenerateContent(context.Background(), nil, llms.WithStreamingFunc(printer.Stream)); err != nil {
+				t.Fatalf("generate content: %s", err)
+			}
+			if err := printer.Flush(); err != nil {
+				t.Fatalf("flush: %s", err)
+			}
+
+			for _, write := range out.writes {
+				if !utf8.ValidString(write) {
+					t.Errorf("expected only complete characters, got %q", write)
+				}
+			}
+			if expected := strings.Join(tt.chunks, ""); out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+		})
+	}
+
+	t.Run("truncated-stream", func(t *testing.T) {
+		var out strings.Builder
+		printer := NewStreamPrinter(&out)
+
+		if err := printer.Stream(context.Background(), []byte("fin\xe8\xaa")); err != nil {
+			t.Fatalf("stream: %s", err)
+		}
+		if out.String() != "fin" {
+			t.Errorf("expected the incomplete character to be held back, got %q", out.String())
+		}
+		if err := printer.Flush(); err != nil || out.String() != "fin\xe8\xaa" {
+			t.Errorf("expected the held back bytes on flush, got %q and error %v", out.String(), err)
+		}
+	})
+
+	t.Run("invalid-bytes", func(t *testing.T) {
+		var out strings.Builder
+		printer := NewStreamPrinter(&out)
+
+		if err := printer.Stream(context.Background(), []byte("bad\xff")); err != nil || out.String() != "bad\xff" {
+			t.Errorf("expected the invalid byte not to be held back, got %q and error %v", out.String(), err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var out strings.Builder
+		printer := NewStreamPrinter(&out)
+		model := &llmtest.StreamingModel{
+			// "café" split in the middle of the "é"
+			Chunks: []string{"Testcontainers ", "is ", "caf\xc3", "\xa9 ", "great"},
+			OnChunk: func(i int) {
+				// Ctrl+C after the second chunk
+				if i == 1 {
+					cancel()
+				}
+			},
+		}
+
+		_, err := model.GenerateContent(ctx, nil, llms.WithStreamingFunc(printer.Stream))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the generation canceled, got %v", err)
+		}
+		if out.String() != "Testcontainers is " {
+			t.Errorf("expected no chunk printed after the cancellation, got %q", out.String())
+		}
+	})
+}
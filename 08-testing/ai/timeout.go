@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// DefaultGenerationTimeout is the deadline of the generation calls, so a stalled model
+// fails the chat or the evaluation instead of hanging forever.
+const DefaultGenerationTimeout = 2 * time.Minute
+
+// ErrGenerationTimeout is returned when the model does not respond before the generation timeout.
+var ErrGenerationTimeout = errors.New("generation timed out")
+
+// GenerationTimeout returns the deadline of the generation calls, which can be overridden
+// with the GENAI_GENERATION_TIMEOUT environment variable, e.g. "5m".
+func GenerationTimeout() time.Duration {
+	v := os.Getenv("GENAI_GENERATION_TIMEOUT")
+	if v == "" {
+		return DefaultGenerationTimeout
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		logging.Default().Warn("Invalid GENAI_GENERATION_TIMEOUT, using the default", "value", v, "default", DefaultGenerationTimeout)
+		return DefaultGenerationTimeout
+	}
+
+	return d
+}
+
+// GenerationError returns an [ErrGenerationTimeout] error when the generation bounded by the timeout
+// exceeded its deadline, or the error as is otherwise. The timeout is the one the caller computed with
+// [GenerationTimeout], so the environment is only read, and an invalid value only logged, once.
+func GenerationError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %w", ErrGenerationTimeout, timeout, err)
+	}
+
+	return err
+}
+
+// generateContent calls the model bounded by the generation timeout, see [GenerationTimeout].
+func generateContent(ctx context.Context, model llms.Model, content []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	timeout := GenerationTimeout()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	completion, err := model.GenerateContent(ctx, content, opts...)
+	if err != nil {
+		return nil, GenerationError(err, timeout)
+	}
+
+	return completion, nil
+}
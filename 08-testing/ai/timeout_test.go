@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// blockingModel never responds, until the context is done
+type blockingModel struct{}
+
+func (blockingModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (m blockingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestGenerationTimeout(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("GENAI_GENERATION_TIMEOUT", "")
+
+		if got := GenerationTimeout(); got != DefaultGenerationTimeout {
+			t.Fatalf("expected the default timeout, got %s", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("GENAI_GENERATION_TIMEOUT", "soon")
+
+		if got := GenerationTimeout(); got != DefaultGenerationTimeout {
+			t.Fatalf("expected the default timeout, got %s", got)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("GENAI_GENERATION_TIMEOUT", "5m")
+
+		if got := GenerationTimeout(); got != 5*time.Minute {
+			t.Fatalf("expected 5m, got %s", got)
+		}
+	})
+}
+
+func TestGenerationError(t *testing.T) {
+	err := GenerationError(fmt.Errorf("generate: %w", context.DeadlineExceeded), time.Minute)
+	if !errors.Is(err, ErrGenerationTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a generation timeout wrapping the deadline, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "after 1m0s") {
+		t.Errorf("expected the timeout in the error, got %q", err)
+	}
+
+	other := errors.New("connection refused")
+	if got := GenerationError(other, time.Minute); got != other {
+		t.Errorf("expected the error as is, got %v", got)
+	}
+}
+
+func TestChat_timeout(t *testing.T) {
+	t.Setenv("GENAI_GENERATION_TIMEOUT", "50ms")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewChat(blockingModel{}).Chat("Are you there?")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrGenerationTimeout) {
+			t.Fatalf("expected a generation timeout, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the generation timeout did not fire")
+	}
+}
+
+func TestEvaluate_timeout(t *testing.T) {
+	t.Setenv("GENAI_GENERATION_TIMEOUT", "50ms")
+
+	_, err := NewEvaluatorAgent(blockingModel{}).Evaluate("question", "answer", "reference")
+	if !errors.Is(err, ErrGenerationTimeout) {
+		t.Fatalf("expected a generation timeout, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package chroma
+
+import (
+	"context"
+	"fmt"
+
+	chromatypes "github.com/amikos-tech/chroma-go/types"
+	"github.com/testcontainers/testcontainers-go"
+	tcchroma "github.com/testcontainers/testcontainers-go/modules/chroma"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores/chroma"
+)
+
+// NewStore creates a new Chroma store. It will use a chroma container to store the data.
+func NewStore(ctx context.Context, embedder embeddings.Embedder, opts ...Option) (chroma.Store, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return chroma.Store{}, fmt.Errorf("chroma options: %w", err)
+	}
+
+	endpoint, err := mustGetEndpoint(ctx)
+	if err != nil {
+		return chroma.Store{}, fmt.Errorf("run chroma: %w", err)
+	}
+
+	return chroma.New(
+		chroma.WithChromaURL(endpoint),
+		chroma.WithEmbedder(embedder),
+		// the langchaingo store names the collection after its namespace
+		chroma.WithNameSpace(o.collectionName),
+		chroma.WithDistanceFunction(chromatypes.DistanceFunction(o.distanceFunction)),
+	)
+}
+
+func mustGetEndpoint(ctx context.Context) (string, error) {
+	c, err := tcchroma.Run(ctx, "chromadb/chroma:0.4.24",
+		testcontainers.WithReuseByName("chroma-db"),
+		// the API is ready once the heartbeat endpoint responds
+		testcontainers.WithWaitStrategy(wait.ForHTTP("/api/v1/heartbeat").WithPort("8000/tcp")),
+	)
+	if err != nil {
+		return "", fmt.Errorf("run container: %w", err)
+	}
+
+	endpoint, err := c.RESTEndpoint(ctx)
+	if err != nil {
+		return "", fmt.Errorf("chroma container endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
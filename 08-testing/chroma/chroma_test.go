@@ -0,0 +1,70 @@
+//go:build integration
+
+package chroma
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// keywordEmbedder returns a vector per topic, so the test controls the vector similarity
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) vector(text string) []float32 {
+	switch {
+	case strings.Contains(text, "verbose"):
+		return []float32{0, 1, 0}
+	case strings.Contains(text, "Postgres"):
+		return []float32{0, 0, 1}
+	default:
+		return []float32{1, 0, 0}
+	}
+}
+
+func (e keywordEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e keywordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vector(text), nil
+}
+
+func TestNewStore(t *testing.T) {
+	ctx := context.Background()
+
+	// a unique collection, as the container is reused across runs
+	collection := fmt.Sprintf("ChromaTest%d", time.Now().UnixNano())
+
+	store, err := NewStore(ctx, keywordEmbedder{}, WithCollectionName(collection))
+	if err != nil {
+		t.Fatalf("new store: %s", err)
+	}
+
+	verboseDoc := "Set cloud.logs.verbose to true to enable verbose logging."
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Testcontainers Desktop helps with local development."},
+		{PageContent: "The Postgres module runs a database."},
+		{PageContent: verboseDoc},
+	})
+	if err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+
+	docs, err := store.SimilaritySearch(ctx, "How do I get verbose logs?", 1)
+	if err != nil {
+		t.Fatalf("similarity search: %s", err)
+	}
+
+	if len(docs) != 1 || docs[0].PageContent != verboseDoc {
+		t.Fatalf("expected the verbose logging document, got %v", docs)
+	}
+}
@@ -0,0 +1,69 @@
+package chroma
+
+import "fmt"
+
+// DistanceFunction is the distance function used by the collection to compare the embeddings
+type DistanceFunction string
+
+const (
+	// Cosine is the cosine distance, the default.
+	Cosine DistanceFunction = "cosine"
+	// L2 is the squared euclidean distance, the Chroma default
+	L2 DistanceFunction = "l2"
+	// InnerProduct is the inner product, equivalent to cosine for normalized vectors
+	InnerProduct DistanceFunction = "ip"
+)
+
+func (d DistanceFunction) validate() error {
+	switch d {
+	case Cosine, L2, InnerProduct:
+		return nil
+	default:
+		return fmt.Errorf("unsupported distance function %q: use one of %q, %q or %q", d, Cosine, L2, InnerProduct)
+	}
+}
+
+type options struct {
+	collectionName   string
+	distanceFunction DistanceFunction
+}
+
+// Option is a functional option for the Chroma store
+type Option func(*options)
+
+// WithCollectionName sets the collection where the documents are stored. Defaults to "Testcontainers".
+func WithCollectionName(name string) Option {
+	return func(o *options) {
+		o.collectionName = name
+	}
+}
+
+// WithDistanceFunction sets the distance function of the collection. Defaults to Cosine.
+// It is only applied when the collection is created.
+func WithDistanceFunction(distanceFunction DistanceFunction) Option {
+	return func(o *options) {
+		o.distanceFunction = distanceFunction
+	}
+}
+
+func newOptions(opts ...Option) (options, error) {
+	o := options{
+		collectionName:   "Testcontainers",
+		distanceFunction: Cosine,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Chroma collection names are 3-63 characters long
+	if n := len(o.collectionName); n < 3 || n > 63 {
+		return options{}, fmt.Errorf("invalid collection name %q: must be between 3 and 63 characters", o.collectionName)
+	}
+
+	if err := o.distanceFunction.validate(); err != nil {
+		return options{}, err
+	}
+
+	return o, nil
+}
@@ -0,0 +1,51 @@
+package chroma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		o, err := newOptions()
+		if err != nil {
+			t.Fatalf("new options: %s", err)
+		}
+
+		if o.collectionName != "Testcontainers" {
+			t.Fatalf("expected Testcontainers collection by default, got %q", o.collectionName)
+		}
+		if o.distanceFunction != Cosine {
+			t.Fatalf("expected cosine by default, got %q", o.distanceFunction)
+		}
+	})
+
+	for _, fn := range []DistanceFunction{Cosine, L2, InnerProduct} {
+		t.Run(string(fn), func(t *testing.T) {
+			o, err := newOptions(WithDistanceFunction(fn))
+			if err != nil {
+				t.Fatalf("new options: %s", err)
+			}
+
+			if o.distanceFunction != fn {
+				t.Fatalf("expected distance function %q, got %q", fn, o.distanceFunction)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := map[string]Option{
+			"short-collection": WithCollectionName("tc"),
+			"long-collection":  WithCollectionName(strings.Repeat("a", 64)),
+			"unknown-distance": WithDistanceFunction("manhattan"),
+		}
+
+		for name, opt := range invalid {
+			t.Run(name, func(t *testing.T) {
+				if _, err := newOptions(opt); err == nil {
+					t.Fatal("expected an error")
+				}
+			})
+		}
+	})
+}
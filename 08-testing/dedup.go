@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// contentHashKey is the metadata key where the SHA-256 of the page content is stored,
+// so that the documents already ingested can be identified in the store.
+const contentHashKey = "content_hash"
+
+// contentDeduplicater skips the documents whose content was already seen in the ingestion run,
+// avoiding the cost of embedding the same content twice and duplicated results in the retrieval.
+type contentDeduplicater struct {
+	seen map[string]struct{}
+}
+
+func newContentDeduplicater() *contentDeduplicater {
+	return &contentDeduplicater{
+		seen: make(map[string]struct{}),
+	}
+}
+
+// contentHash returns the hex-encoded SHA-256 of the page content of the document
+func contentHash(doc schema.Document) string {
+	sum := sha256.Sum256([]byte(doc.PageContent))
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter returns the documents not seen before, adding their content hash to the metadata.
+func (d *contentDeduplicater) Filter(docs []schema.Document) []schema.Document {
+	unique := make([]schema.Document, 0, len(docs))
+
+	for _, doc := range docs {
+		hash := contentHash(doc)
+		if _, ok := d.seen[hash]; ok {
+			continue
+		}
+		d.seen[hash] = struct{}{}
+
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		maps.Copy(metadata, doc.Metadata)
+		metadata[contentHashKey] = hash
+		doc.Metadata = metadata
+
+		unique = append(unique, doc)
+	}
+
+	return unique
+}
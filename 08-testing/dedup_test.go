@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestContentDeduplicater(t *testing.T) {
+	d := newContentDeduplicater()
+
+	docs := d.Filter([]schema.Document{
+		{PageContent: "Testcontainers Desktop"},
+		{PageContent: "cloud.logs.verbose", Metadata: map[string]any{"source": "tcc.txt"}},
+		{PageContent: "Testcontainers Desktop"},
+	})
+
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 unique documents, got %d", len(docs))
+	}
+
+	for _, doc := range docs {
+		if doc.Metadata[contentHashKey] != contentHash(doc) {
+			t.Errorf("expected content hash in the metadata of %q", doc.PageContent)
+		}
+	}
+
+	if docs[1].Metadata["source"] != "tcc.txt" {
+		t.Errorf("expected existing metadata to be preserved, got %v", docs[1].Metadata)
+	}
+
+	// documents are also deduplicated across calls in the same ingestion run
+	docs = d.Filter([]schema.Document{
+		{PageContent: "cloud.logs.verbose"},
+		{PageContent: "A new document"},
+	})
+
+	if len(docs) != 1 || docs[0].PageContent != "A new document" {
+		t.Fatalf("expected only the distinct document to pass through, got %v", docs)
+	}
+}
@@ -0,0 +1,81 @@
+package llmtest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// update rewrites the golden files with the current output: go test -run _golden -update .
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// ScriptedModel answers the requests with its answers in order, writing each request to the
+// transcript, so the prompts sent by an example are part of its golden output
+type ScriptedModel struct {
+	Answers    []string
+	Transcript io.Writer
+
+	calls int
+}
+
+var _ llms.Model = (*ScriptedModel)(nil)
+
+// GenerateContent writes the request to the transcript and returns the next answer, failing
+// when there are no answers left
+func (m *ScriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	if m.calls >= len(m.Answers) {
+		return nil, fmt.Errorf("unexpected request %d", m.calls+1)
+	}
+	m.calls++
+
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	fmt.Fprintf(m.Transcript, "=== request %d (temperature=%g, top_k=%d) ===\n", m.calls, opts.Temperature, opts.TopK)
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			fmt.Fprintf(m.Transcript, "[%s] %s\n", message.Role, part)
+		}
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: m.Answers[m.calls-1]}},
+	}, nil
+}
+
+// Call generates the content of the prompt
+func (m *ScriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// AssertGolden compares the output with the golden file testdata/<name>.golden, or rewrites the
+// file when the tests run with -update
+func AssertGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("update golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %s, run the tests with -update to create it", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("the output differs from %s, run the tests with -update if the change is intended\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
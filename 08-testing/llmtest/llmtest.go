@@ -0,0 +1,57 @@
+// Package llmtest provides fake language models for the tests of the examples, so they run
+// without a model runner.
+package llmtest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// StreamingModel streams its chunks, stopping when the streaming function fails or the context is
+// canceled, like the OpenAI client. The messages of each request are recorded in Requests.
+type StreamingModel struct {
+	Chunks []string
+	// OnChunk, if set, runs after each streamed chunk, e.g. to interrupt the generation
+	OnChunk func(i int)
+	// Requests are the messages of the requests, in order
+	Requests [][]llms.MessageContent
+}
+
+var _ llms.Model = (*StreamingModel)(nil)
+
+// GenerateContent streams the chunks to the streaming function of the options, if any, and
+// returns their concatenation
+func (m *StreamingModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.Requests = append(m.Requests, messages)
+
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var content strings.Builder
+	for i, chunk := range m.Chunks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return nil, err
+			}
+		}
+		content.WriteString(chunk)
+
+		if m.OnChunk != nil {
+			m.OnChunk(i)
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content.String()}}}, nil
+}
+
+// Call generates the content of the prompt
+func (m *StreamingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
@@ -0,0 +1,59 @@
+// Package logging provides the leveled, structured logger of the benchmarks and the examples, so their
+// diagnostics can be filtered, e.g. silenced in CI, while the model output stays on stdout.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvLogLevel is the environment variable with the minimum level of the logs:
+// debug, info, warn or error. Defaults to info.
+const EnvLogLevel = "GENAI_LOG_LEVEL"
+
+var (
+	defaultLogger *slog.Logger
+	defaultOnce   sync.Once
+)
+
+// Default returns the logger writing to stderr, at the level of the GENAI_LOG_LEVEL environment variable.
+func Default() *slog.Logger {
+	defaultOnce.Do(func() {
+		defaultLogger = New(os.Stderr)
+	})
+
+	return defaultLogger
+}
+
+// New returns a logger writing text logs to w, at the level of the GENAI_LOG_LEVEL environment variable.
+// An invalid level falls back to info, logging a warning.
+func New(w io.Writer) *slog.Logger {
+	value := os.Getenv(EnvLogLevel)
+
+	level, err := ParseLevel(value)
+	logger := slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+	if err != nil {
+		logger.Warn("Invalid log level, using info", "env", EnvLogLevel, "value", value, "error", err)
+	}
+
+	return logger
+}
+
+// ParseLevel parses a level name, case-insensitively. An empty name is the info level.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo, err
+	}
+
+	return level, nil
+}
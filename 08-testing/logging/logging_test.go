@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"":      slog.LevelInfo,
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"Warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+
+	for name, want := range tests {
+		got, err := ParseLevel(name)
+		if err != nil {
+			t.Fatalf("parse level %q: %s", name, err)
+		}
+		if got != want {
+			t.Errorf("expected %s for %q, got %s", want, name, got)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("filtering", func(t *testing.T) {
+		t.Setenv(EnvLogLevel, "warn")
+
+		var buf bytes.Buffer
+		logger := New(&buf)
+
+		logger.Debug("debug message")
+		logger.Info("info message")
+		logger.Warn("warn message")
+		logger.Error("error message")
+
+		out := buf.String()
+		for _, filtered := range []string{"debug message", "info message"} {
+			if strings.Contains(out, filtered) {
+				t.Errorf("expected %q to be filtered out, got %q", filtered, out)
+			}
+		}
+		for _, logged := range []string{"warn message", "error message"} {
+			if !strings.Contains(out, logged) {
+				t.Errorf("expected %q to be logged, got %q", logged, out)
+			}
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv(EnvLogLevel, "")
+
+		var buf bytes.Buffer
+		logger := New(&buf)
+
+		logger.Debug("debug message")
+		logger.Info("info message")
+
+		if out := buf.String(); strings.Contains(out, "debug message") || !strings.Contains(out, "info message") {
+			t.Errorf("expected the info level by default, got %q", out)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv(EnvLogLevel, "verbose")
+
+		var buf bytes.Buffer
+		logger := New(&buf)
+		logger.Info("info message")
+
+		out := buf.String()
+		if !strings.Contains(out, "Invalid log level") || !strings.Contains(out, "info message") {
+			t.Errorf("expected a warning and the info level, got %q", out)
+		}
+	})
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"embed"
 	"fmt"
-	"log"
+	"os"
 
 	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
-	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/vectorstores"
@@ -30,9 +30,10 @@ const (
 var knowledge embed.FS
 
 func main() {
-	log.Println(question)
+	logging.Default().Info("Asking", "question", question)
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -50,7 +51,7 @@ func run() error {
 
 	resp, err := straightAnswer(chatModel)
 	if err != nil {
-		log.Fatalf("straight chat: %s", err)
+		return fmt.Errorf("straight chat: %w", err)
 	}
 	fmt.Println(">> Straight answer:\n", resp)
 
@@ -95,24 +96,30 @@ func buildRaggedChat(chatModel llms.Model) (ai.Chatter, *dmr.Container, error) {
 		return nil, embeddingsCtr, fmt.Errorf("build embedding model: %w", err)
 	}
 
-	embedder, err := embeddings.NewEmbedder(embeddingModel)
+	embedder, err := newEmbedder(embeddingModel)
 	if err != nil {
 		return nil, embeddingsCtr, fmt.Errorf("new embedder: %w", err)
 	}
 
+	caps, err := ai.ProbeEmbeddings(context.Background(), embedder)
+	if err != nil {
+		return nil, embeddingsCtr, fmt.Errorf("probe embeddings: %w", err)
+	}
+	logging.Default().Info("Embedding model", "model", fqEmbeddingsModelName, "dimension", caps.EmbeddingDim, "normalized", caps.Normalized)
+
 	store, err := selectStore(context.Background(), embedder)
 	if err != nil {
 		return nil, embeddingsCtr, fmt.Errorf("new store: %w", err)
 	}
+	defer store.Close()
 
-	if err := ingestion(store); err != nil {
+	if err := ingestion(store, ""); err != nil {
 		return nil, embeddingsCtr, fmt.Errorf("ingestion: %w", err)
 	}
 
 	// Enrich the response with the relevant documents after the ingestion
 	optionsVector := []vectorstores.Option{
 		vectorstores.WithScoreThreshold(0.60), // use for precision, when you want to get only the most relevant documents
-		//vectorstores.WithNameSpace("default"),            // use for set a namespace in the storage
 		//vectorstores.WithFilters(map[string]interface{}{"language": "en"}), // use for filter the documents
 		vectorstores.WithEmbedder(embedder), // use when you want add documents or doing similarity search
 		//vectorstores.WithDeduplicater(vectorstores.NewSimpleDeduplicater()), //  This is useful to prevent wasting time on creating an embedding
@@ -120,11 +127,12 @@ func buildRaggedChat(chatModel llms.Model) (ai.Chatter, *dmr.Container, error) {
 
 	maxResults := 3 // Number of relevant documents to return
 
-	relevantDocs, err := store.SimilaritySearch(context.Background(), "cloud.logs.verbose", maxResults, optionsVector...)
+	// use a namespace to isolate the documents of a user or tenant in the storage, see ingestion
+	relevantDocs, err := search(context.Background(), store, "", "cloud.logs.verbose", maxResults, optionsVector...)
 	if err != nil {
-		return nil, embeddingsCtr, fmt.Errorf("similarity search: %w", err)
+		return nil, embeddingsCtr, fmt.Errorf("search: %w", err)
 	}
-	log.Printf("Relevant documents for RAG: %d\n", len(relevantDocs))
+	logging.Default().Info("Relevant documents for RAG", "count", len(relevantDocs))
 
 	return ai.NewChat(chatModel, ai.WithRAGContext(relevantDocs)), embeddingsCtr, nil
 }
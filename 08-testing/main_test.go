@@ -9,7 +9,6 @@ import (
 	"github.com/chewxy/math32"
 	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
 	"github.com/testcontainers/testcontainers-go"
-	"github.com/tmc/langchaingo/embeddings"
 )
 
 func Test1_oldSchool(t *testing.T) {
@@ -85,7 +84,7 @@ func Test2_embeddings(t *testing.T) {
 		t.Fatalf("build embedding model: %s", err)
 	}
 
-	embedder, err := embeddings.NewEmbedder(embeddingModel)
+	embedder, err := newEmbedder(embeddingModel)
 	if err != nil {
 		t.Fatalf("new embedder: %s", err)
 	}
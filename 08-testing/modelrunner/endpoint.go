@@ -0,0 +1,53 @@
+package modelrunner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WaitForOpenAIEndpoint waits until the OpenAI-compatible API at endpoint (e.g. the
+// OpenAIEndpoint of the Model Runner container) lists its models with a 200 status,
+// or the timeout elapses. The container can be returned before the API is fully up
+// on slower machines, so the first requests of a client could fail otherwise.
+func WaitForOpenAIEndpoint(ctx context.Context, endpoint string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url := strings.TrimSuffix(endpoint, "/") + "/models"
+
+	var lastErr error
+	for {
+		lastErr = checkEndpoint(ctx, url)
+		if lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for endpoint %s: %w (last error: %w)", endpoint, ctx.Err(), lastErr)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func checkEndpoint(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s returned %s", url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package modelrunner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForOpenAIEndpoint(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/engines/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+
+		// the endpoint is unavailable for the first requests
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer srv.Close()
+
+	t.Run("ready", func(t *testing.T) {
+		if err := WaitForOpenAIEndpoint(context.Background(), srv.URL+"/engines/v1", 5*time.Second); err != nil {
+			t.Fatalf("wait for endpoint: %s", err)
+		}
+
+		if got := calls.Load(); got != 3 {
+			t.Fatalf("expected 3 calls, got %d", got)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := WaitForOpenAIEndpoint(context.Background(), srv.URL+"/missing", 100*time.Millisecond)
+		if err == nil {
+			t.Fatal("expected a timeout error")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package modelrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// probePrompt is the prompt of the probe completion, answered with a single token.
+const probePrompt = "Hi"
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model     string        `json:"model"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// probeCompletion asks the model for a single token through the OpenAI-compatible API exposed at
+// engineURL, e.g. "http://localhost:12434/engines/llama.cpp", to check that it can generate text.
+func probeCompletion(ctx context.Context, engineURL, model string) error {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:     model,
+		Messages:  []chatMessage{{Role: "user", Content: probePrompt}},
+		MaxTokens: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimSuffix(engineURL, "/") + "/v1/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("chat completions returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var completion chatCompletionResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if len(completion.Choices) == 0 {
+		return fmt.Errorf("chat completions returned no choices")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package modelrunner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProbeCompletion(t *testing.T) {
+	var got chatCompletionRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/engines/llama.cpp/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Hello"}}]}`))
+	}))
+	defer srv.Close()
+
+	if err := probeCompletion(context.Background(), srv.URL+"/engines/llama.cpp", "ai/llama3.2:1B-Q4_0"); err != nil {
+		t.Fatalf("probe completion: %s", err)
+	}
+
+	if got.Model != "ai/llama3.2:1B-Q4_0" {
+		t.Fatalf("unexpected model: %q", got.Model)
+	}
+	if got.MaxTokens != 1 {
+		t.Fatalf("expected a single token, got max_tokens=%d", got.MaxTokens)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" || got.Messages[0].Content != probePrompt {
+		t.Fatalf("unexpected messages: %+v", got.Messages)
+	}
+}
+
+func TestProbeCompletion_nonOK(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	err := probeCompletion(context.Background(), srv.URL, "missing")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model not found") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestProbeCompletion_noChoices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer srv.Close()
+
+	if err := probeCompletion(context.Background(), srv.URL, "ai/llama3.2:1B-Q4_0"); err == nil {
+		t.Fatal("expected an error without choices")
+	}
+}
@@ -0,0 +1,78 @@
+package modelrunner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/socat"
+)
+
+const (
+	// DefaultTunnelHost is the host where Docker Desktop exposes the Model Runner API to containers.
+	DefaultTunnelHost = "model-runner.docker.internal"
+	// DefaultTunnelPort is the port where Docker Desktop exposes the Model Runner API to containers.
+	DefaultTunnelPort = 80
+
+	socatImage = "alpine/socat:1.8.0.1"
+)
+
+// Tunnel is a socat container forwarding requests to the Model Runner API,
+// so that it's reachable from the host in any test or example.
+type Tunnel struct {
+	ctr  *socat.Container
+	port int
+}
+
+type tunnelConfig struct {
+	host string
+	port int
+}
+
+// TunnelOption is a functional option for the Tunnel
+type TunnelOption func(*tunnelConfig)
+
+// WithTunnelHost sets the host the tunnel forwards to. Defaults to [DefaultTunnelHost].
+func WithTunnelHost(host string) TunnelOption {
+	return func(c *tunnelConfig) {
+		c.host = host
+	}
+}
+
+// WithTunnelPort sets the port the tunnel forwards to. Defaults to [DefaultTunnelPort].
+func WithTunnelPort(port int) TunnelOption {
+	return func(c *tunnelConfig) {
+		c.port = port
+	}
+}
+
+// NewTunnel starts a socat container tunneling to the Model Runner API.
+// The caller is responsible for closing the tunnel.
+func NewTunnel(ctx context.Context, opts ...TunnelOption) (*Tunnel, error) {
+	cfg := &tunnelConfig{
+		host: DefaultTunnelHost,
+		port: DefaultTunnelPort,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctr, err := socat.Run(ctx, socatImage, socat.WithTarget(socat.NewTarget(cfg.port, cfg.host)))
+	if err != nil {
+		return nil, fmt.Errorf("run socat container: %w", err)
+	}
+
+	return &Tunnel{ctr: ctr, port: cfg.port}, nil
+}
+
+// BaseURL returns the URL of the Model Runner API, as reachable from the host,
+// e.g. "http://localhost:32768".
+func (t *Tunnel) BaseURL() string {
+	return t.ctr.TargetURL(t.port).String()
+}
+
+// Close terminates the tunnel container.
+func (t *Tunnel) Close() error {
+	return testcontainers.TerminateContainer(t.ctr)
+}
@@ -0,0 +1,30 @@
+//go:build integration
+
+package modelrunner
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestNewTunnel(t *testing.T) {
+	tunnel, err := NewTunnel(context.Background())
+	if err != nil {
+		t.Fatalf("new tunnel: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := tunnel.Close(); err != nil {
+			t.Errorf("close tunnel: %s", err)
+		}
+	})
+
+	u, err := url.Parse(tunnel.BaseURL())
+	if err != nil {
+		t.Fatalf("parse base url: %s", err)
+	}
+
+	if u.Port() == "" {
+		t.Fatalf("expected a mapped port in %s", u)
+	}
+}
@@ -0,0 +1,123 @@
+package modelrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultEngine is the inference engine used by the Model Runner to serve models.
+const DefaultEngine = "llama.cpp"
+
+// pollInterval is the time to wait between two readiness checks.
+var pollInterval = 500 * time.Millisecond
+
+type modelList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// WaitForModel waits until the model is listed by the Model Runner available at baseURL
+// (e.g. "http://localhost:12434") and a tiny probe completion succeeds, or the timeout elapses.
+// Pulling a model does not mean it can be loaded right away, so generating text right after
+// a pull can fail with a "model loading" error.
+// It uses the default engine, see [DefaultEngine].
+func WaitForModel(ctx context.Context, baseURL, modelName string, timeout time.Duration) error {
+	return WaitForModelOnEngine(ctx, baseURL, DefaultEngine, modelName, timeout)
+}
+
+// WaitForModelOnEngine is like [WaitForModel], but it targets the given Model Runner engine.
+func WaitForModelOnEngine(ctx context.Context, baseURL, engine, modelName string, timeout time.Duration) error {
+	engineURL, err := EngineURL(baseURL, engine)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		lastErr = checkModel(ctx, engineURL, modelName)
+		if lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for model %s: %w (last error: %w)", modelName, ctx.Err(), lastErr)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// EngineURL returns the URL of the OpenAI-compatible API served by the given engine
+// of the Model Runner available at baseURL, e.g. "http://localhost:12434/engines/llama.cpp".
+func EngineURL(baseURL, engine string) (string, error) {
+	engine = strings.Trim(engine, "/")
+	if engine == "" {
+		return "", fmt.Errorf("engine is required")
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + "/engines/" + engine, nil
+}
+
+// checkModel verifies that the model is listed by the engine, and that it's able to generate text.
+func checkModel(ctx context.Context, engineURL, modelName string) error {
+	listed, err := isModelListed(ctx, engineURL, modelName)
+	if err != nil {
+		return err
+	}
+
+	if !listed {
+		return fmt.Errorf("model %s not listed yet", modelName)
+	}
+
+	if err := probeCompletion(ctx, engineURL, modelName); err != nil {
+		return fmt.Errorf("probe completion: %w", err)
+	}
+
+	return nil
+}
+
+func isModelListed(ctx context.Context, engineURL, modelName string) (bool, error) {
+	url := engineURL + "/v1/models"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("list models returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var models modelList
+	if err := json.Unmarshal(body, &models); err != nil {
+		return false, fmt.Errorf("unmarshal models: %w", err)
+	}
+
+	for _, m := range models.Data {
+		// Hugging Face models are stored lowercased by the Model Runner
+		if strings.EqualFold(m.ID, modelName) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
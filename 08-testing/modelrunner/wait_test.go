@@ -0,0 +1,80 @@
+package modelrunner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEngineURL(t *testing.T) {
+	t.Run("engine", func(t *testing.T) {
+		url, err := EngineURL("http://localhost:12434/", "vllm")
+		if err != nil {
+			t.Fatalf("engine url: %s", err)
+		}
+
+		if url != "http://localhost:12434/engines/vllm" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		url, err := EngineURL("http://localhost:12434", DefaultEngine)
+		if err != nil {
+			t.Fatalf("engine url: %s", err)
+		}
+
+		if url != "http://localhost:12434/engines/llama.cpp" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := EngineURL("http://localhost:12434", ""); err == nil {
+			t.Fatal("expected an error for an empty engine")
+		}
+	})
+}
+
+func TestWaitForModel(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+
+	readyAt := time.Now().Add(200 * time.Millisecond)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/engines/llama.cpp/v1/models":
+			if time.Now().Before(readyAt) {
+				_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"ai/llama3.2:1B-Q4_0"}]}`))
+		case "/engines/llama.cpp/v1/chat/completions":
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Hello"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("ready", func(t *testing.T) {
+		if err := WaitForModel(context.Background(), srv.URL, "ai/llama3.2:1B-Q4_0", 5*time.Second); err != nil {
+			t.Fatalf("wait for model: %s", err)
+		}
+
+		if time.Now().Before(readyAt) {
+			t.Fatal("returned before the model was listed")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := WaitForModel(context.Background(), srv.URL, "ai/missing:latest", 100*time.Millisecond)
+		if err == nil {
+			t.Fatal("expected a timeout error")
+		}
+	})
+}
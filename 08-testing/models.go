@@ -3,9 +3,16 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/modelrunner"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -15,6 +22,14 @@ func buildChatModel() (llm *openai.LLM, dmrCtr *dmr.Container, err error) {
 		return nil, dmrCtr, err
 	}
 
+	if err := waitForEndpoint(dmrCtr); err != nil {
+		return nil, dmrCtr, err
+	}
+
+	if err := waitForModel(dmrCtr, fqModelName); err != nil {
+		return nil, dmrCtr, err
+	}
+
 	opts := []openai.Option{
 		openai.WithBaseURL(dmrCtr.OpenAIEndpoint()),
 		openai.WithModel(fqModelName),
@@ -36,6 +51,10 @@ func buildEmbeddingModel() (llm *openai.LLM, dmrCtr *dmr.Container, err error) {
 		return nil, dmrCtr, err
 	}
 
+	if err := waitForEndpoint(dmrCtr); err != nil {
+		return nil, dmrCtr, err
+	}
+
 	opts := []openai.Option{
 		openai.WithBaseURL(dmrCtr.OpenAIEndpoint()),
 		openai.WithEmbeddingModel(fqEmbeddingsModelName),
@@ -49,3 +68,46 @@ func buildEmbeddingModel() (llm *openai.LLM, dmrCtr *dmr.Container, err error) {
 
 	return llm, dmrCtr, nil
 }
+
+// newEmbedder creates the embedder of the embedding model, caching the vectors of the texts already embedded.
+// The cache is persisted across runs in the directory defined by the GENAI_EMBEDDINGS_CACHE_DIR
+// environment variable, in a subdirectory per embedding model.
+func newEmbedder(embeddingModel *openai.LLM) (embeddings.Embedder, error) {
+	embedder, err := embeddings.NewEmbedder(embeddingModel)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings new: %w", err)
+	}
+
+	var opts []ai.CachingEmbedderOption
+	if dir := os.Getenv("GENAI_EMBEDDINGS_CACHE_DIR"); dir != "" {
+		modelDir := strings.NewReplacer("/", "_", ":", "_").Replace(fqEmbeddingsModelName)
+		opts = append(opts, ai.WithCacheDir(filepath.Join(dir, modelDir)))
+	}
+
+	cached, err := ai.NewCachingEmbedder(embedder, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new caching embedder: %w", err)
+	}
+
+	return cached, nil
+}
+
+// waitForEndpoint waits until the OpenAI-compatible endpoint of the Model Runner container is ready.
+func waitForEndpoint(dmrCtr *dmr.Container) error {
+	if err := modelrunner.WaitForOpenAIEndpoint(context.Background(), dmrCtr.OpenAIEndpoint(), time.Minute); err != nil {
+		return fmt.Errorf("wait for endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// waitForModel waits until the model pulled by the Model Runner container is able to serve requests.
+func waitForModel(dmrCtr *dmr.Container, model string) error {
+	baseURL := strings.TrimSuffix(dmrCtr.OpenAIEndpoint(), "/engines/v1")
+
+	if err := modelrunner.WaitForModel(context.Background(), baseURL, model, 2*time.Minute); err != nil {
+		return fmt.Errorf("wait for model: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package pgvector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/pgvector"
+)
+
+// Store is a PgVector store isolating the documents of each namespace in their own collection.
+// The langchaingo pgvector store only honours [vectorstores.WithNameSpace] when searching,
+// so Store routes both the ingestion and the search to the collection named after the namespace.
+// An empty namespace uses the collection of the store, see [WithCollectionName].
+type Store struct {
+	defaultCollection string
+	newCollection     collectionFactory
+	embedder          embeddings.Embedder
+	distanceMetric    DistanceMetric
+	embeddingTable    string
+
+	mu          sync.Mutex
+	collections map[string]collection
+	closers     []func(ctx context.Context) error
+}
+
+// collection is the langchaingo store of a collection, ingesting its documents, and the
+// connection of the store, searching them, see [similaritySearch]
+type collection struct {
+	store pgvector.Store
+	conn  querier
+	// close releases the connection of the store, if the store owns it, or nil otherwise
+	close func(ctx context.Context) error
+}
+
+// collectionFactory creates the store for a collection
+type collectionFactory func(ctx context.Context, name string) (collection, error)
+
+var _ vectorstores.VectorStore = (*Store)(nil)
+
+func newNamespacedStore(ctx context.Context, o options, embedder embeddings.Embedder, newCollection collectionFactory) (*Store, error) {
+	s := &Store{
+		defaultCollection: o.collectionName,
+		newCollection:     newCollection,
+		embedder:          embedder,
+		distanceMetric:    o.distanceMetric,
+		embeddingTable:    o.embeddingTableName(),
+		collections:       map[string]collection{},
+	}
+
+	// create the default collection eagerly, surfacing the connection errors
+	if _, err := s.collection(ctx, ""); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// AddDocuments adds the documents to the collection of the namespace.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) {
+	opts := applyOptions(options...)
+
+	c, err := s.collection(ctx, opts.NameSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	// the namespace is already resolved to the collection, and pgvector rejects it when adding documents
+	var addOptions []vectorstores.Option
+	if opts.Embedder != nil {
+		addOptions = append(addOptions, vectorstores.WithEmbedder(opts.Embedder))
+	}
+	if opts.Deduplicater != nil {
+		addOptions = append(addOptions, vectorstores.WithDeduplicater(opts.Deduplicater))
+	}
+
+	return c.store.AddDocuments(ctx, docs, addOptions...)
+}
+
+// SimilaritySearch searches for similar documents in the collection of the namespace, ranked by
+// the distance metric of the store, see [WithDistanceMetric].
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) {
+	opts := applyOptions(options...)
+
+	c, err := s.collection(ctx, opts.NameSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	return similaritySearch(ctx, c.conn, s.embedder, s.distanceMetric, s.embeddingTable, s.collectionName(opts.NameSpace), query, numDocuments, opts)
+}
+
+// Close releases the connections owned by the store. The connection pool of the stores
+// created with [NewStoreWithPool] is shared, so it is not closed.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, closer := range s.closers {
+		if err := closer(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.closers = nil
+	s.collections = map[string]collection{}
+
+	return errors.Join(errs...)
+}
+
+// collectionName returns the name of the collection of the namespace.
+func (s *Store) collectionName(namespace string) string {
+	if namespace == "" {
+		return s.defaultCollection
+	}
+
+	return namespace
+}
+
+// collection returns the collection of the namespace, creating it on first use.
+func (s *Store) collection(ctx context.Context, namespace string) (collection, error) {
+	name := s.collectionName(namespace)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.collections[name]; ok {
+		return c, nil
+	}
+
+	c, err := s.newCollection(ctx, name)
+	if err != nil {
+		return collection{}, fmt.Errorf("new collection (%s): %w", name, err)
+	}
+
+	s.collections[name] = c
+	if c.close != nil {
+		s.closers = append(s.closers, c.close)
+	}
+
+	return c, nil
+}
+
+func applyOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return opts
+}
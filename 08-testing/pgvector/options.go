@@ -0,0 +1,136 @@
+package pgvector
+
+import "fmt"
+
+// DistanceMetric is the distance function used to compare the embeddings
+type DistanceMetric string
+
+const (
+	// Cosine is the cosine distance, the default. It doesn't depend on the length of the vectors.
+	Cosine DistanceMetric = "cosine"
+	// L2 is the euclidean distance
+	L2 DistanceMetric = "l2"
+	// InnerProduct is the negative inner product, equivalent to cosine for normalized vectors, but faster
+	InnerProduct DistanceMetric = "ip"
+)
+
+// IndexOpClass returns the operator class of the HNSW index for the metric
+func (m DistanceMetric) IndexOpClass() string {
+	switch m {
+	case L2:
+		return "vector_l2_ops"
+	case InnerProduct:
+		return "vector_ip_ops"
+	default:
+		return "vector_cosine_ops"
+	}
+}
+
+// QueryOperator returns the pgvector operator calculating the distance for the metric.
+// The index is only used by queries ordered by the operator matching its operator class.
+func (m DistanceMetric) QueryOperator() string {
+	switch m {
+	case L2:
+		return "<->"
+	case InnerProduct:
+		return "<#>"
+	default:
+		return "<=>"
+	}
+}
+
+// Score returns the SQL expression of the similarity score of the documents, higher for the
+// closer ones, from the SQL expression of their distance: the cosine similarity for Cosine, the
+// inner product for InnerProduct, as the operator returns it negated, and 1 / (1 + distance) for L2.
+func (m DistanceMetric) Score(distance string) string {
+	switch m {
+	case L2:
+		return "1 / (1 + " + distance + ")"
+	case InnerProduct:
+		return "-" + distance
+	default:
+		return "1 - " + distance
+	}
+}
+
+func (m DistanceMetric) validate() error {
+	switch m {
+	case Cosine, L2, InnerProduct:
+		return nil
+	default:
+		return fmt.Errorf("unsupported distance metric %q: use one of %q, %q or %q", m, Cosine, L2, InnerProduct)
+	}
+}
+
+// HNSW index parameters: the pgvector defaults
+const (
+	hnswM              = 16
+	hnswEfConstruction = 64
+)
+
+// defaultDimension is the dimension of the embeddings of the store, unless [WithDimension] is used
+const defaultDimension = 384
+
+type options struct {
+	distanceMetric DistanceMetric
+	collectionName string
+	dimension      int
+}
+
+// Option is a functional option for the PgVector store
+type Option func(*options)
+
+// WithDistanceMetric sets the distance metric of the store. Defaults to Cosine.
+// Use the metric the embedding model was trained with, as a wrong metric silently degrades the retrieval quality.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(o *options) {
+		o.distanceMetric = metric
+	}
+}
+
+// WithCollectionName sets the collection where the documents are stored. Defaults to "Testcontainers".
+func WithCollectionName(name string) Option {
+	return func(o *options) {
+		o.collectionName = name
+	}
+}
+
+// WithDimension sets the dimension of the embeddings, which must match the embedding model. Defaults to 384.
+// The embedding column has a fixed dimension, so the embeddings of any other dimension are stored in their
+// own table, see [options.embeddingTableName].
+func WithDimension(dimension int) Option {
+	return func(o *options) {
+		o.dimension = dimension
+	}
+}
+
+func newOptions(opts ...Option) (options, error) {
+	o := options{
+		distanceMetric: Cosine,
+		collectionName: "Testcontainers",
+		dimension:      defaultDimension,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := o.distanceMetric.validate(); err != nil {
+		return options{}, err
+	}
+	if o.dimension <= 0 {
+		return options{}, fmt.Errorf("invalid dimension %d: must be positive", o.dimension)
+	}
+
+	return o, nil
+}
+
+// embeddingTableName returns the table of the embeddings of the dimension of the store, so the
+// tables created by the stores of other dimensions in the reused container are left untouched.
+func (o options) embeddingTableName() string {
+	if o.dimension == defaultDimension {
+		return embeddingTableName
+	}
+
+	return fmt.Sprintf("%s_%d", embeddingTableName, o.dimension)
+}
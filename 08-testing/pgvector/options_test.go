@@ -0,0 +1,76 @@
+package pgvector
+
+import "testing"
+
+func TestNewOptions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		o, err := newOptions()
+		if err != nil {
+			t.Fatalf("new options: %s", err)
+		}
+
+		if o.distanceMetric != Cosine {
+			t.Fatalf("expected cosine by default, got %q", o.distanceMetric)
+		}
+		if o.collectionName != "Testcontainers" {
+			t.Fatalf("expected Testcontainers collection by default, got %q", o.collectionName)
+		}
+		if o.dimension != 384 || o.embeddingTableName() != embeddingTableName {
+			t.Fatalf("expected 384 dimensions in the %s table by default, got %d in %s", embeddingTableName, o.dimension, o.embeddingTableName())
+		}
+	})
+
+	t.Run("dimension", func(t *testing.T) {
+		o, err := newOptions(WithDimension(1024))
+		if err != nil {
+			t.Fatalf("new options: %s", err)
+		}
+
+		if expected := embeddingTableName + "_1024"; o.embeddingTableName() != expected {
+			t.Errorf("expected the %s table, got %s", expected, o.embeddingTableName())
+		}
+
+		if _, err := newOptions(WithDimension(0)); err == nil {
+			t.Error("expected an error for a zero dimension")
+		}
+	})
+
+	tests := []struct {
+		metric   DistanceMetric
+		opClass  string
+		operator string
+		score    string
+	}{
+		{metric: Cosine, opClass: "vector_cosine_ops", operator: "<=>", score: "1 - d"},
+		{metric: L2, opClass: "vector_l2_ops", operator: "<->", score: "1 / (1 + d)"},
+		{metric: InnerProduct, opClass: "vector_ip_ops", operator: "<#>", score: "-d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			o, err := newOptions(WithDistanceMetric(tt.metric))
+			if err != nil {
+				t.Fatalf("new options: %s", err)
+			}
+
+			if o.distanceMetric != tt.metric {
+				t.Fatalf("expected metric %q, got %q", tt.metric, o.distanceMetric)
+			}
+			if got := o.distanceMetric.IndexOpClass(); got != tt.opClass {
+				t.Errorf("expected index operator class %q, got %q", tt.opClass, got)
+			}
+			if got := o.distanceMetric.QueryOperator(); got != tt.operator {
+				t.Errorf("expected query operator %q, got %q", tt.operator, got)
+			}
+			if got := o.distanceMetric.Score("d"); got != tt.score {
+				t.Errorf("expected score %q, got %q", tt.score, got)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := newOptions(WithDistanceMetric("manhattan")); err == nil {
+			t.Fatal("expected an error for an unsupported metric")
+		}
+	})
+}
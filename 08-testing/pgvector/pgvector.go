@@ -2,8 +2,10 @@ package pgvector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/testcontainers/testcontainers-go"
 	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/tmc/langchaingo/embeddings"
@@ -11,20 +13,42 @@ import (
 )
 
 // NewStore creates a new PgVector store. It will use a Postgres container with the pgvector module to store the data.
-func NewStore(ctx context.Context, embedder embeddings.Embedder) (pgvector.Store, error) {
+// The embeddings are indexed with an HNSW index for the distance metric of the store, see [WithDistanceMetric].
+// The documents of each namespace are stored in their own collection, see [Store].
+func NewStore(ctx context.Context, embedder embeddings.Embedder, opts ...Option) (*Store, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector options: %w", err)
+	}
+
 	conn, err := mustGetConnection(ctx)
 	if err != nil {
-		return pgvector.Store{}, fmt.Errorf("pgvector container connection: %w", err)
+		return nil, fmt.Errorf("pgvector container connection: %w", err)
 	}
 
-	return pgvector.New(
-		ctx,
-		pgvector.WithConnectionURL(conn),
-		pgvector.WithEmbedder(embedder),
-		pgvector.WithVectorDimensions(384),
-		pgvector.WithCollectionName(`Testcontainers`),
-		pgvector.WithCollectionTableName("tctable"),
-	)
+	return newNamespacedStore(ctx, o, embedder, func(ctx context.Context, name string) (collection, error) {
+		// each collection owns its connection, released by Close
+		pgConn, err := pgx.Connect(ctx, conn)
+		if err != nil {
+			return collection{}, fmt.Errorf("connect: %w", err)
+		}
+
+		store, err := pgvector.New(
+			ctx,
+			pgvector.WithConn(pgConn),
+			pgvector.WithEmbedder(embedder),
+			pgvector.WithVectorDimensions(o.dimension),
+			pgvector.WithHNSWIndex(hnswM, hnswEfConstruction, o.distanceMetric.IndexOpClass()),
+			pgvector.WithCollectionName(name),
+			pgvector.WithCollectionTableName(collectionTableName),
+			pgvector.WithEmbeddingTableName(o.embeddingTableName()),
+		)
+		if err != nil {
+			return collection{}, errors.Join(err, pgConn.Close(ctx))
+		}
+
+		return collection{store: store, conn: pgConn, close: pgConn.Close}, nil
+	})
 }
 
 func mustGetConnection(ctx context.Context) (string, error) {
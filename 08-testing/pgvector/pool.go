@@ -0,0 +1,94 @@
+package pgvector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores/pgvector"
+)
+
+// PoolConfig configures the connection pool shared by the stores using the same Postgres container
+type PoolConfig struct {
+	// MaxConns is the maximum number of open connections. Zero means the pgxpool default.
+	MaxConns int32
+	// MaxConnIdleTime closes the connections idle for longer than this duration. Zero means the pgxpool default.
+	MaxConnIdleTime time.Duration
+}
+
+var (
+	// pools holds the connection pools, by connection string, so they are reused across collections
+	pools   = map[string]*pgxpool.Pool{}
+	poolsMu sync.Mutex
+)
+
+// NewStoreWithPool creates a new PgVector store backed by a connection pool. The pool is shared
+// by all the stores, e.g. one per collection, created against the same Postgres container,
+// improving the throughput of ingestion-heavy workloads.
+func NewStoreWithPool(ctx context.Context, embedder embeddings.Embedder, poolConfig PoolConfig, opts ...Option) (*Store, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector options: %w", err)
+	}
+
+	conn, err := mustGetConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector container connection: %w", err)
+	}
+
+	pool, err := getPool(ctx, conn, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector connection pool: %w", err)
+	}
+
+	return newNamespacedStore(ctx, o, embedder, func(ctx context.Context, name string) (collection, error) {
+		store, err := pgvector.New(
+			ctx,
+			pgvector.WithConn(pool),
+			pgvector.WithEmbedder(embedder),
+			pgvector.WithVectorDimensions(o.dimension),
+			pgvector.WithHNSWIndex(hnswM, hnswEfConstruction, o.distanceMetric.IndexOpClass()),
+			pgvector.WithCollectionName(name),
+			pgvector.WithCollectionTableName(collectionTableName),
+			pgvector.WithEmbeddingTableName(o.embeddingTableName()),
+		)
+
+		// the pool is shared by all the stores, so it is not released by the store
+		return collection{store: store, conn: pool}, err
+	})
+}
+
+// getPool returns the pool for the connection string, creating it on first use.
+// The configuration of the first call wins for the lifetime of the pool.
+func getPool(ctx context.Context, conn string, poolConfig PoolConfig) (*pgxpool.Pool, error) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if pool, ok := pools[conn]; ok {
+		return pool, nil
+	}
+
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return nil, fmt.Errorf("parse pool config: %w", err)
+	}
+
+	if poolConfig.MaxConns > 0 {
+		cfg.MaxConns = poolConfig.MaxConns
+	}
+	if poolConfig.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("new pool: %w", err)
+	}
+
+	pools[conn] = pool
+
+	return pool, nil
+}
@@ -0,0 +1,112 @@
+package pgvector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	pgv "github.com/pgvector/pgvector-go"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/pgvector"
+)
+
+// Tables of the documents, shared by the collections of all the stores, see [options.embeddingTableName]
+const (
+	collectionTableName = "tctable"
+	embeddingTableName  = pgvector.DefaultEmbeddingStoreTableName
+)
+
+// querier runs the similarity search queries: a connection, or a pool of them
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// similaritySearch returns the documents of the collection closest to the query, from the table of
+// the embeddings, with the distance metric of the store. The langchaingo pgvector store always ranks the documents by cosine distance,
+// so the search runs its own query, ordered by the operator of the metric, see [DistanceMetric.QueryOperator],
+// which is the one the HNSW index of the collection was built for.
+func similaritySearch(ctx context.Context, conn querier, embedder embeddings.Embedder, metric DistanceMetric, table, collection, query string, numDocuments int, opts vectorstores.Options) ([]schema.Document, error) {
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+
+	filters, err := searchFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	embedding, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	sql, args := searchQuery(table, metric, filters, opts.ScoreThreshold)
+	args = append([]any{pgv.NewVector(embedding), collection, numDocuments}, args...)
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("similarity search: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, numDocuments)
+	for rows.Next() {
+		var doc schema.Document
+		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &doc.Score); err != nil {
+			return nil, fmt.Errorf("scan document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+// searchFilters returns the metadata filters of the search, which must be a map, as in langchaingo
+func searchFilters(opts vectorstores.Options) (map[string]any, error) {
+	if opts.Filters == nil {
+		return nil, nil
+	}
+
+	filters, ok := opts.Filters.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid filters: must be a map[string]any")
+	}
+
+	return filters, nil
+}
+
+// searchQuery returns the similarity search query of the embeddings table for the metric, with the arguments of the filters
+// and the score threshold. The query takes the embedding of the query, the collection name and the
+// number of documents as its first three arguments. The documents are ordered by the distance
+// operator alone, so the HNSW index is used, and scored by the similarity of the metric, see
+// [DistanceMetric.Score].
+func searchQuery(table string, metric DistanceMetric, filters map[string]any, scoreThreshold float32) (string, []any) {
+	distance := "(e.embedding " + metric.QueryOperator() + " $1)"
+	score := metric.Score(distance)
+
+	var args []any
+	where := []string{"c.name = $2"}
+	for _, key := range slices.Sorted(maps.Keys(filters)) {
+		args = append(args, key, fmt.Sprint(filters[key]))
+		where = append(where, fmt.Sprintf("(e.cmetadata ->> $%d) = $%d", len(args)+2, len(args)+3))
+	}
+	if scoreThreshold != 0 {
+		args = append(args, scoreThreshold)
+		where = append(where, fmt.Sprintf("%s >= $%d", score, len(args)+3))
+	}
+
+	sql := fmt.Sprintf(`SELECT e.document, e.cmetadata, %s AS score
+FROM %s e
+JOIN %s c ON e.collection_id = c.uuid
+WHERE %s
+ORDER BY %s
+LIMIT $3`, score, table, collectionTableName, strings.Join(where, " AND "), distance)
+
+	return sql, args
+}
@@ -0,0 +1,69 @@
+package pgvector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestSearchQuery(t *testing.T) {
+	for _, metric := range []DistanceMetric{Cosine, L2, InnerProduct} {
+		t.Run(string(metric), func(t *testing.T) {
+			sql, args := searchQuery(embeddingTableName, metric, nil, 0)
+			if len(args) != 0 {
+				t.Fatalf("expected no extra arguments, got %v", args)
+			}
+
+			orderBy := "ORDER BY (e.embedding " + metric.QueryOperator() + " $1)"
+			if !strings.Contains(sql, orderBy) {
+				t.Errorf("expected the query to be ordered by the %s operator, got:\n%s", metric, sql)
+			}
+			if !strings.Contains(sql, metric.Score("(e.embedding "+metric.QueryOperator()+" $1)")+" AS score") {
+				t.Errorf("expected the query to be scored by the %s similarity, got:\n%s", metric, sql)
+			}
+		})
+	}
+
+	t.Run("filters-and-threshold", func(t *testing.T) {
+		sql, args := searchQuery("langchain_pg_embedding_1024", L2, map[string]any{"type": "doc", "lang": "go"}, 0.5)
+
+		for _, clause := range []string{
+			"FROM langchain_pg_embedding_1024 e",
+			"c.name = $2",
+			"(e.cmetadata ->> $4) = $5",
+			"(e.cmetadata ->> $6) = $7",
+			"1 / (1 + (e.embedding <-> $1)) >= $8",
+			"LIMIT $3",
+		} {
+			if !strings.Contains(sql, clause) {
+				t.Errorf("expected the query to contain %q, got:\n%s", clause, sql)
+			}
+		}
+
+		// the filters are sorted by key, so the query is stable
+		expected := []any{"lang", "go", "type", "doc", float32(0.5)}
+		if len(args) != len(expected) {
+			t.Fatalf("expected arguments %v, got %v", expected, args)
+		}
+		for i := range expected {
+			if args[i] != expected[i] {
+				t.Errorf("expected argument %d to be %v, got %v", i, expected[i], args[i])
+			}
+		}
+	})
+}
+
+func TestSearchFilters(t *testing.T) {
+	if _, err := searchFilters(vectorstores.Options{Filters: "type = doc"}); err == nil {
+		t.Fatal("expected an error for filters other than a map")
+	}
+
+	filters, err := searchFilters(vectorstores.Options{Filters: map[string]any{"type": "doc"}})
+	if err != nil {
+		t.Fatalf("search filters: %s", err)
+	}
+	if filters["type"] != "doc" {
+		t.Fatalf("expected the type filter, got %v", filters)
+	}
+}
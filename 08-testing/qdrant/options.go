@@ -0,0 +1,81 @@
+package qdrant
+
+import "fmt"
+
+// Distance is the distance function used by the collection to compare the embeddings
+type Distance string
+
+const (
+	// Cosine is the cosine distance, the default.
+	Cosine Distance = "Cosine"
+	// Euclid is the euclidean distance
+	Euclid Distance = "Euclid"
+	// Dot is the dot product, equivalent to cosine for normalized vectors
+	Dot Distance = "Dot"
+)
+
+func (d Distance) validate() error {
+	switch d {
+	case Cosine, Euclid, Dot:
+		return nil
+	default:
+		return fmt.Errorf("unsupported distance %q: use one of %q, %q or %q", d, Cosine, Euclid, Dot)
+	}
+}
+
+type options struct {
+	collectionName string
+	dimension      int
+	distance       Distance
+}
+
+// Option is a functional option for the Qdrant store
+type Option func(*options)
+
+// WithCollectionName sets the collection where the documents are stored. Defaults to "Testcontainers".
+func WithCollectionName(name string) Option {
+	return func(o *options) {
+		o.collectionName = name
+	}
+}
+
+// WithDimension sets the dimension of the vectors of the collection, which must match the embedding model.
+// Defaults to 1024, the dimension of the mxbai-embed-large model.
+func WithDimension(dimension int) Option {
+	return func(o *options) {
+		o.dimension = dimension
+	}
+}
+
+// WithDistance sets the distance function of the collection. Defaults to Cosine.
+func WithDistance(distance Distance) Option {
+	return func(o *options) {
+		o.distance = distance
+	}
+}
+
+func newOptions(opts ...Option) (options, error) {
+	o := options{
+		collectionName: "Testcontainers",
+		dimension:      1024,
+		distance:       Cosine,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.collectionName == "" {
+		return options{}, fmt.Errorf("empty collection name")
+	}
+
+	if o.dimension <= 0 {
+		return options{}, fmt.Errorf("invalid dimension %d: must be positive", o.dimension)
+	}
+
+	if err := o.distance.validate(); err != nil {
+		return options{}, err
+	}
+
+	return o, nil
+}
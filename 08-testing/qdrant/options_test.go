@@ -0,0 +1,49 @@
+package qdrant
+
+import "testing"
+
+func TestNewOptions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		o, err := newOptions()
+		if err != nil {
+			t.Fatalf("new options: %s", err)
+		}
+
+		if o.collectionName != "Testcontainers" {
+			t.Fatalf("expected Testcontainers collection by default, got %q", o.collectionName)
+		}
+		if o.dimension != 1024 {
+			t.Fatalf("expected 1024 dimensions by default, got %d", o.dimension)
+		}
+		if o.distance != Cosine {
+			t.Fatalf("expected cosine by default, got %q", o.distance)
+		}
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		o, err := newOptions(WithCollectionName("docs"), WithDimension(384), WithDistance(Dot))
+		if err != nil {
+			t.Fatalf("new options: %s", err)
+		}
+
+		if o.collectionName != "docs" || o.dimension != 384 || o.distance != Dot {
+			t.Fatalf("unexpected options: %+v", o)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := map[string]Option{
+			"empty-collection": WithCollectionName(""),
+			"zero-dimension":   WithDimension(0),
+			"unknown-distance": WithDistance("Manhattan"),
+		}
+
+		for name, opt := range invalid {
+			t.Run(name, func(t *testing.T) {
+				if _, err := newOptions(opt); err == nil {
+					t.Fatal("expected an error")
+				}
+			})
+		}
+	})
+}
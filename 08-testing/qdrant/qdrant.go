@@ -0,0 +1,110 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcqdrant "github.com/testcontainers/testcontainers-go/modules/qdrant"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores/qdrant"
+)
+
+// NewStore creates a new Qdrant store. It will use a qdrant container to store the data.
+// The collection is created with the configured dimension and distance if it does not exist,
+// see [WithDimension] and [WithDistance].
+func NewStore(ctx context.Context, embedder embeddings.Embedder, opts ...Option) (qdrant.Store, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return qdrant.Store{}, fmt.Errorf("qdrant options: %w", err)
+	}
+
+	endpoint, err := mustGetEndpoint(ctx)
+	if err != nil {
+		return qdrant.Store{}, fmt.Errorf("run qdrant: %w", err)
+	}
+
+	qdrantURL, err := url.Parse(endpoint)
+	if err != nil {
+		return qdrant.Store{}, fmt.Errorf("parse qdrant endpoint: %w", err)
+	}
+
+	if err := createCollection(ctx, qdrantURL, o); err != nil {
+		return qdrant.Store{}, fmt.Errorf("create collection: %w", err)
+	}
+
+	return qdrant.New(
+		qdrant.WithURL(*qdrantURL),
+		qdrant.WithCollectionName(o.collectionName),
+		qdrant.WithEmbedder(embedder),
+	)
+}
+
+// createCollection creates the collection of the store, as the langchaingo store expects it to exist.
+// An existing collection is reused, as the container is reused across runs.
+func createCollection(ctx context.Context, qdrantURL *url.URL, o options) error {
+	collectionURL := qdrantURL.JoinPath("collections", o.collectionName).String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, collectionURL, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get collection: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"vectors": map[string]any{
+			"size":     o.dimension,
+			"distance": o.distance,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal collection: %w", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPut, collectionURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("put collection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("put collection returned %s: %s", resp.Status, msg)
+	}
+
+	return nil
+}
+
+func mustGetEndpoint(ctx context.Context) (string, error) {
+	c, err := tcqdrant.Run(ctx, "qdrant/qdrant:v1.13.4", testcontainers.WithReuseByName("qdrant-db"))
+	if err != nil {
+		return "", fmt.Errorf("run container: %w", err)
+	}
+
+	endpoint, err := c.RESTEndpoint(ctx)
+	if err != nil {
+		return "", fmt.Errorf("qdrant container endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
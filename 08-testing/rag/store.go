@@ -0,0 +1,39 @@
+package rag
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// Store is the vector store used by the examples, so they do not depend on the concrete backend.
+type Store interface {
+	// AddDocuments embeds and stores the documents, returning their IDs.
+	AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error)
+	// SimilaritySearch returns up to numDocuments documents, the most similar to the query first.
+	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error)
+	// Close releases the resources of the store, like its connections. The backend containers are not terminated.
+	Close() error
+}
+
+// closerStore adapts the vector stores releasing their resources with a Close method
+type closerStore struct {
+	vectorstores.VectorStore
+	closeFn func() error
+}
+
+func (s closerStore) Close() error {
+	return s.closeFn()
+}
+
+// NewStore adapts a vector store to a Store, releasing its resources with the closeFn function.
+func NewStore(store vectorstores.VectorStore, closeFn func() error) Store {
+	return closerStore{VectorStore: store, closeFn: closeFn}
+}
+
+// NopCloser adapts a vector store without resources to release, like the stores talking
+// to their backend over HTTP, to a Store with a no-op Close.
+func NopCloser(store vectorstores.VectorStore) Store {
+	return NewStore(store, func() error { return nil })
+}
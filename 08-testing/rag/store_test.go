@@ -0,0 +1,106 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeVectorStore keeps the documents in memory, scoring them by the words shared with the query
+type fakeVectorStore struct {
+	docs []schema.Document
+}
+
+func (s *fakeVectorStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = fmt.Sprintf("doc-%d", len(s.docs))
+		s.docs = append(s.docs, doc)
+	}
+	return ids, nil
+}
+
+func (s *fakeVectorStore) SimilaritySearch(_ context.Context, query string, numDocuments int, _ ...vectorstores.Option) ([]schema.Document, error) {
+	scored := make([]schema.Document, 0, len(s.docs))
+	for _, doc := range s.docs {
+		var shared int
+		for _, word := range strings.Fields(query) {
+			if strings.Contains(doc.PageContent, word) {
+				shared++
+			}
+		}
+		doc.Score = float32(shared)
+		scored = append(scored, doc)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+
+	if len(scored) > numDocuments {
+		scored = scored[:numDocuments]
+	}
+	return scored, nil
+}
+
+// testStoreContract verifies the behaviour the examples rely on, for any Store
+func testStoreContract(t *testing.T, store Store) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Testcontainers Desktop helps with local development"},
+		{PageContent: "Set cloud.logs.verbose to enable verbose logging"},
+		{PageContent: "Postgres runs in a container"},
+	})
+	if err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected an ID per document, got %d", len(ids))
+	}
+
+	docs, err := store.SimilaritySearch(ctx, "verbose logging", 2)
+	if err != nil {
+		t.Fatalf("similarity search: %s", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected up to 2 documents, got %d", len(docs))
+	}
+	if !strings.Contains(docs[0].PageContent, "verbose") {
+		t.Errorf("expected the most similar document first, got %q", docs[0].PageContent)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Errorf("close: %s", err)
+	}
+}
+
+func TestNopCloser(t *testing.T) {
+	testStoreContract(t, NopCloser(&fakeVectorStore{}))
+}
+
+func TestNewStore(t *testing.T) {
+	var closed int
+	store := NewStore(&fakeVectorStore{}, func() error {
+		closed++
+		return nil
+	})
+
+	testStoreContract(t, store)
+
+	if closed != 1 {
+		t.Fatalf("expected the close function to be called once, got %d", closed)
+	}
+
+	errClose := errors.New("close failed")
+	store = NewStore(&fakeVectorStore{}, func() error { return errClose })
+	if err := store.Close(); !errors.Is(err, errClose) {
+		t.Fatalf("expected the close error, got %v", err)
+	}
+}
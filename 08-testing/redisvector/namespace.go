@@ -0,0 +1,138 @@
+package redisvector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/redisvector"
+)
+
+// defaultNamespace is the namespace of the documents added without one
+const defaultNamespace = "default"
+
+// Store is a Redis store isolating the documents of each namespace in their own index.
+// The langchaingo redisvector store ignores [vectorstores.WithNameSpace], so Store routes both
+// the ingestion and the search to the index of the namespace, see [indexName].
+// The score of the documents is the cosine similarity.
+type Store struct {
+	indexName string
+	newIndex  indexFactory
+
+	mu      sync.Mutex
+	indexes map[string]*redisvector.Store
+}
+
+// indexFactory creates the store for an index
+type indexFactory func(ctx context.Context, name string) (*redisvector.Store, error)
+
+var _ vectorstores.VectorStore = (*Store)(nil)
+
+// AddDocuments embeds and stores the documents in the index of the namespace of the options, if any.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) {
+	// the langchaingo store creates the index from the first document
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	opts := applyOptions(options...)
+
+	index, err := s.index(ctx, opts.NameSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	// the langchaingo store adds the content and the vector to the metadata, so it is copied
+	copied := make([]schema.Document, len(docs))
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata))
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		doc.Metadata = metadata
+		copied[i] = doc
+	}
+
+	return index.AddDocuments(ctx, copied)
+}
+
+// SimilaritySearch returns the documents closest to the query in the index of the namespace of the
+// options, if any.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) {
+	opts := applyOptions(options...)
+
+	index, err := s.index(ctx, opts.NameSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := index.SimilaritySearch(ctx, query, numDocuments, options...)
+	if isUnknownIndex(err) {
+		// nothing was ingested in the namespace yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the langchaingo store scores the documents with the cosine distance
+	for i := range docs {
+		docs[i].Score = 1 - docs[i].Score
+	}
+
+	return docs, nil
+}
+
+// index returns the store for the index of the namespace, creating it on first use.
+func (s *Store) index(ctx context.Context, namespace string) (*redisvector.Store, error) {
+	name := indexName(s.indexName, namespace)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index, ok := s.indexes[name]; ok {
+		return index, nil
+	}
+
+	index, err := s.newIndex(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("new index (%s): %w", name, err)
+	}
+	s.indexes[name] = index
+
+	return index, nil
+}
+
+// indexName returns the name of the index of the namespace. The documents of an index are the keys
+// prefixed with its name, so the escaped namespace is terminated by a colon, which it can't contain:
+// no index name is a prefix of another one.
+func indexName(base, namespace string) string {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	return base + ":" + url.QueryEscape(namespace) + ":"
+}
+
+// isUnknownIndex reports whether the error is the one of a search in an index that does not exist
+func isUnknownIndex(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such index") || strings.Contains(msg, "unknown index")
+}
+
+func applyOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return opts
+}
@@ -0,0 +1,30 @@
+package redisvector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := map[string]string{
+		"":           "testcontainers:default:",
+		"tenanta":    "testcontainers:tenanta:",
+		"tenant:a/1": "testcontainers:tenant%3Aa%2F1:",
+	}
+
+	for namespace, expected := range tests {
+		if got := indexName("testcontainers", namespace); got != expected {
+			t.Errorf("indexName(%q): expected %q, got %q", namespace, expected, got)
+		}
+	}
+
+	// the documents of an index are the keys prefixed with its name
+	names := []string{indexName("testcontainers", "a"), indexName("testcontainers", "ab"), indexName("testcontainers", "a:b")}
+	for _, name := range names {
+		for _, other := range names {
+			if name != other && strings.HasPrefix(other, name) {
+				t.Errorf("the index %q would hold the documents of %q", name, other)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package redisvector
+
+import "fmt"
+
+type options struct {
+	indexName string
+}
+
+// Option is a functional option for the Redis store
+type Option func(*options)
+
+// WithIndexName sets the name of the search index, created on the first ingestion if it does not exist.
+// Defaults to "testcontainers".
+func WithIndexName(name string) Option {
+	return func(o *options) {
+		o.indexName = name
+	}
+}
+
+func newOptions(opts ...Option) (options, error) {
+	o := options{
+		indexName: "testcontainers",
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.indexName == "" {
+		return options{}, fmt.Errorf("empty index name")
+	}
+
+	return o, nil
+}
@@ -0,0 +1,25 @@
+package redisvector
+
+import "testing"
+
+func TestNewOptions(t *testing.T) {
+	o, err := newOptions()
+	if err != nil {
+		t.Fatalf("new options: %s", err)
+	}
+	if o.indexName != "testcontainers" {
+		t.Fatalf("unexpected default options: %+v", o)
+	}
+
+	o, err = newOptions(WithIndexName("docs"))
+	if err != nil {
+		t.Fatalf("new options: %s", err)
+	}
+	if o.indexName != "docs" {
+		t.Fatalf("expected the docs index, got %q", o.indexName)
+	}
+
+	if _, err := newOptions(WithIndexName("")); err == nil {
+		t.Error("expected an error for an empty index name")
+	}
+}
@@ -0,0 +1,53 @@
+package redisvector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores/redisvector"
+)
+
+// NewStore creates a new Redis store. It will use a redis-stack container to store the data.
+// The vector index of each namespace is created by its first ingestion if it does not exist,
+// with the dimension of the embeddings of the documents.
+func NewStore(ctx context.Context, embedder embeddings.Embedder, opts ...Option) (*Store, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("redis options: %w", err)
+	}
+
+	conn, err := mustGetConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("redis container connection: %w", err)
+	}
+
+	return &Store{
+		indexName: o.indexName,
+		newIndex: func(ctx context.Context, name string) (*redisvector.Store, error) {
+			return redisvector.New(
+				ctx,
+				redisvector.WithConnectionURL(conn),
+				redisvector.WithIndexName(name, true),
+				redisvector.WithEmbedder(embedder),
+			)
+		},
+		indexes: map[string]*redisvector.Store{},
+	}, nil
+}
+
+func mustGetConnection(ctx context.Context) (string, error) {
+	c, err := tcredis.Run(ctx, "redis/redis-stack-server:7.4.0-v3", testcontainers.WithReuseByName("redis-db"))
+	if err != nil {
+		return "", fmt.Errorf("run redis container: %w", err)
+	}
+
+	conn, err := c.ConnectionString(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get redis container connection string: %w", err)
+	}
+
+	return conn, nil
+}
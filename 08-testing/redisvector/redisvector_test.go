@@ -0,0 +1,86 @@
+//go:build integration
+
+package redisvector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// keywordEmbedder returns a vector per topic, so the test controls the vector similarity
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) vector(text string) []float32 {
+	switch {
+	case strings.Contains(text, "verbose"):
+		return []float32{0, 1, 0}
+	case strings.Contains(text, "Postgres"):
+		return []float32{0, 0, 1}
+	default:
+		return []float32{1, 0, 0}
+	}
+}
+
+func (e keywordEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e keywordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vector(text), nil
+}
+
+func TestNewStore(t *testing.T) {
+	ctx := context.Background()
+
+	// a unique index, as the container is reused across runs
+	index := fmt.Sprintf("redistest%d", time.Now().UnixNano())
+
+	store, err := NewStore(ctx, keywordEmbedder{}, WithIndexName(index))
+	if err != nil {
+		t.Fatalf("new store: %s", err)
+	}
+
+	// the search returns the metadata fields of the first document, so all of them have a source
+	verboseDoc := "Set cloud.logs.verbose to true to enable verbose logging."
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Testcontainers Desktop helps with local development.", Metadata: map[string]any{"source": "desktop.txt"}},
+		{PageContent: "The Postgres module runs a database.", Metadata: map[string]any{"source": "postgres.txt"}},
+		{PageContent: verboseDoc, Metadata: map[string]any{"source": "tcd.txt"}},
+	})
+	if err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+
+	docs, err := store.SimilaritySearch(ctx, "How do I get verbose logs?", 1)
+	if err != nil {
+		t.Fatalf("similarity search: %s", err)
+	}
+
+	if len(docs) != 1 || docs[0].PageContent != verboseDoc {
+		t.Fatalf("expected the verbose logging document, got %v", docs)
+	}
+	if docs[0].Metadata["source"] != "tcd.txt" {
+		t.Errorf("expected the metadata to be stored, got %v", docs[0].Metadata)
+	}
+	if docs[0].Score < 0.99 {
+		t.Errorf("expected a cosine similarity of 1, got %f", docs[0].Score)
+	}
+
+	docs, err = store.SimilaritySearch(ctx, "How do I get verbose logs?", 1, vectorstores.WithNameSpace("other"))
+	if err != nil {
+		t.Fatalf("similarity search: %s", err)
+	}
+	if len(docs) != 0 {
+		t.Errorf("expected no documents in another namespace, got %v", docs)
+	}
+}
@@ -0,0 +1,67 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// testcontainersLabel is the label added by Testcontainers to the containers it creates
+const testcontainersLabel = "org.testcontainers=true"
+
+// CleanupReused terminates the containers created with [testcontainers.WithReuseByName]
+// for the given names. It is the counterpart to reuse: reused containers are intentionally
+// kept alive across runs, and are not removed by Ryuk, so call it when done with the examples
+// to avoid accumulating containers. Only the containers created by Testcontainers are
+// terminated, and the names without a container are ignored.
+func CleanupReused(ctx context.Context, names ...string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("docker client: %w", err)
+	}
+	defer cli.Close()
+
+	var errs []error
+	for _, name := range names {
+		ids, err := findReused(ctx, cli, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("find %s: %w", name, err))
+			continue
+		}
+
+		for _, id := range ids {
+			err := cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("remove %s: %w", name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// findReused returns the IDs of the Testcontainers containers with the given name, running or not.
+func findReused(ctx context.Context, cli *testcontainers.DockerClient, name string) ([]string, error) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			// the name filter matches substrings, so anchor it to the full name
+			filters.Arg("name", "^/"+name+"$"),
+			filters.Arg("label", testcontainersLabel),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(containers))
+	for i, c := range containers {
+		ids[i] = c.ID
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package reuse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestCleanupReused(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("reuse-cleanup-%d", time.Now().UnixNano())
+
+	ctr, err := testcontainers.Run(ctx, "alpine:3.21",
+		testcontainers.WithCmd("sleep", "infinity"),
+		testcontainers.WithReuseByName(name),
+	)
+	// safety net, in case the cleanup does not work
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatalf("run container: %s", err)
+	}
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		t.Fatalf("docker client: %s", err)
+	}
+	defer cli.Close()
+
+	ids, err := findReused(ctx, cli, name)
+	if err != nil {
+		t.Fatalf("find reused: %s", err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("expected the reused container to be found, got %d containers", len(ids))
+	}
+
+	// unknown names are ignored
+	if err := CleanupReused(ctx, name, name+"-unknown"); err != nil {
+		t.Fatalf("cleanup reused: %s", err)
+	}
+
+	ids, err = findReused(ctx, cli, name)
+	if err != nil {
+		t.Fatalf("find reused: %s", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected the reused container to be removed, got %d containers", len(ids))
+	}
+}
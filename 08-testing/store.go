@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
-	"log"
 	"os"
 	"strings"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/chroma"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/mdelapenya/genai-testcontainers-go/testing/pgvector"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/qdrant"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/rag"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/redisvector"
 	"github.com/mdelapenya/genai-testcontainers-go/testing/weaviate"
 	"github.com/tmc/langchaingo/documentloaders"
 	"github.com/tmc/langchaingo/embeddings"
@@ -17,8 +21,11 @@ import (
 	"github.com/tmc/langchaingo/vectorstores"
 )
 
-func ingestion(store vectorstores.VectorStore) error {
+// ingestion adds the knowledge documents to the store, under the given namespace.
+// An empty namespace uses the shared namespace of the store.
+func ingestion(store rag.Store, namespace string) error {
 	var docs []schema.Document
+	deduplicater := newContentDeduplicater()
 
 	err := fs.WalkDir(knowledge, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -29,7 +36,7 @@ func ingestion(store vectorstores.VectorStore) error {
 			return nil
 		}
 
-		log.Printf("Ingesting document: %s\n", path)
+		logging.Default().Info("Ingesting document", "path", path)
 
 		file, err := os.Open(path)
 		if err != nil {
@@ -48,7 +55,7 @@ func ingestion(store vectorstores.VectorStore) error {
 			return fmt.Errorf("load document (%s): %w", path, err)
 		}
 
-		docs = append(docs, fileDocs...)
+		docs = append(docs, deduplicater.Filter(fileDocs)...)
 
 		return nil
 	})
@@ -56,23 +63,74 @@ func ingestion(store vectorstores.VectorStore) error {
 		return fmt.Errorf("walk dir: %w", err)
 	}
 
-	_, err = store.AddDocuments(context.Background(), docs)
+	_, err = store.AddDocuments(context.Background(), docs, withNamespace(namespace)...)
 	if err != nil {
 		return fmt.Errorf("add documents: %w", err)
 	}
 
-	log.Printf("Ingested %d documents\n", len(docs))
+	logging.Default().Info("Ingested documents", "count", len(docs))
 
 	return nil
 }
 
-func selectStore(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+// search returns the documents most similar to the query, only looking at the documents
+// ingested under the given namespace. An empty namespace uses the shared namespace of the store.
+func search(ctx context.Context, store rag.Store, namespace string, query string, maxResults int, opts ...vectorstores.Option) ([]schema.Document, error) {
+	opts = append(opts, withNamespace(namespace)...)
+
+	docs, err := store.SimilaritySearch(ctx, query, maxResults, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("similarity search (namespace=%q): %w", namespace, err)
+	}
+
+	return docs, nil
+}
+
+// withNamespace returns the store options to isolate the documents in the namespace,
+// if any, so the stores keep their default namespace otherwise.
+func withNamespace(namespace string) []vectorstores.Option {
+	if namespace == "" {
+		return nil
+	}
+
+	return []vectorstores.Option{vectorstores.WithNameSpace(namespace)}
+}
+
+// selectStore returns the store for the VECTOR_STORE environment variable, Weaviate by default.
+// Callers must close the store when done.
+func selectStore(ctx context.Context, embedder embeddings.Embedder) (rag.Store, error) {
 	storeTypeEnv := os.Getenv("VECTOR_STORE")
 
 	switch storeTypeEnv {
+	case "chroma":
+		store, err := chroma.NewStore(ctx, embedder)
+		if err != nil {
+			return nil, err
+		}
+		return rag.NopCloser(store), nil
 	case "pgvector":
-		return pgvector.NewStore(ctx, embedder)
+		store, err := pgvector.NewStore(ctx, embedder)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "qdrant":
+		store, err := qdrant.NewStore(ctx, embedder)
+		if err != nil {
+			return nil, err
+		}
+		return rag.NopCloser(store), nil
+	case "redis":
+		store, err := redisvector.NewStore(ctx, embedder)
+		if err != nil {
+			return nil, err
+		}
+		return rag.NopCloser(store), nil
 	default:
-		return weaviate.NewStore(ctx, embedder)
+		store, err := weaviate.NewStore(ctx, embedder)
+		if err != nil {
+			return nil, err
+		}
+		return rag.NopCloser(store), nil
 	}
 }
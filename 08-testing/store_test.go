@@ -0,0 +1,125 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chewxy/math32"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// bagOfWordsEmbedder is a deterministic embedder hashing the words of the text into a vector,
+// so the stores can be tested without running an embeddings model.
+type bagOfWordsEmbedder struct {
+	dim int
+}
+
+func (e bagOfWordsEmbedder) vector(text string) []float32 {
+	v := make([]float32, e.dim)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(word))
+		v[h.Sum32()%uint32(e.dim)]++
+	}
+
+	var norm float32
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math32.Sqrt(norm)
+	if norm == 0 {
+		v[0] = 1
+		return v
+	}
+
+	for i := range v {
+		v[i] /= norm
+	}
+
+	return v
+}
+
+func (e bagOfWordsEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e bagOfWordsEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vector(text), nil
+}
+
+func TestNamespaceIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	testIsolation := func(t *testing.T, embedder bagOfWordsEmbedder) {
+		t.Helper()
+
+		store, err := selectStore(ctx, embedder)
+		if err != nil {
+			t.Fatalf("new store: %s", err)
+		}
+		t.Cleanup(func() {
+			if err := store.Close(); err != nil {
+				t.Errorf("close store: %s", err)
+			}
+		})
+
+		// unique namespaces, as the store containers are reused across runs
+		suffix := time.Now().UnixNano()
+		tenantA := fmt.Sprintf("tenanta%d", suffix)
+		tenantB := fmt.Sprintf("tenantb%d", suffix)
+
+		if err := ingestion(store, tenantA); err != nil {
+			t.Fatalf("ingestion (%s): %s", tenantA, err)
+		}
+
+		tenantBDoc := schema.Document{PageContent: "The verbose logs of tenant B are private"}
+		if _, err := store.AddDocuments(ctx, []schema.Document{tenantBDoc}, withNamespace(tenantB)...); err != nil {
+			t.Fatalf("add documents (%s): %s", tenantB, err)
+		}
+
+		query := "cloud.logs.verbose"
+
+		docsA, err := search(ctx, store, tenantA, query, 3)
+		if err != nil {
+			t.Fatalf("search (%s): %s", tenantA, err)
+		}
+		if len(docsA) == 0 {
+			t.Fatalf("expected documents for %s", tenantA)
+		}
+		for _, doc := range docsA {
+			if doc.PageContent == tenantBDoc.PageContent {
+				t.Errorf("search in %s returned a document of %s", tenantA, tenantB)
+			}
+		}
+
+		docsB, err := search(ctx, store, tenantB, query, 3)
+		if err != nil {
+			t.Fatalf("search (%s): %s", tenantB, err)
+		}
+		if len(docsB) != 1 || docsB[0].PageContent != tenantBDoc.PageContent {
+			t.Errorf("expected only the document of %s, got %d documents: %v", tenantB, len(docsB), docsB)
+		}
+	}
+
+	t.Run("pgvector", func(t *testing.T) {
+		t.Setenv("VECTOR_STORE", "pgvector")
+
+		// the pgvector table is created with 384 dimensions
+		testIsolation(t, bagOfWordsEmbedder{dim: 384})
+	})
+
+	t.Run("weaviate", func(t *testing.T) {
+		// the weaviate index could hold the 1024 dimensions vectors of the embeddings model from previous runs
+		testIsolation(t, bagOfWordsEmbedder{dim: 1024})
+	})
+}
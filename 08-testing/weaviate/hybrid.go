@@ -0,0 +1,133 @@
+package weaviate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	wvt "github.com/weaviate/weaviate-go-client/v5/weaviate"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
+)
+
+const (
+	// indexName is the Weaviate class where the documents are stored
+	indexName = "Testcontainers"
+	// textKey is the property where langchaingo stores the page content of the documents
+	textKey = "text"
+)
+
+// HybridSearcher runs hybrid queries, combining BM25 keyword scores and vector similarity,
+// against the documents stored by the Weaviate store.
+type HybridSearcher struct {
+	client    *wvt.Client
+	embedder  embeddings.Embedder
+	indexName string
+}
+
+// NewHybridSearcher creates a new HybridSearcher for the index of the Weaviate store.
+// It will use the same weaviate container as the store.
+func NewHybridSearcher(ctx context.Context, embedder embeddings.Embedder) (*HybridSearcher, error) {
+	scheme, host, err := mustGetAddress(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("run weaviate: %w", err)
+	}
+
+	return newHybridSearcher(scheme, host, indexName, embedder)
+}
+
+func newHybridSearcher(scheme, host, index string, embedder embeddings.Embedder) (*HybridSearcher, error) {
+	client, err := wvt.NewClient(wvt.Config{Scheme: scheme, Host: host})
+	if err != nil {
+		return nil, fmt.Errorf("weaviate client: %w", err)
+	}
+
+	return &HybridSearcher{
+		client:    client,
+		embedder:  embedder,
+		indexName: index,
+	}, nil
+}
+
+// HybridSearch returns the k documents that best match the query, with their fused scores.
+// Alpha weights the vector search: 1 is a pure vector search, 0 is a pure keyword (BM25) search.
+// Hybrid search is useful for technical queries that mix identifiers, like "cloud.logs.verbose", and prose.
+func (h *HybridSearcher) HybridSearch(ctx context.Context, query string, k int, alpha float64) ([]schema.Document, error) {
+	if alpha < 0 || alpha > 1 {
+		return nil, fmt.Errorf("alpha must be between 0 and 1: %f", alpha)
+	}
+
+	vector, err := h.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	hybrid := h.client.GraphQL().HybridArgumentBuilder().
+		WithQuery(query).
+		WithVector(vector).
+		WithAlpha(float32(alpha))
+
+	resp, err := h.client.GraphQL().Get().
+		WithClassName(h.indexName).
+		WithHybrid(hybrid).
+		WithLimit(k).
+		WithFields(
+			graphql.Field{Name: textKey},
+			graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "score"}}},
+		).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid query: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("hybrid query: %s", resp.Errors[0].Message)
+	}
+
+	data := make(map[string]any, len(resp.Data))
+	for key, value := range resp.Data {
+		data[key] = value
+	}
+
+	return parseHybridResponse(data, h.indexName)
+}
+
+// parseHybridResponse converts the GraphQL response of a hybrid query into documents
+func parseHybridResponse(data map[string]any, index string) ([]schema.Document, error) {
+	get, ok := data["Get"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected hybrid response: missing Get")
+	}
+
+	items, ok := get[index].([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected hybrid response: missing %s", index)
+	}
+
+	docs := make([]schema.Document, 0, len(items))
+	for _, item := range items {
+		props, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		content, _ := props[textKey].(string)
+		doc := schema.Document{PageContent: content}
+
+		if additional, ok := props["_additional"].(map[string]any); ok {
+			// Weaviate returns the fused hybrid score as a string
+			if score, ok := additional["score"].(string); ok {
+				s, err := strconv.ParseFloat(score, 32)
+				if err != nil {
+					return nil, fmt.Errorf("parse score %q: %w", score, err)
+				}
+				doc.Score = float32(s)
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,97 @@
+//go:build integration
+
+package weaviate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/weaviate"
+)
+
+// fixedEmbedder returns a hardcoded vector per text, so the test controls the vector similarity:
+// the query is closer to the prose document than to the one with the keyword.
+type fixedEmbedder struct{}
+
+func (fixedEmbedder) vector(text string) []float32 {
+	switch {
+	case strings.Contains(text, "cloud.logs.verbose") && strings.HasPrefix(text, "Set"):
+		return []float32{0, 1, 0}
+	case strings.Contains(text, "verbose"):
+		// the query
+		return []float32{1, 0.1, 0}
+	default:
+		return []float32{1, 0, 0}
+	}
+}
+
+func (e fixedEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e fixedEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vector(text), nil
+}
+
+func TestHybridSearch(t *testing.T) {
+	ctx := context.Background()
+	index := "HybridTest"
+
+	scheme, host, err := mustGetAddress(ctx)
+	if err != nil {
+		t.Fatalf("run weaviate: %s", err)
+	}
+
+	store, err := weaviate.New(
+		weaviate.WithScheme(scheme),
+		weaviate.WithHost(host),
+		weaviate.WithIndexName(index),
+		weaviate.WithEmbedder(fixedEmbedder{}),
+	)
+	if err != nil {
+		t.Fatalf("new store: %s", err)
+	}
+
+	keywordDoc := "Set cloud.logs.verbose to true in the Testcontainers Desktop configuration."
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Testcontainers Desktop helps with local development."},
+		{PageContent: keywordDoc},
+	})
+	if err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+
+	query := "cloud.logs.verbose"
+
+	vectorDocs, err := store.SimilaritySearch(ctx, query, 2, vectorstores.WithEmbedder(fixedEmbedder{}))
+	if err != nil {
+		t.Fatalf("similarity search: %s", err)
+	}
+	if len(vectorDocs) == 0 || vectorDocs[0].PageContent == keywordDoc {
+		t.Fatalf("expected pure vector search to rank the keyword document lower, got %v", vectorDocs)
+	}
+
+	searcher, err := newHybridSearcher(scheme, host, index, fixedEmbedder{})
+	if err != nil {
+		t.Fatalf("new hybrid searcher: %s", err)
+	}
+
+	hybridDocs, err := searcher.HybridSearch(ctx, query, 2, 0.25)
+	if err != nil {
+		t.Fatalf("hybrid search: %s", err)
+	}
+
+	if len(hybridDocs) == 0 || hybridDocs[0].PageContent != keywordDoc {
+		t.Fatalf("expected hybrid search to return the keyword document first, got %v", hybridDocs)
+	}
+	if hybridDocs[0].Score <= 0 {
+		t.Errorf("expected a fused score, got %f", hybridDocs[0].Score)
+	}
+}
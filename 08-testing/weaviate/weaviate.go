@@ -20,7 +20,7 @@ func NewStore(ctx context.Context, embedder embeddings.Embedder) (weaviate.Store
 	return weaviate.New(
 		weaviate.WithScheme(schema),
 		weaviate.WithHost(host),
-		weaviate.WithIndexName("Testcontainers"),
+		weaviate.WithIndexName(indexName),
 		weaviate.WithEmbedder(embedder),
 	)
 }
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"os"
 	"strings"
 
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
@@ -22,7 +24,8 @@ const (
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -52,7 +55,9 @@ func run() (err error) {
 		return fmt.Errorf("openai new: %w", err)
 	}
 
-	ctx := context.Background()
+	timeout := ai.GenerationTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 	content := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeSystem, "You are a fellow Go developer."),
 		llms.TextParts(llms.ChatMessageTypeHuman, "Provide 3 short bullet points explaining why Go is awesome"),
@@ -64,7 +69,7 @@ func run() (err error) {
 		return nil
 	}))
 	if generateContentErr != nil {
-		err = fmt.Errorf("llm generate content: %w", generateContentErr)
+		err = fmt.Errorf("llm generate content: %w", ai.GenerationError(generateContentErr, timeout))
 		return
 	}
 
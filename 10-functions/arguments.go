@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// maxArgumentRepairs is the number of times the model is asked to fix the arguments of a tool call
+// that are not valid JSON, before giving up.
+const maxArgumentRepairs = 2
+
+// decodeToolArguments decodes the JSON arguments of the tool call into v. Small models sometimes
+// send slightly malformed JSON, so when decoding fails the parse error is fed back to the model,
+// as the response of the tool, asking it to call the tool again with valid arguments, up to
+// maxArgumentRepairs times. The repair turns are not added to the message history.
+func decodeToolArguments(ctx context.Context, llm llms.Model, messageHistory []llms.MessageContent, toolCall llms.ToolCall, v any) error {
+	arguments := toolCall.FunctionCall.Arguments
+
+	err := json.Unmarshal([]byte(arguments), v)
+	if err == nil {
+		return nil
+	}
+
+	// Copy the history, so the repair turns don't modify the backing array of the caller's one
+	repairHistory := append([]llms.MessageContent(nil), messageHistory...)
+
+	timeout := ai.GenerationTimeout()
+	for attempt := 1; attempt <= maxArgumentRepairs; attempt++ {
+		logging.Default().Warn("Invalid arguments, asking the model to repair them", "tool", toolCall.FunctionCall.Name, "attempt", attempt, "max_attempts", maxArgumentRepairs, "error", err)
+
+		repairHistory = append(repairHistory, llms.MessageContent{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{
+				llms.ToolCallResponse{
+					ToolCallID: toolCall.ID,
+					Name:       toolCall.FunctionCall.Name,
+					Content:    fmt.Sprintf("Error: the arguments %s are not valid JSON (%s). Call %s again with valid JSON arguments.", arguments, err, toolCall.FunctionCall.Name),
+				},
+			},
+		})
+
+		genCtx, cancel := context.WithTimeout(ctx, timeout)
+		resp, genErr := llm.GenerateContent(genCtx, repairHistory,
+			llms.WithTools(availableTools),
+			llms.WithTemperature(0.1),
+		)
+		cancel()
+		if genErr != nil {
+			return fmt.Errorf("repair arguments of %s: %w", toolCall.FunctionCall.Name, ai.GenerationError(genErr, timeout))
+		}
+
+		repaired, ok := findToolCall(resp, toolCall.FunctionCall.Name)
+		if !ok {
+			return fmt.Errorf("repair arguments of %s: the model did not call the tool again", toolCall.FunctionCall.Name)
+		}
+
+		repairHistory = append(repairHistory, llms.MessageContent{
+			Role:  llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{repaired},
+		})
+		toolCall = repaired
+		arguments = repaired.FunctionCall.Arguments
+
+		if err = json.Unmarshal([]byte(arguments), v); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid arguments for %s after %d repairs: %w", toolCall.FunctionCall.Name, maxArgumentRepairs, err)
+}
+
+// findToolCall returns the first call to the named tool in the response.
+func findToolCall(resp *llms.ContentResponse, name string) (llms.ToolCall, bool) {
+	if len(resp.Choices) == 0 {
+		return llms.ToolCall{}, false
+	}
+
+	for _, toolCall := range resp.Choices[0].ToolCalls {
+		if toolCall.FunctionCall != nil && toolCall.FunctionCall.Name == name {
+			return toolCall, true
+		}
+	}
+
+	return llms.ToolCall{}, false
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// scriptedModel answers each generation with the next tool call arguments, recording the last messages
+type scriptedModel struct {
+	arguments []string
+	calls     int
+	lastTurn  llms.MessageContent
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	arguments := m.arguments[m.calls]
+	m.calls++
+	m.lastTurn = messages[len(messages)-1]
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call-repaired",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "fetchPokeAPI", Arguments: arguments},
+			}},
+		}},
+	}, nil
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestDecodeToolArguments(t *testing.T) {
+	badCall := llms.ToolCall{
+		ID:           "call-1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "fetchPokeAPI", Arguments: `{"pokemon": "gengar"`},
+	}
+	history := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Tell me about Gengar."),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{badCall}},
+	}
+
+	type pokemonArgs struct {
+		Pokemon string `json:"pokemon"`
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		model := &scriptedModel{}
+		goodCall := badCall
+		goodCall.FunctionCall = &llms.FunctionCall{Name: "fetchPokeAPI", Arguments: `{"pokemon": "gengar"}`}
+
+		var args pokemonArgs
+		if err := decodeToolArguments(context.Background(), model, history, goodCall, &args); err != nil {
+			t.Fatalf("decode tool arguments: %s", err)
+		}
+
+		if args.Pokemon != "gengar" || model.calls != 0 {
+			t.Errorf("expected the arguments without asking the model, got %+v after %d calls", args, model.calls)
+		}
+	})
+
+	t.Run("bad-then-good", func(t *testing.T) {
+		model := &scriptedModel{arguments: []string{`{pokemon: gengar}`, `{"pokemon": "gengar"}`}}
+
+		var args pokemonArgs
+		if err := decodeToolArguments(context.Background(), model, history, badCall, &args); err != nil {
+			t.Fatalf("decode tool arguments: %s", err)
+		}
+
+		if args.Pokemon != "gengar" {
+			t.Errorf("expected the repaired arguments, got %+v", args)
+		}
+		if model.calls != 2 {
+			t.Errorf("expected 2 repairs, got %d", model.calls)
+		}
+
+		// The parse error of the previous arguments is fed back to the model
+		response, ok := model.lastTurn.Parts[0].(llms.ToolCallResponse)
+		if !ok {
+			t.Fatalf("expected a tool response, got %T", model.lastTurn.Parts[0])
+		}
+		if response.ToolCallID != "call-repaired" || !strings.Contains(response.Content, "{pokemon: gengar}") {
+			t.Errorf("expected the error of the repaired call, got %+v", response)
+		}
+
+		if len(history) != 2 {
+			t.Errorf("expected the history to be unchanged, got %d messages", len(history))
+		}
+	})
+
+	t.Run("gives-up", func(t *testing.T) {
+		model := &scriptedModel{arguments: []string{`{`, `{`, `{`}}
+
+		var args pokemonArgs
+		err := decodeToolArguments(context.Background(), model, history, badCall, &args)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		if model.calls != maxArgumentRepairs {
+			t.Errorf("expected %d repairs, got %d", maxArgumentRepairs, model.calls)
+		}
+	})
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"bytes"
 	"io"
-	"log"
 	"net/http"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 )
 
 // loggingTrasport is a transport that logs the request and response.
@@ -13,7 +14,7 @@ type loggingTrasport struct{}
 
 // RoundTrip implements the http.RoundTripper interface, logging the request and response bodies.
 func (c *loggingTrasport) RoundTrip(req *http.Request) (*http.Response, error) {
-	log.Printf("RoundTrip: %s", req.URL)
+	logging.Default().Debug("RoundTrip", "url", req.URL)
 
 	// read the body
 	body, err := io.ReadAll(req.Body)
@@ -22,7 +23,7 @@ func (c *loggingTrasport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 	// log the response
-	log.Printf("Request: %s", string(body))
+	logging.Default().Debug("Request", "body", string(body))
 
 	// create a new ReadCloser with the same content
 	req.Body = io.NopCloser(bytes.NewReader(body))
@@ -32,7 +33,7 @@ func (c *loggingTrasport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return resp, err
 	}
 
-	log.Printf("Status: %s", resp.Status)
+	logging.Default().Debug("Status", "status", resp.Status)
 
 	// read the body
 	body, err = io.ReadAll(resp.Body)
@@ -42,7 +43,7 @@ func (c *loggingTrasport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	// log the body
-	log.Printf("Response: %s", string(body))
+	logging.Default().Debug("Response", "body", string(body))
 
 	// create a new ReadCloser with the same content
 	resp.Body = io.NopCloser(bytes.NewReader(body))
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// maxToolIterations is the maximum number of model round-trips of the tool loop.
+	maxToolIterations = 3
+
+	// metricIterationCount is the name of the iterations-to-answer metric, the same as in the benchmarks.
+	metricIterationCount = "llm.iteration.count"
+)
+
+// toolExecutor executes the tool calls of the response, returning the updated message history.
+type toolExecutor func(ctx context.Context, llm llms.Model, messageHistory []llms.MessageContent, resp *llms.ContentResponse) ([]llms.MessageContent, error)
+
+// iterationRecorder records how many model round-trips the tool loop needed before the final answer.
+type iterationRecorder interface {
+	RecordIterations(ctx context.Context, model string, testCase string, iterations int)
+}
+
+// otelIterationRecorder records the iterations in the llm.iteration.count histogram of its meter provider.
+type otelIterationRecorder struct {
+	histogram metric.Int64Histogram
+}
+
+// newIterationRecorder creates the recorder of the iterations-to-answer metric, recorded with the provider.
+func newIterationRecorder(provider metric.MeterProvider) (*otelIterationRecorder, error) {
+	histogram, err := provider.Meter("functions").Int64Histogram(metricIterationCount,
+		metric.WithDescription("Model round-trips of the tool loop before the final answer"),
+		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 10),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("iteration count histogram: %w", err)
+	}
+
+	return &otelIterationRecorder{histogram: histogram}, nil
+}
+
+// RecordIterations records the iterations, labeled by model and test case.
+func (r *otelIterationRecorder) RecordIterations(ctx context.Context, model string, testCase string, iterations int) {
+	r.histogram.Record(ctx, int64(iterations), metric.WithAttributes(
+		attribute.String("model", model),
+		attribute.String("case", testCase),
+	))
+}
+
+// runToolLoop calls the model with the tools, executing the tool calls of each response, until
+// it answers without calling any tool or maxToolIterations is reached. The number of round-trips
+// needed to get the final answer is recorded: fewer iterations on the same task indicate a more
+// capable model. It returns the message history, including the responses of the model and tools.
+func runToolLoop(ctx context.Context, llm llms.Model, messageHistory []llms.MessageContent, execute toolExecutor, recorder iterationRecorder, testCase string) ([]llms.MessageContent, error) {
+	timeout := ai.GenerationTimeout()
+	for iteration := 1; iteration <= maxToolIterations; iteration++ {
+		genCtx, cancel := context.WithTimeout(ctx, timeout)
+		resp, err := llm.GenerateContent(genCtx, messageHistory,
+			llms.WithTools(availableTools),
+			llms.WithTemperature(0.1), // Lower temperature for more consistent behavior
+			llms.WithTopP(0.9),        // Adjust for better function calling
+		)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("generateContent (%d): %w", iteration, ai.GenerationError(err, timeout))
+		}
+
+		respchoice := resp.Choices[0]
+
+		assistantResponse := llms.TextParts(llms.ChatMessageTypeAI, respchoice.Content)
+		for _, tc := range respchoice.ToolCalls {
+			assistantResponse.Parts = append(assistantResponse.Parts, tc)
+		}
+		messageHistory = append(messageHistory, assistantResponse)
+
+		if len(respchoice.ToolCalls) == 0 {
+			recorder.RecordIterations(ctx, fqModelName, testCase, iteration)
+			return messageHistory, nil
+		}
+
+		messageHistory, err = execute(ctx, llm, messageHistory, resp)
+		if err != nil {
+			return nil, fmt.Errorf("executeToolCalls (%d): %w", iteration, err)
+		}
+	}
+
+	// Without a final answer, there's no iterations-to-answer to record
+	return messageHistory, nil
+}
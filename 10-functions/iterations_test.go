@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// toolCallingModel calls the fetchPokeAPI tool in its first toolCallTurns responses, answering afterwards
+type toolCallingModel struct {
+	toolCallTurns int
+	calls         int
+}
+
+func (m *toolCallingModel) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.calls++
+
+	choice := &llms.ContentChoice{Content: "Gengar has more moves than Haunter."}
+	if m.calls <= m.toolCallTurns {
+		choice.Content = ""
+		choice.ToolCalls = []llms.ToolCall{{
+			ID:           "call-1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "fetchPokeAPI", Arguments: `{"pokemon": "gengar"}`},
+		}}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *toolCallingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// recordedIterations is an iterationRecorder keeping the recorded values
+type recordedIterations struct {
+	model      string
+	testCase   string
+	iterations []int
+}
+
+func (r *recordedIterations) RecordIterations(_ context.Context, model string, testCase string, iterations int) {
+	r.model = model
+	r.testCase = testCase
+	r.iterations = append(r.iterations, iterations)
+}
+
+// fakeToolResponse answers every tool call with a fixed content
+func fakeToolResponse(_ context.Context, _ llms.Model, messageHistory []llms.MessageContent, resp *llms.ContentResponse) ([]llms.MessageContent, error) {
+	for _, toolCall := range resp.Choices[0].ToolCalls {
+		messageHistory = append(messageHistory, llms.MessageContent{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{
+				llms.ToolCallResponse{ToolCallID: toolCall.ID, Name: toolCall.FunctionCall.Name, Content: "MovesCount: 124"},
+			},
+		})
+	}
+
+	return messageHistory, nil
+}
+
+func TestRunToolLoop(t *testing.T) {
+	question := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Compare Gengar and Haunter.")}
+
+	t.Run("two-iterations", func(t *testing.T) {
+		recorder := &recordedIterations{}
+
+		history, err := runToolLoop(context.Background(), &toolCallingModel{toolCallTurns: 1}, question, fakeToolResponse, recorder, "pokemon-comparison")
+		if err != nil {
+			t.Fatalf("run tool loop: %s", err)
+		}
+
+		if len(recorder.iterations) != 1 || recorder.iterations[0] != 2 {
+			t.Fatalf("expected 2 iterations to be recorded once, got %v", recorder.iterations)
+		}
+		if recorder.model != fqModelName || recorder.testCase != "pokemon-comparison" {
+			t.Errorf("unexpected labels: model %q, case %q", recorder.model, recorder.testCase)
+		}
+
+		// Question, tool call, tool response and final answer
+		if len(history) != 4 {
+			t.Errorf("expected 4 messages, got %d", len(history))
+		}
+	})
+
+	t.Run("no-final-answer", func(t *testing.T) {
+		recorder := &recordedIterations{}
+		model := &toolCallingModel{toolCallTurns: maxToolIterations}
+
+		if _, err := runToolLoop(context.Background(), model, question, fakeToolResponse, recorder, "pokemon-comparison"); err != nil {
+			t.Fatalf("run tool loop: %s", err)
+		}
+
+		if model.calls != maxToolIterations {
+			t.Errorf("expected %d model calls, got %d", maxToolIterations, model.calls)
+		}
+		if len(recorder.iterations) != 0 {
+			t.Errorf("expected no iterations to be recorded, got %v", recorder.iterations)
+		}
+	})
+}
+
+func TestIterationRecorder(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	recorder, err := newIterationRecorder(provider)
+	if err != nil {
+		t.Fatalf("new iteration recorder: %s", err)
+	}
+
+	question := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Compare Gengar and Haunter.")}
+	if _, err := runToolLoop(context.Background(), &toolCallingModel{toolCallTurns: 1}, question, fakeToolResponse, recorder, "pokemon-comparison"); err != nil {
+		t.Fatalf("run tool loop: %s", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	var recorded []metricdata.HistogramDataPoint[int64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Histogram[int64]); ok && m.Name == metricIterationCount {
+				recorded = append(recorded, data.DataPoints...)
+			}
+		}
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected one %s data point, got %d", metricIterationCount, len(recorded))
+	}
+	if recorded[0].Count != 1 || recorded[0].Sum != 2 {
+		t.Errorf("expected 2 iterations recorded once, got a count of %d and a sum of %d", recorded[0].Count, recorded[0].Sum)
+	}
+	if testCase, _ := recorded[0].Attributes.Value("case"); testCase.AsString() != "pokemon-comparison" {
+		t.Errorf("expected the pokemon-comparison case, got %q", testCase.AsString())
+	}
+}
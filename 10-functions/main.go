@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/functions/tools/calculator"
 	"github.com/mdelapenya/genai-testcontainers-go/functions/tools/pokemon"
+	"github.com/mdelapenya/genai-testcontainers-go/functions/tools/weather"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/ai"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	"github.com/tmc/langchaingo/llms"
@@ -41,18 +47,92 @@ var availableTools = []llms.Tool{
 				}`),
 		},
 	},
+	{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name: "fetchWeather",
+			Description: `Fetches the current weather of a city from Open-Meteo.
+			Useful for when you need to answer questions about the current temperature, wind or conditions of a place.
+			Returns a JSON object with the temperature in Celsius, the wind speed in km/h and the conditions.`,
+			Parameters: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"city": {
+							"type": "string",
+							"description": "The name of a single city, e.g. Madrid."
+						}
+					},
+					"required": ["city"]
+				}`),
+		},
+	},
+	{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name: "calculate",
+			Description: `A calculator for the numbers returned by the other tools.
+			Useful for when you need to combine them, e.g. the difference between the temperatures of two cities.
+			Returns a JSON object with the operands and the result.`,
+			Parameters: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"operation": {
+							"type": "string",
+							"enum": ["add", "subtract", "multiply", "divide", "power"],
+							"description": "The operation to apply to a and b."
+						},
+						"a": {
+							"type": "number",
+							"description": "The first operand."
+						},
+						"b": {
+							"type": "number",
+							"description": "The second operand."
+						}
+					},
+					"required": ["operation", "a", "b"]
+				}`),
+		},
+	},
+}
+
+// systemInstructions are the instructions of the system message, followed by the description of the tools
+const systemInstructions = `You are a helpful Pokemon assistant. When asked to compare multiple Pokemon, you MUST:
+1. Call fetchPokeAPI once for EACH Pokemon mentioned
+2. Only after getting information for ALL Pokemon, provide your comparison
+3. Never make assumptions - always get data for each Pokemon individually.`
+
+// toolExamples show the model how to call fetchPokeAPI, once per pokemon, which the 3B model
+// otherwise tends to skip for the second pokemon of a comparison
+var toolExamples = []toolExample{
+	{
+		Question: "Which one has more types, Pikachu or Raichu?",
+		Calls: []toolExampleCall{
+			{
+				Tool:      "fetchPokeAPI",
+				Arguments: `{"pokemon": "pikachu"}`,
+				Response:  "ID: 25, Name: pikachu, MovesCount: 2, Moves: [thunder-punch, quick-attack], Types: [electric]",
+			},
+			{
+				Tool:      "fetchPokeAPI",
+				Arguments: `{"pokemon": "raichu"}`,
+				Response:  "ID: 26, Name: raichu, MovesCount: 2, Moves: [thunderbolt, surf], Types: [electric]",
+			},
+		},
+	},
 }
 
 func main() {
 	if err := run(); err != nil {
-		log.Fatalf("run: %s", err)
+		logging.Default().Error("Run failed", "error", err)
+		os.Exit(1)
 	}
 }
 
 func run() (err error) {
 	const question string = "I have two pokemons, Gengar and Haunter. Please fetch information for both Gengar and Haunter individually so you can compare their move counts."
 
-	log.Printf("Question: %s", question)
+	logging.Default().Info("Asking", "question", question)
 
 	// 3b model version is required to use Tools.
 	// See https://hub.docker.com/r/ai/llama3.2
@@ -78,51 +158,51 @@ func run() (err error) {
 		return fmt.Errorf("openai.New: %w", err)
 	}
 
-	messageHistory := []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeHuman, question),
-		llms.TextParts(llms.ChatMessageTypeSystem, `You are a helpful Pokemon assistant. When asked to compare multiple Pokemon, you MUST:
-1. Call fetchPokeAPI once for EACH Pokemon mentioned
-2. Only after getting information for ALL Pokemon, provide your comparison
-3. Never make assumptions - always get data for each Pokemon individually.
-
-As an example, if the user asks for Gengar and Haunter, you must call fetchPokeAPI twice, once for Gengar and once for Haunter.
-`),
-	}
+	messageHistory := buildPrompt(availableTools, promptOptions{
+		Instructions: systemInstructions,
+		Examples:     toolExamples,
+	})
+	messageHistory = append(messageHistory, llms.TextParts(llms.ChatMessageTypeHuman, question))
 
 	ctx := context.Background()
 
-	for retries := 3; retries > 0; retries = retries - 1 {
-		resp, err := llm.GenerateContent(ctx, messageHistory,
-			llms.WithTools(availableTools),
-			llms.WithTemperature(0.1), // Lower temperature for more consistent behavior
-			llms.WithTopP(0.9),        // Adjust for better function calling
-		)
-		if err != nil {
-			return fmt.Errorf("generateContent (%d): %w", retries, err)
+	meterProvider, err := newMeterProvider()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// flushes the metrics of the tool loop to the exporter
+		if shutdownErr := meterProvider.Shutdown(context.Background()); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("shutdown meter provider: %w", shutdownErr)
 		}
+	}()
 
-		respchoice := resp.Choices[0]
+	recorder, err := newIterationRecorder(meterProvider)
+	if err != nil {
+		return err
+	}
 
-		assistantResponse := llms.TextParts(llms.ChatMessageTypeAI, respchoice.Content)
-		for _, tc := range respchoice.ToolCalls {
-			assistantResponse.Parts = append(assistantResponse.Parts, tc)
-		}
-		messageHistory = append(messageHistory, assistantResponse)
+	toolRecorder, err := newToolCallRecorder(meterProvider)
+	if err != nil {
+		return err
+	}
 
-		toolsResponse, err := executeToolCalls(ctx, messageHistory, resp)
-		if err != nil {
-			return fmt.Errorf("executeToolCalls (%d): %w", retries, err)
-		}
-		messageHistory = append(messageHistory, toolsResponse...)
+	messageHistory, err = runToolLoop(ctx, llm, messageHistory, newToolExecutor(builtinTools, toolRecorder), recorder, "pokemon-comparison")
+	if err != nil {
+		return err
 	}
 
 	messageHistory = append(messageHistory, llms.TextParts(llms.ChatMessageTypeHuman, "Can you compare the two?"))
 
 	// Send query to the model again, this time with a history containing its
 	// request to invoke a tool and our response to the tool call.
-	resp, err := llm.GenerateContent(ctx, messageHistory, llms.WithTools(availableTools))
+	timeout := ai.GenerationTimeout()
+	genCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := llm.GenerateContent(genCtx, messageHistory, llms.WithTools(availableTools))
 	if err != nil {
-		return fmt.Errorf("generateContent: %w", err)
+		return fmt.Errorf("generateContent: %w", ai.GenerationError(err, timeout))
 	}
 
 	fmt.Println(resp.Choices[0].Content)
@@ -130,42 +210,136 @@ As an example, if the user asks for Gengar and Haunter, you must call fetchPokeA
 	return nil
 }
 
-// executeToolCalls executes the tool calls in the response and returns the
-// updated message history.
-func executeToolCalls(ctx context.Context, messageHistory []llms.MessageContent, resp *llms.ContentResponse) ([]llms.MessageContent, error) {
-	fmt.Println("Executing", len(resp.Choices[0].ToolCalls), "tool calls")
-	for _, toolCall := range resp.Choices[0].ToolCalls {
-		switch toolCall.FunctionCall.Name {
-		case "fetchPokeAPI":
-			var args struct {
-				Pokemon string `json:"pokemon"`
+const (
+	// maxUnknownToolCalls is the number of calls to unknown tools answered with the available
+	// tools, so the model can correct itself, before failing.
+	maxUnknownToolCalls = 2
+
+	// unknownToolPrefix starts the responses to the calls to unknown tools.
+	unknownToolPrefix = "unknown tool: "
+)
+
+// tool decodes the arguments of a call to a tool, with decode, returning the invocation of the tool
+// with them. The decoding is kept apart from the invocation so only the latter is timed.
+type tool func(decode func(args any) error) (func(ctx context.Context) (string, error), error)
+
+// newTool returns the tool running fn with the arguments of the calls decoded into T.
+func newTool[T any](fn func(ctx context.Context, args T) (string, error)) tool {
+	return func(decode func(args any) error) (func(ctx context.Context) (string, error), error) {
+		var args T
+		if err := decode(&args); err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) (string, error) {
+			return fn(ctx, args)
+		}, nil
+	}
+}
+
+// builtinTools are the implementations of the available tools, by name.
+var builtinTools = map[string]tool{
+	"fetchPokeAPI": newTool(func(ctx context.Context, args struct {
+		Pokemon string `json:"pokemon"`
+	}) (string, error) {
+		return pokemon.FetchAPI(ctx, args.Pokemon)
+	}),
+	"fetchWeather": newTool(func(ctx context.Context, args struct {
+		City string `json:"city"`
+	}) (string, error) {
+		return weather.FetchAPI(ctx, args.City)
+	}),
+	"calculate": newTool(func(_ context.Context, args struct {
+		Operation string  `json:"operation"`
+		A         float64 `json:"a"`
+		B         float64 `json:"b"`
+	}) (string, error) {
+		return calculator.Calculate(args.Operation, args.A, args.B)
+	}),
+}
+
+// newToolExecutor returns the executor of the tool calls in the response, which returns the
+// updated message history. The model is asked to fix the malformed arguments of the tool calls,
+// see decodeToolArguments. The latency and the outcome of each invocation are recorded.
+func newToolExecutor(tools map[string]tool, recorder toolCallRecorder) toolExecutor {
+	return func(ctx context.Context, llm llms.Model, messageHistory []llms.MessageContent, resp *llms.ContentResponse) ([]llms.MessageContent, error) {
+		logging.Default().Info("Executing tool calls", "count", len(resp.Choices[0].ToolCalls))
+		for _, toolCall := range resp.Choices[0].ToolCalls {
+			name := toolCall.FunctionCall.Name
+
+			t, ok := tools[name]
+			if !ok {
+				// Let the model correct a hallucinated tool, unless it keeps calling unknown tools
+				if countUnknownToolCalls(messageHistory) >= maxUnknownToolCalls {
+					return nil, fmt.Errorf("unsupported tool: %s, after %d calls to unknown tools", name, maxUnknownToolCalls)
+				}
+
+				logging.Default().Warn("Unknown tool, asking the model to use one of the available tools", "tool", name)
+				messageHistory = append(messageHistory, llms.MessageContent{
+					Role: llms.ChatMessageTypeTool,
+					Parts: []llms.ContentPart{
+						llms.ToolCallResponse{
+							ToolCallID: toolCall.ID,
+							Name:       name,
+							Content:    unknownToolPrefix + name + ", available tools are " + strings.Join(toolNames(availableTools), ", "),
+						},
+					},
+				})
+				continue
 			}
-			if err := json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &args); err != nil {
-				log.Fatal("invalid input: ", err)
+
+			invoke, err := t(func(args any) error {
+				return decodeToolArguments(ctx, llm, messageHistory, toolCall, args)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
 			}
 
-			p, err := pokemon.FetchAPI(ctx, args.Pokemon)
+			start := time.Now()
+			content, err := invoke(ctx)
+			recorder.RecordToolCall(ctx, name, time.Since(start), err)
 			if err != nil {
-				return nil, fmt.Errorf("fetchPokeAPI: %w", err)
+				return nil, fmt.Errorf("%s: %w", name, err)
 			}
 
-			pokeAPICallResponse := llms.MessageContent{
+			messageHistory = append(messageHistory, llms.MessageContent{
 				Role: llms.ChatMessageTypeTool,
 				Parts: []llms.ContentPart{
 					llms.ToolCallResponse{
 						ToolCallID: toolCall.ID,
-						Name:       toolCall.FunctionCall.Name,
-						Content:    p,
+						Name:       name,
+						Content:    content,
 					},
 				},
+			})
+		}
+
+		return messageHistory, nil
+	}
+}
+
+// countUnknownToolCalls returns the number of calls to unknown tools answered in the message history.
+func countUnknownToolCalls(messageHistory []llms.MessageContent) int {
+	count := 0
+	for _, message := range messageHistory {
+		for _, part := range message.Parts {
+			if response, ok := part.(llms.ToolCallResponse); ok && strings.HasPrefix(response.Content, unknownToolPrefix) {
+				count++
 			}
+		}
+	}
 
-			messageHistory = append(messageHistory, pokeAPICallResponse)
+	return count
+}
 
-		default:
-			return nil, fmt.Errorf("unsupported tool: %s", toolCall.FunctionCall.Name)
+// toolNames returns the names of the tools.
+func toolNames(tools []llms.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function != nil {
+			names = append(names, tool.Function.Name)
 		}
 	}
 
-	return messageHistory, nil
+	return names
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// hallucinatingModel calls a nonexistent tool in its first unknownCalls responses, answering afterwards.
+// It keeps the messages of the last request.
+type hallucinatingModel struct {
+	unknownCalls int
+	calls        int
+	messages     []llms.MessageContent
+}
+
+func (m *hallucinatingModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.calls++
+	m.messages = messages
+
+	choice := &llms.ContentChoice{Content: "Gengar is a ghost pokemon."}
+	if m.calls <= m.unknownCalls {
+		choice.Content = ""
+		choice.ToolCalls = []llms.ToolCall{{
+			ID:           "call-pokedex",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "fetchPokedex", Arguments: `{"pokemon": "gengar"}`},
+		}}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *hallucinatingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestExecuteToolCalls_unknownTool(t *testing.T) {
+	question := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Tell me about Gengar.")}
+
+	t.Run("recovers", func(t *testing.T) {
+		model := &hallucinatingModel{unknownCalls: 1}
+		recorder := &recordedIterations{}
+
+		history, err := runToolLoop(context.Background(), model, question, newToolExecutor(builtinTools, &recordedToolCalls{}), recorder, "unknown-tool")
+		if err != nil {
+			t.Fatalf("run tool loop: %s", err)
+		}
+
+		if len(recorder.iterations) != 1 || recorder.iterations[0] != 2 {
+			t.Errorf("expected the answer after 2 iterations, got %v", recorder.iterations)
+		}
+
+		// The model got the list of the available tools in the response to its call
+		response, ok := model.messages[len(model.messages)-1].Parts[0].(llms.ToolCallResponse)
+		if !ok {
+			t.Fatalf("expected a tool response, got %T", model.messages[len(model.messages)-1].Parts[0])
+		}
+		if response.ToolCallID != "call-pokedex" || !strings.Contains(response.Content, "unknown tool: fetchPokedex") || !strings.Contains(response.Content, "fetchPokeAPI") {
+			t.Errorf("unexpected tool response: %+v", response)
+		}
+
+		if last := history[len(history)-1]; last.Role != llms.ChatMessageTypeAI {
+			t.Errorf("expected the final answer last, got a %s message", last.Role)
+		}
+	})
+
+	t.Run("fails-after-repeated-calls", func(t *testing.T) {
+		model := &hallucinatingModel{unknownCalls: maxToolIterations}
+
+		_, err := runToolLoop(context.Background(), model, question, newToolExecutor(builtinTools, &recordedToolCalls{}), &recordedIterations{}, "unknown-tool")
+		if err == nil || !strings.Contains(err.Error(), "unsupported tool: fetchPokedex") {
+			t.Fatalf("expected an unsupported tool error, got %v", err)
+		}
+
+		if model.calls != maxUnknownToolCalls+1 {
+			t.Errorf("expected %d model calls, got %d", maxUnknownToolCalls+1, model.calls)
+		}
+	})
+}
+
+// scriptedToolModel calls the tools of its responses in order, answering when it runs out of them.
+// It keeps the messages of the last request.
+type scriptedToolModel struct {
+	responses [][]llms.ToolCall
+	calls     int
+	messages  []llms.MessageContent
+}
+
+func (m *scriptedToolModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.calls++
+	m.messages = messages
+
+	choice := &llms.ContentChoice{Content: "It's 13 degrees warmer in Madrid than in Oslo."}
+	if m.calls <= len(m.responses) {
+		choice.Content = ""
+		choice.ToolCalls = m.responses[m.calls-1]
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *scriptedToolModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestRunToolLoop_weatherAndCalculator(t *testing.T) {
+	if !slices.Contains(toolNames(availableTools), "calculate") {
+		t.Fatal("expected the calculate tool to be available")
+	}
+
+	// The weather of the cities is canned, the calculator is the registered one
+	temperatures := map[string]float64{"Madrid": 31.5, "Oslo": 18.5}
+	tools := map[string]tool{
+		"fetchWeather": newTool(func(_ context.Context, args struct {
+			City string `json:"city"`
+		}) (string, error) {
+			return fmt.Sprintf(`{"city": %q, "temperature_celsius": %g}`, args.City, temperatures[args.City]), nil
+		}),
+		"calculate": builtinTools["calculate"],
+	}
+
+	call := func(id, name, arguments string) llms.ToolCall {
+		return llms.ToolCall{ID: id, Type: "function", FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments}}
+	}
+	model := &scriptedToolModel{responses: [][]llms.ToolCall{
+		{call("call-madrid", "fetchWeather", `{"city": "Madrid"}`), call("call-oslo", "fetchWeather", `{"city": "Oslo"}`)},
+		{call("call-difference", "calculate", `{"operation": "subtract", "a": 31.5, "b": 18.5}`)},
+	}}
+	toolCalls := &recordedToolCalls{}
+	recorder := &recordedIterations{}
+
+	question := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "How much warmer is Madrid than Oslo?")}
+	history, err := runToolLoop(context.Background(), model, question, newToolExecutor(tools, toolCalls), recorder, "weather-difference")
+	if err != nil {
+		t.Fatalf("run tool loop: %s", err)
+	}
+
+	if !slices.Equal(toolCalls.tools, []string{"fetchWeather", "fetchWeather", "calculate"}) {
+		t.Errorf("unexpected tool calls: %v", toolCalls.tools)
+	}
+	if len(recorder.iterations) != 1 || recorder.iterations[0] != 3 {
+		t.Errorf("expected the answer after 3 iterations, got %v", recorder.iterations)
+	}
+
+	// The result of the calculator was sent to the model before its answer
+	response, ok := model.messages[len(model.messages)-1].Parts[0].(llms.ToolCallResponse)
+	if !ok {
+		t.Fatalf("expected a tool response, got %T", model.messages[len(model.messages)-1].Parts[0])
+	}
+	if response.ToolCallID != "call-difference" || !strings.Contains(response.Content, `"result":13`) {
+		t.Errorf("unexpected calculator response: %+v", response)
+	}
+
+	if last := history[len(history)-1]; last.Role != llms.ChatMessageTypeAI {
+		t.Errorf("expected the final answer last, got a %s message", last.Role)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// toolExample is a few-shot example of the expected tool use: a question and the tool calls
+// answering it, each one paired with the response of the tool.
+type toolExample struct {
+	Question string
+	Calls    []toolExampleCall
+}
+
+// toolExampleCall is a tool call of a few-shot example, with the response of the tool.
+type toolExampleCall struct {
+	Tool      string
+	Arguments string // JSON arguments of the call
+	Response  string
+}
+
+// promptOptions configures the messages built by buildPrompt.
+type promptOptions struct {
+	// Instructions of the system message, before the description of the tools.
+	Instructions string
+	// Examples are sent as previous turns of the conversation, so the model can imitate them.
+	Examples []toolExample
+}
+
+// buildPrompt returns the system message describing the tools, followed by the turns of the
+// few-shot examples, if any. Small models call the tools more reliably when the tools are
+// listed in the system message and they have seen a few calls answering similar questions.
+// The question of the user goes after the returned messages.
+func buildPrompt(tools []llms.Tool, opts promptOptions) []llms.MessageContent {
+	var sb strings.Builder
+
+	if opts.Instructions != "" {
+		sb.WriteString(strings.TrimSpace(opts.Instructions))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("You have access to the following tools:\n")
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		// Collapse the indentation and line breaks of the multi-line descriptions
+		fmt.Fprintf(&sb, "- %s: %s\n", tool.Function.Name, strings.Join(strings.Fields(tool.Function.Description), " "))
+	}
+
+	if len(opts.Examples) > 0 {
+		sb.WriteString("\nThe previous turns of the conversation are examples of how to call the tools.\n")
+	}
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, sb.String()),
+	}
+
+	for i, example := range opts.Examples {
+		messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, example.Question))
+
+		calls := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+		responses := make([]llms.MessageContent, 0, len(example.Calls))
+		for j, call := range example.Calls {
+			id := fmt.Sprintf("example-%d-%d", i+1, j+1)
+
+			calls.Parts = append(calls.Parts, llms.ToolCall{
+				ID:   id,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      call.Tool,
+					Arguments: call.Arguments,
+				},
+			})
+
+			responses = append(responses, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{
+					llms.ToolCallResponse{
+						ToolCallID: id,
+						Name:       call.Tool,
+						Content:    call.Response,
+					},
+				},
+			})
+		}
+
+		if len(calls.Parts) > 0 {
+			messages = append(messages, calls)
+			messages = append(messages, responses...)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestBuildPrompt(t *testing.T) {
+	tools := []llms.Tool{
+		{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name: "fetchPokeAPI",
+				Description: `A wrapper around PokeAPI.
+				Input should be a single pokemon name.`,
+			},
+		},
+	}
+
+	t.Run("without-examples", func(t *testing.T) {
+		messages := buildPrompt(tools, promptOptions{Instructions: "You are a Pokemon assistant."})
+
+		if len(messages) != 1 {
+			t.Fatalf("expected only the system message, got %d messages", len(messages))
+		}
+
+		system := textOf(t, messages[0])
+		if messages[0].Role != llms.ChatMessageTypeSystem {
+			t.Errorf("expected a system message, got %s", messages[0].Role)
+		}
+		if !strings.HasPrefix(system, "You are a Pokemon assistant.") {
+			t.Errorf("expected the instructions first, got %q", system)
+		}
+		if !strings.Contains(system, "- fetchPokeAPI: A wrapper around PokeAPI. Input should be a single pokemon name.") {
+			t.Errorf("expected the tool description in a single line, got %q", system)
+		}
+		if strings.Contains(system, "examples") {
+			t.Errorf("expected no mention of examples, got %q", system)
+		}
+	})
+
+	t.Run("with-examples", func(t *testing.T) {
+		messages := buildPrompt(tools, promptOptions{
+			Examples: []toolExample{
+				{
+					Question: "Compare Pikachu and Raichu.",
+					Calls: []toolExampleCall{
+						{Tool: "fetchPokeAPI", Arguments: `{"pokemon": "pikachu"}`, Response: "Name: pikachu"},
+						{Tool: "fetchPokeAPI", Arguments: `{"pokemon": "raichu"}`, Response: "Name: raichu"},
+					},
+				},
+			},
+		})
+
+		// System message, question, tool calls and one response per call
+		if len(messages) != 5 {
+			t.Fatalf("expected 5 messages, got %d", len(messages))
+		}
+
+		if !strings.Contains(textOf(t, messages[0]), "examples") {
+			t.Error("expected the system message to introduce the examples")
+		}
+
+		if messages[1].Role != llms.ChatMessageTypeHuman || textOf(t, messages[1]) != "Compare Pikachu and Raichu." {
+			t.Errorf("expected the example question, got %+v", messages[1])
+		}
+
+		if messages[2].Role != llms.ChatMessageTypeAI || len(messages[2].Parts) != 2 {
+			t.Fatalf("expected an AI message with the two tool calls, got %+v", messages[2])
+		}
+
+		for i, pokemon := range []string{"pikachu", "raichu"} {
+			call, ok := messages[2].Parts[i].(llms.ToolCall)
+			if !ok {
+				t.Fatalf("expected a tool call, got %T", messages[2].Parts[i])
+			}
+			if call.FunctionCall.Name != "fetchPokeAPI" || !strings.Contains(call.FunctionCall.Arguments, pokemon) {
+				t.Errorf("unexpected tool call: %+v", call.FunctionCall)
+			}
+
+			response := messages[3+i]
+			if response.Role != llms.ChatMessageTypeTool {
+				t.Errorf("expected a tool message, got %s", response.Role)
+			}
+			toolResponse, ok := response.Parts[0].(llms.ToolCallResponse)
+			if !ok {
+				t.Fatalf("expected a tool call response, got %T", response.Parts[0])
+			}
+			if toolResponse.ToolCallID != call.ID || toolResponse.Content != "Name: "+pokemon {
+				t.Errorf("expected the response of call %s, got %+v", call.ID, toolResponse)
+			}
+		}
+	})
+}
+
+func textOf(t *testing.T, message llms.MessageContent) string {
+	t.Helper()
+
+	text, ok := message.Parts[0].(llms.TextContent)
+	if !ok {
+		t.Fatalf("expected a text part, got %T", message.Parts[0])
+	}
+
+	return text.Text
+}
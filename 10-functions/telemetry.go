@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newMeterProvider creates the meter provider of the tool loop metrics, exporting them to the
+// standard error: they are printed when the provider is shut down, at the end of the example.
+func newMeterProvider() (*sdkmetric.MeterProvider, error) {
+	exporter, err := stdoutmetric.New(
+		stdoutmetric.WithWriter(os.Stderr),
+		stdoutmetric.WithPrettyPrint(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("stdout metric exporter: %w", err)
+	}
+
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter))), nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// metricToolCallLatency is the name of the tool latency metric, the same as in the benchmarks.
+	metricToolCallLatency = "llm.tool_call.latency"
+
+	// metricToolSuccessRate is the name of the tool success rate metric, the same as in the benchmarks.
+	metricToolSuccessRate = "llm.tool.success_rate"
+
+	// attrToolName is the attribute labeling the tool metrics, the same as in the benchmarks.
+	attrToolName = "tool.name"
+)
+
+// toolCallRecorder records the latency and the outcome of each tool invocation.
+type toolCallRecorder interface {
+	RecordToolCall(ctx context.Context, tool string, latency time.Duration, err error)
+}
+
+// toolCallStats counts the invocations of a tool.
+type toolCallStats struct {
+	calls     int
+	succeeded int
+}
+
+// otelToolCallRecorder records the tool invocations in the llm.tool_call.latency histogram and the
+// llm.tool.success_rate gauge of its meter provider, labeled by tool name, telling apart the
+// tools that are slow or flaky.
+type otelToolCallRecorder struct {
+	latency metric.Float64Histogram
+
+	mu    sync.Mutex
+	stats map[string]*toolCallStats
+}
+
+// newToolCallRecorder creates the recorder of the tool metrics, recorded with the provider.
+func newToolCallRecorder(provider metric.MeterProvider) (*otelToolCallRecorder, error) {
+	meter := provider.Meter("functions")
+	r := &otelToolCallRecorder{stats: make(map[string]*toolCallStats)}
+
+	latency, err := meter.Float64Histogram(metricToolCallLatency,
+		metric.WithDescription("Latency of the tool invocations in milliseconds"),
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(10, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tool call latency histogram: %w", err)
+	}
+	r.latency = latency
+
+	_, err = meter.Float64ObservableGauge(metricToolSuccessRate,
+		metric.WithDescription("Rate of the tool invocations that succeeded"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			for tool, stats := range r.stats {
+				o.Observe(float64(stats.succeeded)/float64(stats.calls), metric.WithAttributes(
+					attribute.String("model", fqModelName),
+					attribute.String(attrToolName, tool),
+				))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tool success rate gauge: %w", err)
+	}
+
+	return r, nil
+}
+
+// RecordToolCall records the latency of the invocation of the tool, and whether it succeeded.
+func (r *otelToolCallRecorder) RecordToolCall(ctx context.Context, tool string, latency time.Duration, err error) {
+	r.latency.Record(ctx, float64(latency)/float64(time.Millisecond), metric.WithAttributes(
+		attribute.String("model", fqModelName),
+		attribute.String(attrToolName, tool),
+		attribute.Bool("success", err == nil),
+	))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[tool]
+	if !ok {
+		stats = &toolCallStats{}
+		r.stats[tool] = stats
+	}
+	stats.calls++
+	if err == nil {
+		stats.succeeded++
+	}
+}
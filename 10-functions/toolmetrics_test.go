@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// recordedToolCalls is a toolCallRecorder keeping the recorded calls
+type recordedToolCalls struct {
+	tools []string
+}
+
+func (r *recordedToolCalls) RecordToolCall(_ context.Context, tool string, _ time.Duration, _ error) {
+	r.tools = append(r.tools, tool)
+}
+
+// toolCallsResponse is a response of the model calling the tools, with empty arguments
+func toolCallsResponse(names ...string) *llms.ContentResponse {
+	choice := &llms.ContentChoice{}
+	for _, name := range names {
+		choice.ToolCalls = append(choice.ToolCalls, llms.ToolCall{
+			ID:           "call-" + name,
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: name, Arguments: `{}`},
+		})
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}
+}
+
+func TestToolCallMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	recorder, err := newToolCallRecorder(provider)
+	if err != nil {
+		t.Fatalf("new tool call recorder: %s", err)
+	}
+
+	errUnavailable := errors.New("service unavailable")
+	tools := map[string]tool{
+		"fast": newTool(func(context.Context, struct{}) (string, error) {
+			return "ok", nil
+		}),
+		"slow": newTool(func(context.Context, struct{}) (string, error) {
+			time.Sleep(60 * time.Millisecond)
+			return "", errUnavailable
+		}),
+	}
+	execute := newToolExecutor(tools, recorder)
+
+	ctx := context.Background()
+	if _, err := execute(ctx, nil, nil, toolCallsResponse("fast", "fast")); err != nil {
+		t.Fatalf("execute tool calls: %s", err)
+	}
+	if _, err := execute(ctx, nil, nil, toolCallsResponse("fast", "slow")); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected the error of the slow tool, got %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	successRates := make(map[string]float64)
+	latencyCounts := make(map[string]uint64)
+	latencyMax := make(map[string]float64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[float64]:
+				if m.Name != metricToolSuccessRate {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					successRates[toolName(dp.Attributes)] = dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name != metricToolCallLatency {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					name := toolName(dp.Attributes)
+					latencyCounts[name] += dp.Count
+					if v, ok := dp.Max.Value(); ok && v > latencyMax[name] {
+						latencyMax[name] = v
+					}
+				}
+			}
+		}
+	}
+
+	if successRates["fast"] != 1 || successRates["slow"] != 0 || len(successRates) != 2 {
+		t.Errorf("expected a success rate of 1 for the fast tool and 0 for the slow one, got %v", successRates)
+	}
+	if latencyCounts["fast"] != 3 || latencyCounts["slow"] != 1 {
+		t.Errorf("expected 3 invocations of the fast tool and 1 of the slow one, got %v", latencyCounts)
+	}
+	if latencyMax["slow"] < 60 || latencyMax["fast"] >= 60 {
+		t.Errorf("expected the slow tool to take at least 60ms and the fast one less, got %v", latencyMax)
+	}
+}
+
+// toolName returns the tool.name attribute of the measurement
+func toolName(attrs attribute.Set) string {
+	v, _ := attrs.Value(attrToolName)
+	return v.AsString()
+}
+
+func TestToolExecutor_decodedArguments(t *testing.T) {
+	recorder := &recordedToolCalls{}
+	execute := newToolExecutor(map[string]tool{
+		"fetchPokeAPI": newTool(func(_ context.Context, args struct {
+			Pokemon string `json:"pokemon"`
+		}) (string, error) {
+			return args.Pokemon, nil
+		}),
+	}, recorder)
+
+	history, err := execute(context.Background(), nil, nil, &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{{
+			ID:           "call-1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "fetchPokeAPI", Arguments: `{"pokemon": "gengar"}`},
+		}},
+	}}})
+	if err != nil {
+		t.Fatalf("execute tool calls: %s", err)
+	}
+
+	response := history[0].Parts[0].(llms.ToolCallResponse)
+	if response.Content != "gengar" || response.ToolCallID != "call-1" {
+		t.Errorf("expected the tool invoked with the decoded arguments, got %+v", response)
+	}
+	if len(recorder.tools) != 1 || recorder.tools[0] != "fetchPokeAPI" {
+		t.Errorf("expected the invocation recorded, got %v", recorder.tools)
+	}
+}
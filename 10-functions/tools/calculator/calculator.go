@@ -0,0 +1,46 @@
+package calculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Result is the result of an operation, returned as JSON to the model.
+type Result struct {
+	Operation string  `json:"operation"`
+	A         float64 `json:"a"`
+	B         float64 `json:"b"`
+	Result    float64 `json:"result"`
+}
+
+// Calculate applies the operation to the operands: add, subtract, multiply, divide or power.
+// It returns a JSON string with the operands and the result, so the model can combine it with the
+// output of other tools, e.g. the difference between the temperatures of two cities.
+func Calculate(operation string, a, b float64) (string, error) {
+	var result float64
+	switch operation {
+	case "add":
+		result = a + b
+	case "subtract":
+		result = a - b
+	case "multiply":
+		result = a * b
+	case "divide":
+		if b == 0 {
+			return "", fmt.Errorf("division by zero")
+		}
+		result = a / b
+	case "power":
+		result = math.Pow(a, b)
+	default:
+		return "", fmt.Errorf("unknown operation: %s", operation)
+	}
+
+	data, err := json.Marshal(Result{Operation: operation, A: a, B: b, Result: result})
+	if err != nil {
+		return "", fmt.Errorf("marshalling result: %w", err)
+	}
+
+	return string(data), nil
+}
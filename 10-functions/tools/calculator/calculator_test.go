@@ -0,0 +1,43 @@
+package calculator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		operation string
+		a, b      float64
+		expected  float64
+	}{
+		{operation: "add", a: 31.4, b: 2.6, expected: 34},
+		{operation: "subtract", a: 31.5, b: 18.5, expected: 13},
+		{operation: "multiply", a: 3, b: 4, expected: 12},
+		{operation: "divide", a: 9, b: 2, expected: 4.5},
+		{operation: "power", a: 2, b: 10, expected: 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			output, err := Calculate(tt.operation, tt.a, tt.b)
+			require.NoError(t, err)
+
+			var r Result
+			require.NoError(t, json.Unmarshal([]byte(output), &r))
+			require.Equal(t, Result{Operation: tt.operation, A: tt.a, B: tt.b, Result: tt.expected}, r)
+		})
+	}
+
+	t.Run("division-by-zero", func(t *testing.T) {
+		_, err := Calculate("divide", 1, 0)
+		require.EqualError(t, err, "division by zero")
+	})
+
+	t.Run("unknown-operation", func(t *testing.T) {
+		_, err := Calculate("modulo", 1, 2)
+		require.EqualError(t, err, "unknown operation: modulo")
+	})
+}
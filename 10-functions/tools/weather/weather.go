@@ -0,0 +1,178 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// DefaultGeocodingURL is the Open-Meteo API resolving the coordinates of a city.
+	DefaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+	// DefaultForecastURL is the Open-Meteo API returning the weather at some coordinates.
+	DefaultForecastURL = "https://api.open-meteo.com/v1/forecast"
+)
+
+// Client fetches the current weather from Open-Meteo, which needs no API key.
+// The URLs of the APIs can be replaced, e.g. by the ones of a test server.
+type Client struct {
+	GeocodingURL string
+	ForecastURL  string
+	HTTPClient   *http.Client
+}
+
+// Weather is the current weather of a city, returned as JSON to the model.
+type Weather struct {
+	City         string  `json:"city"`
+	Country      string  `json:"country"`
+	TemperatureC float64 `json:"temperature_celsius"`
+	WindSpeedKmh float64 `json:"wind_speed_kmh"`
+	Conditions   string  `json:"conditions"`
+	ObservedAt   string  `json:"observed_at"`
+}
+
+// geocodingResponse is the struct that represents the response from the geocoding API.
+// We are only interested in the first result.
+type geocodingResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Country   string  `json:"country"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+// forecastResponse is the struct that represents the response from the forecast API.
+// We are only interested in the current conditions.
+type forecastResponse struct {
+	Current struct {
+		Time          string  `json:"time"`
+		Temperature2m float64 `json:"temperature_2m"`
+		WindSpeed10m  float64 `json:"wind_speed_10m"`
+		WeatherCode   int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// NewClient returns a client of the public Open-Meteo APIs.
+func NewClient() *Client {
+	return &Client{
+		GeocodingURL: DefaultGeocodingURL,
+		ForecastURL:  DefaultForecastURL,
+		HTTPClient:   http.DefaultClient,
+	}
+}
+
+// FetchAPI fetches the current weather of the city from Open-Meteo. It returns a JSON string
+// with the temperature, the wind speed and the conditions.
+func FetchAPI(ctx context.Context, city string) (string, error) {
+	return NewClient().Fetch(ctx, city)
+}
+
+// Fetch fetches the current weather of the city, resolving its coordinates first.
+// It returns a JSON string with the temperature, the wind speed and the conditions.
+func (c *Client) Fetch(ctx context.Context, city string) (string, error) {
+	var geo geocodingResponse
+	err := c.get(ctx, c.GeocodingURL, url.Values{
+		"name":     {city},
+		"count":    {"1"},
+		"language": {"en"},
+		"format":   {"json"},
+	}, &geo)
+	if err != nil {
+		return "", fmt.Errorf("geocoding %s: %w", city, err)
+	}
+
+	if len(geo.Results) == 0 {
+		return "", fmt.Errorf("city not found: %s", city)
+	}
+	location := geo.Results[0]
+
+	var forecast forecastResponse
+	err = c.get(ctx, c.ForecastURL, url.Values{
+		"latitude":  {fmt.Sprintf("%f", location.Latitude)},
+		"longitude": {fmt.Sprintf("%f", location.Longitude)},
+		"current":   {"temperature_2m,wind_speed_10m,weather_code"},
+	}, &forecast)
+	if err != nil {
+		return "", fmt.Errorf("forecast %s: %w", city, err)
+	}
+
+	w := Weather{
+		City:         location.Name,
+		Country:      location.Country,
+		TemperatureC: forecast.Current.Temperature2m,
+		WindSpeedKmh: forecast.Current.WindSpeed10m,
+		Conditions:   describeWeatherCode(forecast.Current.WeatherCode),
+		ObservedAt:   forecast.Current.Time,
+	}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		return "", fmt.Errorf("marshalling weather: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// get sends a GET request to the API with the query parameters, decoding the JSON response into v.
+func (c *Client) get(ctx context.Context, baseURL string, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("User-Agent", "weather-tool")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, body)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshalling response: %w", err)
+	}
+
+	return nil
+}
+
+// describeWeatherCode returns the description of a WMO weather interpretation code,
+// as documented by Open-Meteo.
+func describeWeatherCode(code int) string {
+	switch {
+	case code == 0:
+		return "clear sky"
+	case code <= 3:
+		return "partly cloudy"
+	case code == 45 || code == 48:
+		return "fog"
+	case code >= 51 && code <= 57:
+		return "drizzle"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "rain"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "snow"
+	case code >= 95:
+		return "thunderstorm"
+	default:
+		return "unknown"
+	}
+}
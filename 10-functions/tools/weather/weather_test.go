@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newStubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Madrid" {
+			w.Write([]byte(`{"generationtime_ms": 0.5}`))
+			return
+		}
+		w.Write([]byte(`{"results": [{"name": "Madrid", "country": "Spain", "latitude": 40.4165, "longitude": -3.70256}]}`))
+	})
+	mux.HandleFunc("/v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("latitude") != "40.416500" || r.URL.Query().Get("longitude") != "-3.702560" {
+			http.Error(w, "unexpected coordinates", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"current": {"time": "2025-06-26T17:00", "temperature_2m": 31.4, "wind_speed_10m": 12.2, "weather_code": 0}}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestFetch(t *testing.T) {
+	srv := newStubServer(t)
+
+	client := &Client{
+		GeocodingURL: srv.URL + "/v1/search",
+		ForecastURL:  srv.URL + "/v1/forecast",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		output, err := client.Fetch(context.Background(), "Madrid")
+		require.NoError(t, err)
+
+		var w Weather
+		require.NoError(t, json.Unmarshal([]byte(output), &w))
+		require.Equal(t, Weather{
+			City:         "Madrid",
+			Country:      "Spain",
+			TemperatureC: 31.4,
+			WindSpeedKmh: 12.2,
+			Conditions:   "clear sky",
+			ObservedAt:   "2025-06-26T17:00",
+		}, w)
+	})
+
+	t.Run("city-not-found", func(t *testing.T) {
+		_, err := client.Fetch(context.Background(), "Atlantis")
+		require.ErrorContains(t, err, "city not found: Atlantis")
+	})
+
+	t.Run("server-error", func(t *testing.T) {
+		broken := &Client{GeocodingURL: srv.URL + "/missing", ForecastURL: srv.URL + "/v1/forecast"}
+
+		_, err := broken.Fetch(context.Background(), "Madrid")
+		require.ErrorContains(t, err, "unexpected status 404")
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+)
+
+// defaultDeterminismRuns is the number of generations of each prompt compared by BenchmarkDeterminism
+const defaultDeterminismRuns = 5
+
+// getDeterminismRuns returns the runs defined by the BENCH_DETERMINISM_RUNS environment variable,
+// or defaultDeterminismRuns if it's not set
+func getDeterminismRuns() (int, error) {
+	value := os.Getenv("BENCH_DETERMINISM_RUNS")
+	if value == "" {
+		return defaultDeterminismRuns, nil
+	}
+
+	runs, err := strconv.Atoi(value)
+	if err != nil || runs < 2 {
+		return 0, fmt.Errorf("invalid BENCH_DETERMINISM_RUNS %q: must be an integer of at least 2", value)
+	}
+
+	return runs, nil
+}
+
+// BenchmarkDeterminism runs the same prompt several times for each model/case/temperature and
+// reports the determinism_score, the mean pairwise similarity of the outputs, from 0 to 1, to
+// decide whether a model is reliable enough for production. Set BENCH_SEED to measure the
+// determinism with a fixed seed.
+func BenchmarkDeterminism(b *testing.B) {
+	ctx := context.Background()
+
+	runs, err := getDeterminismRuns()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, model := range models {
+		modelName := model.FQName
+
+		endpoint := model.ExternalURL
+		if !model.IsExternal {
+			if err := pullModel(ctx, modelName); err != nil {
+				b.Fatalf("Failed to pull model %s: %v", modelName, err)
+			}
+			endpoint = modelRunner.OpenAIEndpoint()
+		}
+
+		client, err := llmclient.NewClient(endpoint, modelName)
+		if err != nil {
+			b.Fatalf("Failed to create client for %s: %v", modelName, err)
+		}
+
+		for _, tc := range testCases {
+			// The tool-assisted cases run the tools, so their outputs depend on external data
+			if isToolAssistedCase(tc.Name) {
+				continue
+			}
+
+			for _, temp := range temperatures {
+				b.Run(fmt.Sprintf("Determinism/%s/%s/temp%.1f", model.Name, tc.Name, temp), func(b *testing.B) {
+					defer recoverBenchmark(b)
+
+					generate := func(ctx context.Context) (string, error) {
+						resp, err := client.GenerateWithOptions(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, llmclient.GenerateOptions{
+							Temperature: temp,
+							Seed:        benchSeed,
+						})
+						if err != nil {
+							metricsCollector.LogBenchmarkError(ctx, modelName, tc.Name, temp, err)
+							return "", err
+						}
+						return resp.Content, nil
+					}
+
+					var scores []float64
+					var distinct int
+
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						result, err := MeasureDeterminism(ctx, runs, generate)
+						if err != nil {
+							continue
+						}
+						scores = append(scores, result.Score)
+						distinct += result.Distinct
+					}
+					b.StopTimer()
+
+					if len(scores) == 0 {
+						b.Fatalf("All the determinism measurements failed for %s", modelName)
+					}
+
+					var sum float64
+					for _, score := range scores {
+						sum += score
+					}
+					b.ReportMetric(sum/float64(len(scores)), "determinism_score")
+					b.ReportMetric(float64(distinct)/float64(len(scores)), "distinct_outputs")
+					b.ReportMetric(float64(len(scores))/float64(b.N), "success_rate")
+				})
+			}
+		}
+	}
+}
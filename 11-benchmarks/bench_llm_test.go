@@ -6,38 +6,56 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/dmrremote"
 	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
 	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
-	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/stats"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/tools"
 	"github.com/tmc/langchaingo/llms"
 )
 
 //go:embed testdata/fibonacci.go
 var fibonacciCode string
 
-// getDMRContainer returns the typed DMR container
-func getDMRContainer() *dmr.Container {
-	return dmrContainer.(*dmr.Container)
+// isRemoteModelRunner reports whether the models run on a remote Docker Model Runner, see DMR_ENDPOINT,
+// so the host of the benchmark can't measure their disk or memory usage
+func isRemoteModelRunner() bool {
+	_, remote := modelRunner.(*dmrremote.Runner)
+	return remote
 }
 
-// ModelConfig defines a model to benchmark
-type ModelConfig struct {
-	Namespace   string
-	Name        string
-	Tag         string
-	FQName      string
-	IsExternal  bool   // True if using external API (not Docker Model Runner)
-	ExternalURL string // External API endpoint (e.g., https://api.openai.com/v1)
+// modelConfigurator configures the inference of each model under test once, on its first pull
+var modelConfigurator ModelConfigurator
+
+// pullModel pulls the model into the Docker Model Runner and configures its inference, e.g. to run
+// on the CPU only, see BENCH_DMR_CPU_ONLY. The inference is only configured by the first pull of the
+// model, as DMR rejects the configuration once the model is loaded.
+func pullModel(ctx context.Context, model string) error {
+	if err := PullModelVerified(ctx, modelRunner, modelRunner.OpenAIEndpoint(), model, DefaultPullRetryConfig); err != nil {
+		return err
+	}
+
+	if err := modelConfigurator.Configure(ctx, modelRunner.OpenAIEndpoint(), model, dmrRunOptions.RuntimeFlags()); err != nil {
+		return fmt.Errorf("configure model: %w", err)
+	}
+
+	return nil
 }
 
-// TestCase defines a prompt evaluation test case
-type TestCase struct {
-	Name         string
-	SystemPrompt string
-	UserPrompt   string
+// preflightPull checks the disk space before pulling the model into the local Docker Model Runner,
+// the disk of a remote one is not visible from here
+func preflightPull(ctx context.Context, model ModelConfig) error {
+	if isRemoteModelRunner() {
+		return nil
+	}
+
+	return PreflightPull(ctx, modelRunner.OpenAIEndpoint(), model, modelsDiskPath())
 }
 
 var (
@@ -47,55 +65,153 @@ var (
 	// Test cases for evaluation (without temperature)
 	testCases = []TestCase{
 		{
-			Name:         "code-explanation",
-			SystemPrompt: "You are a helpful coding assistant.",
-			UserPrompt:   "Explain what this Go code does:\n\n```go\n" + fibonacciCode + "\n```",
+			Name:           "code-explanation",
+			SystemPrompt:   "You are a helpful coding assistant.",
+			UserPrompt:     "Explain what this Go code does:\n\n```go\n" + fibonacciCode + "\n```",
+			ExpectedTokens: TokenRange{Min: 150, Max: 600},
 		},
 		{
-			Name:         "mathematical-operations",
-			SystemPrompt: "You are a mathematics expert.",
-			UserPrompt:   "What is the result of sum of all numbers between 1 and 100, both inclusive?",
+			Name:           "mathematical-operations",
+			SystemPrompt:   "You are a mathematics expert.",
+			UserPrompt:     "What is the result of sum of all numbers between 1 and 100, both inclusive?",
+			ExpectedTokens: TokenRange{Min: 20, Max: 250},
 		},
 		{
-			Name:         "factual-question",
-			SystemPrompt: "You are a knowledgeable history expert.",
-			UserPrompt:   "What was the significance of Toledo, Spain during the medieval period, particularly regarding the translation movement?",
+			Name:           "factual-question",
+			SystemPrompt:   "You are a knowledgeable history expert.",
+			UserPrompt:     "What was the significance of Toledo, Spain during the medieval period, particularly regarding the translation movement?",
+			ExpectedTokens: TokenRange{Min: 150, Max: 600},
 		},
 		{
-			Name:         "code-generation",
-			SystemPrompt: "You are a Go programming expert.",
-			UserPrompt:   "Write a Go function that calculates the Fibonacci sequence using recursion.",
+			Name:           "code-generation",
+			SystemPrompt:   "You are a Go programming expert.",
+			UserPrompt:     "Write a Go function that calculates the Fibonacci sequence using recursion, in a complete program whose main function prints the 10th Fibonacci number.",
+			ExpectedTokens: TokenRange{Min: 80, Max: 500},
+			CodeLanguage:   "go",
+			ExpectedOutput: "55",
 		},
 		// Tool-assisted test cases
 		{
 			Name:         "calculator-reasoning",
 			SystemPrompt: "You are a helpful assistant with access to a calculator tool. Use the calculator for all arithmetic operations.",
 			UserPrompt:   "Calculate (125 * 47) + (980 / 20) - 156. Break down each step and use the calculator tool for each operation. Then explain the final result.",
+			ExpectedTool: "calculator",
+			ExpectedArgs: map[string]any{"operation": "multiply", "a": 125, "b": 47},
 		},
 		{
 			Name:         "code-validation",
 			SystemPrompt: "You are a helpful coding assistant with access to a Python code executor. Always execute code to verify correctness.",
 			UserPrompt:   "Write Python code to generate the first 10 Fibonacci numbers, then execute it to verify correctness.",
+			// The generated code differs on each run, only the tool is checked
+			ExpectedTool: "execute_python",
 		},
 		{
 			Name:         "api-data-retrieval",
 			SystemPrompt: "You are a helpful assistant with access to web APIs. Use the HTTP client to fetch real-time data.",
 			UserPrompt:   "Use the HTTP client to fetch information about repository 'testcontainers-go' from GitHub API (https://api.github.com/repos/testcontainers/testcontainers-go) and summarize the key details.",
+			ExpectedTool: "http_get",
+			ExpectedArgs: map[string]any{"url": "https://api.github.com/repos/testcontainers/testcontainers-go"},
+		},
+		{
+			Name:         "currency-conversion",
+			SystemPrompt: "You are a helpful financial assistant with access to a currency converter and a calculator. Use the tools for all conversions and arithmetic operations.",
+			UserPrompt:   "Convert 100 USD to EUR, then tell me whether the result is more than 90 EUR and by how much.",
+			ExpectedTool: "currency",
+			ExpectedArgs: map[string]any{"amount": 100, "from": "USD", "to": "EUR"},
 		},
 	}
 
 	// Temperatures to test with each test case
 	temperatures = []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+	// Seed forwarded to the models for reproducible outputs (nil if BENCH_SEED is not set)
+	benchSeed *int
+
+	// Weight of the token efficiency in the composite score, see BENCH_TOKEN_EFFICIENCY_WEIGHT
+	tokenEfficiencyWeight = DefaultTokenEfficiencyWeight
+
+	// Classifier of the empty and refusal responses, see BENCH_REFUSAL_PATTERNS_FILE
+	refusalDetector, _ = NewRefusalDetector(DefaultRefusalPatterns)
+
+	// Criteria of the evaluator, read from BENCH_CRITERIA_DIR if set, or the embedded ones
+	evaluationCriteria = evaluator.GetCriteria()
 )
 
-// getModelsToTest returns the list of models to benchmark
-// If OPENAI_API_KEY is set, it includes OpenAI models at the beginning
-func getModelsToTest() []ModelConfig {
+// getBenchSeed returns the seed defined by the BENCH_SEED environment variable, or nil if it's not set
+func getBenchSeed() (*int, error) {
+	value := os.Getenv("BENCH_SEED")
+	if value == "" {
+		return nil, nil
+	}
+
+	seed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BENCH_SEED %q: %w", value, err)
+	}
+
+	return &seed, nil
+}
+
+// getEvaluationCriteria returns the criteria read from the directory defined by the BENCH_CRITERIA_DIR
+// environment variable, falling back to the embedded ones for the missing files, or nil if it's not set
+func getEvaluationCriteria() (map[string]evaluator.Criteria, error) {
+	dir := os.Getenv("BENCH_CRITERIA_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	criteria, err := evaluator.LoadCriteriaFromDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BENCH_CRITERIA_DIR %q: %w", dir, err)
+	}
+
+	return criteria, nil
+}
+
+// getTestCases returns the test cases read from the directory defined by the BENCH_TESTCASES_DIR
+// environment variable, merged with the built-in ones, or replacing them if BENCH_TESTCASES_REPLACE
+// is set. It returns nil if BENCH_TESTCASES_DIR is not set.
+func getTestCases() ([]TestCase, error) {
+	dir := os.Getenv("BENCH_TESTCASES_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	replace, err := getEnvBool("BENCH_TESTCASES_REPLACE")
+	if err != nil {
+		return nil, err
+	}
+
+	custom, err := LoadTestCasesFromDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BENCH_TESTCASES_DIR %q: %w", dir, err)
+	}
+
+	if replace {
+		return custom, nil
+	}
+	return mergeTestCases(testCases, custom), nil
+}
+
+// getModelsToTest returns the list of models to benchmark, read from the manifest file defined
+// by the BENCH_MODELS_FILE environment variable if set, see LoadModelsManifest.
+// Otherwise, if OPENAI_API_KEY is set, it includes OpenAI models at the beginning of the defaults
+func getModelsToTest() ([]ModelConfig, error) {
+	if path := os.Getenv("BENCH_MODELS_FILE"); path != "" {
+		manifestModels, err := LoadModelsManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BENCH_MODELS_FILE %q: %w", path, err)
+		}
+
+		logger.Info("📋 Using models from manifest", "file", path, "models", len(manifestModels))
+		return manifestModels, nil
+	}
+
 	var allModels []ModelConfig
 
 	// Check if OpenAI API key is available and add OpenAI model first
 	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
-		fmt.Println("🔑 OpenAI API key detected - adding OpenAI models to benchmark suite")
+		logger.Info("🔑 OpenAI API key detected - adding OpenAI models to benchmark suite")
 		allModels = append(allModels, ModelConfig{
 			Namespace:   "openai",
 			Name:        "gpt-5.1",
@@ -105,7 +221,7 @@ func getModelsToTest() []ModelConfig {
 			ExternalURL: "https://api.openai.com/v1",
 		})
 	} else {
-		fmt.Println("ℹ️  No OPENAI_API_KEY found - skipping OpenAI models (set OPENAI_API_KEY to include OpenAI models)")
+		logger.Info("ℹ️  No OPENAI_API_KEY found - skipping OpenAI models (set OPENAI_API_KEY to include OpenAI models)")
 	}
 
 	// Add local models after OpenAI
@@ -137,31 +253,39 @@ func getModelsToTest() []ModelConfig {
 	}
 
 	allModels = append(allModels, localModels...)
-	return allModels
+	return allModels, nil
 }
 
-// BenchmarkResult stores benchmark results for a single test
-type BenchmarkResult struct {
-	Model            string
-	TestCase         string
-	Temp             float64
-	Latency          time.Duration // Total turnaround time (TAT)
-	TTFT             time.Duration // Time To First Token (measured via streaming)
-	PromptEvalTime   time.Duration // Time to evaluate prompt (from model metadata if available)
-	PromptTokens     int           // Input tokens
-	CompletionTokens int           // Output tokens generated
-	TotalTokens      int           // Total tokens (prompt + completion)
-	Success          bool
-	EvalScore        float64 // Score from evaluator agent (0.0-1.0)
-	EvalResponse     string  // "yes", "no", or "unsure"
-	EvalReason       string  // Reasoning from evaluator
-	ResponseContent  string  // The actual LLM response content
-	// Tool calling metrics (only populated for tool-assisted test cases)
-	ToolCallCount         int     // Number of tool calls made
-	ToolIterationCount    int     // Number of LLM-tool iterations
-	ToolParamAccuracy     float64 // Tool parameter extraction accuracy (0.0-1.0)
-	ToolSelectionAccuracy float64 // Tool selection accuracy (0.0-1.0)
-	ToolConvergence       float64 // Convergence score (1.0 = optimal path)
+// BenchmarkResult stores benchmark results for a single test, exported to results.json
+type BenchmarkResult = benchresult.Result
+
+// measureColdStart sends the first request to the model and records its latency as the cold start,
+// as it includes the model loading time. The host memory is sampled around the request, external
+// models and the ones of a remote Docker Model Runner are not loaded locally.
+func measureColdStart(ctx context.Context, client *llmclient.Client, model ModelConfig) (*llmclient.Response, error) {
+	var resp *llmclient.Response
+	coldStart := func() error {
+		var err error
+		resp, err = client.GenerateWithTemp(ctx, "cold-start", "You are a helpful assistant.", "Say hello.", 0.1)
+		return err
+	}
+
+	if model.IsExternal || isRemoteModelRunner() {
+		if err := coldStart(); err != nil {
+			return nil, err
+		}
+	} else {
+		memoryMB, sampled, err := MeasureModelMemory(NewHostMemorySampler(), coldStart)
+		if err != nil {
+			return nil, err
+		}
+		if sampled {
+			metricsCollector.RecordModelMemory(model.FQName, memoryMB)
+		}
+	}
+
+	metricsCollector.RecordColdStart(model.FQName, resp.Latency)
+	return resp, nil
 }
 
 // BenchmarkLLMs runs benchmarks for all models and test cases
@@ -175,8 +299,12 @@ func BenchmarkLLMs(b *testing.B) {
 		if !model.IsExternal {
 			// Pull the model before benchmarking
 			b.Run(fmt.Sprintf("Pull/%s", model.Name), func(b *testing.B) {
+				if err := preflightPull(ctx, model); err != nil {
+					b.Fatalf("Failed the preflight of model %s: %v", modelName, err)
+				}
+
 				b.ResetTimer()
-				if err := getDMRContainer().PullModel(ctx, modelName); err != nil {
+				if err := pullModel(ctx, modelName); err != nil {
 					b.Fatalf("Failed to pull model %s: %v", modelName, err)
 				}
 			})
@@ -187,30 +315,87 @@ func BenchmarkLLMs(b *testing.B) {
 		if model.IsExternal {
 			endpoint = model.ExternalURL
 		} else {
-			endpoint = getDMRContainer().OpenAIEndpoint()
+			endpoint = modelRunner.OpenAIEndpoint()
 		}
 
-		client, err := llmclient.NewClient(endpoint, modelName)
+		clientOpts := []llmclient.ClientOption{llmclient.WithRefusalDetector(refusalDetector.IsRefusal)}
+		client, err := llmclient.NewClient(endpoint, modelName, clientOpts...)
 		if err != nil {
 			b.Fatalf("Failed to create client for %s: %v", modelName, err)
 		}
 
+		// Measure the cold start before any other request, so it captures the model loading time
+		b.Run(fmt.Sprintf("ColdStart/%s", model.Name), func(b *testing.B) {
+			defer recoverBenchmark(b)
+
+			// The warmup in TestMain may have already measured it
+			if _, measured := metricsCollector.ColdStart(modelName); !measured {
+				if _, err := measureColdStart(ctx, client, model); err != nil {
+					b.Fatalf("Failed to measure cold start for %s: %v", modelName, err)
+				}
+			}
+
+			coldStartMs, _ := metricsCollector.ColdStart(modelName)
+			b.ReportMetric(coldStartMs, "cold_start_ms")
+			if memoryMB, sampled := metricsCollector.ModelMemory(modelName); sampled {
+				b.ReportMetric(memoryMB, "model_memory_mb")
+			}
+		})
+
+		// Detect the features the model honors, to skip the test cases it cannot run
+		caps, err := llmclient.ProbeCapabilities(ctx, client)
+		if err != nil {
+			b.Fatalf("Failed to probe capabilities of %s: %v", modelName, err)
+		}
+		logger.Info("🔎 Model capabilities", "model", modelName, "tools", caps.Tools, "json_mode", caps.JSONMode, "system_role", caps.SystemRole)
+		if !model.SystemPromptSupported(caps.SystemRole) {
+			// Sending the instructions in the user message keeps the comparison fair for the models
+			// ignoring the system role
+			logger.Warn("⚠️  The model doesn't support system prompts, prepending them to the user messages", "model", modelName)
+			client, err = llmclient.NewClient(endpoint, modelName, append(clientOpts, llmclient.WithSystemPrompt(false))...)
+			if err != nil {
+				b.Fatalf("Failed to create client for %s: %v", modelName, err)
+			}
+		}
+
 		// Benchmark each test case with each temperature
 		for _, tc := range testCases {
+			if isToolAssistedCase(tc.Name) && !caps.Tools {
+				logger.Warn("⏭️  Skipping tool-assisted test case: the model does not support tool calls", "model", modelName, "case", tc.Name)
+				continue
+			}
+
 			for _, temp := range temperatures {
 				benchName := fmt.Sprintf("%s/%s/temp%.1f", model.Name, tc.Name, temp)
 
 				b.Run(benchName, func(b *testing.B) {
+					defer recoverBenchmark(b)
+
 					results := make([]BenchmarkResult, 0, b.N)
 
 					b.ResetTimer()
-					for i := 0; i < b.N; i++ {
-						var result BenchmarkResult
-						// Route to appropriate function based on test case type
-						if isToolAssistedCase(tc.Name) {
-							result = runSingleBenchmarkWithTools(ctx, client, modelName, tc, temp)
-						} else {
-							result = runSingleBenchmark(ctx, client, modelName, tc, temp)
+					// Only the generation is timed, the evaluation and the bookkeeping would pollute ns/op
+					runTimedIterations(b, b.N, func(i int) BenchmarkResult {
+						result, err := runRecovered(func() BenchmarkResult {
+							// Route to appropriate function based on test case type
+							if isToolAssistedCase(tc.Name) {
+								return runSingleBenchmarkWithTools(ctx, client, modelName, tc, temp)
+							}
+							return runSingleBenchmark(ctx, client, modelName, tc, temp)
+						})
+						if err != nil {
+							// A crashing iteration counts as a failure in the success rate
+							logger.Error("💥 Benchmark iteration panicked, counting it as failed", "model", modelName, "case", tc.Name, "temperature", temp, "error", err)
+							metricsCollector.LogBenchmarkError(ctx, modelName, tc.Name, temp, err)
+							return BenchmarkResult{Model: modelName, TestCase: tc.Name, Temp: temp}
+						}
+						return result
+					}, func(i int, result BenchmarkResult) {
+						if _, err := runRecovered(func() BenchmarkResult {
+							evaluateBenchmarkResult(ctx, &result, tc)
+							return result
+						}); err != nil {
+							logger.Error("💥 Evaluation panicked, keeping the result unscored", "model", modelName, "case", tc.Name, "temperature", temp, "error", err)
 						}
 						results = append(results, result)
 
@@ -222,9 +407,20 @@ func BenchmarkLLMs(b *testing.B) {
 							metricsCollector.RecordTTFT(ctx, result.TTFT, modelName, tc.Name, temp)
 						}
 
-						// Record prompt evaluation time with OpenTelemetry
+						// Record prompt evaluation time with OpenTelemetry, and to measure the prompt cache
 						if result.PromptEvalTime > 0 {
 							metricsCollector.RecordPromptEvalTime(ctx, result.PromptEvalTime, modelName, tc.Name, temp)
+							metricsCollector.RecordPromptEval(modelName, tc.Name, tc.SystemPrompt, result.PromptEvalTime)
+						}
+
+						// Record inter-token latency with OpenTelemetry
+						if result.InterTokenP50 > 0 {
+							metricsCollector.RecordInterTokenLatency(ctx, result.InterTokenP50, result.InterTokenP95, modelName, tc.Name, temp)
+						}
+
+						// Record the output length with OpenTelemetry
+						if result.CompletionTokens > 0 {
+							metricsCollector.RecordCompletionTokens(ctx, result.CompletionTokens, modelName, tc.Name, temp)
 						}
 
 						if result.Success {
@@ -247,17 +443,30 @@ func BenchmarkLLMs(b *testing.B) {
 								metricsCollector.UpdateGPUMetrics(modelName, tc.Name, temp, gpuMetrics.Utilization, gpuMetrics.MemoryUsed)
 							}
 						}
-					}
+					})
 					b.StopTimer()
 
+					// Keep the results of the last run of the benchmark, the one reported, to export them
+					resultsRecorder.Record(b.Name(), results)
+
 					// Calculate and report aggregate metrics
 					reportAggregateMetrics(b, results)
 
+					// The system prompt stays in the KV cache across iterations and temperatures
+					if speedup, ok := metricsCollector.PromptCacheSpeedup(modelName, tc.SystemPrompt); ok {
+						b.ReportMetric(speedup, "prompt_cache_speedup")
+					}
+
 					// Calculate ns/op from Go benchmark framework
 					nsPerOp := float64(b.Elapsed().Nanoseconds()) / float64(b.N)
 
 					// Update OpenTelemetry gauges with model/case/temp labels
-					updateGauges(modelName, tc.Name, temp, results, nsPerOp)
+					agg := updateGauges(modelName, tc.Name, temp, results, nsPerOp)
+
+					// Print the result as soon as the combination completes, see BENCH_PROGRESS
+					if len(results) > 0 {
+						progress.Report(agg)
+					}
 				})
 			}
 		}
@@ -266,36 +475,32 @@ func BenchmarkLLMs(b *testing.B) {
 
 // runSingleBenchmark executes a single benchmark iteration
 func runSingleBenchmark(ctx context.Context, client *llmclient.Client, model string, tc TestCase, temp float64) BenchmarkResult {
-	resp, err := client.GenerateWithTemp(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, temp)
+	resp, err := client.GenerateWithOptions(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, llmclient.GenerateOptions{
+		Temperature: temp,
+		Seed:        benchSeed,
+	})
 
 	result := BenchmarkResult{
-		Model:    model,
-		TestCase: tc.Name,
-		Temp:     temp,
-		Success:  err == nil,
+		Model:      model,
+		TestCase:   tc.Name,
+		Temp:       temp,
+		UserPrompt: tc.UserPrompt,
+		Success:    err == nil,
 	}
 
 	if err == nil {
 		result.Latency = resp.Latency
 		result.TTFT = resp.TTFT
 		result.PromptEvalTime = resp.PromptEvalTime
+		result.InterTokenP50 = resp.InterTokenP50
+		result.InterTokenP95 = resp.InterTokenP95
+		result.InterTokenGaps = resp.InterTokenGaps
 		result.PromptTokens = resp.PromptTokens
 		result.CompletionTokens = resp.CompletionTokens
 		result.TotalTokens = resp.TotalTokens
 		result.ResponseContent = resp.Content
-
-		// Evaluate the response using the evaluator agent
-		if evaluatorAgent != nil {
-			evalResult, evalErr := evaluateResponse(ctx, model, temp, tc.Name, tc.UserPrompt, resp.Content)
-			if evalErr == nil {
-				result.EvalScore = evalResult.Score
-				result.EvalResponse = evalResult.Response
-				result.EvalReason = evalResult.Reason
-			} else {
-				// Log evaluation error to OTel backend instead of stdout
-				metricsCollector.LogEvaluationError(ctx, model, tc.Name, temp, evalErr)
-			}
-		}
+		result.Refusal = resp.Refusal
+		result.TokenEfficiency = TokenEfficiency(resp.CompletionTokens, tc.ExpectedTokenRange())
 	} else {
 		// Log error to OTel backend instead of stdout
 		metricsCollector.LogBenchmarkError(ctx, model, tc.Name, temp, err)
@@ -306,7 +511,7 @@ func runSingleBenchmark(ctx context.Context, client *llmclient.Client, model str
 
 // isToolAssistedCase checks if a test case requires tool calling
 func isToolAssistedCase(name string) bool {
-	toolCases := []string{"calculator-reasoning", "code-validation", "api-data-retrieval"}
+	toolCases := []string{"calculator-reasoning", "code-validation", "api-data-retrieval", "currency-conversion"}
 	for _, tc := range toolCases {
 		if tc == name {
 			return true
@@ -324,6 +529,8 @@ func getToolsForCase(name string) []llms.Tool {
 		return []llms.Tool{llmclient.GetCodeExecutorTool()}
 	case "api-data-retrieval":
 		return []llms.Tool{llmclient.GetHTTPClientTool()}
+	case "currency-conversion":
+		return []llms.Tool{llmclient.GetCurrencyTool(), llmclient.GetCalculatorTool()}
 	default:
 		return nil
 	}
@@ -337,10 +544,11 @@ func runSingleBenchmarkWithTools(ctx context.Context, client *llmclient.Client,
 	resp, err := client.GenerateWithTools(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, temp, tools, maxIterations)
 
 	result := BenchmarkResult{
-		Model:    model,
-		TestCase: tc.Name,
-		Temp:     temp,
-		Success:  err == nil,
+		Model:      model,
+		TestCase:   tc.Name,
+		Temp:       temp,
+		UserPrompt: tc.UserPrompt,
+		Success:    err == nil,
 	}
 
 	if err == nil {
@@ -351,32 +559,13 @@ func runSingleBenchmarkWithTools(ctx context.Context, client *llmclient.Client,
 		result.CompletionTokens = resp.CompletionTokens
 		result.TotalTokens = resp.TotalTokens
 		result.ResponseContent = resp.Content
+		result.Refusal = resp.Refusal
+		result.TokenEfficiency = TokenEfficiency(resp.CompletionTokens, tc.ExpectedTokenRange())
 
 		// Populate tool metrics
 		result.ToolCallCount = len(resp.ToolCalls)
 		result.ToolIterationCount = resp.Iterations
-
-		// Evaluate the response using the evaluator agent
-		if evaluatorAgent != nil {
-			evalResult, evalErr := evaluateResponse(ctx, model, temp, tc.Name, tc.UserPrompt, resp.Content)
-			if evalErr == nil {
-				result.EvalScore = evalResult.Score
-				result.EvalResponse = evalResult.Response
-				result.EvalReason = evalResult.Reason
-			} else {
-				fmt.Printf("⚠️  Evaluation error for %s/%s/temp%.1f: %v\n", model, tc.Name, temp, evalErr)
-			}
-
-			// Evaluate tool parameter extraction accuracy
-			toolEvalResult, toolEvalErr := evaluateToolCalls(ctx, model, temp, tc.Name, tc.UserPrompt, resp.Content)
-			if toolEvalErr == nil {
-				result.ToolParamAccuracy = toolEvalResult.ParameterAccuracy
-				result.ToolSelectionAccuracy = toolEvalResult.ToolSelectionScore
-			} else {
-				// Log tool evaluation error to OTel backend instead of stdout
-				metricsCollector.LogToolEvaluationError(ctx, model, tc.Name, temp, toolEvalErr)
-			}
-		}
+		result.ToolResults = resp.ToolCalls
 	} else {
 		// Log error to OTel backend instead of stdout
 		metricsCollector.LogBenchmarkError(ctx, model, tc.Name, temp, err)
@@ -385,10 +574,83 @@ func runSingleBenchmarkWithTools(ctx context.Context, client *llmclient.Client,
 	return result
 }
 
+// evaluateBenchmarkResult scores a successful response with the evaluator agent, if any, and the
+// tool calls of the tool-assisted test cases. It runs out of the timed region of the benchmark.
+func evaluateBenchmarkResult(ctx context.Context, result *BenchmarkResult, tc TestCase) {
+	if evaluatorAgent == nil || !result.Success {
+		return
+	}
+
+	model, temp := result.Model, result.Temp
+
+	evalResult, evalErr := evaluateResponse(ctx, model, temp, tc.Name, tc.UserPrompt, result.ResponseContent)
+	if evalErr == nil {
+		result.EvalScore = evalResult.Score
+		result.EvalResponse = evalResult.Response
+		result.EvalReason = evalResult.Reason
+		result.EvalCategory = evalResult.Category
+	} else if isToolAssistedCase(tc.Name) {
+		logger.Warn("⚠️  Evaluation error", "model", model, "case", tc.Name, "temperature", temp, "error", evalErr)
+	} else {
+		// Log evaluation error to OTel backend instead of stdout
+		metricsCollector.LogEvaluationError(ctx, model, tc.Name, temp, evalErr)
+	}
+	if evalErr != nil {
+		// Kept in the exported results, to re-score them with cmd/score
+		result.EvalError = evalErr.Error()
+	}
+
+	if tc.CodeLanguage != "" {
+		evaluateCodeExecution(ctx, result, tc, evalErr == nil)
+	}
+
+	if !isToolAssistedCase(tc.Name) {
+		return
+	}
+
+	// Evaluate tool parameter extraction accuracy, against the expected call if the test case defines it
+	var toolEvalResult *evaluator.ToolEvaluationResult
+	var toolEvalErr error
+	if tc.ExpectedTool != "" {
+		toolEvalResult, toolEvalErr = evaluateToolUse(ctx, tc, result.ToolResults)
+	} else {
+		toolEvalResult, toolEvalErr = evaluateToolCalls(ctx, model, temp, tc.Name, tc.UserPrompt, result.ResponseContent)
+	}
+	if toolEvalErr == nil {
+		result.ToolParamAccuracy = toolEvalResult.ParameterAccuracy
+		result.ToolSelectionAccuracy = toolEvalResult.ToolSelectionScore
+	} else {
+		// Log tool evaluation error to OTel backend instead of stdout
+		metricsCollector.LogToolEvaluationError(ctx, model, tc.Name, temp, toolEvalErr)
+	}
+}
+
+// codeRunner runs the code blocks of the responses, see evaluateCodeExecution
+var codeRunner evaluator.CodeRunner = tools.NewCodeExecutorWithTimeout(2 * time.Minute)
+
+// evaluateCodeExecution runs the code blocks of the response, averaging whether they run and print the
+// expected output with the judge's score, if judged, so the score reflects whether the code works.
+func evaluateCodeExecution(ctx context.Context, result *BenchmarkResult, tc TestCase, judged bool) {
+	execResult, err := evaluator.EvaluateCodeExecution(ctx, codeRunner, tc.CodeLanguage, result.ResponseContent, tc.ExpectedOutput)
+	if err != nil {
+		metricsCollector.LogEvaluationError(ctx, result.Model, tc.Name, result.Temp, fmt.Errorf("code execution: %w", err))
+		return
+	}
+
+	result.CodeExecutionScore = execResult.Score
+	if !judged {
+		result.EvalScore = execResult.Score
+		result.EvalReason = execResult.Reason
+		return
+	}
+
+	result.EvalScore = (result.EvalScore + execResult.Score) / 2
+	result.EvalReason = strings.TrimSpace(result.EvalReason + " " + execResult.Reason)
+}
+
 // evaluateResponse uses the evaluator agent to assess response quality
 func evaluateResponse(ctx context.Context, model string, temperature float64, testCaseName string, question string, answer string) (*evaluator.EvaluationResult, error) {
-	criteria := evaluator.GetCriteria()
-	evalCriteria, ok := criteria[testCaseName]
+	evalCriteria, ok := evaluationCriteria[testCaseName]
 	if !ok {
 		return nil, fmt.Errorf("no evaluation criteria found for test case: %s", testCaseName)
 	}
@@ -402,8 +664,7 @@ func evaluateResponse(ctx context.Context, model string, temperature float64, te
 
 // evaluateToolCalls uses the evaluator agent to assess tool calling accuracy
 func evaluateToolCalls(ctx context.Context, model string, temperature float64, testCaseName string, question string, answer string) (*evaluator.ToolEvaluationResult, error) {
-	criteria := evaluator.GetCriteria()
-	evalCriteria, ok := criteria[testCaseName]
+	evalCriteria, ok := evaluationCriteria[testCaseName]
 	if !ok {
 		return nil, fmt.Errorf("no tool evaluation criteria found for test case: %s", testCaseName)
 	}
@@ -415,344 +676,332 @@ func evaluateToolCalls(ctx context.Context, model string, temperature float64, t
 	return agent.EvaluateToolCalls(ctx, model, temperature, testCaseName, question, answer, evalCriteria.Reference)
 }
 
-// reportAggregateMetrics calculates and reports aggregate metrics
-func reportAggregateMetrics(b *testing.B, results []BenchmarkResult) {
+// evaluateToolUse scores the tool calls made by the model against the expected call of the test case
+func evaluateToolUse(ctx context.Context, tc TestCase, toolResults []llmclient.ToolResult) (*evaluator.ToolEvaluationResult, error) {
+	evalCriteria, ok := evaluationCriteria[evaluator.ToolSelectionCriteria]
+	if !ok {
+		return nil, fmt.Errorf("no evaluation criteria found for %s", evaluator.ToolSelectionCriteria)
+	}
+
+	toolCalls := make([]llms.ToolCall, 0, len(toolResults))
+	for _, r := range toolResults {
+		toolCalls = append(toolCalls, llms.ToolCall{
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: r.ToolName, Arguments: r.Input},
+		})
+	}
+
+	// The judge only decides on the arguments not matching the expected ones literally
+	agent := evaluator.NewAgent(evaluatorAgent, evalCriteria.SystemPrompt)
+
+	return agent.EvaluateToolUse(ctx, tc.UserPrompt, toolCalls, tc.ExpectedTool, tc.ExpectedArgs)
+}
+
+// computeAggregates calculates the aggregate metrics of the results of a model/case/temp combination,
+// shared by the Go benchmark metrics and the OpenTelemetry gauges so both always agree. The latency
+// metrics are in milliseconds, and only the successful results count, but for the success rate.
+// The identity of the combination and the ns/op are left to the caller.
+func computeAggregates(results []BenchmarkResult) AggregateMetrics {
+	var agg AggregateMetrics
 	if len(results) == 0 {
-		return
+		return agg
 	}
 
-	// Calculate latency percentiles
 	latencies := make([]float64, 0, len(results))
 	ttfts := make([]float64, 0, len(results))
 	promptEvalTimes := make([]float64, 0, len(results))
+	var interTokenGaps []float64
 	totalPromptTokens := 0
 	totalCompletionTokens := 0
 	totalTurnaroundTimeMs := 0.0
 	totalGenerationTimeMs := 0.0
 	successCount := 0
-	totalEvalScore := 0.0
-	evalCount := 0
-	evalYesCount := 0
-	evalNoCount := 0
-	evalUnsureCount := 0
+	refusalCount := 0
+	// Tool metrics
+	totalToolCalls := 0
+	totalToolIterations := 0
+	totalToolParamAccuracy := 0.0
+	totalToolSelectionAccuracy := 0.0
+	toolMetricsCount := 0
 
 	for _, r := range results {
-		if r.Success {
-			// Store in milliseconds to match histogram metrics
-			latencies = append(latencies, float64(r.Latency.Milliseconds()))
-			if r.TTFT > 0 {
-				ttfts = append(ttfts, float64(r.TTFT.Milliseconds()))
-			}
-			if r.PromptEvalTime > 0 {
-				promptEvalTimes = append(promptEvalTimes, float64(r.PromptEvalTime.Milliseconds()))
-			}
-			totalPromptTokens += r.PromptTokens
-			totalCompletionTokens += r.CompletionTokens
-			totalTurnaroundTimeMs += float64(r.Latency.Milliseconds())
-
-			// Generation time = Total time - TTFT (more accurate than using PromptEvalTime)
-			generationTime := r.Latency - r.TTFT
-			if generationTime > 0 {
-				totalGenerationTimeMs += float64(generationTime.Milliseconds())
-			}
-
-			successCount++
-
-			// Track evaluation scores if available
-			if r.EvalResponse != "" {
-				totalEvalScore += r.EvalScore
-				evalCount++
-
-				// Count response types
-				switch r.EvalResponse {
-				case "yes":
-					evalYesCount++
-				case "no":
-					evalNoCount++
-				case "unsure":
-					evalUnsureCount++
-				}
-			}
+		if !r.Success {
+			continue
 		}
-	}
 
-	if len(latencies) == 0 {
-		// No successful results - report zeros for metrics except success_rate
-		successRate := float64(successCount) / float64(len(results))
-		b.ReportMetric(0, "latency_p50_ms")
-		b.ReportMetric(0, "latency_p95_ms")
-		b.ReportMetric(0, "prompt_eval_p50_ms")
-		b.ReportMetric(0, "prompt_eval_p95_ms")
-		b.ReportMetric(0, "tokens_per_op")
-		b.ReportMetric(successRate, "success_rate")
-		b.ReportMetric(0, "eval_score")
-		b.ReportMetric(0, "eval_pass_rate")
-		b.ReportMetric(0, "tokens_per_sec")
-		b.ReportMetric(0, "output_tokens_per_sec")
-		return
-	}
-
-	sort.Float64s(latencies)
+		// Store in milliseconds to match histogram metrics
+		latencies = append(latencies, float64(r.Latency.Milliseconds()))
+		if r.TTFT > 0 {
+			ttfts = append(ttfts, float64(r.TTFT.Milliseconds()))
+		}
+		if r.PromptEvalTime > 0 {
+			promptEvalTimes = append(promptEvalTimes, float64(r.PromptEvalTime.Milliseconds()))
+		}
+		for _, gap := range r.InterTokenGaps {
+			interTokenGaps = append(interTokenGaps, float64(gap)/float64(time.Millisecond))
+		}
+		totalPromptTokens += r.PromptTokens
+		totalCompletionTokens += r.CompletionTokens
+		totalTurnaroundTimeMs += float64(r.Latency.Milliseconds())
+
+		// Generation time = Total time - TTFT (more accurate than using PromptEvalTime)
+		generationTime := r.Latency - r.TTFT
+		if generationTime > 0 {
+			totalGenerationTimeMs += float64(generationTime.Milliseconds())
+		}
 
-	p50 := percentile(latencies, 50)
-	p95 := percentile(latencies, 95)
+		successCount++
+		if r.Refusal {
+			refusalCount++
+		}
 
-	// Calculate TTFT percentiles
-	ttftP50 := 0.0
-	ttftP95 := 0.0
-	if len(ttfts) > 0 {
-		sort.Float64s(ttfts)
-		ttftP50 = percentile(ttfts, 50)
-		ttftP95 = percentile(ttfts, 95)
+		// Track tool metrics
+		if r.ToolCallCount > 0 || r.ToolIterationCount > 0 {
+			totalToolCalls += r.ToolCallCount
+			totalToolIterations += r.ToolIterationCount
+			totalToolParamAccuracy += r.ToolParamAccuracy
+			totalToolSelectionAccuracy += r.ToolSelectionAccuracy
+			toolMetricsCount++
+		}
 	}
 
-	// Calculate prompt eval time percentiles
-	promptEvalP50 := 0.0
-	promptEvalP95 := 0.0
-	if len(promptEvalTimes) > 0 {
-		sort.Float64s(promptEvalTimes)
-		promptEvalP50 = percentile(promptEvalTimes, 50)
-		promptEvalP95 = percentile(promptEvalTimes, 95)
+	agg.SuccessRate = float64(successCount) / float64(len(results))
+	agg.LatencySamples = len(latencies)
+	if successCount == 0 {
+		return agg
 	}
 
-	avgTotalTokens := float64(totalPromptTokens+totalCompletionTokens) / float64(successCount)
-	successRate := float64(successCount) / float64(len(results))
+	// Calculate latency, TTFT and prompt eval time percentiles
+	agg.LatencyP50, agg.LatencyP95 = sortedPercentiles(latencies)
+	agg.TTFTP50, agg.TTFTP95 = sortedPercentiles(ttfts)
+	agg.PromptEvalTimeP50, agg.PromptEvalTimeP95 = sortedPercentiles(promptEvalTimes)
+
+	// Calculate inter-token latency percentiles of the gaps pooled across the requests, so the
+	// p95 reflects the stutter of all the streamed tokens, not the one of a typical request
+	agg.InterTokenP50, agg.InterTokenP95 = sortedPercentiles(interTokenGaps)
+
+	agg.TokensPerOp = float64(totalPromptTokens+totalCompletionTokens) / float64(successCount)
+	agg.RefusalRate = float64(refusalCount) / float64(successCount)
 
 	// Calculate evaluator metrics
-	avgEvalScore := 0.0
-	evalPassRate := 0.0
-	if evalCount > 0 {
-		avgEvalScore = totalEvalScore / float64(evalCount)
-		evalPassRate = float64(evalYesCount) / float64(evalCount)
-	}
+	agg.EvalScore, agg.EvalPassRate = evalStats(results)
+	agg.CategoryRates = categoryRates(results)
+	agg.TokenEfficiency = tokenEfficiencyStats(results)
 
 	// Calculate TPS = (Input Tokens + Output Tokens) / Total Turnaround Time (TAT in seconds)
 	// This represents average TPS accounting for both input and output tokens
 	avgTurnaroundTimeSec := (totalTurnaroundTimeMs / float64(successCount)) / 1000.0
-	tokensPerSec := 0.0
 	if avgTurnaroundTimeSec > 0 {
-		tokensPerSec = avgTotalTokens / avgTurnaroundTimeSec
+		agg.TokensPerSec = agg.TokensPerOp / avgTurnaroundTimeSec
 	}
 
 	// Calculate Output TPS = Output Tokens / Time to Generate Output Tokens
 	// This specifically measures generation speed, excluding input processing
 	avgGenerationTimeSec := (totalGenerationTimeMs / float64(successCount)) / 1000.0
 	avgOutputTokens := float64(totalCompletionTokens) / float64(successCount)
-	outputTokensPerSec := 0.0
 	if avgGenerationTimeSec > 0 {
-		outputTokensPerSec = avgOutputTokens / avgGenerationTimeSec
+		agg.OutputTokensPerSec = avgOutputTokens / avgGenerationTimeSec
 	}
 
-	// Report custom metrics in milliseconds
-	b.ReportMetric(p50, "latency_p50_ms")
-	b.ReportMetric(p95, "latency_p95_ms")
-	b.ReportMetric(ttftP50, "ttft_p50_ms")
-	b.ReportMetric(ttftP95, "ttft_p95_ms")
-	b.ReportMetric(promptEvalP50, "prompt_eval_p50_ms")
-	b.ReportMetric(promptEvalP95, "prompt_eval_p95_ms")
-	b.ReportMetric(avgTotalTokens, "tokens_per_op")
-	b.ReportMetric(successRate, "success_rate")
-	b.ReportMetric(avgEvalScore, "eval_score")
-	b.ReportMetric(evalPassRate, "eval_pass_rate")
-	b.ReportMetric(tokensPerSec, "tokens_per_sec")
-	b.ReportMetric(outputTokensPerSec, "output_tokens_per_sec")
+	// Calculate tool metrics averages, zero for the results without tool calls
+	if toolMetricsCount > 0 {
+		agg.ToolCallCount = float64(totalToolCalls) / float64(toolMetricsCount)
+		agg.ToolIterationCount = float64(totalToolIterations) / float64(toolMetricsCount)
+		agg.ToolParamAccuracy = totalToolParamAccuracy / float64(toolMetricsCount)
+		agg.ToolSelectionAccuracy = totalToolSelectionAccuracy / float64(toolMetricsCount)
+		// Tool success rate = successful tool-assisted operations / total operations
+		agg.ToolSuccessRate = float64(toolMetricsCount) / float64(successCount)
+		agg.ToolConvergence = toolConvergence(results)
+	}
+
+	return agg
 }
 
-// updateGauges updates OpenTelemetry gauge metrics with model/case/temp labels
-func updateGauges(model, testCase string, temp float64, results []BenchmarkResult, nsPerOp float64) {
-	if len(results) == 0 {
-		return
+// sortedPercentiles sorts the values and returns their p50 and p95, zero if there are none
+func sortedPercentiles(values []float64) (p50, p95 float64) {
+	if len(values) == 0 {
+		return 0, 0
 	}
 
-	latencies := make([]float64, 0, len(results))
-	ttfts := make([]float64, 0, len(results))
-	promptEvalTimes := make([]float64, 0, len(results))
-	totalPromptTokens := 0
-	totalCompletionTokens := 0
-	totalTurnaroundTimeMs := 0.0
-	totalGenerationTimeMs := 0.0
-	successCount := 0
-	totalEvalScore := 0.0
-	evalCount := 0
-	evalYesCount := 0
-	// Tool metrics
-	totalToolCalls := 0
-	totalToolIterations := 0
-	totalToolParamAccuracy := 0.0
-	totalToolSelectionAccuracy := 0.0
-	toolMetricsCount := 0
+	sort.Float64s(values)
+	return stats.Percentile(values, 50), stats.Percentile(values, 95)
+}
 
+// toolConvergence measures how closely the agent follows the optimal path:
+// S_optimal = minimum tool calls across all runs
+// Convergence = (Σ min(1, S_optimal / S_agent,i)) / N
+func toolConvergence(results []BenchmarkResult) float64 {
+	sOptimal := -1
 	for _, r := range results {
-		if r.Success {
-			// Store in milliseconds to match histogram metrics
-			latencies = append(latencies, float64(r.Latency.Milliseconds()))
-			if r.TTFT > 0 {
-				ttfts = append(ttfts, float64(r.TTFT.Milliseconds()))
-			}
-			if r.PromptEvalTime > 0 {
-				promptEvalTimes = append(promptEvalTimes, float64(r.PromptEvalTime.Milliseconds()))
-			}
-			totalPromptTokens += r.PromptTokens
-			totalCompletionTokens += r.CompletionTokens
-			totalTurnaroundTimeMs += float64(r.Latency.Milliseconds())
-
-			// Generation time = Total time - TTFT (more accurate than using PromptEvalTime)
-			generationTime := r.Latency - r.TTFT
-			if generationTime > 0 {
-				totalGenerationTimeMs += float64(generationTime.Milliseconds())
-			}
-
-			successCount++
-
-			// Track evaluation scores
-			if r.EvalResponse != "" {
-				totalEvalScore += r.EvalScore
-				evalCount++
-				if r.EvalResponse == "yes" {
-					evalYesCount++
-				}
+		if r.Success && r.ToolCallCount > 0 {
+			if sOptimal == -1 || r.ToolCallCount < sOptimal {
+				sOptimal = r.ToolCallCount
 			}
+		}
+	}
+	if sOptimal <= 0 {
+		return 0
+	}
 
-			// Track tool metrics
-			if r.ToolCallCount > 0 || r.ToolIterationCount > 0 {
-				totalToolCalls += r.ToolCallCount
-				totalToolIterations += r.ToolIterationCount
-				totalToolParamAccuracy += r.ToolParamAccuracy
-				totalToolSelectionAccuracy += r.ToolSelectionAccuracy
-				toolMetricsCount++
+	totalConvergence := 0.0
+	convergenceCount := 0
+	for _, r := range results {
+		if r.Success && r.ToolCallCount > 0 {
+			// Convergence for this run: min(1, S_optimal / S_agent)
+			// If agent uses optimal or fewer calls: convergence = 1.0
+			// If agent uses more calls: convergence = S_optimal / S_agent (< 1.0)
+			convergenceScore := float64(sOptimal) / float64(r.ToolCallCount)
+			if convergenceScore > 1.0 {
+				convergenceScore = 1.0
 			}
+			totalConvergence += convergenceScore
+			convergenceCount++
 		}
 	}
 
-	if len(latencies) == 0 {
-		// No successful results - still update with correct success rate (which will be 0)
-		successRate := float64(successCount) / float64(len(results))
-		metricsCollector.UpdateAggregates(model, testCase, temp, 0, 0, 0, 0, 0, 0, successRate, 0, 0, 0, 0, 0, 0)
+	return totalConvergence / float64(convergenceCount)
+}
+
+// reportAggregateMetrics calculates and reports aggregate metrics
+func reportAggregateMetrics(b *testing.B, results []BenchmarkResult) {
+	if len(results) == 0 {
 		return
 	}
 
-	sort.Float64s(latencies)
-
-	p50 := percentile(latencies, 50)
-	p95 := percentile(latencies, 95)
-
-	// Calculate TTFT percentiles
-	ttftP50 := 0.0
-	ttftP95 := 0.0
-	if len(ttfts) > 0 {
-		sort.Float64s(ttfts)
-		ttftP50 = percentile(ttfts, 50)
-		ttftP95 = percentile(ttfts, 95)
+	agg := computeAggregates(results)
+	if agg.LatencySamples > 0 && stats.LowConfidencePercentile(agg.LatencySamples, 95) {
+		logger.Warn("⚠️  Low-confidence percentiles: the p95 is the maximum of too few samples, increase -benchtime",
+			"benchmark", b.Name(), "samples", agg.LatencySamples, "min_samples", stats.PercentileMinSamples(95))
 	}
 
-	// Calculate prompt eval time percentiles
-	promptEvalP50 := 0.0
-	promptEvalP95 := 0.0
-	if len(promptEvalTimes) > 0 {
-		sort.Float64s(promptEvalTimes)
-		promptEvalP50 = percentile(promptEvalTimes, 50)
-		promptEvalP95 = percentile(promptEvalTimes, 95)
+	// Report custom metrics in milliseconds
+	b.ReportMetric(agg.LatencyP50, "latency_p50_ms")
+	b.ReportMetric(agg.LatencyP95, "latency_p95_ms")
+	b.ReportMetric(agg.TTFTP50, "ttft_p50_ms")
+	b.ReportMetric(agg.TTFTP95, "ttft_p95_ms")
+	b.ReportMetric(agg.PromptEvalTimeP50, "prompt_eval_p50_ms")
+	b.ReportMetric(agg.PromptEvalTimeP95, "prompt_eval_p95_ms")
+	b.ReportMetric(agg.InterTokenP50, "inter_token_p50_ms")
+	b.ReportMetric(agg.InterTokenP95, "inter_token_p95_ms")
+	b.ReportMetric(agg.TokensPerOp, "tokens_per_op")
+	b.ReportMetric(agg.SuccessRate, "success_rate")
+	b.ReportMetric(agg.RefusalRate, "refusal_rate")
+	b.ReportMetric(agg.EvalScore, "eval_score")
+	b.ReportMetric(agg.EvalPassRate, "eval_pass_rate")
+	b.ReportMetric(agg.TokensPerSec, "tokens_per_sec")
+	b.ReportMetric(agg.OutputTokensPerSec, "output_tokens_per_sec")
+	b.ReportMetric(float64(agg.LatencySamples), "latency_samples")
+	b.ReportMetric(agg.TokenEfficiency, "token_efficiency")
+
+	// Tell apart why the answers fail, once the evaluator classified them
+	if evaluatorAgent != nil && agg.LatencySamples > 0 {
+		for _, category := range evaluator.Categories {
+			b.ReportMetric(agg.CategoryRates[category], categoryRateUnit(category))
+		}
 	}
 
-	avgTotalTokens := float64(totalPromptTokens+totalCompletionTokens) / float64(successCount)
-	successRate := float64(successCount) / float64(len(results))
+	// Weigh the quality of the responses against their cost, once the evaluator scored them
+	if evaluatorAgent != nil && agg.LatencySamples > 0 {
+		b.ReportMetric(CompositeScore(agg.EvalScore, agg.TokenEfficiency, tokenEfficiencyWeight), "composite_score")
+	}
+}
 
-	// Calculate evaluator metrics
-	avgEvalScore := 0.0
-	evalPassRate := 0.0
-	if evalCount > 0 {
-		avgEvalScore = totalEvalScore / float64(evalCount)
-		evalPassRate = float64(evalYesCount) / float64(evalCount)
+// tokenEfficiencyStats returns the average token efficiency of the successful results
+func tokenEfficiencyStats(results []BenchmarkResult) float64 {
+	total := 0.0
+	count := 0
+	for _, r := range results {
+		if r.Success {
+			total += r.TokenEfficiency
+			count++
+		}
 	}
 
-	// Calculate TPS = (Input Tokens + Output Tokens) / Total Turnaround Time (TAT in seconds)
-	avgTurnaroundTimeSec := (totalTurnaroundTimeMs / float64(successCount)) / 1000.0
-	tokensPerSec := 0.0
-	if avgTurnaroundTimeSec > 0 {
-		tokensPerSec = avgTotalTokens / avgTurnaroundTimeSec
+	if count == 0 {
+		return 0
 	}
 
-	// Calculate Output TPS = Output Tokens / Time to Generate Output Tokens
-	avgGenerationTimeSec := (totalGenerationTimeMs / float64(successCount)) / 1000.0
-	avgOutputTokens := float64(totalCompletionTokens) / float64(successCount)
-	outputTokensPerSec := 0.0
-	if avgGenerationTimeSec > 0 {
-		outputTokensPerSec = avgOutputTokens / avgGenerationTimeSec
+	return total / float64(count)
+}
+
+// updateGauges updates OpenTelemetry gauge metrics with model/case/temp labels, and returns the
+// aggregate metrics, zero without results
+func updateGauges(model, testCase string, temp float64, results []BenchmarkResult, nsPerOp float64) AggregateMetrics {
+	if len(results) == 0 {
+		return AggregateMetrics{}
 	}
 
-	// Calculate tool metrics averages
-	avgToolCallCount := 0.0
-	avgToolIterationCount := 0.0
-	avgToolParamAccuracy := 0.0
-	avgToolSelectionAccuracy := 0.0
-	avgToolConvergence := 0.0
-	toolSuccessRate := 1.0 // Default to 1.0 for non-tool cases
+	agg := computeAggregates(results)
+	agg.Model = model
+	agg.TestCase = testCase
+	agg.Temp = temp
+	// nsPerOp is passed in from the Go benchmark framework (b.Elapsed() / b.N)
+	agg.NsPerOp = nsPerOp
 
-	if toolMetricsCount > 0 {
-		avgToolCallCount = float64(totalToolCalls) / float64(toolMetricsCount)
-		avgToolIterationCount = float64(totalToolIterations) / float64(toolMetricsCount)
-		avgToolParamAccuracy = totalToolParamAccuracy / float64(toolMetricsCount)
-		avgToolSelectionAccuracy = totalToolSelectionAccuracy / float64(toolMetricsCount)
-		// Tool success rate = successful tool-assisted operations / total operations
-		toolSuccessRate = float64(toolMetricsCount) / float64(successCount)
-
-		// Calculate convergence score: measures how closely agent follows optimal path
-		// S_optimal = minimum tool calls across all runs
-		// Convergence = (Σ min(1, S_optimal / S_agent,i)) / N
-		sOptimal := -1
-		for _, r := range results {
-			if r.Success && r.ToolCallCount > 0 {
-				if sOptimal == -1 || r.ToolCallCount < sOptimal {
-					sOptimal = r.ToolCallCount
-				}
-			}
+	metricsCollector.SetAggregates(agg)
+
+	return agg
+}
+
+// evalStats returns the average evaluator score and the pass rate, the fraction of the responses
+// marked as "yes" by the evaluator. Only the successful, evaluated results are considered.
+// The pass rate is a hard signal, distinct from the average score: a model can score
+// well on average while rarely producing a fully correct answer.
+func evalStats(results []BenchmarkResult) (avgScore, passRate float64) {
+	totalScore := 0.0
+	evalCount := 0
+	passCount := 0
+
+	for _, r := range results {
+		if !r.Success || r.EvalResponse == "" {
+			continue
 		}
 
-		if sOptimal > 0 {
-			totalConvergence := 0.0
-			convergenceCount := 0
-			for _, r := range results {
-				if r.Success && r.ToolCallCount > 0 {
-					// Convergence for this run: min(1, S_optimal / S_agent)
-					// If agent uses optimal or fewer calls: convergence = 1.0
-					// If agent uses more calls: convergence = S_optimal / S_agent (< 1.0)
-					convergenceScore := float64(sOptimal) / float64(r.ToolCallCount)
-					if convergenceScore > 1.0 {
-						convergenceScore = 1.0
-					}
-					totalConvergence += convergenceScore
-					convergenceCount++
-				}
-			}
-			if convergenceCount > 0 {
-				avgToolConvergence = totalConvergence / float64(convergenceCount)
-			}
+		totalScore += r.EvalScore
+		evalCount++
+		if strings.EqualFold(strings.TrimSpace(r.EvalResponse), "yes") {
+			passCount++
 		}
 	}
 
-	// nsPerOp is passed in from the Go benchmark framework (b.Elapsed() / b.N)
-	// Check if this is a tool-assisted case
-	if isToolAssistedCase(testCase) {
-		metricsCollector.UpdateAggregatesWithToolMetrics(model, testCase, temp, p50, p95, ttftP50, ttftP95, promptEvalP50, promptEvalP95, successRate, avgTotalTokens, avgEvalScore, evalPassRate, tokensPerSec, outputTokensPerSec, nsPerOp, avgToolCallCount, avgToolIterationCount, toolSuccessRate, avgToolParamAccuracy, avgToolSelectionAccuracy, avgToolConvergence)
-	} else {
-		metricsCollector.UpdateAggregates(model, testCase, temp, p50, p95, ttftP50, ttftP95, promptEvalP50, promptEvalP95, successRate, avgTotalTokens, avgEvalScore, evalPassRate, tokensPerSec, outputTokensPerSec, nsPerOp)
+	if evalCount == 0 {
+		return 0, 0
 	}
+
+	return totalScore / float64(evalCount), float64(passCount) / float64(evalCount)
 }
 
-// percentile calculates the nth percentile of a sorted slice
-func percentile(sorted []float64, p int) float64 {
-	if len(sorted) == 0 {
-		return 0
+// categoryRates returns the rate of each category among the successful, evaluated results. The
+// results the evaluator did not classify count as evaluated, so the rates may not add up to 1.
+func categoryRates(results []BenchmarkResult) map[evaluator.Category]float64 {
+	counts := make(map[evaluator.Category]int)
+	evalCount := 0
+
+	for _, r := range results {
+		if !r.Success || r.EvalResponse == "" {
+			continue
+		}
+
+		evalCount++
+		if r.EvalCategory != evaluator.CategoryUnknown {
+			counts[r.EvalCategory]++
+		}
 	}
 
-	index := (float64(p) / 100.0) * float64(len(sorted)-1)
-	lower := int(index)
-	upper := lower + 1
+	if evalCount == 0 {
+		return nil
+	}
 
-	if upper >= len(sorted) {
-		return sorted[len(sorted)-1]
+	rates := make(map[evaluator.Category]float64, len(counts))
+	for category, count := range counts {
+		rates[category] = float64(count) / float64(evalCount)
 	}
 
-	weight := index - float64(lower)
-	return sorted[lower]*(1-weight) + sorted[upper]*weight
+	return rates
+}
+
+// categoryRateUnit returns the unit of the rate of the category reported by the benchmark, e.g.
+// "eval_off_topic_rate", prefixed not to clash with the refusal_rate of the RefusalDetector
+func categoryRateUnit(category evaluator.Category) string {
+	return "eval_" + strings.ReplaceAll(string(category), "-", "_") + "_rate"
 }
@@ -2,192 +2,436 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/joho/godotenv"
-	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/callbacks"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/dmrremote"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/judge"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
 	lgtm "github.com/testcontainers/testcontainers-go/modules/grafana-lgtm"
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/openai"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 var (
 	dmrContainer     testcontainers.Container
+	modelRunner      ModelRunner // Local DMR container, or the remote one of DMR_ENDPOINT
+	dmrRunOptions    RunOptions  // Inference options of the models under test, see BENCH_DMR_CPU_ONLY
 	lgtmContainer    testcontainers.Container
 	otelSetup        *OtelSetup
 	metricsCollector *MetricsCollector
-	evaluatorAgent   llms.Model // LLM model used for evaluation
-	gpuDeltaSampler  *GPUDeltaSampler // GPU delta sampler for accurate model memory tracking
+	resultsRecorder  *ResultsRecorder    // Results of the requests, exported to results.json
+	evaluatorAgent   llms.Model          // LLM model used for evaluation
+	gpuDeltaSampler  *GPUDeltaSampler    // GPU delta sampler for accurate model memory tracking
+	progress         *ProgressReporter   // Progress lines of the completed benchmarks, nil unless BENCH_PROGRESS is set
+	logger           = logging.Default() // leveled logger for the diagnostics, see GENAI_LOG_LEVEL
 )
 
 // TestMain sets up the test environment
 func TestMain(m *testing.M) {
+	// Unit tests don't need the benchmark environment: only set it up when running benchmarks
+	flag.Parse()
+	if f := flag.Lookup("test.bench"); f == nil || f.Value.String() == "" {
+		os.Exit(m.Run())
+	}
+
 	loadErr := godotenv.Load()
 	if loadErr != nil {
-		log.Printf("No .env file found, continuing without it: %v", loadErr)
+		logger.Debug("No .env file found, continuing without it", "error", loadErr)
 	}
 
 	// Load the models to benchmark
-	models = getModelsToTest()
+	benchModels, err := getModelsToTest()
+	if err != nil {
+		logger.Error("Failed to load models to benchmark", "error", err)
+		os.Exit(1)
+	}
+	models = benchModels
+
+	// Load the optional seed for reproducible runs
+	seed, err := getBenchSeed()
+	if err != nil {
+		logger.Error("Failed to read benchmark seed", "error", err)
+		os.Exit(1)
+	}
+	benchSeed = seed
+	if benchSeed != nil {
+		logger.Info("🎲 Using seed for reproducible generations", "seed", *benchSeed)
+	}
+
+	// Load the weight of the token efficiency in the composite score
+	tokenEfficiencyWeight, err = getTokenEfficiencyWeight()
+	if err != nil {
+		logger.Error("Failed to read the token efficiency weight", "error", err)
+		os.Exit(1)
+	}
+
+	// Load the patterns of the refusals, not counted as useful answers
+	refusalDetector, err = getRefusalDetector()
+	if err != nil {
+		logger.Error("Failed to load the refusal patterns", "error", err)
+		os.Exit(1)
+	}
+
+	// Load the optional judge criteria, to tune them without rebuilding
+	criteria, err := getEvaluationCriteria()
+	if err != nil {
+		logger.Error("Failed to load evaluation criteria", "error", err)
+		os.Exit(1)
+	}
+	if criteria != nil {
+		evaluationCriteria = criteria
+		logger.Info("⚖️  Using evaluation criteria from directory", "dir", os.Getenv("BENCH_CRITERIA_DIR"))
+	}
+
+	// Load whether to keep the containers running after the benchmark
+	keepContainers, err := getKeepContainers()
+	if err != nil {
+		logger.Error("Failed to read whether to keep the containers", "error", err)
+		os.Exit(1)
+	}
+
+	// Load the optional custom test cases, to benchmark arbitrary prompt suites
+	customTestCases, err := getTestCases()
+	if err != nil {
+		logger.Error("Failed to load test cases", "error", err)
+		os.Exit(1)
+	}
+	if customTestCases != nil {
+		testCases = customTestCases
+		logger.Info("🧪 Using test cases from directory", "dir", os.Getenv("BENCH_TESTCASES_DIR"), "test_cases", len(testCases))
+	}
+
+	// Load whether to print the progress lines of the completed benchmarks
+	progress, err = getProgressReporter()
+	if err != nil {
+		logger.Error("Failed to read whether to print the progress", "error", err)
+		os.Exit(1)
+	}
+
+	// Load the judge, isolated from the models under test if configured
+	judgeConfig, err := judge.GetConfig()
+	if err != nil {
+		logger.Error("Failed to read the judge configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Create the directory of the artifacts of the run, if they are collected
+	runOutput, err := getRunOutput(time.Now())
+	if err != nil {
+		logger.Error("Failed to create the output directory", "error", err)
+		os.Exit(1)
+	}
+	if runOutput.Dir != "" {
+		logger.Info("📁 Writing the artifacts of the run", "run_id", runOutput.RunID, "dir", runOutput.Dir)
+	}
 
 	ctx := context.Background()
 
-	// Disable Ryuk to keep containers running after tests complete
-	// This allows you to explore the Grafana dashboard with all collected metrics
-	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+	if keepContainers {
+		// Disable Ryuk to keep containers running after tests complete
+		// This allows you to explore the Grafana dashboard with all collected metrics
+		os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
 
-	fmt.Printf("\n=================================================\n")
-	fmt.Printf("🚀 Starting LLM Benchmark Environment\n")
-	fmt.Printf("=================================================\n")
-	fmt.Printf("Note: Containers will remain running after the\n")
-	fmt.Printf("benchmark completes so you can explore Grafana.\n")
-	fmt.Printf("=================================================\n\n")
+		logger.Info("🚀 Starting LLM Benchmark Environment",
+			"note", "containers will remain running after the benchmark completes so you can explore Grafana")
+	} else {
+		logger.Info("🚀 Starting LLM Benchmark Environment",
+			"note", "containers will be terminated after the benchmark completes, set BENCH_KEEP_CONTAINERS=true to explore Grafana")
+	}
 
 	// Start LGTM stack
-	var err error
 	lgtmCtr, err := lgtm.Run(
 		ctx, "grafana/otel-lgtm:0.11.18",
 		testcontainers.WithReuseByName("lgtm-llm-benchmarks"),
 	)
 	if err != nil {
-		log.Fatalf("Failed to start LGTM container: %s", err)
+		logger.Error("Failed to start LGTM container", "error", err)
+		os.Exit(1)
 	}
 	lgtmContainer = lgtmCtr
 
 	// Get OTLP endpoint
 	otlpEndpoint, err := lgtmCtr.OtlpHttpEndpoint(ctx)
 	if err != nil {
-		log.Fatalf("Failed to get OTLP endpoint: %s", err)
+		logger.Error("Failed to get OTLP endpoint", "error", err)
+		os.Exit(1)
+	}
+
+	// Load the inference options of the models, e.g. to force CPU-only runs
+	dmrRunOptions, err = getDMRRunOptions()
+	if err != nil {
+		logger.Error("Failed to read the Docker Model Runner options", "error", err)
+		os.Exit(1)
+	}
+	dmrRunOptions.ReuseName = "dmr-llm-benchmarks"
+	if flags := dmrRunOptions.RuntimeFlags(); len(flags) > 0 {
+		logger.Info("⚙️  Configuring the inference of the models", "runtime_flags", flags)
+	}
+
+	// Start DMR container, unless the benchmark targets a remote one
+	dmrEndpoint := dmrremote.Endpoint()
+	modelRunner, dmrContainer, err = startModelRunner(ctx, dmrEndpoint, func(ctx context.Context) (*dmr.Container, error) {
+		return RunDMR(ctx, "", dmrRunOptions)
+	})
+	if err != nil {
+		logger.Error("Failed to start DMR container", "error", err)
+		os.Exit(1)
+	}
+	if dmrEndpoint != "" {
+		logger.Info("🌐 Using remote Docker Model Runner", "endpoint", modelRunner.OpenAIEndpoint())
+	}
+
+	// Detect if the models run on the GPU or the CPU, as the numbers of both are not comparable
+	dmrStatus, err := FetchDMRBackendStatus(ctx, modelRunner.OpenAIEndpoint())
+	if err != nil {
+		logger.Warn("Failed to get the Docker Model Runner status, detecting the inference backend from the GPU", "error", err)
+	}
+	inferenceBackend := InferenceBackend(DetectGPUVendor(), dmrStatus)
+	logger.Info("🖥️  Inference backend detected", "backend", inferenceBackend, "dmr_status", dmrStatus)
+	inferenceBackendAttr := attribute.String(semconv.AttrInferenceBackend, inferenceBackend)
+
+	// Start the optional Prometheus scrape endpoint, to monitor the run live from an existing Prometheus
+	prometheusAddr, err := getPrometheusAddr()
+	if err != nil {
+		logger.Error("Failed to read the Prometheus scrape endpoint", "error", err)
+		os.Exit(1)
+	}
+	var prometheusServer *PrometheusServer
+	var metricReaders []sdkmetric.Reader
+	if prometheusAddr != "" {
+		prometheusServer, err = NewPrometheusServer(prometheusAddr)
+		if err != nil {
+			logger.Error("Failed to create the Prometheus scrape endpoint", "error", err)
+			os.Exit(1)
+		}
+		metricReaders = append(metricReaders, prometheusServer.Reader())
+		prometheusServer.Start()
+		logger.Info("📈 Prometheus scrape endpoint ready", "url", prometheusServer.URL())
 	}
 
 	// Initialize OpenTelemetry
-	otelSetup, err = InitOTel(ctx, otlpEndpoint)
+	otelSetup, err = InitOTel(ctx, otlpEndpoint, metricReaders, inferenceBackendAttr)
 	if err != nil {
-		log.Fatalf("Failed to initialize OpenTelemetry: %s", err)
+		logger.Error("Failed to initialize OpenTelemetry", "error", err)
+		os.Exit(1)
 	}
 
 	// Initialize metrics collector
-	metricsCollector, err = NewMetricsCollector()
+	latencyBuckets, err := getLatencyBuckets()
 	if err != nil {
-		log.Fatalf("Failed to create metrics collector: %s", err)
+		logger.Error("Failed to read the latency buckets", "error", err)
+		os.Exit(1)
 	}
-
-	// Start DMR container
-	dmrCtr, err := dmr.Run(ctx, testcontainers.WithReuseByName("dmr-llm-benchmarks"))
+	metricsCollector, err = NewMetricsCollector(WithBaseAttributes(inferenceBackendAttr), WithLatencyBuckets(latencyBuckets...))
 	if err != nil {
-		log.Fatalf("Failed to start DMR container: %s", err)
+		logger.Error("Failed to create metrics collector", "error", err)
+		os.Exit(1)
 	}
-	dmrContainer = dmrCtr
+	resultsRecorder = NewResultsRecorder()
 
 	// Initialize GPU delta sampler and capture baseline
 	// This allows us to track model-specific GPU memory usage by comparing against system baseline
 	gpuDeltaSampler = NewGPUDeltaSampler()
 	if gpuDeltaSampler.IsAvailable() {
-		fmt.Printf("📊 GPU metrics available - capturing baseline...\n")
+		logger.Info("📊 GPU metrics available - capturing baseline...")
 		if err := gpuDeltaSampler.CaptureBaseline(); err == nil {
-			fmt.Printf("✅ GPU baseline captured (delta measurements enabled)\n")
+			logger.Info("✅ GPU baseline captured (delta measurements enabled)")
 		} else {
-			fmt.Printf("⚠️  Failed to capture GPU baseline: %s (using absolute measurements)\n", err)
+			logger.Warn("⚠️  Failed to capture GPU baseline (using absolute measurements)", "error", err)
 		}
 	}
 
 	// Initialize evaluator agent
-	evaluatorAgent, err = initializeEvaluatorAgent(ctx)
+	evaluatorAgent, err = judge.New(ctx, judgeConfig, modelRunner)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize evaluator agent: %s", err)
-		log.Printf("Benchmarks will run without evaluation scoring.")
+		logger.Warn("Failed to initialize evaluator agent, benchmarks will run without evaluation scoring", "error", err)
 	} else {
-		fmt.Printf("✅ Evaluator agent initialized\n\n")
+		logger.Info("✅ Evaluator agent initialized")
+	}
+
+	// Load whether to create a new dashboard on each run, instead of replacing it
+	appendDashboard, err := getEnvBool("BENCH_DASHBOARD_APPEND")
+	if err != nil {
+		logger.Error("Failed to read whether to append the Grafana dashboard", "error", err)
+		os.Exit(1)
+	}
+	var dashboardOpts []DashboardOption
+	if appendDashboard {
+		dashboardOpts = append(dashboardOpts, WithAppendDashboard())
+	}
+
+	// Write the optional dashboard file, to commit it or import it into another Grafana
+	dashboardTitle := "LLM Bench (DMR + Testcontainers)"
+	if path := runOutput.Path(os.Getenv("BENCH_DASHBOARD_FILE"), dashboardFileName); path != "" {
+		if err := WriteDashboardJSON(path, dashboardTitle); err != nil {
+			logger.Warn("Failed to write the Grafana dashboard", "file", path, "error", err)
+		} else {
+			logger.Info("📝 Grafana dashboard written", "file", path)
+		}
+	}
+
+	// Warm up the first model, so the dashboard has a data point when the user opens it
+	skipWarmup, err := getEnvBool("BENCH_SKIP_WARMUP")
+	if err != nil {
+		logger.Error("Failed to read whether to skip the warmup", "error", err)
+		os.Exit(1)
+	}
+	if model, ok := warmupModel(models, skipWarmup); ok {
+		logger.Info("🔥 Warming up the first model, the dashboard is populating...", "model", model.FQName)
+		if err := warmUp(ctx, model); err != nil {
+			logger.Warn("Failed to warm up the model, the dashboard stays empty until the first benchmark completes", "model", model.FQName, "error", err)
+		}
 	}
 
 	// Get Grafana endpoint and create dashboard
 	grafanaEndpoint, err := lgtmCtr.HttpEndpoint(ctx)
 	if err != nil {
-		log.Printf("Warning: Failed to get Grafana endpoint: %s", err)
+		logger.Warn("Failed to get Grafana endpoint", "error", err)
 		grafanaEndpoint = ""
 	} else {
-		fmt.Printf("\n=================================================\n")
-		fmt.Printf("📊 Grafana Observability Stack Ready\n")
-		fmt.Printf("=================================================\n")
-		fmt.Printf("URL: %s\n", grafanaEndpoint)
-		fmt.Printf("Credentials: admin / admin\n")
-		fmt.Printf("=================================================\n\n")
+		logger.Info("📊 Grafana Observability Stack Ready", "url", grafanaEndpoint, "credentials", "admin / admin")
 
 		// Create Grafana dashboard immediately so users can watch metrics populate in real-time
-		fmt.Printf("📊 Creating Grafana dashboard...\n")
-		dashboardTitle := "LLM Bench (DMR + Testcontainers)"
-		if err := CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle); err != nil {
-			log.Printf("Warning: Failed to create Grafana dashboard: %s", err)
+		logger.Info("📊 Creating Grafana dashboard...")
+		if err := CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle, dashboardOpts...); err != nil {
+			logger.Warn("Failed to create Grafana dashboard", "error", err)
 		} else {
-			fmt.Printf("✅ Dashboard created! Visit %s/dashboards to watch metrics populate\n\n", grafanaEndpoint)
+			logger.Info("✅ Dashboard created! Watch the metrics populate", "url", grafanaEndpoint+"/dashboards")
 		}
 	}
 
 	// Run tests
 	exitCode := m.Run()
 
+	// Print the results, for a quick look without opening Grafana
+	if err := WriteSummary(os.Stdout, metricsCollector.Aggregates()); err != nil {
+		logger.Warn("Failed to write the benchmark summary", "error", err)
+	}
+	if path := runOutput.Path("", summaryFileName); path != "" {
+		if err := writeSummaryFile(path); err != nil {
+			logger.Warn("Failed to write the benchmark summary", "file", path, "error", err)
+		}
+	}
+
+	// Write the optional markdown report, to share the results in pull requests or docs
+	if path := runOutput.Path(os.Getenv("BENCH_REPORT_FILE"), reportFileName); path != "" {
+		if err := writeMarkdownReport(path); err != nil {
+			logger.Warn("Failed to write the markdown report", "file", path, "error", err)
+		} else {
+			logger.Info("📝 Markdown report written", "file", path)
+		}
+	}
+
+	// Export the results of the requests, to re-score them with cmd/score or compare the runs with cmd/compare
+	if path := runOutput.Path(os.Getenv("BENCH_RESULTS_FILE"), resultsFileName); path != "" {
+		if err := benchresult.Save(path, resultsRecorder.Results()); err != nil {
+			logger.Warn("Failed to export the results", "file", path, "error", err)
+		} else {
+			logger.Info("💾 Results exported", "file", path)
+		}
+	}
+
 	// Shutdown OpenTelemetry to flush remaining data
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := otelSetup.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Warning: Failed to shutdown OpenTelemetry: %s", err)
+		logger.Warn("Failed to shutdown OpenTelemetry", "error", err)
+	}
+	if prometheusServer != nil {
+		if err := prometheusServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shutdown the Prometheus scrape endpoint", "error", err)
+		}
+	}
+
+	// Terminate the containers, unless they are kept to explore Grafana
+	cleanupCmd, err := teardownContainers(keepContainers, lgtmContainer, dmrContainer)
+	if err != nil {
+		logger.Warn("Failed to terminate the containers", "error", err)
 	}
 
 	// Print completion banner with instructions
-	fmt.Printf("\n=================================================\n")
-	fmt.Printf("✅ Benchmark Complete!\n")
-	fmt.Printf("=================================================\n")
-	if grafanaEndpoint != "" {
-		fmt.Printf("Grafana is still running at:\n")
-		fmt.Printf("  %s/dashboards\n\n", grafanaEndpoint)
-		fmt.Printf("Explore your metrics and traces, then stop\n")
-		fmt.Printf("containers when done:\n")
-		fmt.Printf("  docker ps --filter label=org.testcontainers.session-id\n")
-		fmt.Printf("  docker stop <container-ids>\n")
-	}
-	fmt.Printf("=================================================\n\n")
+	logger.Info("✅ Benchmark Complete!")
+	if runOutput.Dir != "" {
+		logger.Info("📁 Artifacts of the run written", "run_id", runOutput.RunID, "dir", runOutput.Dir)
+	}
+	if keepContainers && grafanaEndpoint != "" {
+		logger.Info("Grafana is still running: explore your metrics and traces, then remove the containers when done",
+			"url", grafanaEndpoint+"/dashboards",
+			"cleanup", cleanupCmd)
+	}
 
 	os.Exit(exitCode)
 }
 
-// initializeEvaluatorAgent creates and configures the LLM model used for evaluation
-func initializeEvaluatorAgent(ctx context.Context) (llms.Model, error) {
-	// Check if OpenAI API key is available
-	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
-		fmt.Printf("🔑 Using OpenAI for evaluation (gpt-4o-mini)\n")
-		// Use OpenAI's gpt-4o-mini for evaluation (fast and cost-effective)
-		return openai.New(
-			openai.WithModel("gpt-4o-mini"),
-			openai.WithToken(apiKey),
-			openai.WithCallback(callbacks.NewOTelCallbackHandler()),
-		)
-	}
-
-	// Fall back to using the DMR container with a local model
-	fmt.Printf("🔑 Using local model for evaluation (ai/llama3.2:3B-Q4_K_M via DMR)\n")
-	dmrEndpoint := getDMRContainer().OpenAIEndpoint()
-
-	// Use a local model from DMR for evaluation
-	// We'll use llama3.2:3B as it's a good balance of speed and quality for evaluation
-	evaluatorModel := "ai/llama3.2:3B-Q4_K_M"
-
-	// Pull the evaluator model
-	if err := getDMRContainer().PullModel(ctx, evaluatorModel); err != nil {
-		return nil, fmt.Errorf("failed to pull evaluator model: %w", err)
-	}
-
-	// Create OpenAI-compatible client pointing to DMR
-	return openai.New(
-		openai.WithModel(evaluatorModel),
-		openai.WithBaseURL(dmrEndpoint),
-		openai.WithToken("dummy"), // DMR doesn't require auth
-		openai.WithCallback(callbacks.NewOTelCallbackHandler()),
-	)
+// writeMarkdownReport writes the markdown report of the collected aggregates to the file
+func writeMarkdownReport(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create report: %w", err)
+	}
+	defer f.Close()
+
+	metricsCollector.aggregatesMu.RLock()
+	defer metricsCollector.aggregatesMu.RUnlock()
+
+	if err := GenerateMarkdownReport(metricsCollector.aggregates, f); err != nil {
+		return fmt.Errorf("generate report: %w", err)
+	}
+
+	return f.Close()
+}
+
+// writeSummaryFile writes the summary of the collected aggregates to the file
+func writeSummaryFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create summary: %w", err)
+	}
+	defer f.Close()
+
+	if err := WriteSummary(f, metricsCollector.Aggregates()); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+
+	return f.Close()
+}
+
+// warmUp pulls the model and sends it a throwaway request, recorded as its cold start and under the
+// warmup test case, so the dashboard is not empty until the first benchmark completes
+func warmUp(ctx context.Context, model ModelConfig) error {
+	endpoint := model.ExternalURL
+	if !model.IsExternal {
+		endpoint = modelRunner.OpenAIEndpoint()
+		if err := preflightPull(ctx, model); err != nil {
+			return fmt.Errorf("preflight pull: %w", err)
+		}
+		if err := pullModel(ctx, model.FQName); err != nil {
+			return fmt.Errorf("pull model: %w", err)
+		}
+	}
+
+	client, err := llmclient.NewClient(endpoint, model.FQName)
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	resp, err := measureColdStart(ctx, client, model)
+	if err != nil {
+		return fmt.Errorf("cold start: %w", err)
+	}
+	metricsCollector.RecordLatency(ctx, resp.Latency, model.FQName, warmupTestCase, 0.1)
+
+	return nil
 }
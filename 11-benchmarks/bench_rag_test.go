@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/rag"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/stats"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/chroma"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/pgvector"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/qdrant"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/redisvector"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/weaviate"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// ragEmbeddingModel is the model embedding the knowledge base and the questions of the RAG benchmark
+	ragEmbeddingModel = "ai/mxbai-embed-large:335M-F16"
+	// ragDimension is the dimension of the embeddings of ragEmbeddingModel
+	ragDimension = 1024
+)
+
+// ragStore is a vector store the RAG pipeline is benchmarked with. New creates the store, isolating
+// the documents of the benchmark under the given name, as the containers of the stores are reused.
+type ragStore struct {
+	Name string
+	New  func(ctx context.Context, name string) (rag.Store, error)
+}
+
+var (
+	// Vector stores to benchmark the RAG pipeline with: the in-memory store, as the baseline,
+	// and the stores of the examples, started in their containers by the 08-testing packages
+	ragStores = []ragStore{
+		{Name: "memory", New: func(context.Context, string) (rag.Store, error) { return rag.NewMemoryStore(), nil }},
+		{Name: "pgvector", New: func(ctx context.Context, name string) (rag.Store, error) {
+			return newRAGVectorStore(ctx, func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+				return pgvector.NewStore(ctx, embedder, pgvector.WithDimension(ragDimension), pgvector.WithCollectionName(name))
+			})
+		}},
+		{Name: "weaviate", New: func(ctx context.Context, name string) (rag.Store, error) {
+			return newRAGVectorStore(ctx, func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+				return weaviate.NewStore(ctx, embedder)
+			}, vectorstores.WithNameSpace(name))
+		}},
+		{Name: "qdrant", New: func(ctx context.Context, name string) (rag.Store, error) {
+			return newRAGVectorStore(ctx, func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+				return qdrant.NewStore(ctx, embedder, qdrant.WithDimension(ragDimension), qdrant.WithCollectionName(name))
+			})
+		}},
+		{Name: "chroma", New: func(ctx context.Context, name string) (rag.Store, error) {
+			return newRAGVectorStore(ctx, func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+				return chroma.NewStore(ctx, embedder, chroma.WithCollectionName(name))
+			})
+		}},
+		{Name: "redis", New: func(ctx context.Context, name string) (rag.Store, error) {
+			return newRAGVectorStore(ctx, func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+				return redisvector.NewStore(ctx, embedder, redisvector.WithIndexName(name))
+			})
+		}},
+	}
+
+	// Knowledge base indexed in the stores
+	ragDocuments = []string{
+		"Testcontainers for Go is a Go package that makes it simple to create and clean up container-based dependencies for automated integration tests.",
+		"Docker Model Runner runs AI models locally, exposing them through an OpenAI-compatible API.",
+		"The Grafana LGTM stack bundles Loki for logs, Grafana for dashboards, Tempo for traces and Mimir for metrics.",
+		"Toledo, Spain was a center of the translation movement in the 12th and 13th centuries, where Arabic texts were translated into Latin.",
+		"My favourite sport is football, and I watch every match of my team on Sundays.",
+	}
+
+	// Questions answered from the knowledge base, one benchmark per question
+	ragQuestions = []TestCase{
+		{Name: "rag-testcontainers", UserPrompt: "What is Testcontainers for Go used for?"},
+		{Name: "rag-lgtm", UserPrompt: "Which component of the LGTM stack stores the traces?"},
+	}
+
+	// Number of documents retrieved per question
+	ragNumDocs = 2
+)
+
+// newRAGVectorStore adapts the vector store created by newStore to a rag.Store, searched by the query vector
+func newRAGVectorStore(ctx context.Context, newStore rag.VectorStoreFactory, options ...vectorstores.Option) (rag.Store, error) {
+	store, err := rag.NewVectorStore(ctx, newStore, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// getRAGStores returns the stores named by the comma-separated BENCH_RAG_STORES environment variable,
+// e.g. "memory,pgvector", or all the stores if it's not set
+func getRAGStores() ([]ragStore, error) {
+	value := os.Getenv("BENCH_RAG_STORES")
+	if value == "" {
+		return ragStores, nil
+	}
+
+	var stores []ragStore
+	for name := range strings.SplitSeq(value, ",") {
+		name = strings.TrimSpace(name)
+
+		i := slices.IndexFunc(ragStores, func(s ragStore) bool { return s.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("invalid BENCH_RAG_STORES %q: unknown store %q", value, name)
+		}
+		stores = append(stores, ragStores[i])
+	}
+
+	return stores, nil
+}
+
+// newRAGEmbedder creates the embedder of the embedding model served by the DMR container
+func newRAGEmbedder(ctx context.Context) (embeddings.Embedder, error) {
+	endpoint := modelRunner.OpenAIEndpoint()
+
+	if err := PullModelVerified(ctx, modelRunner, endpoint, ragEmbeddingModel, DefaultPullRetryConfig); err != nil {
+		return nil, err
+	}
+
+	llm, err := openai.New(
+		openai.WithBaseURL(endpoint),
+		openai.WithEmbeddingModel(ragEmbeddingModel),
+		openai.WithToken("foo"), // No API key needed for Model Runner
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new embedding model: %w", err)
+	}
+
+	embedder, err := embeddings.NewEmbedder(llm)
+	if err != nil {
+		return nil, fmt.Errorf("new embedder: %w", err)
+	}
+
+	return embedder, nil
+}
+
+// BenchmarkRAG measures the end-to-end latency of RAG queries for each model/store combination,
+// reporting the query embedding, search and generation phases apart
+func BenchmarkRAG(b *testing.B) {
+	ctx := context.Background()
+
+	stores, err := getRAGStores()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	embedder, err := newRAGEmbedder(ctx)
+	if err != nil {
+		b.Fatalf("Failed to create the embedder: %v", err)
+	}
+
+	for _, model := range models {
+		modelName := model.FQName
+
+		endpoint := model.ExternalURL
+		if !model.IsExternal {
+			if err := pullModel(ctx, modelName); err != nil {
+				b.Fatalf("Failed to pull model %s: %v", modelName, err)
+			}
+			endpoint = modelRunner.OpenAIEndpoint()
+		}
+
+		client, err := llmclient.NewClient(endpoint, modelName)
+		if err != nil {
+			b.Fatalf("Failed to create client for %s: %v", modelName, err)
+		}
+
+		for _, store := range stores {
+			benchmarkRAGStore(ctx, b, embedder, client, model, store)
+		}
+	}
+}
+
+// benchmarkRAGStore indexes the knowledge base in a new store and benchmarks the questions with
+// the model. The documents are indexed under a unique name, so previous runs don't add duplicates.
+func benchmarkRAGStore(ctx context.Context, b *testing.B, embedder embeddings.Embedder, client *llmclient.Client, model ModelConfig, store ragStore) {
+	modelName := model.FQName
+
+	s, err := store.New(ctx, fmt.Sprintf("benchmarks%d", time.Now().UnixNano()))
+	if err != nil {
+		b.Fatalf("Failed to create the %s store: %v", store.Name, err)
+	}
+	if closer, ok := s.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				b.Errorf("Failed to close the %s store: %v", store.Name, err)
+			}
+		}()
+	}
+
+	pipeline := rag.NewPipeline(embedder, s, client, ragNumDocs)
+	if err := pipeline.Index(ctx, ragDocuments); err != nil {
+		b.Fatalf("Failed to index the documents in the %s store: %v", store.Name, err)
+	}
+
+	for _, tc := range ragQuestions {
+		b.Run(fmt.Sprintf("RAG/%s/%s/%s", model.Name, store.Name, tc.Name), func(b *testing.B) {
+			defer recoverBenchmark(b)
+
+			var embedLatencies, searchLatencies, generateLatencies, ragLatencies []time.Duration
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				result, err := pipeline.Query(ctx, tc.Name, tc.UserPrompt, llmclient.GenerateOptions{
+					Temperature: 0.1,
+					Seed:        benchSeed,
+				})
+				if err != nil {
+					metricsCollector.LogBenchmarkError(ctx, modelName, tc.Name, 0.1, err)
+					continue
+				}
+
+				metricsCollector.RecordRAGLatency(ctx, result.Latency, modelName, store.Name)
+
+				embedLatencies = append(embedLatencies, result.EmbedLatency)
+				searchLatencies = append(searchLatencies, result.SearchLatency)
+				generateLatencies = append(generateLatencies, result.GenerateLatency)
+				ragLatencies = append(ragLatencies, result.Latency)
+			}
+			b.StopTimer()
+
+			if len(ragLatencies) == 0 {
+				b.Fatalf("All the RAG queries failed for %s", modelName)
+			}
+
+			b.ReportMetric(medianMs(embedLatencies), "embed_p50_ms")
+			b.ReportMetric(medianMs(searchLatencies), "search_p50_ms")
+			b.ReportMetric(medianMs(generateLatencies), "generate_p50_ms")
+			b.ReportMetric(medianMs(ragLatencies), "rag_latency_p50_ms")
+			b.ReportMetric(float64(len(ragLatencies))/float64(b.N), "success_rate")
+		})
+	}
+}
+
+// medianMs returns the median of the latencies in fractional milliseconds, as searching
+// an in-memory store usually takes less than one millisecond
+func medianMs(latencies []time.Duration) float64 {
+	sorted := make([]float64, len(latencies))
+	for i, latency := range latencies {
+		sorted[i] = float64(latency) / float64(time.Millisecond)
+	}
+	slices.Sort(sorted)
+
+	return stats.Percentile(sorted, 50)
+}
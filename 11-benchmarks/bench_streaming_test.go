@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+)
+
+// streamingTemperature is the temperature of the streaming comparison, low to keep the length of
+// the responses, and so the latency, similar in both modes
+const streamingTemperature = 0.1
+
+// BenchmarkStreaming compares the latency of each model/case with and without streaming the
+// responses, as some deployments are faster in non-streaming mode. The streaming_overhead_ms
+// metric is the median of the streaming latency minus the non-streaming one, negative when
+// streaming is faster.
+func BenchmarkStreaming(b *testing.B) {
+	ctx := context.Background()
+
+	for _, model := range models {
+		modelName := model.FQName
+
+		endpoint := model.ExternalURL
+		if !model.IsExternal {
+			if err := pullModel(ctx, modelName); err != nil {
+				b.Fatalf("Failed to pull model %s: %v", modelName, err)
+			}
+			endpoint = modelRunner.OpenAIEndpoint()
+		}
+
+		client, err := llmclient.NewClient(endpoint, modelName)
+		if err != nil {
+			b.Fatalf("Failed to create client for %s: %v", modelName, err)
+		}
+
+		for _, tc := range testCases {
+			// The tool-assisted cases run several requests per operation, which hides the difference
+			if isToolAssistedCase(tc.Name) {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("Streaming/%s/%s", model.Name, tc.Name), func(b *testing.B) {
+				defer recoverBenchmark(b)
+
+				var streamingLatencies, nonStreamingLatencies, overheads []time.Duration
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					// Alternate the mode sent first, so neither always benefits from a warm KV cache
+					cmp, err := client.CompareStreaming(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, llmclient.GenerateOptions{
+						Temperature: streamingTemperature,
+						Seed:        benchSeed,
+					}, i%2 == 0)
+					if err != nil {
+						metricsCollector.LogBenchmarkError(ctx, modelName, tc.Name, streamingTemperature, err)
+						continue
+					}
+
+					streamingLatencies = append(streamingLatencies, cmp.Streaming.Latency)
+					nonStreamingLatencies = append(nonStreamingLatencies, cmp.NonStreaming.Latency)
+					overheads = append(overheads, cmp.Overhead)
+				}
+				b.StopTimer()
+
+				if len(overheads) == 0 {
+					b.Fatalf("All the streaming comparisons failed for %s", modelName)
+				}
+
+				b.ReportMetric(medianMs(streamingLatencies), "stream_latency_p50_ms")
+				b.ReportMetric(medianMs(nonStreamingLatencies), "non_stream_latency_p50_ms")
+				b.ReportMetric(medianMs(overheads), "streaming_overhead_ms")
+				b.ReportMetric(float64(len(overheads))/float64(b.N), "success_rate")
+			})
+		}
+	}
+}
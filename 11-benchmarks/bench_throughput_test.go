@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+)
+
+// throughputTemperature is the temperature of the throughput sweep, low to keep the length of the
+// responses, and so the latency, similar across the concurrency levels
+const throughputTemperature = 0.1
+
+// getThroughputConfig returns the configuration of the throughput sweep, from the
+// BENCH_THROUGHPUT_MAX_CONCURRENCY, BENCH_THROUGHPUT_REQUESTS and BENCH_THROUGHPUT_SLO_MS
+// environment variables, or the defaults if they are not set
+func getThroughputConfig() (ThroughputConfig, error) {
+	cfg := ThroughputConfig{
+		MaxConcurrency:    defaultThroughputMaxConcurrency,
+		RequestsPerWorker: defaultThroughputRequestsPerWorker,
+		SLO:               defaultThroughputSLO,
+	}
+
+	if value := os.Getenv("BENCH_THROUGHPUT_MAX_CONCURRENCY"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return ThroughputConfig{}, fmt.Errorf("invalid BENCH_THROUGHPUT_MAX_CONCURRENCY %q: must be a positive integer", value)
+		}
+		cfg.MaxConcurrency = n
+	}
+
+	if value := os.Getenv("BENCH_THROUGHPUT_REQUESTS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return ThroughputConfig{}, fmt.Errorf("invalid BENCH_THROUGHPUT_REQUESTS %q: must be a positive integer", value)
+		}
+		cfg.RequestsPerWorker = n
+	}
+
+	if value := os.Getenv("BENCH_THROUGHPUT_SLO_MS"); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms < 1 {
+			return ThroughputConfig{}, fmt.Errorf("invalid BENCH_THROUGHPUT_SLO_MS %q: must be a positive integer", value)
+		}
+		cfg.SLO = time.Duration(ms) * time.Millisecond
+	}
+
+	return cfg, nil
+}
+
+// BenchmarkThroughput ramps the concurrent requests to each model/case (1, 2, 4, 8, ...) and reports
+// the max_sustainable_rps metric, the highest requests per second with the p95 latency under the
+// SLO, to size the serving capacity of a model. The latency at each concurrency is exported as the
+// capacity curve of the model. It's skipped unless BENCH_THROUGHPUT_SWEEP is true, as it sends many
+// more requests than the other benchmarks.
+func BenchmarkThroughput(b *testing.B) {
+	ctx := context.Background()
+
+	enabled, err := getEnvBool("BENCH_THROUGHPUT_SWEEP")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !enabled {
+		b.Skip("Set BENCH_THROUGHPUT_SWEEP=true to run the throughput sweep")
+	}
+
+	cfg, err := getThroughputConfig()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, model := range models {
+		modelName := model.FQName
+
+		endpoint := model.ExternalURL
+		if !model.IsExternal {
+			if err := pullModel(ctx, modelName); err != nil {
+				b.Fatalf("Failed to pull model %s: %v", modelName, err)
+			}
+			endpoint = modelRunner.OpenAIEndpoint()
+		}
+
+		client, err := llmclient.NewClient(endpoint, modelName)
+		if err != nil {
+			b.Fatalf("Failed to create client for %s: %v", modelName, err)
+		}
+
+		for _, tc := range testCases {
+			// The tool-assisted cases run several requests per operation, which hides the capacity
+			if isToolAssistedCase(tc.Name) {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("Throughput/%s/%s", model.Name, tc.Name), func(b *testing.B) {
+				defer recoverBenchmark(b)
+
+				generate := func(ctx context.Context) error {
+					_, err := client.GenerateWithOptions(ctx, tc.Name, tc.SystemPrompt, tc.UserPrompt, llmclient.GenerateOptions{
+						Temperature: throughputTemperature,
+						Seed:        benchSeed,
+					})
+					if err != nil {
+						metricsCollector.LogBenchmarkError(ctx, modelName, tc.Name, throughputTemperature, err)
+					}
+					return err
+				}
+
+				var maxRPS float64
+				var maxConcurrency, sweeps int
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					sweep, err := MeasureThroughput(ctx, cfg, generate)
+					if err != nil {
+						continue
+					}
+					sweeps++
+					maxRPS += sweep.MaxSustainableRPS
+					maxConcurrency += sweep.MaxSustainableConcurrency
+
+					metricsCollector.RecordThroughputSweep(modelName, tc.Name, sweep)
+					for _, level := range sweep.Levels {
+						logger.Info("📈 Throughput level",
+							"model", modelName,
+							"case", tc.Name,
+							"concurrency", level.Concurrency,
+							"rps", fmt.Sprintf("%.2f", level.RPS),
+							"latency_p95", level.LatencyP95,
+							"failed", level.Failed,
+							"sustainable", level.Sustainable(cfg.SLO))
+					}
+				}
+				b.StopTimer()
+
+				if sweeps == 0 {
+					b.Fatalf("All the throughput sweeps failed for %s", modelName)
+				}
+
+				b.ReportMetric(maxRPS/float64(sweeps), "max_sustainable_rps")
+				b.ReportMetric(float64(maxConcurrency)/float64(sweeps), "max_sustainable_concurrency")
+				b.ReportMetric(float64(sweeps)/float64(b.N), "success_rate")
+			})
+		}
+	}
+}
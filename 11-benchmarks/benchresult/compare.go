@@ -0,0 +1,137 @@
+package benchresult
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/stats"
+)
+
+// Thresholds defines how much a benchmark can worsen before it's considered a regression
+type Thresholds struct {
+	LatencyP95Pct   float64 // Maximum allowed increase of the p95 latency, in percent (e.g. 10 = 10%)
+	SuccessRateDrop float64 // Maximum allowed drop of the success rate, in absolute terms (e.g. 0.05 = 5 points)
+}
+
+// RunComparison compares the baseline and current results of a model/case/temp combination
+type RunComparison struct {
+	Model               string
+	TestCase            string
+	Temp                float64
+	BaselineP95Ms       float64
+	CurrentP95Ms        float64
+	LatencyChangePct    float64 // Positive when the current run is slower
+	BaselineSuccessRate float64
+	CurrentSuccessRate  float64
+	Regressed           bool
+	Reasons             []string
+}
+
+// RegressionReport is the outcome of comparing two benchmark runs
+type RegressionReport struct {
+	Regressed   bool
+	Comparisons []RunComparison
+	Missing     []string // model/case/temp combinations present in the baseline but not in the current run
+}
+
+// runStats holds the statistics of a model/case/temp combination used to detect regressions
+type runStats struct {
+	model       string
+	testCase    string
+	temp        float64
+	p95Ms       float64
+	successRate float64
+}
+
+// CompareRuns pairs the baseline and current results by model/case/temp, and flags the combinations
+// where the p95 latency or the success rate worsened beyond the thresholds
+func CompareRuns(baseline, current []Result, thresholds Thresholds) (*RegressionReport, error) {
+	if thresholds.LatencyP95Pct < 0 || thresholds.SuccessRateDrop < 0 {
+		return nil, fmt.Errorf("thresholds must not be negative: %+v", thresholds)
+	}
+	if len(baseline) == 0 {
+		return nil, fmt.Errorf("baseline has no results")
+	}
+
+	baselineStats := groupRunStats(baseline)
+	currentStats := groupRunStats(current)
+
+	keys := make([]string, 0, len(baselineStats))
+	for key := range baselineStats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	report := &RegressionReport{}
+	for _, key := range keys {
+		base := baselineStats[key]
+		curr, ok := currentStats[key]
+		if !ok {
+			report.Missing = append(report.Missing, key)
+			continue
+		}
+
+		cmp := RunComparison{
+			Model:               base.model,
+			TestCase:            base.testCase,
+			Temp:                base.temp,
+			BaselineP95Ms:       base.p95Ms,
+			CurrentP95Ms:        curr.p95Ms,
+			BaselineSuccessRate: base.successRate,
+			CurrentSuccessRate:  curr.successRate,
+		}
+
+		if base.p95Ms > 0 {
+			cmp.LatencyChangePct = (curr.p95Ms - base.p95Ms) / base.p95Ms * 100
+			if cmp.LatencyChangePct > thresholds.LatencyP95Pct {
+				cmp.Reasons = append(cmp.Reasons, fmt.Sprintf("latency p95 worsened by %.1f%% (%.0fms -> %.0fms)", cmp.LatencyChangePct, base.p95Ms, curr.p95Ms))
+			}
+		}
+
+		if drop := base.successRate - curr.successRate; drop > thresholds.SuccessRateDrop {
+			cmp.Reasons = append(cmp.Reasons, fmt.Sprintf("success rate dropped from %.2f to %.2f", base.successRate, curr.successRate))
+		}
+
+		cmp.Regressed = len(cmp.Reasons) > 0
+		if cmp.Regressed {
+			report.Regressed = true
+		}
+
+		report.Comparisons = append(report.Comparisons, cmp)
+	}
+
+	return report, nil
+}
+
+// groupRunStats calculates the p95 latency and success rate of each model/case/temp combination
+func groupRunStats(results []Result) map[string]*runStats {
+	grouped := make(map[string][]Result)
+	for _, r := range results {
+		key := fmt.Sprintf("%s|%s|%.1f", r.Model, r.TestCase, r.Temp)
+		grouped[key] = append(grouped[key], r)
+	}
+
+	byKey := make(map[string]*runStats, len(grouped))
+	for key, group := range grouped {
+		latencies := make([]float64, 0, len(group))
+		successCount := 0
+		for _, r := range group {
+			if r.Success {
+				latencies = append(latencies, float64(r.Latency)/float64(time.Millisecond))
+				successCount++
+			}
+		}
+		sort.Float64s(latencies)
+
+		byKey[key] = &runStats{
+			model:       group[0].Model,
+			testCase:    group[0].TestCase,
+			temp:        group[0].Temp,
+			p95Ms:       stats.Percentile(latencies, 95),
+			successRate: float64(successCount) / float64(len(group)),
+		}
+	}
+
+	return byKey
+}
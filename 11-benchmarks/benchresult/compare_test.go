@@ -0,0 +1,95 @@
+package benchresult
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareRuns(t *testing.T) {
+	newResults := func(model string, latencies ...time.Duration) []Result {
+		results := make([]Result, 0, len(latencies))
+		for _, l := range latencies {
+			results = append(results, Result{
+				Model:    model,
+				TestCase: "factual-question",
+				Temp:     0.1,
+				Latency:  l,
+				Success:  l > 0, // a zero latency represents a failed request
+			})
+		}
+		return results
+	}
+
+	thresholds := Thresholds{LatencyP95Pct: 10, SuccessRateDrop: 0.05}
+	baseline := newResults("ai/llama3.2:1B-Q4_0", time.Second, time.Second, time.Second, time.Second)
+
+	t.Run("pass", func(t *testing.T) {
+		current := newResults("ai/llama3.2:1B-Q4_0", 1050*time.Millisecond, time.Second, time.Second, time.Second)
+
+		report, err := CompareRuns(baseline, current, thresholds)
+		if err != nil {
+			t.Fatalf("compare runs: %s", err)
+		}
+
+		if report.Regressed {
+			t.Errorf("expected no regression, got %+v", report.Comparisons)
+		}
+		if len(report.Comparisons) != 1 {
+			t.Fatalf("expected 1 comparison, got %d", len(report.Comparisons))
+		}
+	})
+
+	t.Run("latency-regression", func(t *testing.T) {
+		current := newResults("ai/llama3.2:1B-Q4_0", 2*time.Second, 2*time.Second, 2*time.Second, 2*time.Second)
+
+		report, err := CompareRuns(baseline, current, thresholds)
+		if err != nil {
+			t.Fatalf("compare runs: %s", err)
+		}
+
+		if !report.Regressed {
+			t.Fatal("expected a latency regression")
+		}
+		if got := report.Comparisons[0].LatencyChangePct; got != 100 {
+			t.Errorf("expected latency change of 100%%, got %.1f%%", got)
+		}
+	})
+
+	t.Run("success-rate-regression", func(t *testing.T) {
+		current := newResults("ai/llama3.2:1B-Q4_0", time.Second, time.Second, time.Second, 0)
+
+		report, err := CompareRuns(baseline, current, thresholds)
+		if err != nil {
+			t.Fatalf("compare runs: %s", err)
+		}
+
+		if !report.Regressed {
+			t.Fatal("expected a success rate regression")
+		}
+		if got := report.Comparisons[0].CurrentSuccessRate; got != 0.75 {
+			t.Errorf("expected current success rate of 0.75, got %.2f", got)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		current := newResults("ai/qwen3:0.6B-Q4_0", time.Second)
+
+		report, err := CompareRuns(baseline, current, thresholds)
+		if err != nil {
+			t.Fatalf("compare runs: %s", err)
+		}
+
+		if report.Regressed {
+			t.Error("expected missing combinations not to be flagged as regressions")
+		}
+		if len(report.Missing) != 1 {
+			t.Errorf("expected 1 missing combination, got %v", report.Missing)
+		}
+	})
+
+	t.Run("invalid-thresholds", func(t *testing.T) {
+		if _, err := CompareRuns(baseline, baseline, Thresholds{LatencyP95Pct: -1}); err == nil {
+			t.Error("expected an error for negative thresholds")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// Package benchresult defines the results of the benchmark requests, exported by the run to
+// results.json so the runs can be re-scored and compared offline, see cmd/score and cmd/compare.
+package benchresult
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+)
+
+// Result stores the outcome of a single benchmark request. The durations are exported in nanoseconds.
+type Result struct {
+	Model            string             `json:"model"`
+	TestCase         string             `json:"test_case"`
+	Temp             float64            `json:"temperature"`
+	UserPrompt       string             `json:"user_prompt,omitempty"`         // Prompt of the test case, the question the judge evaluates the response against
+	Latency          time.Duration      `json:"latency_ns"`                    // Total turnaround time (TAT)
+	TTFT             time.Duration      `json:"ttft_ns,omitempty"`             // Time To First Token (measured via streaming)
+	PromptEvalTime   time.Duration      `json:"prompt_eval_time_ns,omitempty"` // Time to evaluate prompt (from model metadata if available)
+	InterTokenP50    time.Duration      `json:"inter_token_p50_ns,omitempty"`  // Median time between streamed tokens
+	InterTokenP95    time.Duration      `json:"inter_token_p95_ns,omitempty"`  // 95th percentile time between streamed tokens
+	InterTokenGaps   []time.Duration    `json:"inter_token_gaps_ns,omitempty"` // Times between streamed tokens, pooled across the requests by computeAggregates
+	PromptTokens     int                `json:"prompt_tokens"`                 // Input tokens
+	CompletionTokens int                `json:"completion_tokens"`             // Output tokens generated
+	TotalTokens      int                `json:"total_tokens"`                  // Total tokens (prompt + completion)
+	Success          bool               `json:"success"`
+	Refusal          bool               `json:"refusal,omitempty"`          // Successful, but empty or refusing to answer, see RefusalDetector
+	EvalScore        float64            `json:"eval_score"`                 // Score from evaluator agent (0.0-1.0)
+	EvalResponse     string             `json:"eval_response,omitempty"`    // "yes", "no", or "unsure"
+	EvalReason       string             `json:"eval_reason,omitempty"`      // Reasoning from evaluator
+	EvalCategory     evaluator.Category `json:"eval_category,omitempty"`    // Why the answer is right or wrong, as classified by the evaluator
+	EvalError        string             `json:"eval_error,omitempty"`       // Why the response could not be evaluated, empty when it was
+	ResponseContent  string             `json:"response_content,omitempty"` // The actual LLM response content
+	// Tool calling metrics (only populated for tool-assisted test cases)
+	ToolCallCount         int     `json:"tool_call_count,omitempty"`         // Number of tool calls made
+	ToolIterationCount    int     `json:"tool_iteration_count,omitempty"`    // Number of LLM-tool iterations
+	ToolParamAccuracy     float64 `json:"tool_param_accuracy,omitempty"`     // Tool parameter extraction accuracy (0.0-1.0)
+	ToolSelectionAccuracy float64 `json:"tool_selection_accuracy,omitempty"` // Tool selection accuracy (0.0-1.0)
+	ToolConvergence       float64 `json:"tool_convergence,omitempty"`        // Convergence score (1.0 = optimal path)
+	TokenEfficiency       float64 `json:"token_efficiency,omitempty"`        // Length of the response against the expected one (0.0-1.0)
+	CodeExecutionScore    float64 `json:"code_execution_score,omitempty"`    // Code blocks running and printing the expected output (0.0-1.0), see TestCase.CodeLanguage
+	// Tool calls made, kept to evaluate them once the generation is no longer timed. Not exported,
+	// as their errors don't round-trip through JSON.
+	ToolResults []llmclient.ToolResult `json:"-"`
+}
+
+// Save writes the results to the JSON file at path
+func Save(path string, results []Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal results: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write results: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the results from the JSON file at path, as written by Save
+func Load(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read results: %w", err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("unmarshal results: %w", err)
+	}
+
+	return results, nil
+}
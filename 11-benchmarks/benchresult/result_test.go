@@ -0,0 +1,69 @@
+package benchresult
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+
+	results := []Result{
+		{
+			Model:           "ai/llama3.2:1B-Q4_0",
+			TestCase:        "factual-question",
+			Temp:            0.1,
+			Latency:         1500 * time.Millisecond,
+			TTFT:            200 * time.Millisecond,
+			InterTokenGaps:  []time.Duration{20 * time.Millisecond, 25 * time.Millisecond},
+			TotalTokens:     42,
+			Success:         true,
+			EvalScore:       1,
+			EvalResponse:    "yes",
+			EvalCategory:    evaluator.CategoryCorrect,
+			ResponseContent: "Paris",
+		},
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "factual-question", Temp: 0.1},
+	}
+
+	if err := Save(path, results); err != nil {
+		t.Fatalf("save: %s", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %s", err)
+	}
+
+	if !reflect.DeepEqual(loaded, results) {
+		t.Errorf("expected the results to round-trip:\nexpected %+v\ngot      %+v", results, loaded)
+	}
+
+	t.Run("tool-results", func(t *testing.T) {
+		// The tool calls are only kept during the run, their errors can't be unmarshaled
+		withTools := []Result{{Model: "ai/qwen3:0.6B-Q4_0", ToolCallCount: 1, ToolResults: []llmclient.ToolResult{{ToolName: "weather", Error: errors.New("timeout")}}}}
+		if err := Save(path, withTools); err != nil {
+			t.Fatalf("save: %s", err)
+		}
+
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatalf("load: %s", err)
+		}
+		if loaded[0].ToolCallCount != 1 || loaded[0].ToolResults != nil {
+			t.Errorf("expected the tool call count without the tool results, got %+v", loaded[0])
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("expected an error for a missing file")
+		}
+	})
+}
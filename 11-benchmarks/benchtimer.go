@@ -0,0 +1,20 @@
+package main
+
+// benchTimer is the timer of a benchmark, implemented by *testing.B
+type benchTimer interface {
+	StartTimer()
+	StopTimer()
+}
+
+// runTimedIterations runs the operation n times, stopping the timer while the result of each
+// iteration is recorded, e.g. evaluated, exported as metrics or sampled with the GPU metrics,
+// so the ns/op of the benchmark only measures the operation. The timer must be running.
+func runTimedIterations[T any](timer benchTimer, n int, op func(i int) T, record func(i int, result T)) {
+	for i := 0; i < n; i++ {
+		result := op(i)
+
+		timer.StopTimer()
+		record(i, result)
+		timer.StartTimer()
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeBenchTimer measures the time between the starts and stops of the timer, like testing.B
+type fakeBenchTimer struct {
+	start   time.Time
+	elapsed time.Duration
+}
+
+func (f *fakeBenchTimer) StartTimer() {
+	f.start = time.Now()
+}
+
+func (f *fakeBenchTimer) StopTimer() {
+	f.elapsed += time.Since(f.start)
+}
+
+func TestRunTimedIterations(t *testing.T) {
+	const (
+		iterations   = 5
+		opDuration   = 2 * time.Millisecond
+		slowRecorder = 20 * time.Millisecond
+	)
+
+	timer := &fakeBenchTimer{}
+	var recorded []int
+
+	timer.StartTimer()
+	runTimedIterations(timer, iterations, func(i int) int {
+		time.Sleep(opDuration)
+		return i * 10
+	}, func(i int, result int) {
+		// A slow metric recorder, which must not count in the ns/op
+		time.Sleep(slowRecorder)
+		recorded = append(recorded, result)
+	})
+	timer.StopTimer()
+
+	if len(recorded) != iterations || recorded[iterations-1] != (iterations-1)*10 {
+		t.Fatalf("expected every result to be recorded, got %v", recorded)
+	}
+
+	if timer.elapsed < iterations*opDuration {
+		t.Errorf("expected the operations to be timed, got %s", timer.elapsed)
+	}
+	// The recorder sleeps 100ms in total: half of it would mean it's partially timed
+	if timer.elapsed >= iterations*slowRecorder/2 {
+		t.Errorf("expected the recording to be excluded from the timer, got %s", timer.elapsed)
+	}
+}
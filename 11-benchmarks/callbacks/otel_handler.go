@@ -90,16 +90,13 @@ func (h *OTelCallbackHandler) HandleLLMGenerateContentEnd(ctx context.Context, r
 	}
 
 	// Add token usage if available from GenerationInfo
-	if len(res.Choices) > 0 && res.Choices[0].GenerationInfo != nil {
-		genInfo := res.Choices[0].GenerationInfo
-		if promptTokens, ok := genInfo["prompt_tokens"].(int); ok {
-			span.SetAttributes(attribute.Int(semconv.AttrLLMUsagePromptTokens, promptTokens))
-		}
-		if completionTokens, ok := genInfo["completion_tokens"].(int); ok {
-			span.SetAttributes(attribute.Int(semconv.AttrLLMUsageCompletionTokens, completionTokens))
-		}
-		if totalTokens, ok := genInfo["total_tokens"].(int); ok {
-			span.SetAttributes(attribute.Int(semconv.AttrLLMUsageTotalTokens, totalTokens))
+	if len(res.Choices) > 0 {
+		if promptTokens, completionTokens, totalTokens, ok := ExtractTokenUsage(res.Choices[0].GenerationInfo); ok {
+			span.SetAttributes(
+				attribute.Int(semconv.AttrLLMUsagePromptTokens, promptTokens),
+				attribute.Int(semconv.AttrLLMUsageCompletionTokens, completionTokens),
+				attribute.Int(semconv.AttrLLMUsageTotalTokens, totalTokens),
+			)
 		}
 	}
 }
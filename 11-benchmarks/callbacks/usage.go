@@ -0,0 +1,87 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// tokenUsageKeys are the keys of the token counts in the GenerationInfo of a choice, which depend
+// on the langchaingo provider: the openai provider uses PascalCase keys, while others forward the
+// snake_case fields of the OpenAI API
+var tokenUsageKeys = struct {
+	prompt, completion, total []string
+}{
+	prompt:     []string{"PromptTokens", "prompt_tokens"},
+	completion: []string{"CompletionTokens", "completion_tokens"},
+	total:      []string{"TotalTokens", "total_tokens"},
+}
+
+// ExtractTokenUsage returns the token counts reported by the model in the GenerationInfo of a
+// choice, accepting both the PascalCase and snake_case keys, at the top level or nested in a
+// "usage" object. The total defaults to the sum of the prompt and completion tokens when it's not
+// reported. ok is false when no token count was found, so the caller can fall back to estimates.
+func ExtractTokenUsage(genInfo map[string]any) (prompt, completion, total int, ok bool) {
+	if genInfo == nil {
+		return 0, 0, 0, false
+	}
+
+	prompt, promptOK := lookupTokens(genInfo, tokenUsageKeys.prompt)
+	completion, completionOK := lookupTokens(genInfo, tokenUsageKeys.completion)
+	total, totalOK := lookupTokens(genInfo, tokenUsageKeys.total)
+
+	if !promptOK && !completionOK && !totalOK {
+		return 0, 0, 0, false
+	}
+
+	if !totalOK {
+		total = prompt + completion
+	}
+
+	return prompt, completion, total, true
+}
+
+// lookupTokens returns the first token count found under the keys, looking at the top level
+// of the GenerationInfo first and then in its "usage" object
+func lookupTokens(genInfo map[string]any, keys []string) (int, bool) {
+	for _, key := range keys {
+		if n, ok := toInt(genInfo[key]); ok {
+			return n, true
+		}
+	}
+
+	if usage, ok := genInfo["usage"].(map[string]any); ok {
+		for _, key := range keys {
+			if n, ok := toInt(usage[key]); ok {
+				return n, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// toInt converts the numeric types a token count can be decoded into, e.g. float64 when it
+// comes from a JSON document
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, false
+		}
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return 0, false
+		}
+		return int(i), true
+	default:
+		return 0, false
+	}
+}
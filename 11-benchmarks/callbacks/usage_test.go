@@ -0,0 +1,94 @@
+package callbacks
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractTokenUsage(t *testing.T) {
+	tests := []struct {
+		name           string
+		genInfo        map[string]any
+		wantPrompt     int
+		wantCompletion int
+		wantTotal      int
+		wantOK         bool
+	}{
+		{
+			name:           "pascal-case",
+			genInfo:        map[string]any{"PromptTokens": 12, "CompletionTokens": 30, "TotalTokens": 42},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name:           "snake-case",
+			genInfo:        map[string]any{"prompt_tokens": 12, "completion_tokens": 30, "total_tokens": 42},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name: "usage-nested",
+			genInfo: map[string]any{
+				"usage": map[string]any{"prompt_tokens": 12, "completion_tokens": 30, "total_tokens": 42},
+			},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name: "top-level-wins",
+			genInfo: map[string]any{
+				"PromptTokens": 12,
+				"usage":        map[string]any{"prompt_tokens": 99, "completion_tokens": 30},
+			},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name:           "json-numbers",
+			genInfo:        map[string]any{"prompt_tokens": float64(12), "completion_tokens": json.Number("30"), "total_tokens": int64(42)},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name:           "missing-total",
+			genInfo:        map[string]any{"PromptTokens": 12, "CompletionTokens": 30},
+			wantPrompt:     12,
+			wantCompletion: 30,
+			wantTotal:      42,
+			wantOK:         true,
+		},
+		{
+			name:    "no-usage",
+			genInfo: map[string]any{"StopReason": "stop", "prompt_tokens": "12"},
+			wantOK:  false,
+		},
+		{
+			name:    "nil",
+			genInfo: nil,
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, completion, total, ok := ExtractTokenUsage(tt.genInfo)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			if prompt != tt.wantPrompt || completion != tt.wantCompletion || total != tt.wantTotal {
+				t.Errorf("got %d/%d/%d tokens, want %d/%d/%d", prompt, completion, total, tt.wantPrompt, tt.wantCompletion, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Command compare detects regressions between two benchmark runs, comparing the results.json files
+// written to their run directories (see BENCH_OUTPUT_DIR) and flagging the model/case/temp
+// combinations whose p95 latency or success rate worsened beyond the thresholds. It exits with
+// status 1 when there are regressions, so it can gate a CI job.
+//
+// Usage:
+//
+//	go run ./cmd/compare -baseline runs/<baseline>/results.json -current runs/<current>/results.json -latency-p95-pct 10 -success-rate-drop 0.05
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+)
+
+func main() {
+	baseline := flag.String("baseline", "baseline.json", "results.json of the baseline run")
+	current := flag.String("current", "results.json", "results.json of the run to check")
+	latencyP95Pct := flag.Float64("latency-p95-pct", 10, "maximum allowed increase of the p95 latency, in percent")
+	successRateDrop := flag.Float64("success-rate-drop", 0.05, "maximum allowed drop of the success rate, in absolute terms")
+	flag.Parse()
+
+	thresholds := benchresult.Thresholds{LatencyP95Pct: *latencyP95Pct, SuccessRateDrop: *successRateDrop}
+	report, err := run(*baseline, *current, thresholds, os.Stdout)
+	if err != nil {
+		log.Fatalf("run: %s", err)
+	}
+
+	if report.Regressed {
+		os.Exit(1)
+	}
+}
+
+func run(baselinePath string, currentPath string, thresholds benchresult.Thresholds, w io.Writer) (*benchresult.RegressionReport, error) {
+	baseline, err := benchresult.Load(baselinePath)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+
+	current, err := benchresult.Load(currentPath)
+	if err != nil {
+		return nil, fmt.Errorf("current: %w", err)
+	}
+
+	report, err := benchresult.CompareRuns(baseline, current, thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	printReport(w, report)
+
+	return report, nil
+}
+
+// printReport writes a line per compared combination, with the reasons of the regressions,
+// followed by the combinations missing in the current run
+func printReport(w io.Writer, report *benchresult.RegressionReport) {
+	for _, cmp := range report.Comparisons {
+		status := "ok"
+		if cmp.Regressed {
+			status = "REGRESSED"
+		}
+
+		fmt.Fprintf(w, "%s %s/%s/temp%.1f: p95 %.0fms -> %.0fms (%+.1f%%), success rate %.2f -> %.2f\n",
+			status, cmp.Model, cmp.TestCase, cmp.Temp,
+			cmp.BaselineP95Ms, cmp.CurrentP95Ms, cmp.LatencyChangePct,
+			cmp.BaselineSuccessRate, cmp.CurrentSuccessRate)
+		for _, reason := range cmp.Reasons {
+			fmt.Fprintf(w, "  - %s\n", reason)
+		}
+	}
+
+	for _, key := range report.Missing {
+		fmt.Fprintf(w, "missing %s\n", key)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "baseline.json")
+	current := filepath.Join(dir, "results.json")
+
+	writeFile(t, baseline, `[
+		{"model": "ai/llama3.2:1B-Q4_0", "test_case": "factual-question", "temperature": 0.1, "latency_ns": 1000000000, "success": true, "response_content": "Paris"},
+		{"model": "ai/llama3.2:1B-Q4_0", "test_case": "code-generation", "temperature": 0.1, "latency_ns": 2000000000, "success": true}
+	]`)
+	writeFile(t, current, `[
+		{"model": "ai/llama3.2:1B-Q4_0", "test_case": "factual-question", "temperature": 0.1, "latency_ns": 1500000000, "success": true, "response_content": "Paris"}
+	]`)
+
+	var out bytes.Buffer
+	report, err := run(baseline, current, benchresult.Thresholds{LatencyP95Pct: 10, SuccessRateDrop: 0.05}, &out)
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	if !report.Regressed {
+		t.Error("expected a latency regression")
+	}
+
+	for _, expected := range []string{
+		"REGRESSED ai/llama3.2:1B-Q4_0/factual-question/temp0.1: p95 1000ms -> 1500ms (+50.0%), success rate 1.00 -> 1.00",
+		"  - latency p95 worsened by 50.0% (1000ms -> 1500ms)",
+		"missing ai/llama3.2:1B-Q4_0|code-generation|0.1",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the output to contain %q:\n%s", expected, out.String())
+		}
+	}
+
+	if _, err := run(filepath.Join(dir, "missing.json"), current, benchresult.Thresholds{}, &out); err == nil {
+		t.Error("expected an error for a missing baseline")
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %s", path, err)
+	}
+}
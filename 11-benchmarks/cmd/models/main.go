@@ -0,0 +1,202 @@
+// Command models lists and pulls the models of Docker Model Runner, to pre-warm the cache and
+// check the size of the models before a benchmark run, instead of pulling them when it starts.
+//
+// Usage:
+//
+//	go run ./cmd/models list
+//	go run ./cmd/models pull ai/llama3.2:1B-Q4_0
+//
+// Without -endpoint, the Model Runner API of Docker Desktop is reached through a tunnel container.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/modelrunner"
+)
+
+// Model is a model stored by the Model Runner, as listed by its /models endpoint
+type Model struct {
+	ID     string         `json:"id"`
+	Tags   []string       `json:"tags"`
+	Config map[string]any `json:"config"`
+}
+
+// pullProgress is a line of the progress streamed while pulling a model
+type pullProgress struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-endpoint URL] list | pull <model>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	endpoint := flag.String("endpoint", "", "Model Runner API URL, e.g. http://localhost:12434 (default: tunnel to Docker Desktop)")
+	flag.Parse()
+
+	if err := run(context.Background(), *endpoint, flag.Args(), os.Stdout); err != nil {
+		log.Fatalf("run: %s", err)
+	}
+}
+
+func run(ctx context.Context, endpoint string, args []string, w io.Writer) (err error) {
+	if len(args) == 0 {
+		return errors.New("missing subcommand: list or pull")
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return errors.New("usage: list")
+		}
+	case "pull":
+		if len(args) != 2 {
+			return errors.New("usage: pull <model>")
+		}
+	default:
+		return fmt.Errorf("unknown subcommand %q: use list or pull", args[0])
+	}
+
+	if endpoint == "" {
+		tunnel, err := modelrunner.NewTunnel(ctx)
+		if err != nil {
+			return fmt.Errorf("new tunnel: %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, tunnel.Close())
+		}()
+		endpoint = tunnel.BaseURL()
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	if args[0] == "pull" {
+		return pullModel(ctx, endpoint, args[1], w)
+	}
+
+	models, err := listModels(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	return writeModels(w, models)
+}
+
+// listModels returns the models stored by the Model Runner
+func listModels(ctx context.Context, endpoint string) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("list models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list models returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var models []Model
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("unmarshal models: %w", err)
+	}
+
+	return models, nil
+}
+
+// writeModels writes a table with the tags, parameters, quantization and size of the models,
+// to estimate the disk and memory needs of a run
+func writeModels(w io.Writer, models []Model) error {
+	if len(models) == 0 {
+		_, err := fmt.Fprintln(w, "No models pulled")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tPARAMETERS\tQUANTIZATION\tSIZE")
+	for _, m := range models {
+		name := m.ID
+		if len(m.Tags) > 0 {
+			name = strings.Join(m.Tags, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, configValue(m.Config, "parameters"), configValue(m.Config, "quantization"), configValue(m.Config, "size"))
+	}
+
+	return tw.Flush()
+}
+
+// configValue returns a value of the model config, or "-" if it's not set
+func configValue(config map[string]any, key string) string {
+	v, ok := config[key]
+	if !ok || v == nil || v == "" {
+		return "-"
+	}
+
+	return fmt.Sprint(v)
+}
+
+// pullModel pulls the model into the Model Runner, writing the progress messages
+func pullModel(ctx context.Context, endpoint, model string, w io.Writer) error {
+	payload, err := json.Marshal(map[string]string{"from": model})
+	if err != nil {
+		return fmt.Errorf("marshal pull request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/models/create", strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pull model %s: %w", model, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("pull model %s returned %s: %s", model, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// The progress is streamed as JSON lines, older versions stream plain text
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var progress pullProgress
+		if err := json.Unmarshal([]byte(line), &progress); err != nil {
+			fmt.Fprintln(w, line)
+			continue
+		}
+		if progress.Type == "error" {
+			return fmt.Errorf("pull model %s: %s", model, progress.Message)
+		}
+		fmt.Fprintln(w, progress.Message)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read pull progress: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "Model %s pulled\n", model)
+	return err
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStubModelRunner serves the /models and /models/create endpoints of the Model Runner,
+// adding the pulled models to the listed ones
+func newStubModelRunner(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	models := []Model{
+		{
+			ID:     "sha256:1a2b",
+			Tags:   []string{"ai/llama3.2:1B-Q4_0"},
+			Config: map[string]any{"parameters": "1.24 B", "quantization": "Q4_0", "size": "727.75 MiB"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /models", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(models)
+	})
+	mux.HandleFunc("POST /models/create", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			From string `json:"from"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if req.From == "ai/missing" {
+			w.Write([]byte(`{"type":"error","message":"model not found"}` + "\n"))
+			return
+		}
+
+		w.Write([]byte(`{"type":"progress","message":"Downloaded: 50.00 MB"}` + "\n"))
+		w.Write([]byte(`{"type":"progress","message":"Downloaded: 100.00 MB"}` + "\n"))
+		w.Write([]byte(`{"type":"success","message":"Model pulled successfully"}` + "\n"))
+		models = append(models, Model{ID: "sha256:3c4d", Tags: []string{req.From}})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestRun(t *testing.T) {
+	srv := newStubModelRunner(t)
+	ctx := context.Background()
+
+	t.Run("list", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := run(ctx, srv.URL, []string{"list"}, &out); err != nil {
+			t.Fatalf("list: %s", err)
+		}
+
+		for _, expected := range []string{"MODEL", "SIZE", "ai/llama3.2:1B-Q4_0", "1.24 B", "Q4_0", "727.75 MiB"} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("expected the list to contain %q:\n%s", expected, out.String())
+			}
+		}
+	})
+
+	t.Run("pull", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := run(ctx, srv.URL, []string{"pull", "ai/qwen3:0.6B-Q4_0"}, &out); err != nil {
+			t.Fatalf("pull: %s", err)
+		}
+
+		for _, expected := range []string{"Downloaded: 100.00 MB", "Model ai/qwen3:0.6B-Q4_0 pulled"} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("expected the progress to contain %q:\n%s", expected, out.String())
+			}
+		}
+
+		// The pulled model is listed afterwards
+		out.Reset()
+		if err := run(ctx, srv.URL, []string{"list"}, &out); err != nil {
+			t.Fatalf("list: %s", err)
+		}
+		if !strings.Contains(out.String(), "ai/qwen3:0.6B-Q4_0") {
+			t.Errorf("expected the pulled model to be listed:\n%s", out.String())
+		}
+	})
+
+	t.Run("pull/error", func(t *testing.T) {
+		err := run(ctx, srv.URL, []string{"pull", "ai/missing"}, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), "model not found") {
+			t.Fatalf("expected the pull error, got %v", err)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"push"}, {"pull"}, {"list", "extra"}} {
+			if err := run(ctx, srv.URL, args, &bytes.Buffer{}); err == nil {
+				t.Errorf("expected a usage error for %q", args)
+			}
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Command score re-evaluates the results exported by a benchmark run (results.json, see
+// BENCH_OUTPUT_DIR) with an LLM judge, without running the expensive generation step again.
+// The judge is configured as in the benchmarks, with BENCH_JUDGE_ENDPOINT, BENCH_JUDGE_MODEL and
+// OPENAI_API_KEY. A local judge is served by the remote Docker Model Runner of DMR_ENDPOINT, or by
+// the DMR container of the benchmarks otherwise.
+//
+// Usage:
+//
+//	go run ./cmd/score -in runs/<run>/results.json -out scored.json -judge ai/llama3.2:3B-Q4_K_M
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/dmrremote"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/judge"
+	"github.com/testcontainers/testcontainers-go"
+	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func main() {
+	in := flag.String("in", "results.json", "results exported by the benchmark run to score")
+	out := flag.String("out", "scored.json", "file where the scored results are written")
+	judgeModel := flag.String("judge", "", "judge model, overriding BENCH_JUDGE_MODEL")
+	criteriaDir := flag.String("criteria", "", "directory with the evaluation criteria overriding the embedded ones")
+	flag.Parse()
+
+	if err := run(*in, *out, *judgeModel, *criteriaDir); err != nil {
+		log.Fatalf("run: %s", err)
+	}
+}
+
+func run(in string, out string, judgeModel string, criteriaDir string) (err error) {
+	ctx := context.Background()
+
+	criteria := evaluator.GetCriteria()
+	if criteriaDir != "" {
+		if criteria, err = evaluator.LoadCriteriaFromDir(criteriaDir); err != nil {
+			return fmt.Errorf("load criteria: %w", err)
+		}
+	}
+
+	cfg, err := judge.GetConfig()
+	if err != nil {
+		return fmt.Errorf("judge config: %w", err)
+	}
+	if judgeModel != "" {
+		cfg.Model = judgeModel
+	}
+
+	var runner judge.Runner
+	if cfg.IsLocal() {
+		if runner, err = startJudgeRunner(ctx); err != nil {
+			return err
+		}
+	}
+
+	model, err := judge.New(ctx, cfg, runner)
+	if err != nil {
+		return fmt.Errorf("new judge: %w", err)
+	}
+
+	results, err := benchresult.Load(in)
+	if err != nil {
+		return err
+	}
+
+	scored := score(ctx, model, criteria, results)
+
+	if err := benchresult.Save(out, results); err != nil {
+		return err
+	}
+
+	fmt.Printf("Scored %d/%d results with %s into %s\n", scored, len(results), cfg.Model, out)
+
+	return nil
+}
+
+// startJudgeRunner returns the Docker Model Runner serving a local judge: the remote one of
+// DMR_ENDPOINT, or the DMR container of the benchmarks, reused by name
+func startJudgeRunner(ctx context.Context) (judge.Runner, error) {
+	if endpoint := dmrremote.Endpoint(); endpoint != "" {
+		remote, err := dmrremote.New(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", dmrremote.EnvEndpoint, err)
+		}
+		return remote, nil
+	}
+
+	ctr, err := dmr.Run(ctx, testcontainers.WithReuseByName("dmr-llm-benchmarks"))
+	if err != nil {
+		return nil, fmt.Errorf("run model runner: %w", err)
+	}
+
+	return ctr, nil
+}
+
+// score evaluates each successful result with the judge model, using the evaluation criteria of its
+// test case, and replaces its evaluation. It returns the number of results that were scored. Results
+// without criteria, or failing the evaluation, keep the error in the EvalError field.
+func score(ctx context.Context, judgeModel llms.Model, criteria map[string]evaluator.Criteria, results []benchresult.Result) int {
+	scored := 0
+	for i := range results {
+		r := &results[i]
+		if !r.Success {
+			continue
+		}
+
+		r.EvalScore, r.EvalResponse, r.EvalReason, r.EvalCategory = 0, "", "", ""
+
+		c, ok := criteria[r.TestCase]
+		if !ok {
+			r.EvalError = fmt.Sprintf("no evaluation criteria for test case %q", r.TestCase)
+			continue
+		}
+
+		agent := evaluator.NewAgent(judgeModel, c.SystemPrompt)
+		evalResult, err := agent.Evaluate(ctx, r.Model, r.Temp, r.TestCase, r.UserPrompt, r.ResponseContent, c.Reference)
+		if err != nil {
+			r.EvalError = err.Error()
+			continue
+		}
+
+		r.EvalScore = evalResult.Score
+		r.EvalResponse = evalResult.Response
+		r.EvalReason = evalResult.Reason
+		r.EvalCategory = evalResult.Category
+		r.EvalError = ""
+		scored++
+	}
+
+	return scored
+}
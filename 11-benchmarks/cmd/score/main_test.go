@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeJudge is a judge model answering "yes" to answers containing the expected text, and "no" otherwise.
+type fakeJudge struct {
+	expected string
+}
+
+func (f *fakeJudge) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	verdict := "no"
+	for _, part := range messages[len(messages)-1].Parts {
+		if text, ok := part.(llms.TextContent); ok && strings.Contains(text.Text, f.expected) {
+			verdict = "yes"
+		}
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{
+			{Content: `{"provided_answer": "fake", "response": "` + verdict + `", "reason": "fake judge"}`},
+		},
+	}, nil
+}
+
+func (f *fakeJudge) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func TestScore(t *testing.T) {
+	results := []benchresult.Result{
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "mathematical-operations", Temp: 0.1, UserPrompt: "What is 2+2?", ResponseContent: "The answer is 4", Success: true},
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "mathematical-operations", Temp: 0.9, UserPrompt: "What is 2+2?", ResponseContent: "The answer is 5", Success: true, EvalScore: 1, EvalResponse: "yes"},
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "unknown-case", Temp: 0.1, UserPrompt: "?", ResponseContent: "!", Success: true},
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "mathematical-operations", Temp: 0.1, UserPrompt: "What is 2+2?"},
+	}
+
+	scored := score(context.Background(), &fakeJudge{expected: "is 4"}, evaluator.GetCriteria(), results)
+	if scored != 2 {
+		t.Fatalf("expected 2 scored results, got %d", scored)
+	}
+
+	if results[0].EvalScore != 1.0 || results[0].EvalResponse != "yes" {
+		t.Errorf("expected first result to pass, got score=%.1f response=%q", results[0].EvalScore, results[0].EvalResponse)
+	}
+	// The evaluation of the run is replaced by the one of the judge
+	if results[1].EvalScore != 0.0 || results[1].EvalResponse != "no" {
+		t.Errorf("expected second result to fail, got score=%.1f response=%q", results[1].EvalScore, results[1].EvalResponse)
+	}
+	if results[2].EvalError == "" {
+		t.Errorf("expected an error for a test case without criteria")
+	}
+	if results[3].EvalResponse != "" || results[3].EvalError != "" {
+		t.Errorf("expected the failed request not to be scored, got %+v", results[3])
+	}
+}
+
+func TestRun(t *testing.T) {
+	judgeSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "{\"provided_answer\": \"4\", \"response\": \"yes\", \"reason\": \"correct\"}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer judgeSrv.Close()
+
+	// The judge of the benchmarks, isolated at its endpoint: no model runner is started
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("BENCH_JUDGE_ENDPOINT", judgeSrv.URL)
+	t.Setenv("BENCH_JUDGE_MODEL", "ai/judge")
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "results.json")
+	out := filepath.Join(dir, "scored.json")
+
+	err := benchresult.Save(in, []benchresult.Result{
+		{Model: "ai/llama3.2:1B-Q4_0", TestCase: "mathematical-operations", Temp: 0.1, UserPrompt: "What is 2+2?", ResponseContent: "4", Success: true, Latency: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("save results: %s", err)
+	}
+
+	if err := run(in, out, "", ""); err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	got, err := benchresult.Load(out)
+	if err != nil {
+		t.Fatalf("load scored results: %s", err)
+	}
+	if len(got) != 1 || got[0].EvalResponse != "yes" || got[0].Latency != time.Second {
+		t.Errorf("expected the scored result with its metrics, got %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// containersStopTimeout is the time the containers are given to stop gracefully before being killed
+const containersStopTimeout = 10 * time.Second
+
+// getKeepContainers returns whether the BENCH_KEEP_CONTAINERS environment variable asks to keep the
+// containers running after the benchmark, to explore Grafana. It defaults to false.
+func getKeepContainers() (bool, error) {
+	return getEnvBool("BENCH_KEEP_CONTAINERS")
+}
+
+// teardownContainers terminates the containers of the benchmark, unless keep is set. When keeping
+// them, it returns the command to stop and remove them by hand. Nil containers are skipped.
+func teardownContainers(keep bool, ctrs ...testcontainers.Container) (string, error) {
+	if keep {
+		ids := make([]string, 0, len(ctrs))
+		for _, ctr := range ctrs {
+			if ctr != nil {
+				ids = append(ids, ctr.GetContainerID())
+			}
+		}
+		if len(ids) == 0 {
+			return "", nil
+		}
+		return "docker rm -f " + strings.Join(ids, " "), nil
+	}
+
+	var errs []error
+	for _, ctr := range ctrs {
+		if err := testcontainers.TerminateContainer(ctr, testcontainers.StopTimeout(containersStopTimeout)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return "", errors.Join(errs...)
+}
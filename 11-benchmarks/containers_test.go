@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// fakeContainer records the termination of a container, without Docker
+type fakeContainer struct {
+	testcontainers.Container
+	id           string
+	terminateErr error
+	terminated   bool
+}
+
+func (c *fakeContainer) GetContainerID() string {
+	return c.id
+}
+
+func (c *fakeContainer) Terminate(_ context.Context, _ ...testcontainers.TerminateOption) error {
+	c.terminated = true
+	return c.terminateErr
+}
+
+func TestGetKeepContainers(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+		wantErr  bool
+	}{
+		{value: "", expected: false},
+		{value: "true", expected: true},
+		{value: "1", expected: true},
+		{value: "false", expected: false},
+		{value: "yes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("BENCH_KEEP_CONTAINERS", tt.value)
+
+			keep, err := getKeepContainers()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if keep != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, keep)
+			}
+		})
+	}
+}
+
+func TestTeardownContainers(t *testing.T) {
+	t.Run("terminate", func(t *testing.T) {
+		lgtmCtr := &fakeContainer{id: "lgtm"}
+		dmrCtr := &fakeContainer{id: "dmr"}
+
+		cmd, err := teardownContainers(false, lgtmCtr, dmrCtr)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cmd != "" {
+			t.Errorf("expected no cleanup command, got %q", cmd)
+		}
+		if !lgtmCtr.terminated || !dmrCtr.terminated {
+			t.Error("expected all the containers terminated")
+		}
+	})
+
+	t.Run("terminate/error", func(t *testing.T) {
+		lgtmCtr := &fakeContainer{id: "lgtm", terminateErr: errors.New("boom")}
+		dmrCtr := &fakeContainer{id: "dmr"}
+
+		if _, err := teardownContainers(false, lgtmCtr, nil, dmrCtr); err == nil {
+			t.Fatal("expected an error")
+		}
+		// A failure doesn't prevent terminating the rest of the containers
+		if !dmrCtr.terminated {
+			t.Error("expected the DMR container terminated")
+		}
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		lgtmCtr := &fakeContainer{id: "lgtm"}
+		dmrCtr := &fakeContainer{id: "dmr"}
+
+		cmd, err := teardownContainers(true, lgtmCtr, nil, dmrCtr)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if expected := "docker rm -f lgtm dmr"; cmd != expected {
+			t.Errorf("expected %q, got %q", expected, cmd)
+		}
+		if lgtmCtr.terminated || dmrCtr.terminated {
+			t.Error("expected the containers kept running")
+		}
+	})
+}
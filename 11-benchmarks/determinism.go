@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DeterminismResult contains the variance of the outputs of the same prompt run several times
+type DeterminismResult struct {
+	// Score is the mean pairwise similarity of the outputs, from 0 (all different) to 1 (all identical)
+	Score float64
+	// Runs is the number of successful generations the score is computed from
+	Runs int
+	// Distinct is the number of distinct outputs
+	Distinct int
+}
+
+// MeasureDeterminism runs the generation the given times and returns the mean pairwise similarity of
+// the outputs, to characterize whether a model answers the same prompt consistently at a fixed
+// temperature and seed. The failed generations are skipped, returning their joined errors if
+// fewer than two succeed.
+func MeasureDeterminism(ctx context.Context, runs int, generate func(ctx context.Context) (string, error)) (DeterminismResult, error) {
+	if runs < 2 {
+		return DeterminismResult{}, fmt.Errorf("at least 2 runs are needed to measure the determinism, got %d", runs)
+	}
+
+	var outputs []string
+	var errs []error
+	for i := 0; i < runs; i++ {
+		output, err := generate(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("run %d: %w", i+1, err))
+			continue
+		}
+		outputs = append(outputs, output)
+	}
+
+	if len(outputs) < 2 {
+		return DeterminismResult{}, fmt.Errorf("not enough successful runs: %w", errors.Join(errs...))
+	}
+
+	return DeterminismResult{
+		Score:    DeterminismScore(outputs),
+		Runs:     len(outputs),
+		Distinct: countDistinct(outputs),
+	}, nil
+}
+
+// DeterminismScore returns the mean of the similarity of each pair of outputs, 1 for fewer than two outputs
+func DeterminismScore(outputs []string) float64 {
+	if len(outputs) < 2 {
+		return 1
+	}
+
+	words := make([][]string, len(outputs))
+	for i, output := range outputs {
+		words[i] = strings.Fields(output)
+	}
+
+	var sum float64
+	var pairs int
+	for i := 0; i < len(words); i++ {
+		for j := i + 1; j < len(words); j++ {
+			sum += wordSimilarity(words[i], words[j])
+			pairs++
+		}
+	}
+
+	return sum / float64(pairs)
+}
+
+// wordSimilarity returns one minus the word-level edit distance of the texts, normalized by the
+// length of the longest one. Words are compared instead of characters, as a different word is a
+// different output regardless of its length, and it keeps the distance cheap for long responses.
+func wordSimilarity(a, b []string) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance returns the Levenshtein distance of the word sequences
+func editDistance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// countDistinct returns the number of distinct outputs
+func countDistinct(outputs []string) int {
+	distinct := make(map[string]struct{}, len(outputs))
+	for _, output := range outputs {
+		distinct[output] = struct{}{}
+	}
+
+	return len(distinct)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// fakeModel answers the prompt with the outputs in order, cycling through them
+type fakeModel struct {
+	outputs []string
+	calls   int
+}
+
+func (f *fakeModel) Generate(ctx context.Context) (string, error) {
+	output := f.outputs[f.calls%len(f.outputs)]
+	f.calls++
+	return output, nil
+}
+
+func TestMeasureDeterminism(t *testing.T) {
+	ctx := context.Background()
+
+	deterministic := &fakeModel{outputs: []string{"The capital of France is Paris."}}
+	nondeterministic := &fakeModel{outputs: []string{
+		"The capital of France is Paris.",
+		"Paris is the capital city of France, known for the Eiffel Tower.",
+		"France's capital? That would be Paris.",
+	}}
+
+	det, err := MeasureDeterminism(ctx, 5, deterministic.Generate)
+	if err != nil {
+		t.Fatalf("measure deterministic: %s", err)
+	}
+	if det.Score != 1 || det.Distinct != 1 || det.Runs != 5 {
+		t.Errorf("expected a perfect score for the deterministic model, got %+v", det)
+	}
+
+	nondet, err := MeasureDeterminism(ctx, 5, nondeterministic.Generate)
+	if err != nil {
+		t.Fatalf("measure nondeterministic: %s", err)
+	}
+	if nondet.Score >= det.Score || nondet.Distinct != 3 {
+		t.Errorf("expected a lower score for the nondeterministic model, got %+v", nondet)
+	}
+
+	t.Run("failed-runs-skipped", func(t *testing.T) {
+		calls := 0
+		result, err := MeasureDeterminism(ctx, 4, func(ctx context.Context) (string, error) {
+			calls++
+			if calls%2 == 0 {
+				return "", errors.New("timeout")
+			}
+			return "same", nil
+		})
+		if err != nil {
+			t.Fatalf("measure: %s", err)
+		}
+		if result.Runs != 2 || result.Score != 1 {
+			t.Errorf("expected 2 successful runs, got %+v", result)
+		}
+	})
+
+	t.Run("not-enough-runs", func(t *testing.T) {
+		_, err := MeasureDeterminism(ctx, 3, func(ctx context.Context) (string, error) {
+			return "", fmt.Errorf("timeout")
+		})
+		if err == nil {
+			t.Error("expected an error when fewer than two runs succeed")
+		}
+
+		if _, err := MeasureDeterminism(ctx, 1, deterministic.Generate); err == nil {
+			t.Error("expected an error for a single run")
+		}
+	})
+}
+
+func TestDeterminismScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		outputs  []string
+		expected float64
+	}{
+		{name: "identical", outputs: []string{"a b c", "a b c", "a b c"}, expected: 1},
+		{name: "whitespace-ignored", outputs: []string{"a b  c", "a\nb c"}, expected: 1},
+		{name: "one-word-changed", outputs: []string{"a b c d", "a b x d"}, expected: 0.75},
+		{name: "all-different", outputs: []string{"a b", "c d"}, expected: 0},
+		{name: "mean-of-pairs", outputs: []string{"a b", "a b", "c d"}, expected: 1.0 / 3},
+		{name: "empty-outputs", outputs: []string{"", ""}, expected: 1},
+		{name: "single-output", outputs: []string{"a"}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeterminismScore(tt.outputs); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f", tt.expected, got)
+			}
+		})
+	}
+}
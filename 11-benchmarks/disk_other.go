@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// availableDiskBytes is not supported on this platform, so the disk space preflight is skipped
+func availableDiskBytes(path string) (uint64, error) {
+	return 0, fmt.Errorf("disk space check is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// availableDiskBytes returns the bytes available to unprivileged users in the filesystem of the path
+func availableDiskBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("statfs %s: %w", path, err)
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
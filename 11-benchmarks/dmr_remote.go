@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/dmrremote"
+	"github.com/testcontainers/testcontainers-go"
+	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+)
+
+// ModelRunner is the Docker Model Runner the models are pulled into and benchmarked on: the local
+// container, or a remote one reached over TCP
+type ModelRunner interface {
+	ModelPuller
+	// OpenAIEndpoint returns the URL of the OpenAI-compatible API
+	OpenAIEndpoint() string
+}
+
+// startModelRunner returns the remote Docker Model Runner at the endpoint, skipping the local container,
+// or starts the local one with startLocal when the endpoint is empty. The local container is returned
+// too, to terminate it after the benchmark, nil for the remote one.
+func startModelRunner(ctx context.Context, endpoint string, startLocal func(context.Context) (*dmr.Container, error)) (ModelRunner, testcontainers.Container, error) {
+	if endpoint != "" {
+		remote, err := dmrremote.New(endpoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", dmrremote.EnvEndpoint, err)
+		}
+		return remote, nil, nil
+	}
+
+	local, err := startLocal(ctx)
+	// Avoid a typed nil container, the teardown only skips the nil ones
+	var ctr testcontainers.Container
+	if local != nil {
+		ctr = local
+	}
+	if err != nil {
+		return nil, ctr, fmt.Errorf("start local model runner: %w", err)
+	}
+
+	return local, ctr, nil
+}
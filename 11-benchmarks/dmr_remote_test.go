@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+)
+
+func TestStartModelRunner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("remote", func(t *testing.T) {
+		for _, endpoint := range []string{"http://gpu-box:12434", "http://gpu-box:12434/", "http://gpu-box:12434/engines/v1"} {
+			started := false
+			runner, ctr, err := startModelRunner(ctx, endpoint, func(context.Context) (*dmr.Container, error) {
+				started = true
+				return nil, errors.New("unexpected local start")
+			})
+			if err != nil {
+				t.Fatalf("start model runner at %s: %s", endpoint, err)
+			}
+			if started {
+				t.Errorf("expected the local container not to start with the endpoint %s", endpoint)
+			}
+			if ctr != nil {
+				t.Errorf("expected no container to terminate, got %v", ctr)
+			}
+			if got := runner.OpenAIEndpoint(); got != "http://gpu-box:12434/engines/v1" {
+				t.Errorf("expected the OpenAI endpoint of the remote runner, got %s", got)
+			}
+		}
+	})
+
+	t.Run("invalid-endpoint", func(t *testing.T) {
+		for _, endpoint := range []string{"gpu-box:12434", "tcp://gpu-box:12434", "http://", "http://gpu box"} {
+			started := false
+			_, _, err := startModelRunner(ctx, endpoint, func(context.Context) (*dmr.Container, error) {
+				started = true
+				return nil, errors.New("unexpected local start")
+			})
+			if err == nil {
+				t.Errorf("expected an error for the endpoint %q", endpoint)
+			}
+			if started {
+				t.Errorf("expected the local container not to start with the invalid endpoint %q", endpoint)
+			}
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		started := false
+		_, ctr, err := startModelRunner(ctx, "", func(context.Context) (*dmr.Container, error) {
+			started = true
+			return nil, errors.New("docker not available")
+		})
+		if !started {
+			t.Fatal("expected the local container to start without an endpoint")
+		}
+		if err == nil {
+			t.Error("expected the error starting the local container")
+		}
+		if ctr != nil {
+			t.Errorf("expected a nil container, not a typed nil, got %#v", ctr)
+		}
+	})
+}
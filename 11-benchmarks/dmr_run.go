@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+)
+
+// RunOptions configures the inference of the Docker Model Runner started by RunDMR.
+//
+// The DMR container is a proxy to the Model Runner of Docker Desktop, or of Docker CE, so the
+// inference is not configured through its environment: DMR accepts the llama.cpp runtime flags
+// per model, as `docker model configure` does. The supported knobs are the GPU offloading
+// (--n-gpu-layers) and the CPU threads (--threads), applied with ConfigureModel before the first
+// load of each model.
+type RunOptions struct {
+	// CPUOnly keeps all the layers of the models on the CPU, to compare the models fairly on
+	// machines with and without a GPU
+	CPUOnly bool
+	// Threads is the number of CPU threads of the inference, zero uses the llama.cpp default
+	Threads int
+	// ReuseName is the name of the container to reuse across runs, empty to always start a new one
+	ReuseName string
+}
+
+// RuntimeFlags returns the llama.cpp runtime flags of the options, nil for the defaults
+func (o RunOptions) RuntimeFlags() []string {
+	var flags []string
+	if o.CPUOnly {
+		flags = append(flags, "--n-gpu-layers", "0")
+	}
+	if o.Threads > 0 {
+		flags = append(flags, "--threads", strconv.Itoa(o.Threads))
+	}
+
+	return flags
+}
+
+// customizers returns the customizers of the DMR container pulling the model, if any
+func (o RunOptions) customizers(fqModel string) []testcontainers.ContainerCustomizer {
+	var opts []testcontainers.ContainerCustomizer
+	if o.ReuseName != "" {
+		opts = append(opts, testcontainers.WithReuseByName(o.ReuseName))
+	}
+	if fqModel != "" {
+		opts = append(opts, dmr.WithModel(fqModel))
+	}
+
+	return opts
+}
+
+// RunDMR starts the Docker Model Runner container, pulling the model if it's not empty, and
+// configures its inference with the runtime flags of the options
+func RunDMR(ctx context.Context, fqModel string, opts RunOptions) (*dmr.Container, error) {
+	ctr, err := dmr.Run(ctx, opts.customizers(fqModel)...)
+	if err != nil {
+		return ctr, fmt.Errorf("run model runner: %w", err)
+	}
+
+	if fqModel != "" {
+		if err := ConfigureModel(ctx, ctr.OpenAIEndpoint(), fqModel, opts.RuntimeFlags()); err != nil {
+			return ctr, fmt.Errorf("configure model: %w", err)
+		}
+	}
+
+	return ctr, nil
+}
+
+// ConfigureModel sets the llama.cpp runtime flags of the model in the Docker Model Runner of the
+// OpenAI-compatible endpoint, e.g. http://host:port/engines/v1. It must be called before the
+// model is loaded by its first request. Without flags, the model is left as is.
+func ConfigureModel(ctx context.Context, openAIEndpoint, model string, runtimeFlags []string) error {
+	if len(runtimeFlags) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(struct {
+		Model        string   `json:"model"`
+		RuntimeFlags []string `json:"runtime-flags"`
+	}{Model: model, RuntimeFlags: runtimeFlags})
+	if err != nil {
+		return fmt.Errorf("marshal configuration: %w", err)
+	}
+
+	configureURL := strings.TrimSuffix(strings.TrimSuffix(openAIEndpoint, "/"), "/v1") + "/_configure"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, configureURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create configure request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("configure request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("configure request returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// ModelConfigurator configures the runtime flags of each model once. DMR rejects the configuration
+// of a loaded model with 409 Conflict, and the benchmarks pull each model again before running it,
+// so only the first pull, before the model is loaded, configures it. The zero value is ready to use.
+type ModelConfigurator struct {
+	mu         sync.Mutex
+	configured map[string]bool
+}
+
+// Configure calls ConfigureModel for the model, unless it was already configured
+func (c *ModelConfigurator) Configure(ctx context.Context, openAIEndpoint, model string, runtimeFlags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.configured[model] {
+		return nil
+	}
+
+	if err := ConfigureModel(ctx, openAIEndpoint, model, runtimeFlags); err != nil {
+		return err
+	}
+
+	if c.configured == nil {
+		c.configured = map[string]bool{}
+	}
+	c.configured[model] = true
+
+	return nil
+}
+
+// getDMRRunOptions returns the inference options of the BENCH_DMR_CPU_ONLY and BENCH_DMR_THREADS
+// environment variables
+func getDMRRunOptions() (RunOptions, error) {
+	cpuOnly, err := getEnvBool("BENCH_DMR_CPU_ONLY")
+	if err != nil {
+		return RunOptions{}, err
+	}
+
+	opts := RunOptions{CPUOnly: cpuOnly}
+	if value := os.Getenv("BENCH_DMR_THREADS"); value != "" {
+		threads, err := strconv.Atoi(value)
+		if err != nil || threads < 1 {
+			return RunOptions{}, fmt.Errorf("invalid BENCH_DMR_THREADS %q: must be a positive integer", value)
+		}
+		opts.Threads = threads
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	dmr "github.com/testcontainers/testcontainers-go/modules/dockermodelrunner"
+)
+
+func TestRunOptions(t *testing.T) {
+	t.Run("runtime-flags", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			opts     RunOptions
+			expected []string
+		}{
+			{name: "defaults", opts: RunOptions{}},
+			{name: "cpu-only", opts: RunOptions{CPUOnly: true}, expected: []string{"--n-gpu-layers", "0"}},
+			{name: "threads", opts: RunOptions{Threads: 4}, expected: []string{"--threads", "4"}},
+			{name: "cpu-only-with-threads", opts: RunOptions{CPUOnly: true, Threads: 8}, expected: []string{"--n-gpu-layers", "0", "--threads", "8"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := tt.opts.RuntimeFlags(); !slices.Equal(got, tt.expected) {
+					t.Errorf("expected runtime flags %v, got %v", tt.expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("customizers", func(t *testing.T) {
+		customizers := RunOptions{CPUOnly: true, Threads: 4, ReuseName: "dmr-llm-benchmarks"}.customizers("ai/llama3.2:1B-Q4_0")
+		if len(customizers) != 2 {
+			t.Fatalf("expected the reuse and model customizers, got %d", len(customizers))
+		}
+
+		var req testcontainers.GenericContainerRequest
+		for _, c := range customizers {
+			if err := c.Customize(&req); err != nil {
+				t.Fatalf("customize: %s", err)
+			}
+		}
+		if req.Name != "dmr-llm-benchmarks" || !req.Reuse {
+			t.Errorf("expected the container to be reused by name, got name %q and reuse %t", req.Name, req.Reuse)
+		}
+		if _, ok := customizers[1].(dmr.Option); !ok {
+			t.Errorf("expected the model option of the module, got %T", customizers[1])
+		}
+
+		if customizers := (RunOptions{}).customizers(""); len(customizers) != 0 {
+			t.Errorf("expected no customizers by default, got %d", len(customizers))
+		}
+	})
+}
+
+func TestConfigureModel(t *testing.T) {
+	var requests int
+	var configured struct {
+		Model        string   `json:"model"`
+		RuntimeFlags []string `json:"runtime-flags"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost || r.URL.Path != "/engines/_configure" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&configured); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if configured.Model == "ai/loaded:latest" {
+			http.Error(w, "runner already active", http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	flags := RunOptions{CPUOnly: true, Threads: 4}.RuntimeFlags()
+
+	if err := ConfigureModel(ctx, srv.URL+"/engines/v1", "ai/llama3.2:1B-Q4_0", flags); err != nil {
+		t.Fatalf("configure model: %s", err)
+	}
+	if configured.Model != "ai/llama3.2:1B-Q4_0" || !slices.Equal(configured.RuntimeFlags, flags) {
+		t.Errorf("unexpected configuration: %+v", configured)
+	}
+
+	if err := ConfigureModel(ctx, srv.URL+"/engines/v1", "ai/loaded:latest", flags); err == nil {
+		t.Error("expected an error configuring a loaded model")
+	}
+
+	// The defaults don't need any request
+	requests = 0
+	if err := ConfigureModel(ctx, srv.URL+"/engines/v1", "ai/llama3.2:1B-Q4_0", nil); err != nil || requests != 0 {
+		t.Errorf("expected no request without flags, got %d requests and error %v", requests, err)
+	}
+}
+
+func TestModelConfigurator(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// DMR rejects the configuration once the model is loaded by its first request
+		if requests > 1 {
+			http.Error(w, "runner already active", http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	flags := RunOptions{CPUOnly: true}.RuntimeFlags()
+
+	var configurator ModelConfigurator
+	for i := range 3 {
+		if err := configurator.Configure(ctx, srv.URL+"/engines/v1", "ai/llama3.2:1B-Q4_0", flags); err != nil {
+			t.Fatalf("configure model (pull %d): %s", i+1, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the model to be configured once, got %d requests", requests)
+	}
+
+	// A failed configuration is retried by the next pull
+	if err := configurator.Configure(ctx, srv.URL+"/engines/v1", "ai/smollm2:latest", flags); err == nil {
+		t.Fatal("expected an error configuring a loaded model")
+	}
+	if err := configurator.Configure(ctx, srv.URL+"/engines/v1", "ai/smollm2:latest", flags); err == nil || requests != 3 {
+		t.Errorf("expected the configuration to be retried, got %d requests and error %v", requests, err)
+	}
+}
+
+func TestGetDMRRunOptions(t *testing.T) {
+	t.Setenv("BENCH_DMR_CPU_ONLY", "true")
+	t.Setenv("BENCH_DMR_THREADS", "6")
+
+	opts, err := getDMRRunOptions()
+	if err != nil {
+		t.Fatalf("get run options: %s", err)
+	}
+	if !opts.CPUOnly || opts.Threads != 6 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	for _, invalid := range []string{"0", "-1", "many"} {
+		t.Setenv("BENCH_DMR_THREADS", invalid)
+		if _, err := getDMRRunOptions(); err == nil {
+			t.Errorf("expected an error for BENCH_DMR_THREADS=%s", invalid)
+		}
+	}
+}
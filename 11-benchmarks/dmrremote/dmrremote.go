@@ -0,0 +1,94 @@
+// Package dmrremote reaches a remote Docker Model Runner over TCP, e.g. on a shared GPU box, to pull
+// and serve the models instead of the local container. See DMR_ENDPOINT.
+package dmrremote
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+)
+
+// EnvEndpoint is the environment variable with the base URL of a remote Docker Model Runner,
+// e.g. http://gpu-box:12434, to benchmark the models on shared infrastructure instead of starting
+// the local one
+const EnvEndpoint = "DMR_ENDPOINT"
+
+// openAIPath is the path of the OpenAI-compatible API of Docker Model Runner
+const openAIPath = "/engines/v1"
+
+// Runner is a Docker Model Runner reached over TCP, e.g. on a shared GPU box
+type Runner struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New returns the remote Docker Model Runner at the endpoint, its base URL or its OpenAI-compatible
+// one. The endpoint must be an absolute http or https URL.
+func New(endpoint string) (*Runner, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint %q must be an http or https URL", endpoint)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("endpoint %q has no host", endpoint)
+	}
+
+	baseURL := strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), openAIPath)
+
+	return &Runner{baseURL: baseURL, httpClient: http.DefaultClient}, nil
+}
+
+// Endpoint returns the endpoint of the remote Docker Model Runner defined by the DMR_ENDPOINT
+// environment variable, empty when it's not set
+func Endpoint() string {
+	return strings.TrimSpace(os.Getenv(EnvEndpoint))
+}
+
+// OpenAIEndpoint returns the URL of the OpenAI-compatible API of the remote Docker Model Runner
+func (r *Runner) OpenAIEndpoint() string {
+	return r.baseURL + openAIPath
+}
+
+// PullModel pulls the model into the remote Docker Model Runner, as the container does for the local one
+func (r *Runner) PullModel(ctx context.Context, model string) error {
+	payload := fmt.Sprintf(`{"from": %q}`, model)
+	reqURL := r.baseURL + "/models/create"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("new pull request (%s): %w", reqURL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	logging.Default().Info("🙏 Pulling model into the remote Docker Model Runner, please be patient", "model", model, "endpoint", r.baseURL)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pull request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pull request returned %d", resp.StatusCode)
+	}
+
+	// The progress is streamed until the pull completes
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		logging.Default().Debug("Pull progress", "model", model, "progress", scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read pull progress: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package dmrremote
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunnerPullModel(t *testing.T) {
+	var pulled string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/models/create" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		pulled = string(body)
+		if pulled == `{"from": "ai/missing:latest"}` {
+			http.Error(w, "model not found", http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte("{\"type\":\"progress\"}\n{\"type\":\"success\"}\n"))
+	}))
+	defer srv.Close()
+
+	runner, err := New(srv.URL)
+	if err != nil {
+		t.Fatalf("new remote model runner: %s", err)
+	}
+
+	if err := runner.PullModel(context.Background(), "ai/llama3.2:1B-Q4_0"); err != nil {
+		t.Fatalf("pull model: %s", err)
+	}
+	if pulled != `{"from": "ai/llama3.2:1B-Q4_0"}` {
+		t.Errorf("expected a pull request of the model, got %s", pulled)
+	}
+
+	if err := runner.PullModel(context.Background(), "ai/missing:latest"); err == nil {
+		t.Error("expected an error pulling a missing model")
+	}
+}
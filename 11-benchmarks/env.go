@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// getEnvBool returns the boolean value of the environment variable, false when it's not set
+func getEnvBool(name string) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+
+	return b, nil
+}
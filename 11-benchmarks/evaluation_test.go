@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// stubCodeRunner runs every code block successfully, printing the output
+type stubCodeRunner struct {
+	output string
+}
+
+func (r stubCodeRunner) Execute(string) (string, error) {
+	return `{"stdout": "` + r.output + `\n", "exit_code": 0}`, nil
+}
+
+func TestEvaluateCodeExecution(t *testing.T) {
+	tc := TestCase{Name: "code-generation", CodeLanguage: "go", ExpectedOutput: "55"}
+	answer := "```go\npackage main\n\nfunc main() { println(55) }\n```"
+
+	tests := []struct {
+		name      string
+		output    string
+		judged    bool
+		evalScore float64
+		expected  float64
+	}{
+		{name: "judged", output: "55", judged: true, evalScore: 0.5, expected: 0.75},
+		{name: "wrong-output", output: "34", judged: true, evalScore: 1, expected: 0.75},
+		{name: "not-judged", output: "55", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := codeRunner
+			codeRunner = stubCodeRunner{output: tt.output}
+			t.Cleanup(func() { codeRunner = original })
+
+			result := &BenchmarkResult{Model: "ai/smollm2", TestCase: tc.Name, Success: true, ResponseContent: answer, EvalScore: tt.evalScore}
+			evaluateCodeExecution(context.Background(), result, tc, tt.judged)
+
+			if result.EvalScore != tt.expected {
+				t.Errorf("expected the score %.2f, got %.2f", tt.expected, result.EvalScore)
+			}
+			if result.CodeExecutionScore == 0 || result.EvalReason == "" {
+				t.Errorf("expected the code execution score and reason, got %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/markdown"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/tools"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// CodeRunner executes code, taking a tools.CodeExecutorInput and returning a tools.CodeExecutorResult
+// as JSON. It's satisfied by tools.CodeExecutor, running the code in an isolated container.
+type CodeRunner interface {
+	Execute(inputJSON string) (string, error)
+}
+
+// CodeExecutionResult represents the functional correctness of the code blocks of a response
+type CodeExecutionResult struct {
+	Blocks         int     `json:"blocks"`          // Code blocks executed
+	Succeeded      int     `json:"succeeded"`       // Code blocks exiting successfully
+	ExecutionScore float64 `json:"execution_score"` // 0.0-1.0: code blocks exiting successfully
+	OutputMatch    bool    `json:"output_match"`    // The output of a successful block contains the expected one
+	Score          float64 `json:"score"`           // 0.0-1.0: execution score, averaged with the output match if expected
+	Reason         string  `json:"reason"`          // Explanation
+}
+
+// EvaluateCodeExecution runs the code blocks of the answer in the language, e.g. python or go, through
+// the runner, giving an objective signal of the functional correctness of the generated code, on top of
+// the judge's opinion. The score is the fraction of blocks exiting successfully. When an expected output
+// is given, it's averaged with whether a successful block prints it. The blocks in other languages are
+// skipped, the ones without a language are assumed to be in the language, and an answer without
+// runnable blocks scores zero.
+func EvaluateCodeExecution(ctx context.Context, runner CodeRunner, language string, answer string, expectedOutput string) (*CodeExecutionResult, error) {
+	language = tools.NormalizeLanguage(language)
+	result := &CodeExecutionResult{}
+
+	var failures []string
+	for _, block := range markdown.ExtractCodeBlocks(answer) {
+		if block.Language != "" && tools.NormalizeLanguage(block.Language) != language {
+			continue
+		}
+		result.Blocks++
+
+		input, err := json.Marshal(tools.CodeExecutorInput{Code: block.Code, Language: language})
+		if err != nil {
+			return nil, fmt.Errorf("marshal code executor input: %w", err)
+		}
+
+		// The runner returns an error when the code fails, along with its result
+		output, execErr := runner.Execute(string(input))
+
+		var execResult tools.CodeExecutorResult
+		if err := json.Unmarshal([]byte(output), &execResult); err != nil {
+			if execErr != nil {
+				return nil, fmt.Errorf("execute code block %d: %w", result.Blocks, execErr)
+			}
+			return nil, fmt.Errorf("unmarshal code executor result: %w", err)
+		}
+
+		if execErr != nil || execResult.ExitCode != 0 {
+			failures = append(failures, fmt.Sprintf("block %d exited with code %d", result.Blocks, execResult.ExitCode))
+			continue
+		}
+		result.Succeeded++
+
+		if expectedOutput != "" && strings.Contains(execResult.Stdout, strings.TrimSpace(expectedOutput)) {
+			result.OutputMatch = true
+		}
+	}
+
+	if result.Blocks == 0 {
+		result.Reason = fmt.Sprintf("No %s code blocks to execute.", language)
+		return result, nil
+	}
+
+	result.ExecutionScore = float64(result.Succeeded) / float64(result.Blocks)
+	result.Score = result.ExecutionScore
+	if expectedOutput != "" {
+		outputScore := 0.0
+		if result.OutputMatch {
+			outputScore = 1.0
+		}
+		result.Score = (result.ExecutionScore + outputScore) / 2.0
+	}
+
+	result.Reason = fmt.Sprintf("%d of %d code blocks executed successfully.", result.Succeeded, result.Blocks)
+	if len(failures) > 0 {
+		result.Reason += " Failed: " + strings.Join(failures, ", ") + "."
+	}
+	if expectedOutput != "" && !result.OutputMatch {
+		result.Reason += " None printed the expected output."
+	}
+
+	// Log the code execution evaluation result
+	logger := global.GetLoggerProvider().Logger("evaluator")
+	var record log.Record
+	record.SetSeverity(log.SeverityInfo)
+	record.SetBody(log.StringValue("Code execution evaluation"))
+	record.AddAttributes(
+		log.Int("blocks", result.Blocks),
+		log.Int("succeeded", result.Succeeded),
+		log.Bool("output_match", result.OutputMatch),
+		log.Float64("score", result.Score),
+		log.String("reason", sanitizeUTF8(truncateString(result.Reason, 500))),
+	)
+	logger.Emit(ctx, record)
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/tools"
+)
+
+// fakeRunner "executes" the code without containers: the code raising an error fails, and the
+// code printing a literal writes it to the stdout
+type fakeRunner struct {
+	executed  []string
+	languages []string
+}
+
+func (r *fakeRunner) Execute(inputJSON string) (string, error) {
+	var input tools.CodeExecutorInput
+	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+		return "", err
+	}
+	r.executed = append(r.executed, input.Code)
+	r.languages = append(r.languages, input.Language)
+
+	var result tools.CodeExecutorResult
+	var err error
+	if strings.Contains(input.Code, "raise") {
+		result.ExitCode = 1
+		result.Stdout = "Traceback (most recent call last):\nValueError"
+		err = errors.New("code execution failed with exit code 1")
+	} else if _, printed, ok := strings.Cut(input.Code, "print('"); ok {
+		result.Stdout, _, _ = strings.Cut(printed, "'")
+	}
+
+	output, _ := json.Marshal(result)
+	return string(output), err
+}
+
+func TestEvaluateCodeExecution(t *testing.T) {
+	correct := "Here is the code:\n\n```python\nprint('0 1 1 2 3 5 8 13 21 34')\n```\n\nIt prints the sequence."
+	incorrect := "Here is the code:\n\n```python\nraise ValueError('fibonacci')\n```\n\nIt prints the sequence."
+	mixed := "First:\n```py\nprint('0 1 1 2 3 5 8 13 21 34')\n```\nThen:\n```python\nraise ValueError('oops')\n```"
+	expected := "0 1 1 2 3 5 8 13 21 34"
+
+	tests := []struct {
+		name           string
+		answer         string
+		expectedOutput string
+		blocks         int
+		executionScore float64
+		score          float64
+	}{
+		{name: "correct", answer: correct, expectedOutput: expected, blocks: 1, executionScore: 1, score: 1},
+		{name: "incorrect", answer: incorrect, expectedOutput: expected, blocks: 1, executionScore: 0, score: 0},
+		{name: "mixed", answer: mixed, expectedOutput: expected, blocks: 2, executionScore: 0.5, score: 0.75},
+		{name: "wrong-output", answer: correct, expectedOutput: "55", blocks: 1, executionScore: 1, score: 0.5},
+		{name: "no-expected-output", answer: mixed, blocks: 2, executionScore: 0.5, score: 0.5},
+		{name: "no-code", answer: "The sequence is 0 1 1 2 3 5 8 13 21 34.", expectedOutput: expected},
+		{name: "other-languages-skipped", answer: "```go\nfunc main() {}\n```", expectedOutput: expected},
+		{name: "unlabelled-block", answer: "```\nprint('0 1 1 2 3 5 8 13 21 34')\n```", expectedOutput: expected, blocks: 1, executionScore: 1, score: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{}
+
+			result, err := EvaluateCodeExecution(context.Background(), runner, "python", tt.answer, tt.expectedOutput)
+			if err != nil {
+				t.Fatalf("evaluate code execution: %s", err)
+			}
+
+			if result.Blocks != tt.blocks || len(runner.executed) != tt.blocks {
+				t.Errorf("expected %d blocks executed, got %d (%d runs)", tt.blocks, result.Blocks, len(runner.executed))
+			}
+			assertScore(t, "execution", tt.executionScore, result.ExecutionScore)
+			assertScore(t, "overall", tt.score, result.Score)
+			if result.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+
+	t.Run("correct-scores-higher", func(t *testing.T) {
+		correctResult, err := EvaluateCodeExecution(context.Background(), &fakeRunner{}, "py", correct, expected)
+		if err != nil {
+			t.Fatalf("evaluate correct code: %s", err)
+		}
+		incorrectResult, err := EvaluateCodeExecution(context.Background(), &fakeRunner{}, "python", incorrect, expected)
+		if err != nil {
+			t.Fatalf("evaluate incorrect code: %s", err)
+		}
+
+		if correctResult.Score <= incorrectResult.Score {
+			t.Errorf("expected the correct code to score higher, got %.2f and %.2f", correctResult.Score, incorrectResult.Score)
+		}
+	})
+}
+
+func TestEvaluateCodeExecution_go(t *testing.T) {
+	answer := "Here is the program:\n\n```golang\npackage main\n\nfunc main() { println(55) }\n```\n\nAnd in Python:\n\n```python\nprint('55')\n```"
+	runner := &fakeRunner{}
+
+	result, err := EvaluateCodeExecution(context.Background(), runner, "go", answer, "")
+	if err != nil {
+		t.Fatalf("evaluate code execution: %s", err)
+	}
+
+	// Only the Go block runs, in the Go runtime of the executor
+	if result.Blocks != 1 || len(runner.languages) != 1 || runner.languages[0] != "go" {
+		t.Errorf("expected the Go block to run as go, got %d blocks in %v", result.Blocks, runner.languages)
+	}
+}
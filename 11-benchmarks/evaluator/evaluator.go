@@ -4,10 +4,14 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"unicode/utf8"
 
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/prompt"
 	"github.com/tmc/langchaingo/llms"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
@@ -15,12 +19,39 @@ import (
 
 // EvaluationResult represents the structured response from the evaluator LLM
 type EvaluationResult struct {
-	ProvidedAnswer string  `json:"provided_answer"`
-	Response       string  `json:"response"` // "yes", "no", or "unsure"
-	Reason         string  `json:"reason"`
-	Score          float64 `json:"score"` // 0.0 to 1.0
+	ProvidedAnswer string   `json:"provided_answer"`
+	Response       string   `json:"response"` // "yes", "no", or "unsure"
+	Category       Category `json:"category"` // Why the answer is right or wrong, for error-mode analysis
+	Reason         string   `json:"reason"`
+	Score          float64  `json:"score"` // 0.0 to 1.0
 }
 
+// Category classifies the evaluated answer, telling apart the wrong answers made up by the model
+// from the ones it refused to give
+type Category string
+
+const (
+	CategoryCorrect       Category = "correct"       // Meets the criteria
+	CategoryPartial       Category = "partial"       // Correct, but incomplete
+	CategoryHallucination Category = "hallucination" // States made-up or wrong facts
+	CategoryRefusal       Category = "refusal"       // Declines to answer, or answers nothing
+	CategoryOffTopic      Category = "off-topic"     // Answers another question
+	CategoryUnknown       Category = ""              // Not classified by the judge
+)
+
+// Categories are the categories the judge classifies the answers in, in the order they are reported
+var Categories = []Category{CategoryCorrect, CategoryPartial, CategoryHallucination, CategoryRefusal, CategoryOffTopic}
+
+// categoryInstructions expand the system prompts of the criteria, so the judge classifies the answers
+// besides scoring them. They are appended to every prompt, including the ones of BENCH_CRITERIA_DIR.
+const categoryInstructions = `
+Also classify the answer, adding a "category" field to the JSON object with one of these values:
+- "correct": the answer meets the criteria
+- "partial": the answer is correct but incomplete
+- "hallucination": the answer states made-up or wrong facts, code or numbers
+- "refusal": the answer declines to answer, or is empty
+- "off-topic": the answer addresses something else than the question`
+
 // ToolEvaluationResult represents the evaluation of tool calling accuracy
 type ToolEvaluationResult struct {
 	ToolSelectionScore float64 `json:"tool_selection_score"` // 0.0-1.0: correct tool chosen
@@ -35,44 +66,110 @@ type Evaluator interface {
 	Evaluate(ctx context.Context, model string, temperature float64, testCase string, question string, answer string, reference string) (*EvaluationResult, error)
 }
 
+// Default sampling parameters of the judge, deterministic so the same answer always gets the same score
+const (
+	DefaultTemperature = 0.0
+	DefaultTopK        = 1
+	DefaultSeed        = 42
+)
+
 // Agent implements the Evaluator interface using an LLM as a judge
 type Agent struct {
 	systemMessage string
 	chatModel     llms.Model
 	userTemplate  string
+	temperature   float64
+	topK          int
+	seed          int
 }
 
-// NewAgent creates a new evaluator agent with a specific system prompt
-func NewAgent(model llms.Model, systemPrompt string) *Agent {
-	userTemplate := `Question: %s
-Answer: %s
-Reference: %s
+// AgentOption is a functional option for the Agent
+type AgentOption func(*Agent)
+
+// WithTemperature sets the sampling temperature of the judge, DefaultTemperature by default.
+// Raising it allows studying how the stability of the scores depends on the judge calibration.
+func WithTemperature(temperature float64) AgentOption {
+	return func(a *Agent) {
+		a.temperature = temperature
+	}
+}
+
+// WithTopK sets the number of most likely tokens the judge samples from, DefaultTopK by default
+func WithTopK(topK int) AgentOption {
+	return func(a *Agent) {
+		a.topK = topK
+	}
+}
+
+// WithSeed sets the sampling seed of the judge, DefaultSeed by default
+func WithSeed(seed int) AgentOption {
+	return func(a *Agent) {
+		a.seed = seed
+	}
+}
+
+// defaultUserTemplate is the message of the judge with the answer to evaluate, see prompt.Render
+const defaultUserTemplate = `Question: {{question}}
+Answer: {{answer}}
+Reference: {{reference}}
 JSON response:`
 
-	return &Agent{
+// NewAgent creates a new evaluator agent with a specific system prompt
+func NewAgent(model llms.Model, systemPrompt string, opts ...AgentOption) *Agent {
+	a := &Agent{
 		systemMessage: systemPrompt,
 		chatModel:     model,
-		userTemplate:  userTemplate,
+		userTemplate:  defaultUserTemplate,
+		temperature:   DefaultTemperature,
+		topK:          DefaultTopK,
+		seed:          DefaultSeed,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// userMessage renders the message of the judge with the question, the answer to evaluate and the reference
+func (e *Agent) userMessage(question, answer, reference string) (string, error) {
+	msg, err := prompt.Render(e.userTemplate, map[string]string{
+		"question":  question,
+		"answer":    answer,
+		"reference": reference,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render user message: %w", err)
 	}
+
+	return msg, nil
+}
+
+// callOptions returns the sampling parameters of the judge calls, deterministic by default
+func (e *Agent) callOptions(opts ...llms.CallOption) []llms.CallOption {
+	return append([]llms.CallOption{
+		llms.WithTemperature(e.temperature),
+		llms.WithTopK(e.topK),
+		llms.WithSeed(e.seed),
+	}, opts...)
 }
 
 // Evaluate assesses the quality of an answer against a reference using the LLM judge
 func (e *Agent) Evaluate(ctx context.Context, model string, temperature float64, testCase string, question string, answer string, reference string) (*EvaluationResult, error) {
 	// Construct the user message with the question, answer, and reference
-	userMessage := fmt.Sprintf(e.userTemplate, question, answer, reference)
+	userMessage, err := e.userMessage(question, answer, reference)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create message content
 	msgContent := []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeSystem, e.systemMessage),
+		llms.TextParts(llms.ChatMessageTypeSystem, e.systemMessage+categoryInstructions),
 		llms.TextParts(llms.ChatMessageTypeHuman, userMessage),
 	}
 
-	// Generate response with deterministic parameters
-	resp, err := e.chatModel.GenerateContent(ctx, msgContent,
-		llms.WithTemperature(0.0),
-		llms.WithTopK(1),
-		llms.WithSeed(42),
-	)
+	// Generate response with the sampling parameters of the judge
+	resp, err := e.chatModel.GenerateContent(ctx, msgContent, e.callOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate evaluation: %w", err)
 	}
@@ -89,22 +186,16 @@ func (e *Agent) Evaluate(ctx context.Context, model string, temperature float64,
 		}
 	}
 
-	// Try to extract JSON from the response
-	// Sometimes the model may add extra text before/after the JSON
-	jsonText := extractJSON(responseText)
-	if jsonText == "" {
-		return nil, fmt.Errorf("no JSON found in evaluation response (response: %s)", responseText)
-	}
+	return parseEvaluation(ctx, model, temperature, testCase, question, answer, responseText)
+}
 
-	// Parse JSON response
-	var result EvaluationResult
-	if err := json.Unmarshal([]byte(jsonText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse evaluation response as JSON: %w (response: %s)", err, jsonText)
+// parseEvaluation extracts the evaluation result from the response of the judge, logging it
+func parseEvaluation(ctx context.Context, model string, temperature float64, testCase string, question string, answer string, responseText string) (*EvaluationResult, error) {
+	result, err := decodeEvaluation(responseText)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert response to score
-	result.Score = responseToScore(result.Response)
-
 	// Log the evaluation result
 	logger := global.GetLoggerProvider().Logger("evaluator")
 	var record log.Record
@@ -118,14 +209,105 @@ func (e *Agent) Evaluate(ctx context.Context, model string, temperature float64,
 		log.String("answer", truncateString(answer, 200)),
 		log.String("provided_answer", sanitizeUTF8(truncateString(result.ProvidedAnswer, 200))),
 		log.String("response", sanitizeUTF8(result.Response)),
+		log.String("category", string(result.Category)),
 		log.String("reason", sanitizeUTF8(truncateString(result.Reason, 500))),
 		log.Float64("score", result.Score),
 	)
 	logger.Emit(ctx, record)
 
+	return result, nil
+}
+
+// decodeEvaluation extracts the JSON evaluation from the response of the judge, scoring it
+func decodeEvaluation(responseText string) (*EvaluationResult, error) {
+	// Try to extract JSON from the response
+	// Sometimes the model may add extra text before/after the JSON
+	jsonText := extractJSON(responseText)
+	if jsonText == "" {
+		return nil, fmt.Errorf("no JSON found in evaluation response (response: %s)", responseText)
+	}
+
+	// Parse JSON response
+	var result EvaluationResult
+	if err := json.Unmarshal([]byte(jsonText), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse evaluation response as JSON: %w (response: %s)", err, jsonText)
+	}
+
+	if err := result.validate(); err != nil {
+		return nil, fmt.Errorf("%w (response: %s)", err, jsonText)
+	}
+
+	// Convert response to score
+	result.Score = responseToScore(result.Response)
+	result.Category = parseCategory(string(result.Category), result.Response)
+
 	return &result, nil
 }
 
+// ErrInvalidEvaluation is returned when the JSON evaluation of the judge doesn't follow the expected
+// structure, e.g. a "maybe" response or a score out of range, instead of silently scoring it 0
+var ErrInvalidEvaluation = errors.New("invalid evaluation")
+
+// validate checks that the response of the judge is "yes", "no" or "unsure", normalizing its case
+func (r *EvaluationResult) validate() error {
+	response := strings.ToLower(strings.TrimSpace(r.Response))
+	switch response {
+	case "yes", "no", "unsure":
+		r.Response = response
+		return nil
+	default:
+		return fmt.Errorf("%w: response %q must be yes, no or unsure", ErrInvalidEvaluation, r.Response)
+	}
+}
+
+// validate checks that the scores of the judge are between 0 and 1
+func (r *ToolEvaluationResult) validate() error {
+	scores := []struct {
+		name  string
+		value float64
+	}{
+		{"tool_selection_score", r.ToolSelectionScore},
+		{"parameter_accuracy", r.ParameterAccuracy},
+		{"sequence_score", r.SequenceScore},
+	}
+
+	var errs []error
+	for _, score := range scores {
+		if score.value < 0 || score.value > 1 {
+			errs = append(errs, fmt.Errorf("%w: %s %v must be between 0 and 1", ErrInvalidEvaluation, score.name, score.value))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseCategory normalizes the category of the judge, e.g. "Off topic" or "hallucinated", deriving
+// it from the response when the judge didn't classify the answer: a "no" can't tell why it's wrong
+func parseCategory(category string, response string) Category {
+	normalized := strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(category)))
+	switch normalized {
+	case "correct":
+		return CategoryCorrect
+	case "partial", "partially-correct", "incomplete":
+		return CategoryPartial
+	case "hallucination", "hallucinated", "hallucinating":
+		return CategoryHallucination
+	case "refusal", "refused", "refusing":
+		return CategoryRefusal
+	case "off-topic", "offtopic", "irrelevant":
+		return CategoryOffTopic
+	}
+
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "yes":
+		return CategoryCorrect
+	case "unsure":
+		return CategoryPartial
+	default:
+		return CategoryUnknown
+	}
+}
+
 // truncateString truncates a string to a maximum length and ensures valid UTF-8
 func truncateString(s string, maxLen int) string {
 	// First, sanitize to valid UTF-8
@@ -146,96 +328,6 @@ func sanitizeUTF8(s string) string {
 	return strings.ToValidUTF8(s, "�")
 }
 
-// extractJSON attempts to extract a JSON object from a string
-// It looks for the first '{' and the last '}' to handle cases where
-// the model adds extra text before or after the JSON
-// If the JSON appears incomplete (no closing }), it tries to fix it
-// Also fixes common JSON formatting issues like unescaped tabs and newlines
-func extractJSON(text string) string {
-	startIdx := strings.Index(text, "{")
-	if startIdx == -1 {
-		return ""
-	}
-
-	endIdx := strings.LastIndex(text, "}")
-	if endIdx == -1 || endIdx < startIdx {
-		// JSON appears incomplete - try to fix it by adding a closing }
-		// First, ensure the last field is properly closed
-		jsonText := text[startIdx:]
-
-		// If the text ends with a quote, it's likely the last string field
-		// Add closing brace
-		if strings.HasSuffix(strings.TrimSpace(jsonText), `"`) {
-			jsonText = strings.TrimSpace(jsonText) + "\n}"
-			return jsonText
-		}
-
-		// Otherwise, try to close any open quotes and add closing brace
-		if strings.Count(jsonText, `"`)%2 != 0 {
-			jsonText = jsonText + `"` + "\n}"
-			return jsonText
-		}
-
-		// Last resort - just add closing brace
-		jsonText = jsonText + "\n}"
-		return fixJSONEscaping(jsonText)
-	}
-
-	jsonText := text[startIdx : endIdx+1]
-	return fixJSONEscaping(jsonText)
-}
-
-// fixJSONEscaping fixes common JSON escaping issues in string values
-// Handles unescaped tabs, newlines, and other control characters within quoted strings
-func fixJSONEscaping(jsonText string) string {
-	var result strings.Builder
-	inString := false
-	escaped := false
-
-	for i := 0; i < len(jsonText); i++ {
-		ch := jsonText[i]
-
-		// Track if we're inside a string
-		if ch == '"' && !escaped {
-			inString = !inString
-			result.WriteByte(ch)
-			continue
-		}
-
-		// Track escape sequences
-		if ch == '\\' && !escaped {
-			escaped = true
-			result.WriteByte(ch)
-			continue
-		}
-
-		// If we were escaped, reset the flag
-		if escaped {
-			escaped = false
-			result.WriteByte(ch)
-			continue
-		}
-
-		// Only fix unescaped control characters inside strings
-		if inString {
-			switch ch {
-			case '\t':
-				result.WriteString("\\t")
-			case '\r':
-				result.WriteString("\\r")
-			case '\n':
-				result.WriteString("\\n")
-			default:
-				result.WriteByte(ch)
-			}
-		} else {
-			result.WriteByte(ch)
-		}
-	}
-
-	return result.String()
-}
-
 // responseToScore converts the text response ("yes"/"no"/"unsure") to a numeric score
 func responseToScore(response string) float64 {
 	switch strings.ToLower(strings.TrimSpace(response)) {
@@ -296,6 +388,20 @@ var apiDataRetrievalToolSystemPrompt string
 //go:embed testdata/evaluation/tool-parameter-extraction/api-data-retrieval/reference.txt
 var apiDataRetrievalToolReference string
 
+//go:embed testdata/evaluation/tool-parameter-extraction/currency-conversion/system_prompt.txt
+var currencyConversionToolSystemPrompt string
+
+//go:embed testdata/evaluation/tool-parameter-extraction/currency-conversion/reference.txt
+var currencyConversionToolReference string
+
+// Tool selection evaluation criteria, used by EvaluateToolUse
+//
+//go:embed testdata/evaluation/tool-selection/system_prompt.txt
+var toolSelectionSystemPrompt string
+
+//go:embed testdata/evaluation/tool-selection/reference.txt
+var toolSelectionReference string
+
 // Criteria defines the criteria for evaluating responses for different test cases
 type Criteria struct {
 	TestCaseName string
@@ -343,14 +449,116 @@ func GetCriteria() map[string]Criteria {
 			SystemPrompt: strings.TrimSpace(apiDataRetrievalToolSystemPrompt),
 			Reference:    strings.TrimSpace(apiDataRetrievalToolReference),
 		},
+		"currency-conversion": {
+			TestCaseName: "currency-conversion",
+			SystemPrompt: strings.TrimSpace(currencyConversionToolSystemPrompt),
+			Reference:    strings.TrimSpace(currencyConversionToolReference),
+		},
+		// Tool selection criteria, judging the arguments of the calls
+		ToolSelectionCriteria: {
+			TestCaseName: ToolSelectionCriteria,
+			SystemPrompt: strings.TrimSpace(toolSelectionSystemPrompt),
+			Reference:    strings.TrimSpace(toolSelectionReference),
+		},
+	}
+}
+
+// toolCriteriaDir groups the criteria of the tool-assisted test cases
+const toolCriteriaDir = "tool-parameter-extraction"
+
+// criteriaPath returns the directory of the criteria of a test case, relative to the
+// evaluation directory, following the layout of testdata/evaluation
+func criteriaPath(testCase string) string {
+	switch testCase {
+	case "calculator-reasoning", "code-validation", "api-data-retrieval", "currency-conversion":
+		return filepath.Join(toolCriteriaDir, testCase)
+	default:
+		return testCase
+	}
+}
+
+// LoadCriteriaFromDir returns the evaluation criteria reading the system_prompt.txt and reference.txt
+// files from a directory with the layout of testdata/evaluation, so the judge prompts can be tuned
+// without rebuilding. The embedded defaults of GetCriteria are used for the missing files.
+// New test cases, in their own folder of the directory, must define both files.
+func LoadCriteriaFromDir(path string) (map[string]Criteria, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat criteria dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("criteria path %s is not a directory", path)
+	}
+
+	criteria := GetCriteria()
+
+	for name, c := range criteria {
+		dir := filepath.Join(path, criteriaPath(name))
+
+		if c.SystemPrompt, err = readCriteriaFile(filepath.Join(dir, "system_prompt.txt"), c.SystemPrompt); err != nil {
+			return nil, err
+		}
+		if c.Reference, err = readCriteriaFile(filepath.Join(dir, "reference.txt"), c.Reference); err != nil {
+			return nil, err
+		}
+
+		criteria[name] = c
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("read criteria dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, ok := criteria[name]; ok || !entry.IsDir() || name == toolCriteriaDir {
+			continue
+		}
+
+		c := Criteria{TestCaseName: name}
+		if c.SystemPrompt, err = readCriteriaFile(filepath.Join(path, name, "system_prompt.txt"), ""); err != nil {
+			return nil, err
+		}
+		if c.Reference, err = readCriteriaFile(filepath.Join(path, name, "reference.txt"), ""); err != nil {
+			return nil, err
+		}
+		if c.SystemPrompt == "" || c.Reference == "" {
+			return nil, fmt.Errorf("test case %s must define both system_prompt.txt and reference.txt", name)
+		}
+
+		criteria[name] = c
+	}
+
+	return criteria, nil
+}
+
+// readCriteriaFile returns the trimmed content of a criteria file, or the fallback if it doesn't exist
+func readCriteriaFile(path string, fallback string) (string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fallback, nil
 	}
+	if err != nil {
+		return "", fmt.Errorf("read criteria file: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" {
+		return "", fmt.Errorf("criteria file %s is empty", path)
+	}
+
+	return trimmed, nil
 }
 
 // EvaluateToolCalls evaluates the accuracy of tool calling in an LLM response
 // It checks tool selection, parameter correctness, and call sequence
 func (e *Agent) EvaluateToolCalls(ctx context.Context, model string, temperature float64, testCase string, question string, answer string, reference string) (*ToolEvaluationResult, error) {
 	// Use the same evaluation template but with tool-specific prompt
-	userMessage := fmt.Sprintf(e.userTemplate, question, answer, reference)
+	userMessage, err := e.userMessage(question, answer, reference)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create message content
 	msgContent := []llms.MessageContent{
@@ -358,12 +566,8 @@ func (e *Agent) EvaluateToolCalls(ctx context.Context, model string, temperature
 		llms.TextParts(llms.ChatMessageTypeHuman, userMessage),
 	}
 
-	// Generate response with deterministic parameters
-	resp, err := e.chatModel.GenerateContent(ctx, msgContent,
-		llms.WithTemperature(0.0),
-		llms.WithTopK(1),
-		llms.WithSeed(42),
-	)
+	// Generate response with the sampling parameters of the judge
+	resp, err := e.chatModel.GenerateContent(ctx, msgContent, e.callOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tool evaluation: %w", err)
 	}
@@ -392,6 +596,10 @@ func (e *Agent) EvaluateToolCalls(ctx context.Context, model string, temperature
 		return nil, fmt.Errorf("failed to parse tool evaluation response as JSON: %w (response: %s)", err, jsonText)
 	}
 
+	if err := result.validate(); err != nil {
+		return nil, fmt.Errorf("%w (response: %s)", err, jsonText)
+	}
+
 	// Calculate overall score as average of individual scores
 	result.OverallScore = (result.ToolSelectionScore + result.ParameterAccuracy + result.SequenceScore) / 3.0
 
@@ -1,9 +1,14 @@
 package evaluator
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 // TestEvaluationCriteriaFiles verifies that all evaluation criteria files exist and are readable
@@ -49,11 +54,29 @@ func TestEvaluationCriteriaFiles(t *testing.T) {
 func TestGetCriteria(t *testing.T) {
 	criteria := GetCriteria()
 
-	expectedTestCases := []string{
-		"code-explanation",
-		"mathematical-operations",
-		"factual-question",
-		"code-generation",
+	// Every criteria of testdata/evaluation is embedded, with the layout of criteriaPath
+	evaluationDir := filepath.Join("testdata", "evaluation")
+	var expectedTestCases []string
+	err := filepath.WalkDir(evaluationDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "system_prompt.txt" {
+			return err
+		}
+
+		dir, err := filepath.Rel(evaluationDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+
+		testCase := filepath.Base(dir)
+		if criteriaPath(testCase) != dir {
+			t.Errorf("Criteria of %s in %s, expected in %s", testCase, dir, criteriaPath(testCase))
+		}
+		expectedTestCases = append(expectedTestCases, testCase)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk %s: %v", evaluationDir, err)
 	}
 
 	// Verify all test cases are loaded
@@ -88,3 +111,328 @@ func TestGetCriteria(t *testing.T) {
 		t.Errorf("Expected %d criteria, got %d", len(expectedTestCases), len(criteria))
 	}
 }
+
+// writeCriteriaFile writes a criteria file into the directory, creating its parents
+func writeCriteriaFile(t *testing.T, dir string, content string, elem ...string) {
+	t.Helper()
+
+	path := filepath.Join(append([]string{dir}, elem...)...)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %s", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %s", err)
+	}
+}
+
+// TestLoadCriteriaFromDir tests loading the criteria from a runtime directory
+func TestLoadCriteriaFromDir(t *testing.T) {
+	embedded := GetCriteria()
+
+	t.Run("overrides-and-fallback", func(t *testing.T) {
+		dir := t.TempDir()
+		writeCriteriaFile(t, dir, "  Custom factual judge prompt\n", "factual-question", "system_prompt.txt")
+		writeCriteriaFile(t, dir, "Custom calculator reference", "tool-parameter-extraction", "calculator-reasoning", "reference.txt")
+		writeCriteriaFile(t, dir, "Summarization judge prompt", "summarization", "system_prompt.txt")
+		writeCriteriaFile(t, dir, "Summarization reference", "summarization", "reference.txt")
+
+		criteria, err := LoadCriteriaFromDir(dir)
+		if err != nil {
+			t.Fatalf("load criteria: %s", err)
+		}
+
+		factual := criteria["factual-question"]
+		if factual.SystemPrompt != "Custom factual judge prompt" {
+			t.Errorf("expected the custom system prompt, got %q", factual.SystemPrompt)
+		}
+		if factual.Reference != embedded["factual-question"].Reference {
+			t.Error("expected the embedded reference for the missing file")
+		}
+
+		calculator := criteria["calculator-reasoning"]
+		if calculator.Reference != "Custom calculator reference" {
+			t.Errorf("expected the custom reference, got %q", calculator.Reference)
+		}
+		if calculator.SystemPrompt != embedded["calculator-reasoning"].SystemPrompt {
+			t.Error("expected the embedded system prompt for the missing file")
+		}
+
+		if criteria["code-generation"] != embedded["code-generation"] {
+			t.Error("expected the embedded criteria for a test case without folder")
+		}
+
+		summarization, ok := criteria["summarization"]
+		if !ok {
+			t.Fatal("expected the criteria of the new test case")
+		}
+		if summarization.TestCaseName != "summarization" || summarization.SystemPrompt != "Summarization judge prompt" || summarization.Reference != "Summarization reference" {
+			t.Errorf("unexpected criteria for the new test case: %+v", summarization)
+		}
+
+		if len(criteria) != len(embedded)+1 {
+			t.Errorf("expected %d criteria, got %d", len(embedded)+1, len(criteria))
+		}
+	})
+
+	t.Run("empty-dir", func(t *testing.T) {
+		criteria, err := LoadCriteriaFromDir(t.TempDir())
+		if err != nil {
+			t.Fatalf("load criteria: %s", err)
+		}
+
+		for name, c := range embedded {
+			if criteria[name] != c {
+				t.Errorf("expected the embedded criteria for %s", name)
+			}
+		}
+	})
+
+	t.Run("new-case-without-reference", func(t *testing.T) {
+		dir := t.TempDir()
+		writeCriteriaFile(t, dir, "Summarization judge prompt", "summarization", "system_prompt.txt")
+
+		if _, err := LoadCriteriaFromDir(dir); err == nil {
+			t.Fatal("expected an error for a new test case without reference")
+		}
+	})
+
+	t.Run("empty-file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeCriteriaFile(t, dir, "\n", "factual-question", "reference.txt")
+
+		if _, err := LoadCriteriaFromDir(dir); err == nil {
+			t.Fatal("expected an error for an empty criteria file")
+		}
+	})
+
+	t.Run("missing-dir", func(t *testing.T) {
+		if _, err := LoadCriteriaFromDir(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Fatal("expected an error for a missing directory")
+		}
+	})
+}
+
+func TestAgentSamplingOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		judge := &verdictJudge{verdict: "yes"}
+		if _, err := NewAgent(judge, "system").Evaluate(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4"); err != nil {
+			t.Fatalf("evaluate: %s", err)
+		}
+
+		assertSamplingOptions(t, judge.opts, DefaultTemperature, DefaultTopK, DefaultSeed)
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		judge := &verdictJudge{verdict: "yes"}
+		agent := NewAgent(judge, "system", WithTemperature(0.7), WithTopK(40), WithSeed(7))
+
+		if _, err := agent.Evaluate(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4"); err != nil {
+			t.Fatalf("evaluate: %s", err)
+		}
+		assertSamplingOptions(t, judge.opts, 0.7, 40, 7)
+
+		if _, err := agent.EvaluateToolCalls(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4"); err != nil {
+			t.Fatalf("evaluate tool calls: %s", err)
+		}
+		assertSamplingOptions(t, judge.opts, 0.7, 40, 7)
+	})
+}
+
+func assertSamplingOptions(t *testing.T, opts llms.CallOptions, temperature float64, topK int, seed int) {
+	t.Helper()
+
+	if opts.Temperature != temperature || opts.TopK != topK || opts.Seed != seed {
+		t.Errorf("expected temperature %.1f, top-k %d and seed %d, got %.1f, %d and %d",
+			temperature, topK, seed, opts.Temperature, opts.TopK, opts.Seed)
+	}
+}
+
+func TestDecodeEvaluationCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected Category
+		score    float64
+	}{
+		{
+			name:     "correct",
+			response: `{"provided_answer": "5050", "response": "yes", "category": "correct", "reason": "Right sum."}`,
+			expected: CategoryCorrect, score: 1,
+		},
+		{
+			name:     "partial",
+			response: `{"provided_answer": "Toledo translators", "response": "unsure", "category": "partial", "reason": "Misses the timeframe."}`,
+			expected: CategoryPartial, score: 0.5,
+		},
+		{
+			name:     "hallucination",
+			response: `{"provided_answer": "Toledo was the capital of the Roman Empire", "response": "no", "category": "hallucination", "reason": "Made-up facts."}`,
+			expected: CategoryHallucination, score: 0,
+		},
+		{
+			name:     "refusal",
+			response: `{"provided_answer": "Declines to answer", "response": "no", "category": "refusal", "reason": "No answer given."}`,
+			expected: CategoryRefusal, score: 0,
+		},
+		{
+			name:     "off-topic",
+			response: `{"provided_answer": "Explains bubble sort", "response": "no", "category": "off-topic", "reason": "Another question."}`,
+			expected: CategoryOffTopic, score: 0,
+		},
+		{
+			name:     "normalized",
+			response: "Here is my verdict:\n" + `{"provided_answer": "Explains bubble sort", "response": "no", "category": "Off_Topic", "reason": "Another question."}`,
+			expected: CategoryOffTopic, score: 0,
+		},
+		{
+			name:     "derived-from-yes",
+			response: `{"provided_answer": "5050", "response": "yes", "reason": "Right sum."}`,
+			expected: CategoryCorrect, score: 1,
+		},
+		{
+			name:     "derived-from-unsure",
+			response: `{"provided_answer": "5050?", "response": "unsure", "category": "mostly fine", "reason": "Hesitant."}`,
+			expected: CategoryPartial, score: 0.5,
+		},
+		{
+			name:     "unknown-no",
+			response: `{"provided_answer": "5000", "response": "no", "reason": "Wrong sum."}`,
+			expected: CategoryUnknown, score: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeEvaluation(tt.response)
+			if err != nil {
+				t.Fatalf("decode evaluation: %s", err)
+			}
+
+			if result.Category != tt.expected {
+				t.Errorf("expected category %q, got %q", tt.expected, result.Category)
+			}
+			if result.Score != tt.score {
+				t.Errorf("expected score %.1f, got %.1f", tt.score, result.Score)
+			}
+		})
+	}
+
+	t.Run("prompt", func(t *testing.T) {
+		judge := &verdictJudge{verdict: "yes"}
+		if _, err := NewAgent(judge, "system").Evaluate(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4"); err != nil {
+			t.Fatalf("evaluate: %s", err)
+		}
+
+		system := judge.messages[0].Parts[0].(llms.TextContent).Text
+		if !strings.HasPrefix(system, "system") || !strings.Contains(system, `"category"`) {
+			t.Errorf("expected the system prompt expanded with the categories, got %q", system)
+		}
+	})
+}
+
+func TestEvaluateUserMessage(t *testing.T) {
+	judge := &verdictJudge{verdict: "yes"}
+	answer := "Use fmt.Printf(\"%d%%\\n\", n) and return map[string]int{\"{{reference}}\": 1}"
+	if _, err := NewAgent(judge, "system").Evaluate(context.Background(), "model", 0.1, "test-case", "Print a percentage", answer, "fmt.Printf with %d%%"); err != nil {
+		t.Fatalf("evaluate: %s", err)
+	}
+
+	expected := "Question: Print a percentage\nAnswer: " + answer + "\nReference: fmt.Printf with %d%%\nJSON response:"
+	if user := judge.messages[1].Parts[0].(llms.TextContent).Text; user != expected {
+		t.Errorf("expected the answer and the reference verbatim, got %q", user)
+	}
+}
+
+// staticJudge is a judge answering always with the same content
+type staticJudge struct {
+	content string
+}
+
+func (j *staticJudge) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: j.content}}}, nil
+}
+
+func (j *staticJudge) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, j, prompt, options...)
+}
+
+func TestDecodeEvaluationValidation(t *testing.T) {
+	t.Run("normalized", func(t *testing.T) {
+		result, err := decodeEvaluation(`{"provided_answer": "4", "response": " YES ", "reason": "Right."}`)
+		if err != nil {
+			t.Fatalf("decode evaluation: %s", err)
+		}
+		if result.Response != "yes" || result.Score != 1 {
+			t.Errorf("expected the response normalized to yes with score 1, got %q and %.1f", result.Response, result.Score)
+		}
+	})
+
+	for _, response := range []string{"maybe", "", "yes, mostly", "1"} {
+		t.Run("invalid/"+response, func(t *testing.T) {
+			_, err := decodeEvaluation(`{"provided_answer": "4", "response": "` + response + `", "reason": "Hmm."}`)
+			if !errors.Is(err, ErrInvalidEvaluation) {
+				t.Fatalf("expected an invalid evaluation error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), `"`+response+`"`) {
+				t.Errorf("expected the invalid response in the error, got %s", err)
+			}
+		})
+	}
+
+	t.Run("evaluate", func(t *testing.T) {
+		judge := &staticJudge{content: `{"provided_answer": "4", "response": "maybe", "reason": "Hmm."}`}
+		result, err := NewAgent(judge, "system").Evaluate(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4")
+		if !errors.Is(err, ErrInvalidEvaluation) || result != nil {
+			t.Errorf("expected the evaluation rejected instead of scored, got %+v and %v", result, err)
+		}
+	})
+}
+
+func TestEvaluateToolCallsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		invalid []string
+	}{
+		{
+			name:    "valid",
+			content: `{"tool_selection_score": 1, "parameter_accuracy": 0.5, "sequence_score": 0, "reason": "ok"}`,
+		},
+		{
+			name:    "above-one",
+			content: `{"tool_selection_score": 10, "parameter_accuracy": 0.5, "sequence_score": 1, "reason": "out of 10"}`,
+			invalid: []string{"tool_selection_score"},
+		},
+		{
+			name:    "negative",
+			content: `{"tool_selection_score": 1, "parameter_accuracy": -0.5, "sequence_score": 1.5, "reason": "bad"}`,
+			invalid: []string{"parameter_accuracy", "sequence_score"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge := &staticJudge{content: tt.content}
+			result, err := NewAgent(judge, "system").EvaluateToolCalls(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4")
+
+			if len(tt.invalid) == 0 {
+				if err != nil {
+					t.Fatalf("evaluate tool calls: %s", err)
+				}
+				if result.OverallScore != 0.5 {
+					t.Errorf("expected overall score 0.5, got %.2f", result.OverallScore)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidEvaluation) {
+				t.Fatalf("expected an invalid evaluation error, got %v", err)
+			}
+			for _, name := range tt.invalid {
+				if !strings.Contains(err.Error(), name) {
+					t.Errorf("expected %s in the error, got %s", name, err)
+				}
+			}
+		})
+	}
+}
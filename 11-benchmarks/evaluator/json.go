@@ -0,0 +1,204 @@
+package evaluator
+
+import "strings"
+
+// jsonFrame is an open object or array while scanning the JSON text
+type jsonFrame struct {
+	closer     byte // '}' or ']'
+	afterColon bool // In objects, whether the key of the current member has been read
+}
+
+// extractJSON attempts to extract a JSON object from a string
+// It scans from the first '{' until the object is closed, to handle cases where
+// the model adds extra text before or after the JSON
+// If the JSON appears incomplete (truncated output), it tries to fix it
+// Also fixes common JSON formatting issues like unescaped tabs and newlines
+func extractJSON(text string) string {
+	startIdx := strings.Index(text, "{")
+	if startIdx == -1 {
+		return ""
+	}
+
+	var frames []jsonFrame
+	inString := false
+	escaped := false
+	keyPending := false // A key has been read, but not its colon
+
+	for i := startIdx; i < len(text); i++ {
+		ch := text[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\' && !(i+1 < len(text) && endsStringValue(text, i+1)):
+				escaped = true
+			case ch == '"':
+				inString = false
+				top := &frames[len(frames)-1]
+				keyPending = top.closer == '}' && !top.afterColon
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			frames = append(frames, jsonFrame{closer: '}'})
+		case '[':
+			frames = append(frames, jsonFrame{closer: ']'})
+		case ':':
+			frames[len(frames)-1].afterColon = true
+			keyPending = false
+		case ',':
+			frames[len(frames)-1].afterColon = false
+		case '}', ']':
+			frames = frames[:len(frames)-1]
+			if len(frames) == 0 {
+				return fixJSONEscaping(text[startIdx : i+1])
+			}
+		}
+	}
+
+	// JSON appears incomplete - close whatever was left open
+	return fixJSONEscaping(repairJSON(text[startIdx:], frames, inString, escaped, keyPending))
+}
+
+// repairJSON completes a truncated JSON text, given the state of the scan at its end
+func repairJSON(jsonText string, frames []jsonFrame, inString, escaped, keyPending bool) string {
+	top := frames[len(frames)-1]
+
+	if inString {
+		// A dangling backslash would escape the closing quote
+		if escaped {
+			jsonText = jsonText[:len(jsonText)-1]
+		}
+		jsonText += `"`
+		// A truncated key is still missing its value
+		keyPending = top.closer == '}' && !top.afterColon
+	} else {
+		jsonText = strings.TrimSuffix(strings.TrimRight(jsonText, " \t\r\n"), ",")
+		jsonText += completeLiteral(jsonText)
+
+		if strings.HasSuffix(jsonText, ":") {
+			jsonText += " null"
+		}
+	}
+
+	if keyPending {
+		jsonText += ": null"
+	}
+
+	closers := make([]byte, 0, len(frames)+1)
+	closers = append(closers, '\n')
+	for i := len(frames) - 1; i >= 0; i-- {
+		closers = append(closers, frames[i].closer)
+	}
+
+	return jsonText + string(closers)
+}
+
+// completeLiteral returns the suffix completing a truncated literal or number at the end of the text
+func completeLiteral(text string) string {
+	start := len(text)
+	for start > 0 && strings.IndexByte("abcdefghijklmnopqrstuvwxyz0123456789.+-E", text[start-1]) != -1 {
+		start--
+	}
+
+	word := text[start:]
+	if word == "" {
+		return ""
+	}
+
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(literal, word) {
+			return literal[len(word):]
+		}
+	}
+
+	// Numbers can't end with a decimal point, a sign or an exponent
+	switch word[len(word)-1] {
+	case '.', '+', '-', 'e', 'E':
+		return "0"
+	}
+
+	return ""
+}
+
+// fixJSONEscaping fixes common JSON escaping issues in string values
+// Handles unescaped tabs, newlines, and other control characters within quoted strings,
+// and backslashes not starting a valid escape sequence, such as the ones of Windows paths.
+// Already valid escape sequences are preserved. It only looks at the strings, so nested
+// objects and arrays are kept as they are.
+func fixJSONEscaping(jsonText string) string {
+	var result strings.Builder
+	inString := false
+	// Start of the text not copied to the result yet: the unchanged runs are copied at once,
+	// and nothing is allocated when the text doesn't need any fix
+	pending := 0
+
+	replace := func(i int, replacement string) {
+		if result.Len() == 0 {
+			// Room for a few escapes, avoiding the reallocations of growing the builder
+			result.Grow(len(jsonText) + len(jsonText)/16)
+		}
+		result.WriteString(jsonText[pending:i])
+		result.WriteString(replacement)
+		pending = i + 1
+	}
+
+	for i := 0; i < len(jsonText); i++ {
+		ch := jsonText[i]
+
+		// Track if we're inside a string
+		if ch == '"' {
+			inString = !inString
+			continue
+		}
+
+		if !inString {
+			continue
+		}
+
+		switch ch {
+		case '\\':
+			if i+1 < len(jsonText) && isEscapable(jsonText[i+1]) && !endsStringValue(jsonText, i+1) {
+				// Keep the valid escape sequence as is
+				i++
+				continue
+			}
+			// A lone backslash, at the end of the input or of the string, is a literal backslash
+			replace(i, `\\`)
+		case '\t':
+			replace(i, `\t`)
+		case '\r':
+			replace(i, `\r`)
+		case '\n':
+			replace(i, `\n`)
+		}
+	}
+
+	if result.Len() == 0 {
+		return jsonText
+	}
+
+	result.WriteString(jsonText[pending:])
+	return result.String()
+}
+
+// isEscapable reports whether the character follows a backslash in a valid JSON escape sequence
+func isEscapable(ch byte) bool {
+	return strings.IndexByte(`"\/bfnrtu`, ch) != -1
+}
+
+// endsStringValue reports whether the quote at the given index, preceded by a backslash,
+// is actually closing the last string, as only closing brackets follow it: a string value
+// ending in a backslash, like "C:\", would otherwise swallow the end of the JSON.
+func endsStringValue(jsonText string, quoteIdx int) bool {
+	if jsonText[quoteIdx] != '"' {
+		return false
+	}
+
+	return strings.TrimLeft(jsonText[quoteIdx+1:], "}] \t\r\n") == ""
+}
@@ -0,0 +1,239 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "well-formed",
+			text:     `{"response": "yes", "reason": "correct"}`,
+			expected: `{"response": "yes", "reason": "correct"}`,
+		},
+		{
+			name:     "surrounding-prose",
+			text:     "Here is my evaluation:\n{\"response\": \"yes\"}\nLet me know if you need more {details}.",
+			expected: `{"response": "yes"}`,
+		},
+		{
+			name:     "no-json",
+			text:     "The answer is correct.",
+			expected: "",
+		},
+		{
+			name:     "braces-in-strings",
+			text:     `{"reason": "uses {} and } in the code"} done`,
+			expected: `{"reason": "uses {} and } in the code"}`,
+		},
+		{
+			name:     "escaped-quotes",
+			text:     `{"reason": "the model said \"}\""}`,
+			expected: `{"reason": "the model said \"}\""}`,
+		},
+		{
+			name:     "control-characters",
+			text:     "{\"reason\": \"line one\n\tline two\"}",
+			expected: `{"reason": "line one\n\tline two"}`,
+		},
+		{
+			name:     "nested",
+			text:     `{"response": "yes", "details": {"score": 1, "tags": ["a", "b"]}} trailing`,
+			expected: `{"response": "yes", "details": {"score": 1, "tags": ["a", "b"]}}`,
+		},
+		{
+			name:     "truncated-mid-string",
+			text:     `{"response": "yes", "reason": "the answer is`,
+			expected: "{\"response\": \"yes\", \"reason\": \"the answer is\"\n}",
+		},
+		{
+			name:     "truncated-mid-string-with-escaped-quote",
+			text:     `{"response": "yes", "reason": "the \"answer`,
+			expected: "{\"response\": \"yes\", \"reason\": \"the \\\"answer\"\n}",
+		},
+		{
+			name:     "truncated-mid-escape",
+			text:     `{"response": "yes", "reason": "the answer\`,
+			expected: "{\"response\": \"yes\", \"reason\": \"the answer\"\n}",
+		},
+		{
+			name:     "truncated-mid-key",
+			text:     `{"response": "yes", "rea`,
+			expected: "{\"response\": \"yes\", \"rea\": null\n}",
+		},
+		{
+			name:     "truncated-after-key",
+			text:     `{"response": "yes", "reason"`,
+			expected: "{\"response\": \"yes\", \"reason\": null\n}",
+		},
+		{
+			name:     "truncated-after-colon",
+			text:     `{"response": "yes", "reason": `,
+			expected: "{\"response\": \"yes\", \"reason\": null\n}",
+		},
+		{
+			name:     "truncated-after-value",
+			text:     `{"response": "yes"`,
+			expected: "{\"response\": \"yes\"\n}",
+		},
+		{
+			name:     "truncated-after-comma",
+			text:     "{\"response\": \"yes\",\n",
+			expected: "{\"response\": \"yes\"\n}",
+		},
+		{
+			name:     "truncated-literal",
+			text:     `{"response": "yes", "valid": tr`,
+			expected: "{\"response\": \"yes\", \"valid\": true\n}",
+		},
+		{
+			name:     "truncated-number",
+			text:     `{"response": "yes", "score": 0.`,
+			expected: "{\"response\": \"yes\", \"score\": 0.0\n}",
+		},
+		{
+			name:     "string-ending-in-backslash",
+			text:     "{\"path\": \"C:\\\"}\n",
+			expected: `{"path": "C:\\"}`,
+		},
+		{
+			name:     "unbalanced-nested",
+			text:     `{"response": "yes", "details": {"score": 1}`,
+			expected: "{\"response\": \"yes\", \"details\": {\"score\": 1}\n}",
+		},
+		{
+			name:     "unbalanced-nested-missing-inner-close",
+			text:     `{"response": "yes", "details": {"tags": ["a", "b"`,
+			expected: "{\"response\": \"yes\", \"details\": {\"tags\": [\"a\", \"b\"\n]}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractJSON(tt.text)
+			if got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+
+			if got != "" && !json.Valid([]byte(got)) {
+				t.Errorf("expected valid JSON, got %q", got)
+			}
+		})
+	}
+}
+
+func TestFixJSONEscaping(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "valid",
+			text:     `{"response": "yes", "reason": "correct"}`,
+			expected: `{"response": "yes", "reason": "correct"}`,
+		},
+		{
+			name:     "control-characters",
+			text:     "{\"reason\": \"line one\n\tline two\r\"}",
+			expected: `{"reason": "line one\n\tline two\r"}`,
+		},
+		{
+			name:     "control-characters-outside-strings",
+			text:     "{\n\t\"response\": \"yes\"\n}",
+			expected: "{\n\t\"response\": \"yes\"\n}",
+		},
+		{
+			name:     "pre-escaped-sequences",
+			text:     `{"reason": "line one\nline two\ttabbed \"quoted\" \\ \/ \u00e9"}`,
+			expected: `{"reason": "line one\nline two\ttabbed \"quoted\" \\ \/ \u00e9"}`,
+		},
+		{
+			name:     "invalid-escape",
+			text:     `{"reason": "saved in C:\temp\data"}`,
+			expected: `{"reason": "saved in C:\temp\\data"}`,
+		},
+		{
+			name:     "string-ending-in-backslash",
+			text:     `{"path": "C:\"}`,
+			expected: `{"path": "C:\\"}`,
+		},
+		{
+			name:     "nested-string-ending-in-backslash",
+			text:     `{"details": {"paths": ["C:\"]}}`,
+			expected: `{"details": {"paths": ["C:\\"]}}`,
+		},
+		{
+			name:     "trailing-backslash",
+			text:     `{"path": "C:\`,
+			expected: `{"path": "C:\\`,
+		},
+		{
+			name:     "nested",
+			text:     "{\"details\": {\"tags\": [\"a\tb\", {\"c\": \"d\ne\"}]}}",
+			expected: `{"details": {"tags": ["a\tb", {"c": "d\ne"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixJSONEscaping(tt.text); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// largeJudgeResponse builds a judge response with a long reason, wrapped in prose,
+// with the control characters and escape sequences found in real outputs
+func largeJudgeResponse() string {
+	var reason strings.Builder
+	for i := 0; i < 200; i++ {
+		reason.WriteString("The answer explains the recursion\tcorrectly, \\\"fib(n)\\\" matches the reference.\n")
+	}
+
+	return "Here is my evaluation of the answer:\n" +
+		`{"provided_answer": "fib(10) = 55", "response": "yes", "reason": "` + reason.String() + `"}` +
+		"\nLet me know if you need anything else."
+}
+
+// The JSON helpers run on every judge response. Growing the builder byte by byte used to allocate
+// 17 times for a 17KB response (~63KB/op). Copying the unchanged runs at once into a pre-sized
+// builder allocates once (~18KB/op), and nothing for responses that don't need any fix, while
+// BenchmarkFixJSONEscaping went from ~60µs/op to ~40µs/op:
+//
+//	go test -run xxx -bench JSON -benchmem ./evaluator
+func BenchmarkExtractJSON(b *testing.B) {
+	text := largeJudgeResponse()
+
+	b.Run("complete", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			extractJSON(text)
+		}
+	})
+
+	b.Run("truncated", func(b *testing.B) {
+		truncated := text[:len(text)/2]
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			extractJSON(truncated)
+		}
+	})
+}
+
+func BenchmarkFixJSONEscaping(b *testing.B) {
+	text := largeJudgeResponse()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fixJSONEscaping(text)
+	}
+}
@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrDivergentOutput is returned when the streamed output of the judge clearly won't contain the JSON evaluation
+var ErrDivergentOutput = errors.New("evaluator output diverges from the expected JSON")
+
+const (
+	// maxPreambleBytes is the amount of text the judge may write before opening the JSON object
+	maxPreambleBytes = 512
+	// maxEvaluationBytes is the maximum length of the whole streamed evaluation
+	maxEvaluationBytes = 8192
+)
+
+// errEvaluationComplete stops the stream once the JSON object has been closed, skipping any trailing text
+var errEvaluationComplete = errors.New("evaluation complete")
+
+// jsonStream accumulates the streamed chunks of the judge, tracking the nesting of the JSON object
+// to detect when it is complete, or when the output diverges from it
+type jsonStream struct {
+	buf      strings.Builder
+	started  bool
+	depth    int
+	inString bool
+	escaped  bool
+	complete bool
+}
+
+// Write appends a chunk to the stream. It returns errEvaluationComplete once the JSON object
+// is closed, and ErrDivergentOutput when the output exceeds the length caps.
+func (s *jsonStream) Write(chunk []byte) error {
+	if s.complete {
+		return errEvaluationComplete
+	}
+
+	for _, ch := range chunk {
+		s.buf.WriteByte(ch)
+
+		if !s.started {
+			if ch == '{' {
+				s.started = true
+				s.depth = 1
+			}
+			continue
+		}
+
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case ch == '\\' && s.inString:
+			s.escaped = true
+		case ch == '"':
+			s.inString = !s.inString
+		case ch == '{' && !s.inString:
+			s.depth++
+		case ch == '}' && !s.inString:
+			s.depth--
+			if s.depth == 0 {
+				s.complete = true
+				return errEvaluationComplete
+			}
+		}
+	}
+
+	if !s.started && s.buf.Len() > maxPreambleBytes {
+		return fmt.Errorf("%w: no JSON object in the first %d bytes", ErrDivergentOutput, maxPreambleBytes)
+	}
+	if s.buf.Len() > maxEvaluationBytes {
+		return fmt.Errorf("%w: the output exceeds %d bytes", ErrDivergentOutput, maxEvaluationBytes)
+	}
+
+	return nil
+}
+
+// String returns the accumulated output
+func (s *jsonStream) String() string {
+	return s.buf.String()
+}
+
+// EvaluateStreaming assesses the quality of an answer like Evaluate, but streaming the output of the judge.
+// The stream is stopped as soon as the JSON object is closed, and aborted with ErrDivergentOutput when
+// the judge rambles without opening it, or exceeds the length cap, instead of waiting for a runaway output.
+// The optional progress function receives each chunk, for showing the progress of slow judges.
+func (e *Agent) EvaluateStreaming(ctx context.Context, model string, temperature float64, testCase string, question string, answer string, reference string, progress func(chunk []byte)) (*EvaluationResult, error) {
+	userMessage, err := e.userMessage(question, answer, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	msgContent := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, e.systemMessage+categoryInstructions),
+		llms.TextParts(llms.ChatMessageTypeHuman, userMessage),
+	}
+
+	stream := &jsonStream{}
+	var divergence error
+
+	_, err = e.chatModel.GenerateContent(ctx, msgContent, e.callOptions(
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			if progress != nil {
+				progress(chunk)
+			}
+			err := stream.Write(chunk)
+			if errors.Is(err, ErrDivergentOutput) {
+				divergence = err
+			}
+			return err
+		}),
+	)...)
+	if divergence != nil {
+		return nil, divergence
+	}
+	// Stopping the stream surfaces as an error of the model, which is expected once the JSON is complete
+	if err != nil && !stream.complete {
+		return nil, fmt.Errorf("failed to generate evaluation: %w", err)
+	}
+
+	return parseEvaluation(ctx, model, temperature, testCase, question, answer, stream.String())
+}
@@ -0,0 +1,111 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// streamingModel streams its chunks, recording how many of them were consumed before the stream stopped
+type streamingModel struct {
+	chunks []string
+	sent   int
+}
+
+func (m *streamingModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var content strings.Builder
+	for _, chunk := range m.chunks {
+		m.sent++
+		if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+			return nil, err
+		}
+		content.WriteString(chunk)
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: content.String()}},
+	}, nil
+}
+
+func (m *streamingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestEvaluateStreaming(t *testing.T) {
+	t.Run("recovers", func(t *testing.T) {
+		model := &streamingModel{chunks: []string{
+			"Let me check the answer... ",
+			"it looks right.\n",
+			`{"provided_answer": "4", `,
+			`"response": "yes", "reason": "The answer`,
+			"\tmatches the {reference}\"}",
+			"\nI hope this helps! Anything else?",
+			" Let me know.",
+		}}
+
+		var progress strings.Builder
+		agent := NewAgent(model, "system")
+
+		result, err := agent.EvaluateStreaming(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4", func(chunk []byte) {
+			progress.Write(chunk)
+		})
+		if err != nil {
+			t.Fatalf("evaluate streaming: %s", err)
+		}
+
+		if result.Response != "yes" || result.Score != 1.0 {
+			t.Errorf("expected a passing evaluation, got %+v", result)
+		}
+		if result.Reason != "The answer\tmatches the {reference}" {
+			t.Errorf("unexpected reason: %q", result.Reason)
+		}
+
+		if model.sent != 5 {
+			t.Errorf("expected the stream to stop after the JSON object, got %d chunks", model.sent)
+		}
+		if strings.Contains(progress.String(), "I hope this helps") {
+			t.Errorf("expected no progress after the JSON object, got %q", progress.String())
+		}
+	})
+
+	t.Run("diverges", func(t *testing.T) {
+		chunks := make([]string, 100)
+		for i := range chunks {
+			chunks[i] = "I keep thinking about it without answering. "
+		}
+		model := &streamingModel{chunks: chunks}
+
+		_, err := NewAgent(model, "system").EvaluateStreaming(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "4", nil)
+		if !errors.Is(err, ErrDivergentOutput) {
+			t.Fatalf("expected a divergent output error, got %v", err)
+		}
+
+		if model.sent == len(chunks) {
+			t.Error("expected the stream to be aborted early")
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		model := &streamingModel{chunks: []string{
+			`{"provided_answer": "4", "response": "no", `,
+			`"reason": "The reference says 5"`,
+		}}
+
+		result, err := NewAgent(model, "system").EvaluateStreaming(context.Background(), "model", 0.1, "test-case", "2+2?", "4", "5", nil)
+		if err != nil {
+			t.Fatalf("evaluate streaming: %s", err)
+		}
+
+		if result.Response != "no" || result.Score != 0.0 {
+			t.Errorf("expected a failing evaluation, got %+v", result)
+		}
+	})
+}
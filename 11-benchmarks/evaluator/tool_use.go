@@ -0,0 +1,216 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// ToolSelectionCriteria is the name of the criteria judging the arguments of the tool calls in EvaluateToolUse
+const ToolSelectionCriteria = "tool-selection"
+
+// EvaluateToolUse scores whether the model called the expected tool with the expected arguments.
+// The tool selection and the call order are checked deterministically, as well as the arguments
+// matching literally: numbers by value and strings ignoring case and surrounding spaces.
+// Only the arguments not matching literally are sent to the judge, created with the
+// ToolSelectionCriteria system prompt, to decide if they are equivalent.
+// Without a judge model, the arguments not matching literally are considered wrong.
+func (e *Agent) EvaluateToolUse(ctx context.Context, question string, toolCalls []llms.ToolCall, expectedTool string, expectedArgs map[string]any) (*ToolEvaluationResult, error) {
+	result := &ToolEvaluationResult{}
+
+	if len(toolCalls) == 0 {
+		result.Reason = fmt.Sprintf("No tool calls, expected a call to %s.", expectedTool)
+		return result, nil
+	}
+
+	expected, err := normalizeArgs(expectedArgs)
+	if err != nil {
+		return nil, fmt.Errorf("normalize expected arguments: %w", err)
+	}
+
+	// Pick the call to the expected tool with the most matching arguments
+	matchingCalls := 0
+	bestCall := -1
+	var bestMismatches []string
+	var bestArgs map[string]any
+	for i, call := range toolCalls {
+		if call.FunctionCall == nil || call.FunctionCall.Name != expectedTool {
+			continue
+		}
+		matchingCalls++
+
+		args := map[string]any{}
+		if err := json.Unmarshal([]byte(call.FunctionCall.Arguments), &args); err != nil {
+			// Malformed arguments don't match any expected one
+			args = nil
+		}
+
+		mismatches := mismatchingArgs(expected, args)
+		if bestCall == -1 || len(mismatches) < len(bestMismatches) {
+			bestCall = i
+			bestMismatches = mismatches
+			bestArgs = args
+		}
+	}
+
+	if matchingCalls == 0 {
+		result.Reason = fmt.Sprintf("Called %s instead of the expected %s.", strings.Join(toolNames(toolCalls), ", "), expectedTool)
+		return result, nil
+	}
+
+	result.ToolSelectionScore = float64(matchingCalls) / float64(len(toolCalls))
+
+	result.SequenceScore = 0.5
+	if toolCalls[0].FunctionCall != nil && toolCalls[0].FunctionCall.Name == expectedTool {
+		result.SequenceScore = 1.0
+	}
+
+	result.ParameterAccuracy = 1.0
+	result.Reason = fmt.Sprintf("Called %s with the expected arguments.", expectedTool)
+
+	if len(bestMismatches) > 0 {
+		matched := float64(len(expected) - len(bestMismatches))
+		equivalence := 0.0
+		result.Reason = fmt.Sprintf("Called %s with unexpected values for %s.", expectedTool, strings.Join(bestMismatches, ", "))
+
+		if e.chatModel != nil && bestArgs != nil {
+			judged, err := e.judgeArgs(ctx, question, expectedTool, expected, bestArgs, bestMismatches)
+			if err != nil {
+				return nil, err
+			}
+			equivalence = judged.Score
+			result.Reason = judged.Reason
+		}
+
+		result.ParameterAccuracy = (matched + equivalence*float64(len(bestMismatches))) / float64(len(expected))
+	}
+
+	result.OverallScore = (result.ToolSelectionScore + result.ParameterAccuracy + result.SequenceScore) / 3.0
+
+	// Log the tool use evaluation result
+	logger := global.GetLoggerProvider().Logger("evaluator")
+	var record log.Record
+	record.SetSeverity(log.SeverityInfo)
+	record.SetBody(log.StringValue("Tool use evaluation"))
+	record.AddAttributes(
+		log.String("question", truncateString(question, 100)),
+		log.String("expected_tool", expectedTool),
+		log.String("tool_calls", sanitizeUTF8(strings.Join(toolNames(toolCalls), ", "))),
+		log.Float64("tool_selection_score", result.ToolSelectionScore),
+		log.Float64("parameter_accuracy", result.ParameterAccuracy),
+		log.Float64("sequence_score", result.SequenceScore),
+		log.Float64("overall_score", result.OverallScore),
+		log.String("reason", sanitizeUTF8(truncateString(result.Reason, 500))),
+	)
+	logger.Emit(ctx, record)
+
+	return result, nil
+}
+
+// judgeArgs asks the judge whether the mismatching arguments of the call are equivalent to the expected ones
+func (e *Agent) judgeArgs(ctx context.Context, question string, tool string, expected map[string]any, actual map[string]any, mismatches []string) (*EvaluationResult, error) {
+	expectedSubset := make(map[string]any, len(mismatches))
+	actualSubset := make(map[string]any, len(mismatches))
+	for _, name := range mismatches {
+		expectedSubset[name] = expected[name]
+		actualSubset[name] = actual[name]
+	}
+
+	expectedJSON, err := json.Marshal(expectedSubset)
+	if err != nil {
+		return nil, fmt.Errorf("marshal expected arguments: %w", err)
+	}
+	actualJSON, err := json.Marshal(actualSubset)
+	if err != nil {
+		return nil, fmt.Errorf("marshal arguments: %w", err)
+	}
+
+	userMessage, err := e.userMessage(question,
+		fmt.Sprintf("%s(%s)", tool, actualJSON),
+		fmt.Sprintf("%s(%s)", tool, expectedJSON),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msgContent := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, e.systemMessage),
+		llms.TextParts(llms.ChatMessageTypeHuman, userMessage),
+	}
+
+	// Generate response with the sampling parameters of the judge
+	resp, err := e.chatModel.GenerateContent(ctx, msgContent, e.callOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tool use evaluation: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned from evaluator")
+	}
+
+	var responseText string
+	for _, choice := range resp.Choices {
+		responseText += choice.Content
+	}
+
+	return decodeEvaluation(responseText)
+}
+
+// normalizeArgs round-trips the arguments through JSON, so they have the types of the decoded tool calls
+func normalizeArgs(args map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := map[string]any{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// mismatchingArgs returns the sorted names of the expected arguments not matching the actual ones
+func mismatchingArgs(expected map[string]any, actual map[string]any) []string {
+	var mismatches []string
+	for name, want := range expected {
+		got, ok := actual[name]
+		if !ok || !argEqual(want, got) {
+			mismatches = append(mismatches, name)
+		}
+	}
+
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+// argEqual compares two decoded JSON values, ignoring the case and surrounding spaces of strings
+func argEqual(want any, got any) bool {
+	wantStr, wantIsStr := want.(string)
+	gotStr, gotIsStr := got.(string)
+	if wantIsStr && gotIsStr {
+		return strings.EqualFold(strings.TrimSpace(wantStr), strings.TrimSpace(gotStr))
+	}
+
+	return reflect.DeepEqual(want, got)
+}
+
+// toolNames returns the names of the called tools
+func toolNames(toolCalls []llms.ToolCall) []string {
+	names := make([]string, 0, len(toolCalls))
+	for _, call := range toolCalls {
+		if call.FunctionCall != nil {
+			names = append(names, call.FunctionCall.Name)
+		}
+	}
+
+	return names
+}
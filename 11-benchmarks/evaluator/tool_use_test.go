@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// verdictJudge is a judge answering always with the same verdict, counting the evaluations and
+// recording the messages and the options of the last one
+type verdictJudge struct {
+	verdict  string
+	calls    int
+	messages []llms.MessageContent
+	opts     llms.CallOptions
+}
+
+func (j *verdictJudge) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	j.calls++
+	j.messages = messages
+	j.opts = llms.CallOptions{}
+	for _, opt := range options {
+		opt(&j.opts)
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: `{"provided_answer": "call", "response": "` + j.verdict + `", "reason": "judged"}`,
+		}},
+	}, nil
+}
+
+func (j *verdictJudge) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, j, prompt, options...)
+}
+
+func toolCall(name string, arguments string) llms.ToolCall {
+	return llms.ToolCall{
+		ID:           "call-" + name,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+func TestEvaluateToolUse(t *testing.T) {
+	expectedArgs := map[string]any{"operation": "multiply", "a": 125, "b": 47}
+
+	tests := []struct {
+		name       string
+		toolCalls  []llms.ToolCall
+		verdict    string
+		selection  float64
+		params     float64
+		sequence   float64
+		judgeCalls int
+	}{
+		{
+			name:      "matching",
+			toolCalls: []llms.ToolCall{toolCall("calculator", `{"operation": "multiply", "a": 125, "b": 47}`)},
+			selection: 1, params: 1, sequence: 1,
+		},
+		{
+			name:      "matching-ignoring-case-and-number-format",
+			toolCalls: []llms.ToolCall{toolCall("calculator", `{"operation": " Multiply", "a": 125.0, "b": 47, "precision": 2}`)},
+			selection: 1, params: 1, sequence: 1,
+		},
+		{
+			name:      "no-tool-calls",
+			toolCalls: nil,
+		},
+		{
+			name:      "wrong-tool",
+			toolCalls: []llms.ToolCall{toolCall("execute_python", `{"code": "print(125 * 47)"}`)},
+		},
+		{
+			name: "extra-call-first",
+			toolCalls: []llms.ToolCall{
+				toolCall("http_get", `{"url": "https://example.com"}`),
+				toolCall("calculator", `{"operation": "multiply", "a": 125, "b": 47}`),
+			},
+			selection: 0.5, params: 1, sequence: 0.5,
+		},
+		{
+			name:      "mismatching-argument-judged-wrong",
+			toolCalls: []llms.ToolCall{toolCall("calculator", `{"operation": "add", "a": 125, "b": 47}`)},
+			verdict:   "no",
+			selection: 1, params: 2.0 / 3.0, sequence: 1, judgeCalls: 1,
+		},
+		{
+			name:      "mismatching-argument-judged-equivalent",
+			toolCalls: []llms.ToolCall{toolCall("calculator", `{"operation": "times", "a": 125, "b": 47}`)},
+			verdict:   "yes",
+			selection: 1, params: 1, sequence: 1, judgeCalls: 1,
+		},
+		{
+			name: "best-of-several-calls",
+			toolCalls: []llms.ToolCall{
+				toolCall("calculator", `{"operation": "divide", "a": 980, "b": 20}`),
+				toolCall("calculator", `{"operation": "multiply", "a": 125, "b": 47}`),
+			},
+			selection: 1, params: 1, sequence: 1,
+		},
+		{
+			name:      "malformed-arguments",
+			toolCalls: []llms.ToolCall{toolCall("calculator", `{"operation": "multiply", "a": 125`)},
+			selection: 1, params: 0, sequence: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge := &verdictJudge{verdict: tt.verdict}
+			agent := NewAgent(judge, "system")
+
+			result, err := agent.EvaluateToolUse(context.Background(), "Calculate 125 * 47", tt.toolCalls, "calculator", expectedArgs)
+			if err != nil {
+				t.Fatalf("evaluate tool use: %s", err)
+			}
+
+			assertScore(t, "tool selection", tt.selection, result.ToolSelectionScore)
+			assertScore(t, "parameter accuracy", tt.params, result.ParameterAccuracy)
+			assertScore(t, "sequence", tt.sequence, result.SequenceScore)
+			assertScore(t, "overall", (tt.selection+tt.params+tt.sequence)/3, result.OverallScore)
+
+			if judge.calls != tt.judgeCalls {
+				t.Errorf("expected %d judge calls, got %d", tt.judgeCalls, judge.calls)
+			}
+			if result.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+
+	t.Run("without-judge", func(t *testing.T) {
+		agent := NewAgent(nil, "system")
+
+		result, err := agent.EvaluateToolUse(context.Background(), "Calculate 125 * 47", []llms.ToolCall{toolCall("calculator", `{"operation": "add", "a": 125, "b": 47}`)}, "calculator", expectedArgs)
+		if err != nil {
+			t.Fatalf("evaluate tool use: %s", err)
+		}
+
+		assertScore(t, "parameter accuracy", 2.0/3.0, result.ParameterAccuracy)
+	})
+}
+
+func assertScore(t *testing.T, name string, expected float64, got float64) {
+	t.Helper()
+
+	if math.Abs(expected-got) > 1e-9 {
+		t.Errorf("expected %s score %.3f, got %.3f", name, expected, got)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
 )
@@ -256,14 +258,11 @@ func benchmarkErrorLogLink() []map[string]interface{} {
 	)
 }
 
-// CreateGrafanaDashboard creates a Grafana dashboard for LLM benchmarks
-// Uses a fixed UID to ensure the same dashboard is replaced on each run (no duplicates)
-func CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle string) error {
-	// Ensure the endpoint has a scheme
-	if !strings.HasPrefix(grafanaEndpoint, "http://") && !strings.HasPrefix(grafanaEndpoint, "https://") {
-		grafanaEndpoint = "http://" + grafanaEndpoint
-	}
+// dashboardUID is the fixed UID of the benchmark dashboard
+const dashboardUID = "llm-bench-dmr-tc"
 
+// buildDashboard returns the model of the benchmark dashboard, as imported by Grafana
+func buildDashboard(dashboardTitle string) map[string]interface{} {
 	// Convert OTel metric names to Prometheus format
 	promLatencyP50 := semconv.ToPrometheusMetricName(semconv.MetricLLMLatencyP50)
 	promLatencyP95 := semconv.ToPrometheusMetricName(semconv.MetricLLMLatencyP95)
@@ -283,6 +282,7 @@ func CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle string) error {
 	promEvalScore := semconv.ToPrometheusMetricName(semconv.MetricLLMEvalScore)
 	promEvalPassRate := semconv.ToPrometheusMetricName(semconv.MetricLLMEvalPassRate)
 	// Tool calling metrics
+	promCompletionTokens := semconv.ToPrometheusMetricName(semconv.MetricLLMCompletionTokens)
 	promToolCallLatency := semconv.ToPrometheusMetricName(semconv.MetricLLMToolCallLatency)
 	promToolCallCount := semconv.ToPrometheusMetricName(semconv.MetricLLMToolCallCount)
 	promToolIterationCount := semconv.ToPrometheusMetricName(semconv.MetricLLMIterationCount)
@@ -291,129 +291,172 @@ func CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle string) error {
 	promToolSelectionAccuracy := semconv.ToPrometheusMetricName(semconv.MetricLLMToolSelectionAccuracy)
 	promToolConvergence := semconv.ToPrometheusMetricName(semconv.MetricLLMToolConvergence)
 
-	dashboard := map[string]interface{}{
-		"dashboard": map[string]interface{}{
-			"uid":           "llm-bench-dmr-tc", // Fixed UID ensures we replace the same dashboard
-			"title":         dashboardTitle,
-			"tags":          []string{"llm", "benchmark", "testcontainers"},
-			"timezone":      "browser",
-			"schemaVersion": 16,
-			"version":       0,
-			"refresh":       "5s",
-			"graphTooltip":  1, // Shared crosshair - hovering on one panel shows crosshair on all panels
-			"templating": map[string]interface{}{
-				"list": []map[string]interface{}{
-					{
-						"name":       semconv.AttrModel,
-						"label":      "Model",
-						"type":       "query",
-						"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrModel),
-						"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrModel),
-						"datasource": map[string]interface{}{
-							"type": "prometheus",
-							"uid":  "prometheus",
-						},
-						"refresh": 1,
-						"current": map[string]interface{}{
-							"selected": false,
-							"text":     "All",
-							"value":    "$__all",
-						},
-						"multi":      true,
-						"includeAll": true,
-						"allValue":   ".*",
+	return map[string]interface{}{
+		"uid":           dashboardUID, // Fixed UID ensures we replace the same dashboard
+		"title":         dashboardTitle,
+		"tags":          []string{"llm", "benchmark", "testcontainers"},
+		"timezone":      "browser",
+		"schemaVersion": 16,
+		"version":       0,
+		"refresh":       "5s",
+		"graphTooltip":  1, // Shared crosshair - hovering on one panel shows crosshair on all panels
+		"templating": map[string]interface{}{
+			"list": []map[string]interface{}{
+				{
+					"name":       semconv.AttrModel,
+					"label":      "Model",
+					"type":       "query",
+					"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrModel),
+					"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrModel),
+					"datasource": map[string]interface{}{
+						"type": "prometheus",
+						"uid":  "prometheus",
 					},
-					{
-						"name":       semconv.AttrCase,
-						"label":      "Test Case",
-						"type":       "query",
-						"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrCase),
-						"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrCase),
-						"datasource": map[string]interface{}{
-							"type": "prometheus",
-							"uid":  "prometheus",
-						},
-						"refresh": 1,
-						"current": map[string]interface{}{
-							"selected": false,
-							"text":     "All",
-							"value":    "$__all",
-						},
-						"multi":      true,
-						"includeAll": true,
-						"allValue":   ".*",
+					"refresh": 1,
+					"current": map[string]interface{}{
+						"selected": false,
+						"text":     "All",
+						"value":    "$__all",
 					},
-					{
-						"name":       semconv.AttrTemp,
-						"label":      "Temperature",
-						"type":       "query",
-						"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrTemp),
-						"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrTemp),
-						"datasource": map[string]interface{}{
-							"type": "prometheus",
-							"uid":  "prometheus",
-						},
-						"refresh": 1,
-						"current": map[string]interface{}{
-							"selected": false,
-							"text":     "All",
-							"value":    "$__all",
-						},
-						"multi":      true,
-						"includeAll": true,
-						"allValue":   ".*",
+					"multi":      true,
+					"includeAll": true,
+					"allValue":   ".*",
+				},
+				{
+					"name":       semconv.AttrCase,
+					"label":      "Test Case",
+					"type":       "query",
+					"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrCase),
+					"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrCase),
+					"datasource": map[string]interface{}{
+						"type": "prometheus",
+						"uid":  "prometheus",
+					},
+					"refresh": 1,
+					"current": map[string]interface{}{
+						"selected": false,
+						"text":     "All",
+						"value":    "$__all",
 					},
+					"multi":      true,
+					"includeAll": true,
+					"allValue":   ".*",
+				},
+				{
+					"name":       semconv.AttrTemp,
+					"label":      "Temperature",
+					"type":       "query",
+					"query":      fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrTemp),
+					"definition": fmt.Sprintf("label_values(%s, %s)", promLatencyP50, semconv.AttrTemp),
+					"datasource": map[string]interface{}{
+						"type": "prometheus",
+						"uid":  "prometheus",
+					},
+					"refresh": 1,
+					"current": map[string]interface{}{
+						"selected": false,
+						"text":     "All",
+						"value":    "$__all",
+					},
+					"multi":      true,
+					"includeAll": true,
+					"allValue":   ".*",
 				},
 			},
-			"panels": []map[string]interface{}{
-				// Latency metrics
-				createPercentilePanelWithLinks(1, "Latency Percentiles (p50/p95)", promLatencyP50, promLatencyP95, 0, 0, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createHistogramPanelWithLinks(2, "Latency Distribution (with Exemplars)", promLatency, 12, 0, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// TTFT metrics
-				createPercentilePanelWithLinks(3, "TTFT Percentiles (p50/p95)", promTTFTP50, promTTFTP95, 0, 8, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createHistogramPanelWithLinks(4, "TTFT Distribution (with Exemplars)", promTTFT, 12, 8, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// Prompt Evaluation Time metrics
-				createPercentilePanelWithLinks(5, "Prompt Evaluation Time (p50/p95)", promPromptEvalTimeP50, promPromptEvalTimeP95, 0, 16, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createHistogramPanelWithLinks(6, "Prompt Eval Time Distribution (with Exemplars)", promPromptEvalTime, 12, 16, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// Other metrics
-				createSimpleTimeseriesPanelWithLinks(7, "Tokens per Operation", promTokensPerOp, 0, 24, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(8, "Success Rate", promSuccessRate, 8, 24, 8, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(benchmarkErrorLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(9, "Tokens per Second", promTokensPerSecond, 16, 24, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// GPU metrics
-				createSimpleTimeseriesPanelWithLinks(10, "GPU Utilization", promGPUUtilization, 0, 32, 12, 8, "percent", map[string]interface{}{"min": 0, "max": 100}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(11, "GPU Memory Usage", promGPUMemory, 12, 32, 12, 8, "decmbytes", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// Evaluator metrics with data links to Loki logs
-				// IMPORTANT: These metrics show aggregated average scores calculated from multiple benchmark iterations.
-				// Each data point represents the mean evaluator score across all iterations for that model/test_case combination,
-				// collected over the metric export interval (5s). Clicking a point shows individual evaluation logs within the
-				// dashboard time window. You'll see multiple log entries (one per benchmark iteration)
-				// with individual scores (0.0, 0.5, or 1.0) and detailed reasoning from the evaluator LLM.
-				createSimpleTimeseriesPanelWithLinks(12, "Evaluator Score", promEvalScore, 0, 40, 12, 8, "short",
-					map[string]interface{}{"min": 0, "max": 1}, combineLinks(evaluatorLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(13, "Evaluator Pass Rate", promEvalPassRate, 12, 40, 12, 8, "percentunit",
-					map[string]interface{}{"min": 0, "max": 1}, combineLinks(evaluatorLogLink, metricsLink, tracesLink)),
-
-				// Tool calling metrics (only populated for tool-assisted test cases)
-				createHistogramPanelWithLinks(15, "Tool Call Latency", promToolCallLatency, 0, 48, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(16, "Tool Calls per Operation", promToolCallCount, 0, 56, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(17, "LLM-Tool Iterations", promToolIterationCount, 8, 56, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(18, "Tool Success Rate", promToolSuccessRate, 16, 56, 8, 8, "percentunit",
-					map[string]interface{}{"min": 0, "max": 1}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(19, "Tool Parameter Accuracy", promToolParamAccuracy, 0, 64, 12, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(toolEvaluatorLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(20, "Tool Selection Accuracy", promToolSelectionAccuracy, 12, 64, 12, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(toolEvaluatorLogLink, metricsLink, tracesLink)),
-				createSimpleTimeseriesPanelWithLinks(21, "Tool Convergence (Path Efficiency)", promToolConvergence, 0, 72, 24, 8, "percentunit",
-					map[string]interface{}{"min": 0, "max": 1}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-
-				// ns/op metric (Go benchmark) - moved to bottom
-				createSimpleTimeseriesPanelWithLinks(22, "ns/op (Go Benchmark)", promNsPerOp, 0, 80, 24, 8, "ns", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
-			},
 		},
-		"overwrite": true,
+		"panels": []map[string]interface{}{
+			// Latency metrics
+			createPercentilePanelWithLinks(1, "Latency Percentiles (p50/p95)", promLatencyP50, promLatencyP95, 0, 0, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createHistogramPanelWithLinks(2, "Latency Distribution (with Exemplars)", promLatency, 12, 0, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// TTFT metrics
+			createPercentilePanelWithLinks(3, "TTFT Percentiles (p50/p95)", promTTFTP50, promTTFTP95, 0, 8, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createHistogramPanelWithLinks(4, "TTFT Distribution (with Exemplars)", promTTFT, 12, 8, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// Prompt Evaluation Time metrics
+			createPercentilePanelWithLinks(5, "Prompt Evaluation Time (p50/p95)", promPromptEvalTimeP50, promPromptEvalTimeP95, 0, 16, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createHistogramPanelWithLinks(6, "Prompt Eval Time Distribution (with Exemplars)", promPromptEvalTime, 12, 16, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// Other metrics
+			createSimpleTimeseriesPanelWithLinks(7, "Tokens per Operation", promTokensPerOp, 0, 24, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(8, "Success Rate", promSuccessRate, 8, 24, 8, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(benchmarkErrorLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(9, "Tokens per Second", promTokensPerSecond, 16, 24, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// GPU metrics
+			createSimpleTimeseriesPanelWithLinks(10, "GPU Utilization", promGPUUtilization, 0, 32, 12, 8, "percent", map[string]interface{}{"min": 0, "max": 100}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(11, "GPU Memory Usage", promGPUMemory, 12, 32, 12, 8, "decmbytes", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// Evaluator metrics with data links to Loki logs
+			// IMPORTANT: These metrics show aggregated average scores calculated from multiple benchmark iterations.
+			// Each data point represents the mean evaluator score across all iterations for that model/test_case combination,
+			// collected over the metric export interval (5s). Clicking a point shows individual evaluation logs within the
+			// dashboard time window. You'll see multiple log entries (one per benchmark iteration)
+			// with individual scores (0.0, 0.5, or 1.0) and detailed reasoning from the evaluator LLM.
+			createSimpleTimeseriesPanelWithLinks(12, "Evaluator Score", promEvalScore, 0, 40, 12, 8, "short",
+				map[string]interface{}{"min": 0, "max": 1}, combineLinks(evaluatorLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(13, "Evaluator Pass Rate", promEvalPassRate, 12, 40, 12, 8, "percentunit",
+				map[string]interface{}{"min": 0, "max": 1}, combineLinks(evaluatorLogLink, metricsLink, tracesLink)),
+
+			// Tool calling metrics (only populated for tool-assisted test cases)
+			createHistogramPanelWithLinks(15, "Tool Call Latency", promToolCallLatency, 0, 48, "ms", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createHistogramPanelWithLinks(14, "Completion Tokens Distribution (with Exemplars)", promCompletionTokens, 12, 48, "short", combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(16, "Tool Calls per Operation", promToolCallCount, 0, 56, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(17, "LLM-Tool Iterations", promToolIterationCount, 8, 56, 8, 8, "short", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(18, "Tool Success Rate", promToolSuccessRate, 16, 56, 8, 8, "percentunit",
+				map[string]interface{}{"min": 0, "max": 1}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(19, "Tool Parameter Accuracy", promToolParamAccuracy, 0, 64, 12, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(toolEvaluatorLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(20, "Tool Selection Accuracy", promToolSelectionAccuracy, 12, 64, 12, 8, "percentunit", map[string]interface{}{"min": 0, "max": 1}, combineLinks(toolEvaluatorLogLink, metricsLink, tracesLink)),
+			createSimpleTimeseriesPanelWithLinks(21, "Tool Convergence (Path Efficiency)", promToolConvergence, 0, 72, 24, 8, "percentunit",
+				map[string]interface{}{"min": 0, "max": 1}, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+
+			// ns/op metric (Go benchmark) - moved to bottom
+			createSimpleTimeseriesPanelWithLinks(22, "ns/op (Go Benchmark)", promNsPerOp, 0, 80, 24, 8, "ns", nil, combineLinks(llmClientLogLink, metricsLink, tracesLink)),
+		},
+	}
+}
+
+// dashboardOptions configures how the benchmark dashboard is created
+type dashboardOptions struct {
+	appendDashboard bool
+	now             func() time.Time
+}
+
+// DashboardOption configures how the benchmark dashboard is created
+type DashboardOption func(*dashboardOptions)
+
+// WithAppendDashboard creates a new dashboard on each run, with a timestamp-suffixed UID and title,
+// instead of replacing the previous one, preserving the history and the manual customizations
+func WithAppendDashboard() DashboardOption {
+	return func(o *dashboardOptions) {
+		o.appendDashboard = true
+	}
+}
+
+// CreateGrafanaDashboard creates a Grafana dashboard for LLM benchmarks
+// Uses a fixed UID to ensure the same dashboard is replaced on each run (no duplicates),
+// unless WithAppendDashboard is set
+func CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle string, opts ...DashboardOption) error {
+	options := dashboardOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Ensure the endpoint has a scheme
+	if !strings.HasPrefix(grafanaEndpoint, "http://") && !strings.HasPrefix(grafanaEndpoint, "https://") {
+		grafanaEndpoint = "http://" + grafanaEndpoint
+	}
+
+	spec := buildDashboard(dashboardTitle)
+	if options.appendDashboard {
+		// Grafana rejects dashboards with the same title in a folder, so suffix both the UID and the title
+		now := options.now()
+		spec["uid"] = dashboardUID + "-" + now.Format("20060102-150405")
+		spec["title"] = dashboardTitle + " " + now.Format(time.DateTime)
+	}
+
+	dashboard := map[string]interface{}{
+		"dashboard": spec,
+		"overwrite": !options.appendDashboard,
 	}
 
 	dashboardJSON, err := json.Marshal(dashboard)
@@ -445,3 +488,18 @@ func CreateGrafanaDashboard(grafanaEndpoint, dashboardTitle string) error {
 
 	return nil
 }
+
+// WriteDashboardJSON writes the benchmark dashboard to a file as pretty-printed JSON, to commit it
+// or import it into another Grafana (Dashboards > New > Import)
+func WriteDashboardJSON(path, dashboardTitle string) error {
+	dashboardJSON, err := json.MarshalIndent(buildDashboard(dashboardTitle), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dashboard: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(dashboardJSON, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write dashboard: %w", err)
+	}
+
+	return nil
+}
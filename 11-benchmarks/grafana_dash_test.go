@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateGrafanaDashboard(t *testing.T) {
+	type payload struct {
+		Dashboard struct {
+			UID   string `json:"uid"`
+			Title string `json:"title"`
+		} `json:"dashboard"`
+		Overwrite bool `json:"overwrite"`
+	}
+
+	create := func(t *testing.T, opts ...DashboardOption) payload {
+		t.Helper()
+
+		var got payload
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/dashboards/db" {
+				http.NotFound(w, r)
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		if err := CreateGrafanaDashboard(srv.URL, "LLM Bench", opts...); err != nil {
+			t.Fatalf("create dashboard: %s", err)
+		}
+		return got
+	}
+
+	t.Run("replace", func(t *testing.T) {
+		got := create(t)
+
+		if got.Dashboard.UID != dashboardUID || got.Dashboard.Title != "LLM Bench" {
+			t.Errorf("expected uid %q and title %q, got %q and %q", dashboardUID, "LLM Bench", got.Dashboard.UID, got.Dashboard.Title)
+		}
+		if !got.Overwrite {
+			t.Error("expected the dashboard to be overwritten")
+		}
+	})
+
+	t.Run("append", func(t *testing.T) {
+		now := func(o *dashboardOptions) {
+			o.now = func() time.Time { return time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC) }
+		}
+		got := create(t, WithAppendDashboard(), now)
+
+		if expected := dashboardUID + "-20250314-150926"; got.Dashboard.UID != expected {
+			t.Errorf("expected uid %q, got %q", expected, got.Dashboard.UID)
+		}
+		if expected := "LLM Bench 2025-03-14 15:09:26"; got.Dashboard.Title != expected {
+			t.Errorf("expected title %q, got %q", expected, got.Dashboard.Title)
+		}
+		if got.Overwrite {
+			t.Error("expected the dashboard not to be overwritten")
+		}
+	})
+}
+
+func TestWriteDashboardJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashboard.json")
+
+	if err := WriteDashboardJSON(path, "LLM Bench"); err != nil {
+		t.Fatalf("write dashboard: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read dashboard: %s", err)
+	}
+
+	var dashboard struct {
+		UID    string            `json:"uid"`
+		Title  string            `json:"title"`
+		Panels []json.RawMessage `json:"panels"`
+	}
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		t.Fatalf("expected valid JSON: %s", err)
+	}
+
+	if dashboard.UID != dashboardUID {
+		t.Errorf("expected uid %q, got %q", dashboardUID, dashboard.UID)
+	}
+	if dashboard.Title != "LLM Bench" {
+		t.Errorf("expected title %q, got %q", "LLM Bench", dashboard.Title)
+	}
+	if len(dashboard.Panels) == 0 {
+		t.Error("expected the dashboard panels")
+	}
+
+	// Pretty-printed, to be committed
+	if !strings.Contains(string(data), "\n  \"") {
+		t.Errorf("expected pretty-printed JSON:\n%s", data)
+	}
+}
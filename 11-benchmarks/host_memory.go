@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HostMemoryMetrics holds a sample of the host memory
+type HostMemoryMetrics struct {
+	ProcessMB    float64 // Memory obtained from the OS by the benchmark process, in MB
+	SystemUsedMB float64 // Memory in use by the whole host, in MB
+}
+
+// HostMemorySampler samples the host memory
+type HostMemorySampler interface {
+	Sample() (*HostMemoryMetrics, error)
+}
+
+// NewHostMemorySampler creates a sampler for the OS of the host
+func NewHostMemorySampler() HostMemorySampler {
+	return &systemMemorySampler{}
+}
+
+// systemMemorySampler reads the memory of the process from the Go runtime, and the memory of
+// the host from /proc/meminfo on Linux and vm_stat on macOS
+type systemMemorySampler struct{}
+
+func (s *systemMemorySampler) Sample() (*HostMemoryMetrics, error) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	var (
+		systemUsedMB float64
+		err          error
+	)
+	switch runtime.GOOS {
+	case "linux":
+		var data []byte
+		data, err = os.ReadFile("/proc/meminfo")
+		if err != nil {
+			return nil, fmt.Errorf("read meminfo: %w", err)
+		}
+		systemUsedMB, err = parseMeminfo(string(data))
+	case "darwin":
+		var stdout bytes.Buffer
+		cmd := exec.Command("vm_stat")
+		cmd.Stdout = &stdout
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("vm_stat: %w", err)
+		}
+		systemUsedMB, err = parseVMStat(stdout.String())
+	default:
+		return nil, fmt.Errorf("host memory sampling is not supported on %s", runtime.GOOS)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostMemoryMetrics{
+		ProcessMB:    float64(memStats.Sys) / (1024 * 1024),
+		SystemUsedMB: systemUsedMB,
+	}, nil
+}
+
+// parseMeminfo returns the used memory in MB from the content of /proc/meminfo: the total memory
+// minus the memory available for new processes without swapping
+func parseMeminfo(data string) (float64, error) {
+	values := map[string]float64{}
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		// e.g. "MemAvailable:   12345678 kB"
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		kb, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		values[name] = kb
+	}
+
+	total, hasTotal := values["MemTotal"]
+	available, hasAvailable := values["MemAvailable"]
+	if !hasTotal || !hasAvailable {
+		return 0, fmt.Errorf("meminfo without MemTotal and MemAvailable")
+	}
+
+	return (total - available) / 1024, nil
+}
+
+var (
+	vmStatPageSizePattern = regexp.MustCompile(`page size of (\d+) bytes`)
+	vmStatPagesPattern    = regexp.MustCompile(`(?m)^Pages (active|wired down|occupied by compressor):\s+(\d+)\.`)
+)
+
+// parseVMStat returns the used memory in MB from the output of vm_stat, as reported by Activity
+// Monitor: the active, wired and compressed pages
+func parseVMStat(output string) (float64, error) {
+	match := vmStatPageSizePattern.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("vm_stat output without page size")
+	}
+	pageSize, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse page size: %w", err)
+	}
+
+	matches := vmStatPagesPattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("vm_stat output without page counts")
+	}
+
+	var pages float64
+	for _, m := range matches {
+		count, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse pages %s: %w", m[1], err)
+		}
+		pages += count
+	}
+
+	return pages * pageSize / (1024 * 1024), nil
+}
+
+// ModelMemoryMB returns the host memory growth attributable to the model between two samples: the
+// growth of the system memory minus the growth of the benchmark process, never negative
+func ModelMemoryMB(before, after *HostMemoryMetrics) float64 {
+	processGrowth := max(0, after.ProcessMB-before.ProcessMB)
+	return max(0, after.SystemUsedMB-before.SystemUsedMB-processGrowth)
+}
+
+// MeasureModelMemory samples the host memory around fn, usually the first request to a model,
+// which loads it, returning the memory attributable to the model in MB. fn runs even if the memory
+// can't be sampled, e.g. on an unsupported OS, reporting it with sampled set to false: only the
+// error of fn is returned.
+func MeasureModelMemory(sampler HostMemorySampler, fn func() error) (memoryMB float64, sampled bool, err error) {
+	before, beforeErr := sampler.Sample()
+
+	if err := fn(); err != nil {
+		return 0, false, err
+	}
+
+	if beforeErr != nil {
+		return 0, false, nil
+	}
+	after, err := sampler.Sample()
+	if err != nil {
+		return 0, false, nil
+	}
+
+	return ModelMemoryMB(before, after), true, nil
+}
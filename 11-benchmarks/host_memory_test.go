@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeHostMemorySampler returns the samples in order
+type fakeHostMemorySampler struct {
+	samples []HostMemoryMetrics
+	calls   int
+}
+
+func (f *fakeHostMemorySampler) Sample() (*HostMemoryMetrics, error) {
+	if f.calls >= len(f.samples) {
+		return nil, errors.New("no more samples")
+	}
+	sample := f.samples[f.calls]
+	f.calls++
+	return &sample, nil
+}
+
+func TestModelMemoryMB(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   HostMemoryMetrics
+		after    HostMemoryMetrics
+		expected float64
+	}{
+		{
+			name:     "model-loaded",
+			before:   HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8000},
+			after:    HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8900},
+			expected: 900,
+		},
+		{
+			name:     "process-growth-excluded",
+			before:   HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8000},
+			after:    HostMemoryMetrics{ProcessMB: 70, SystemUsedMB: 8900},
+			expected: 880,
+		},
+		{
+			name:     "process-shrink-ignored",
+			before:   HostMemoryMetrics{ProcessMB: 70, SystemUsedMB: 8000},
+			after:    HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8900},
+			expected: 900,
+		},
+		{
+			name:     "memory-released",
+			before:   HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8900},
+			after:    HostMemoryMetrics{ProcessMB: 50, SystemUsedMB: 8000},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ModelMemoryMB(&tt.before, &tt.after); got != tt.expected {
+				t.Errorf("expected %.0f MB, got %.0f MB", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMeasureModelMemory(t *testing.T) {
+	sampler := &fakeHostMemorySampler{samples: []HostMemoryMetrics{
+		{ProcessMB: 40, SystemUsedMB: 6000},
+		{ProcessMB: 45, SystemUsedMB: 7205},
+	}}
+
+	called := false
+	mb, sampled, err := MeasureModelMemory(sampler, func() error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("measure: %s", err)
+	}
+
+	if !called || sampler.calls != 2 {
+		t.Errorf("expected the request between two samples, got called=%t and %d samples", called, sampler.calls)
+	}
+	if !sampled || mb != 1200 {
+		t.Errorf("expected 1200 MB sampled, got %.0f MB (sampled=%t)", mb, sampled)
+	}
+
+	t.Run("sampling-error", func(t *testing.T) {
+		called := false
+		_, sampled, err := MeasureModelMemory(&fakeHostMemorySampler{}, func() error {
+			called = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected the sampling error to be ignored, got %s", err)
+		}
+		if !called || sampled {
+			t.Errorf("expected the request to run unsampled, got called=%t and sampled=%t", called, sampled)
+		}
+	})
+
+	t.Run("request-error", func(t *testing.T) {
+		requestErr := errors.New("model not found")
+		_, _, err := MeasureModelMemory(&fakeHostMemorySampler{}, func() error { return requestErr })
+		if !errors.Is(err, requestErr) {
+			t.Errorf("expected the request error, got %v", err)
+		}
+	})
+}
+
+func TestParseMeminfo(t *testing.T) {
+	data := "MemTotal:       16384000 kB\nMemFree:         1024000 kB\nMemAvailable:    4096000 kB\nBuffers:          102400 kB\n"
+
+	usedMB, err := parseMeminfo(data)
+	if err != nil {
+		t.Fatalf("parse meminfo: %s", err)
+	}
+	if usedMB != 12000 {
+		t.Errorf("expected 12000 MB, got %.0f MB", usedMB)
+	}
+
+	if _, err := parseMeminfo("MemTotal: 16384000 kB\n"); err == nil {
+		t.Error("expected an error without MemAvailable")
+	}
+}
+
+func TestParseVMStat(t *testing.T) {
+	output := `Mach Virtual Memory Statistics: (page size of 16384 bytes)
+Pages free:                               10000.
+Pages active:                            100000.
+Pages inactive:                           90000.
+Pages speculative:                         5000.
+Pages wired down:                         50000.
+Pages occupied by compressor:             10000.
+`
+
+	usedMB, err := parseVMStat(output)
+	if err != nil {
+		t.Fatalf("parse vm_stat: %s", err)
+	}
+	// (100000 + 50000 + 10000) pages of 16 KiB
+	if usedMB != 2500 {
+		t.Errorf("expected 2500 MB, got %.0f MB", usedMB)
+	}
+}
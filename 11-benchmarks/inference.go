@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Inference backends, the values of the inference.backend attribute
+const (
+	InferenceBackendGPU = "gpu"
+	InferenceBackendCPU = "cpu"
+)
+
+// gpuEngineVariants are the markers of the GPU builds of the inference engines in the status of
+// Docker Model Runner, e.g. "running llama.cpp latest-cuda"
+var gpuEngineVariants = []string{"cuda", "metal", "vulkan", "rocm", "musa", "cann", "opencl", "gpu"}
+
+// InferenceBackend tells whether the models run on the GPU or the CPU. The status of the engines
+// reported by Docker Model Runner wins, as it knows the build of the engine actually running,
+// e.g. a CPU build on a machine with an NVIDIA GPU not exposed to Docker. Without a conclusive
+// status, the detected GPU vendor decides: the models run on the GPU when there is one.
+func InferenceBackend(vendor GPUVendor, dmrStatus string) string {
+	status := strings.ToLower(dmrStatus)
+	for _, variant := range gpuEngineVariants {
+		if strings.Contains(status, variant) {
+			return InferenceBackendGPU
+		}
+	}
+	if strings.Contains(status, "cpu") {
+		return InferenceBackendCPU
+	}
+
+	switch vendor {
+	case GPUVendorNVIDIA, GPUVendorApple:
+		return InferenceBackendGPU
+	default:
+		return InferenceBackendCPU
+	}
+}
+
+// FetchDMRBackendStatus returns the status of the inference engines of Docker Model Runner, from
+// the engines status endpoint next to the OpenAI-compatible one, e.g. http://host:port/engines/v1.
+// The status of every engine is returned in a single line, sorted by engine.
+func FetchDMRBackendStatus(ctx context.Context, openAIEndpoint string) (string, error) {
+	statusURL := strings.TrimSuffix(strings.TrimSuffix(openAIEndpoint, "/"), "/v1") + "/status"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create status request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("status request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read status: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status request returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// The status is a JSON object with the status of each engine, older versions answer plain text
+	var engines map[string]string
+	if err := json.Unmarshal(body, &engines); err != nil {
+		return strings.TrimSpace(string(body)), nil
+	}
+
+	statuses := make([]string, 0, len(engines))
+	for engine, status := range engines {
+		statuses = append(statuses, engine+": "+status)
+	}
+	sort.Strings(statuses)
+
+	return strings.Join(statuses, "; "), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInferenceBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		vendor   GPUVendor
+		status   string
+		expected string
+	}{
+		// Without a conclusive status, the GPU vendor decides
+		{name: "unknown", vendor: GPUVendorUnknown, expected: InferenceBackendCPU},
+		{name: "nvidia", vendor: GPUVendorNVIDIA, expected: InferenceBackendGPU},
+		{name: "apple", vendor: GPUVendorApple, expected: InferenceBackendGPU},
+		{name: "nvidia/not-running", vendor: GPUVendorNVIDIA, status: "llama.cpp: not running", expected: InferenceBackendGPU},
+		// The status of the engine wins
+		{name: "unknown/cuda", vendor: GPUVendorUnknown, status: "llama.cpp: running llama.cpp latest-cuda (sha256:1a2b) version: 34ce48d", expected: InferenceBackendGPU},
+		{name: "unknown/vulkan", vendor: GPUVendorUnknown, status: "llama.cpp: running llama.cpp latest-vulkan", expected: InferenceBackendGPU},
+		{name: "apple/metal", vendor: GPUVendorApple, status: "llama.cpp: running llama.cpp latest-metal", expected: InferenceBackendGPU},
+		{name: "nvidia/cpu", vendor: GPUVendorNVIDIA, status: "llama.cpp: running llama.cpp latest-cpu", expected: InferenceBackendCPU},
+		{name: "apple/cpu", vendor: GPUVendorApple, status: "llama.cpp: running llama.cpp latest-cpu", expected: InferenceBackendCPU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferenceBackend(tt.vendor, tt.status); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetchDMRBackendStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/engines/status" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"vllm": "not running", "llama.cpp": "running llama.cpp latest-cuda"}`))
+	}))
+	defer srv.Close()
+
+	status, err := FetchDMRBackendStatus(context.Background(), srv.URL+"/engines/v1")
+	if err != nil {
+		t.Fatalf("fetch status: %s", err)
+	}
+
+	if expected := "llama.cpp: running llama.cpp latest-cuda; vllm: not running"; status != expected {
+		t.Errorf("expected %q, got %q", expected, status)
+	}
+}
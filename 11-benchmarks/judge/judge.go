@@ -0,0 +1,115 @@
+// Package judge configures the LLM judging the responses of the models under test, the evaluator
+// agent, shared by the benchmarks and cmd/score so both honour BENCH_JUDGE_ENDPOINT and
+// BENCH_JUDGE_MODEL.
+package judge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/callbacks"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+const (
+	// DefaultOpenAIModel is the judge when an OpenAI API key is available: fast and cost-effective
+	DefaultOpenAIModel = "gpt-4o-mini"
+
+	// DefaultLocalModel is the judge served by Docker Model Runner, a good balance of speed and quality
+	DefaultLocalModel = "ai/llama3.2:3B-Q4_K_M"
+
+	// OpenAIEndpoint is the endpoint of the OpenAI API
+	OpenAIEndpoint = "https://api.openai.com/v1"
+)
+
+// Config configures the model judging the responses of the models under test
+type Config struct {
+	// Model is the name of the judge model
+	Model string
+	// Endpoint is the OpenAI-compatible API serving the judge, empty to serve it with the Docker Model
+	// Runner of the models under test
+	Endpoint string
+}
+
+// IsLocal tells whether the judge is served by Docker Model Runner, so its model must be pulled
+func (c Config) IsLocal() bool {
+	return c.Endpoint == ""
+}
+
+// Runner is the Docker Model Runner serving the local judge: the container, or the remote one of DMR_ENDPOINT
+type Runner interface {
+	PullModel(ctx context.Context, model string) error
+	// OpenAIEndpoint returns the URL of the OpenAI-compatible API
+	OpenAIEndpoint() string
+}
+
+// GetConfig returns the judge defined by the environment variables:
+//   - BENCH_JUDGE_ENDPOINT: OpenAI-compatible API serving the judge, isolated from the models under test.
+//   - BENCH_JUDGE_MODEL: name of the judge model.
+//
+// Without an endpoint, OpenAI is the judge when OPENAI_API_KEY is set, and the Docker Model Runner of
+// the models under test otherwise. Another DMR container would not isolate the judge, as all of them
+// proxy the same Model Runner engine, so BENCH_JUDGE_ENDPOINT is the only way to isolate it.
+func GetConfig() (Config, error) {
+	cfg := Config{
+		Model:    os.Getenv("BENCH_JUDGE_MODEL"),
+		Endpoint: os.Getenv("BENCH_JUDGE_ENDPOINT"),
+	}
+
+	if cfg.Endpoint == "" && os.Getenv("OPENAI_API_KEY") != "" {
+		cfg.Endpoint = OpenAIEndpoint
+	}
+
+	if cfg.Model == "" {
+		cfg.Model = DefaultLocalModel
+		if strings.Contains(cfg.Endpoint, "api.openai.com") {
+			cfg.Model = DefaultOpenAIModel
+		}
+	}
+
+	return cfg, nil
+}
+
+// New creates the judge of the configuration. The model of a local judge is pulled into the runner
+// first, and served by its OpenAI-compatible API; the runner is not used otherwise.
+func New(ctx context.Context, cfg Config, runner Runner) (llms.Model, error) {
+	endpoint := cfg.Endpoint
+	if cfg.IsLocal() {
+		if runner == nil {
+			return nil, fmt.Errorf("no model runner to serve the judge %s", cfg.Model)
+		}
+		if err := runner.PullModel(ctx, cfg.Model); err != nil {
+			return nil, fmt.Errorf("failed to pull evaluator model: %w", err)
+		}
+		endpoint = runner.OpenAIEndpoint()
+	}
+
+	logging.Default().Info("🔑 Using judge for evaluation", "model", cfg.Model, "endpoint", endpoint)
+	return NewModel(endpoint, cfg.Model)
+}
+
+// NewModel creates the client of the judge model served by the OpenAI-compatible endpoint.
+// The judge gets its own HTTP client, so its requests never compete for the pooled connections
+// of the clients of the models under test.
+func NewModel(endpoint, model string) (llms.Model, error) {
+	token := "dummy" // Docker Model Runner doesn't require auth
+	if strings.Contains(endpoint, "api.openai.com") {
+		token = os.Getenv("OPENAI_API_KEY")
+		if token == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required for the judge at %s", endpoint)
+		}
+	}
+
+	return openai.New(
+		openai.WithModel(model),
+		openai.WithBaseURL(endpoint),
+		openai.WithToken(token),
+		openai.WithCallback(callbacks.NewOTelCallbackHandler()),
+		openai.WithHTTPClient(&http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}),
+	)
+}
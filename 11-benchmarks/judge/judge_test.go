@@ -0,0 +1,192 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGetConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "local",
+			expected: Config{Model: DefaultLocalModel},
+		},
+		{
+			name:     "openai",
+			env:      map[string]string{"OPENAI_API_KEY": "sk-test"},
+			expected: Config{Model: DefaultOpenAIModel, Endpoint: OpenAIEndpoint},
+		},
+		{
+			name:     "openai/custom-model",
+			env:      map[string]string{"OPENAI_API_KEY": "sk-test", "BENCH_JUDGE_MODEL": "gpt-4.1"},
+			expected: Config{Model: "gpt-4.1", Endpoint: OpenAIEndpoint},
+		},
+		{
+			name:     "endpoint",
+			env:      map[string]string{"BENCH_JUDGE_ENDPOINT": "http://judge:12434/engines/v1"},
+			expected: Config{Model: DefaultLocalModel, Endpoint: "http://judge:12434/engines/v1"},
+		},
+		{
+			name:     "endpoint-over-openai",
+			env:      map[string]string{"OPENAI_API_KEY": "sk-test", "BENCH_JUDGE_ENDPOINT": "http://judge:12434/engines/v1", "BENCH_JUDGE_MODEL": "ai/qwen3:8B-Q4_K_M"},
+			expected: Config{Model: "ai/qwen3:8B-Q4_K_M", Endpoint: "http://judge:12434/engines/v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"OPENAI_API_KEY", "BENCH_JUDGE_MODEL", "BENCH_JUDGE_ENDPOINT"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			cfg, err := GetConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("get judge config: %s", err)
+			}
+			if cfg != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+// newStubOpenAIServer answers the chat completions, recording the requested models
+func newStubOpenAIServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var requestedModels []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requestedModels = append(requestedModels, req.Model)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "yes"}, "finish_reason": "stop"}],` +
+			`"usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requestedModels
+}
+
+func TestNewModel(t *testing.T) {
+	ctx := context.Background()
+	generationSrv, generationModels := newStubOpenAIServer(t)
+	judgeSrv, judgeModels := newStubOpenAIServer(t)
+
+	client, err := llmclient.NewClient(generationSrv.URL, "ai/smollm2", llmclient.WithStream(false))
+	if err != nil {
+		t.Fatalf("new client: %s", err)
+	}
+	judge, err := NewModel(judgeSrv.URL, "ai/judge")
+	if err != nil {
+		t.Fatalf("new judge: %s", err)
+	}
+
+	if _, err := client.GenerateWithTemp(ctx, "test-case", "system", "user", 0.1); err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+	if _, err := llms.GenerateFromSinglePrompt(ctx, judge, "Is the answer correct?"); err != nil {
+		t.Fatalf("judge: %s", err)
+	}
+
+	// Each client only reaches its own endpoint, with its own model
+	if len(*generationModels) != 1 || (*generationModels)[0] != "ai/smollm2" {
+		t.Errorf("expected one generation request for ai/smollm2, got %v", *generationModels)
+	}
+	if len(*judgeModels) != 1 || (*judgeModels)[0] != "ai/judge" {
+		t.Errorf("expected one judge request for ai/judge, got %v", *judgeModels)
+	}
+
+	t.Run("openai-requires-key", func(t *testing.T) {
+		t.Setenv("OPENAI_API_KEY", "")
+		if _, err := NewModel(OpenAIEndpoint, DefaultOpenAIModel); err == nil {
+			t.Error("expected an error without OPENAI_API_KEY")
+		}
+	})
+}
+
+// stubRunner records the pulled models, serving them at the endpoint
+type stubRunner struct {
+	endpoint string
+	pullErr  error
+	pulled   []string
+}
+
+func (r *stubRunner) PullModel(_ context.Context, model string) error {
+	r.pulled = append(r.pulled, model)
+	return r.pullErr
+}
+
+func (r *stubRunner) OpenAIEndpoint() string {
+	return r.endpoint
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	srv, requestedModels := newStubOpenAIServer(t)
+
+	t.Run("local", func(t *testing.T) {
+		runner := &stubRunner{endpoint: srv.URL}
+		judge, err := New(ctx, Config{Model: "ai/judge"}, runner)
+		if err != nil {
+			t.Fatalf("new judge: %s", err)
+		}
+		if len(runner.pulled) != 1 || runner.pulled[0] != "ai/judge" {
+			t.Errorf("expected the judge model to be pulled, got %v", runner.pulled)
+		}
+
+		if _, err := llms.GenerateFromSinglePrompt(ctx, judge, "Is the answer correct?"); err != nil {
+			t.Fatalf("judge: %s", err)
+		}
+		if got := (*requestedModels)[len(*requestedModels)-1]; got != "ai/judge" {
+			t.Errorf("expected the judge to be served by the runner, got a request for %s", got)
+		}
+	})
+
+	t.Run("endpoint", func(t *testing.T) {
+		runner := &stubRunner{endpoint: "http://unused"}
+		if _, err := New(ctx, Config{Model: "ai/judge", Endpoint: srv.URL}, runner); err != nil {
+			t.Fatalf("new judge: %s", err)
+		}
+		if len(runner.pulled) != 0 {
+			t.Errorf("expected no pull for the judge of an endpoint, got %v", runner.pulled)
+		}
+	})
+
+	t.Run("pull-error", func(t *testing.T) {
+		runner := &stubRunner{endpoint: srv.URL, pullErr: errors.New("no space left")}
+		if _, err := New(ctx, Config{Model: "ai/judge"}, runner); err == nil {
+			t.Error("expected the pull error")
+		}
+	})
+
+	t.Run("no-runner", func(t *testing.T) {
+		if _, err := New(ctx, Config{Model: "ai/judge"}, nil); err == nil {
+			t.Error("expected an error without runner for a local judge")
+		}
+	})
+}
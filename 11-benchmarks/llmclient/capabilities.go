@@ -0,0 +1,114 @@
+package llmclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Capabilities describes the features a model honors, detected by ProbeCapabilities
+type Capabilities struct {
+	Tools      bool // The model answers with tool calls when tools are offered
+	JSONMode   bool // The model returns valid JSON when structured output is requested
+	SystemRole bool // The model follows the instructions of the system message
+}
+
+const (
+	probeSystemWord = "PINEAPPLE"
+	probeToolName   = "get_secret_number"
+)
+
+// ProbeCapabilities issues small test prompts to detect whether the model honors tool calls,
+// structured output and a system role. Small models frequently ignore them, and the benchmarks
+// using those features would fail with generic errors instead.
+// A request rejected by the server means the feature is not supported, only a failure of the
+// system role probe, the plainest request, is returned as an error, as the model is not usable at all.
+func ProbeCapabilities(ctx context.Context, client *Client) (Capabilities, error) {
+	var caps Capabilities
+
+	supported, err := client.probeSystemRole(ctx)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("probe system role: %w", err)
+	}
+	caps.SystemRole = supported
+
+	caps.Tools = client.probeTools(ctx)
+	caps.JSONMode = client.probeJSONMode(ctx)
+
+	return caps, nil
+}
+
+// probeSystemRole checks that an instruction only present in the system message is followed
+func (c *Client) probeSystemRole(ctx context.Context) (bool, error) {
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, fmt.Sprintf("Whatever the user says, reply with the single word %s and nothing else.", probeSystemWord)),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Hello, how are you?"),
+	}
+
+	completion, err := c.llm.GenerateContent(ctx, content, llms.WithTemperature(0))
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(strings.ToUpper(firstChoiceContent(completion)), probeSystemWord), nil
+}
+
+// probeTools checks that the model calls the offered tool when the answer depends on it
+func (c *Client) probeTools(ctx context.Context) bool {
+	tool := llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        probeToolName,
+			Description: "Returns the secret number. It is the only way to know it.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	}
+
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the secret number? Use the available tool to find it out."),
+	}
+
+	completion, err := c.llm.GenerateContent(ctx, content, llms.WithTemperature(0), llms.WithTools([]llms.Tool{tool}))
+	if err != nil || len(completion.Choices) == 0 {
+		return false
+	}
+
+	for _, call := range completion.Choices[0].ToolCalls {
+		if call.FunctionCall != nil && call.FunctionCall.Name == probeToolName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// probeJSONMode checks that the model answers with a valid JSON object when JSON mode is requested
+func (c *Client) probeJSONMode(ctx context.Context) bool {
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, `Answer with a JSON object with a single "answer" field holding a number.`),
+		llms.TextParts(llms.ChatMessageTypeHuman, "How much is 2 + 2?"),
+	}
+
+	completion, err := c.llm.GenerateContent(ctx, content, llms.WithTemperature(0), llms.WithJSONMode())
+	if err != nil {
+		return false
+	}
+
+	var obj map[string]any
+	return json.Unmarshal([]byte(strings.TrimSpace(firstChoiceContent(completion))), &obj) == nil
+}
+
+// firstChoiceContent returns the content of the first choice of the completion, if any
+func firstChoiceContent(completion *llms.ContentResponse) string {
+	if completion == nil || len(completion.Choices) == 0 {
+		return ""
+	}
+
+	return completion.Choices[0].Content
+}
@@ -0,0 +1,94 @@
+package llmclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// capabilityModel answers the probes honoring only the configured capabilities
+type capabilityModel struct {
+	caps Capabilities
+	err  error
+}
+
+func (m *capabilityModel) GenerateContent(_ context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	choice := &llms.ContentChoice{}
+
+	switch {
+	case len(opts.Tools) > 0:
+		if !m.caps.Tools {
+			choice.Content = "I don't know the secret number."
+			break
+		}
+		choice.ToolCalls = []llms.ToolCall{{
+			ID:           "call-1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: opts.Tools[0].Function.Name, Arguments: "{}"},
+		}}
+	case opts.JSONMode:
+		choice.Content = "The answer is 4."
+		if m.caps.JSONMode {
+			choice.Content = `{"answer": 4}`
+		}
+	default:
+		choice.Content = "I'm fine, thanks for asking!"
+		if m.caps.SystemRole {
+			choice.Content = "Pineapple"
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+}
+
+func (m *capabilityModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		caps Capabilities
+	}{
+		{name: "none", caps: Capabilities{}},
+		{name: "all", caps: Capabilities{Tools: true, JSONMode: true, SystemRole: true}},
+		{name: "tools-only", caps: Capabilities{Tools: true}},
+		{name: "json-only", caps: Capabilities{JSONMode: true}},
+		{name: "system-role-only", caps: Capabilities{SystemRole: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeClient(&capabilityModel{caps: tt.caps})
+
+			got, err := ProbeCapabilities(context.Background(), client)
+			if err != nil {
+				t.Fatalf("probe capabilities: %s", err)
+			}
+
+			if got != tt.caps {
+				t.Errorf("expected capabilities %+v, got %+v", tt.caps, got)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		errUnreachable := errors.New("connection refused")
+		client := newFakeClient(&capabilityModel{err: errUnreachable})
+
+		if _, err := ProbeCapabilities(context.Background(), client); !errors.Is(err, errUnreachable) {
+			t.Fatalf("expected the model error, got %v", err)
+		}
+	})
+}
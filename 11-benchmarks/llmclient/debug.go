@@ -0,0 +1,142 @@
+package llmclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+)
+
+// EnvDebug is the environment variable enabling the logs of the raw requests and responses
+// of the clients without a debug logger, e.g. GENAI_DEBUG=true
+const EnvDebug = "GENAI_DEBUG"
+
+// debugMaxBytes is the maximum length of the logged request and response bodies
+const debugMaxBytes = 4096
+
+// WithDebugLogger sets a hook receiving each raw HTTP request sent to the API, with the
+// Authorization header redacted, and its raw response, both truncated. The response is passed
+// once it's fully read, so streamed responses include all their chunks. Without a hook, setting
+// GENAI_DEBUG logs them with the benchmark logger.
+func WithDebugLogger(fn func(req, resp string)) ClientOption {
+	return func(c *Client) {
+		c.debugLogger = fn
+	}
+}
+
+// debugEnabled tells whether the GENAI_DEBUG environment variable enables the debug logs
+func debugEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(EnvDebug))
+	return err == nil && enabled
+}
+
+// logDebug is the debug logger used when GENAI_DEBUG is set
+func logDebug(req, resp string) {
+	logging.Default().Info("LLM request", "request", req, "response", resp)
+}
+
+// withDebugTransport returns a copy of the HTTP client passing its requests and responses to the hook
+func withDebugTransport(client *http.Client, fn func(req, resp string)) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	debugClient := *client
+	debugClient.Transport = &debugTransport{next: next, log: fn}
+	return &debugClient
+}
+
+// debugTransport passes the requests and responses going through it to the log hook
+type debugTransport struct {
+	next http.RoundTripper
+	log  func(req, resp string)
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dump, err := dumpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.log(dump, "error: "+err.Error())
+		return nil, err
+	}
+
+	status := fmt.Sprintf("%d %s\n\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+	resp.Body = &capturingBody{
+		ReadCloser: resp.Body,
+		onDone: func(body string) {
+			t.log(dump, status+truncateString(body, debugMaxBytes))
+		},
+	}
+
+	return resp, nil
+}
+
+// dumpRequest returns the method, URL, headers and body of the request, redacting the credentials.
+// The body is restored, so the request can be sent.
+func dumpRequest(req *http.Request) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s\n", req.Method, req.URL)
+
+	headers := req.Header.Clone()
+	if headers.Get("Authorization") != "" {
+		headers.Set("Authorization", "[REDACTED]")
+	}
+	if err := headers.Write(&sb); err != nil {
+		return "", fmt.Errorf("dump headers: %w", err)
+	}
+	sb.WriteString("\n")
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("dump body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		sb.WriteString(truncateString(string(body), debugMaxBytes))
+	}
+
+	return sb.String(), nil
+}
+
+// capturingBody captures the first bytes of a response body while it's read, calling onDone
+// once with them when the body is fully read or closed
+type capturingBody struct {
+	io.ReadCloser
+	buf    bytes.Buffer
+	once   sync.Once
+	onDone func(body string)
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if remaining := debugMaxBytes + 1 - b.buf.Len(); remaining > 0 {
+		b.buf.Write(p[:min(n, remaining)])
+	}
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+func (b *capturingBody) Close() error {
+	b.done()
+	return b.ReadCloser.Close()
+}
+
+func (b *capturingBody) done() {
+	b.once.Do(func() {
+		b.onDone(b.buf.String())
+	})
+}
@@ -0,0 +1,50 @@
+package llmclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithDebugLogger(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-secret-token")
+
+	var logged []struct{ req, resp string }
+	debugLogger := func(req, resp string) {
+		logged = append(logged, struct{ req, resp string }{req, resp})
+	}
+
+	client, err := NewClient("https://api.openai.com/v1", "gpt-5.1",
+		WithHTTPClient(&http.Client{Transport: &recordingTransport{}}),
+		WithStream(false),
+		WithDebugLogger(debugLogger),
+	)
+	if err != nil {
+		t.Fatalf("new client: %s", err)
+	}
+
+	if _, err := client.GenerateWithTemp(context.Background(), "test-case", "You are a pirate.", "What is the capital of France?", 0.1); err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if len(logged) != 1 {
+		t.Fatalf("expected the hook to be invoked once, got %d", len(logged))
+	}
+	req, resp := logged[0].req, logged[0].resp
+
+	for _, expected := range []string{"POST https://api.openai.com/v1/chat/completions", "You are a pirate.", "What is the capital of France?", "[REDACTED]"} {
+		if !strings.Contains(req, expected) {
+			t.Errorf("expected the request to contain %q:\n%s", expected, req)
+		}
+	}
+	if strings.Contains(req, "sk-secret-token") {
+		t.Errorf("expected the API token to be redacted:\n%s", req)
+	}
+
+	for _, expected := range []string{"200 OK", `"content": "ok"`} {
+		if !strings.Contains(resp, expected) {
+			t.Errorf("expected the response to contain %q:\n%s", expected, resp)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package llmclient
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -20,26 +25,111 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrContextExceeded is returned when the prompt does not fit in the context window of the model
+var ErrContextExceeded = errors.New("context window exceeded")
+
+// ErrUnsupportedImage is returned when the image of a multimodal prompt is empty, or its mime type
+// is not supported by the OpenAI-compatible APIs or doesn't match its content
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// supportedImageMimeTypes are the image formats accepted by the OpenAI-compatible vision APIs
+var supportedImageMimeTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
 // Client wraps an LLM client with observability
 type Client struct {
-	llm    llms.Model
-	model  string
-	tracer trace.Tracer
+	llm         llms.Model
+	model       string
+	endpoint    string // OpenAI-compatible API of the model, e.g. http://host:port/engines/v1
+	tracer      trace.Tracer
+	contextSize int          // Maximum number of input tokens, zero means unchecked
+	noStream    bool         // Disables streaming, see WithStream
+	noSystem    bool         // Merges the system prompt into the user message, see WithSystemPrompt
+	httpClient  *http.Client // Client sending the requests to the API, see WithHTTPClient
+
+	refusalDetector func(content string) bool // Classifies the refusals, see WithRefusalDetector
+
+	debugLogger func(req, resp string) // Hook receiving the raw requests and responses, see WithDebugLogger
+}
+
+// defaultHTTPClient is shared by the clients, so the connections to the same API are pooled and
+// reused across models instead of exhausting the ephemeral ports under concurrent benchmarks.
+// It has no timeout, as streamed responses of slow models take minutes: use the context instead.
+var defaultHTTPClient = &http.Client{Transport: newPooledTransport()}
+
+// newPooledTransport returns a transport keeping enough idle connections per host for the
+// concurrent requests of a benchmark, as the default transport only keeps two
+func newPooledTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 32
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
+// ClientOption is a functional option for the Client
+type ClientOption func(*Client)
+
+// WithContextSize sets the context window of the model, in tokens. The input tokens of each request
+// are counted before sending it, failing with ErrContextExceeded when they do not fit, instead of
+// the opaque server error of small-context models. Zero, the default, disables the check.
+func WithContextSize(tokens int) ClientOption {
+	return func(c *Client) {
+		c.contextSize = tokens
+	}
+}
+
+// WithHTTPClient sets the HTTP client sending the requests to the API, to tune its transport,
+// e.g. the idle connections per host or the keep-alives. By default, the clients share a pooled one.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithStream sets whether the responses are streamed, enabled by default to measure the real TTFT
+// and the inter-token latency. Without streaming, the TTFT is the latency of the whole response,
+// as some deployments are faster when they don't stream.
+func WithStream(stream bool) ClientOption {
+	return func(c *Client) {
+		c.noStream = !stream
+	}
+}
+
+// WithSystemPrompt sets whether the model supports a separate system message, enabled by default.
+// Without it, the system prompt is prepended to the user message instead, as some base or instruct
+// variants ignore the system role, or degrade with it, which would be unfair in the comparisons.
+func WithSystemPrompt(supported bool) ClientOption {
+	return func(c *Client) {
+		c.noSystem = !supported
+	}
+}
+
+// WithRefusalDetector sets the classifier of the empty responses, or the ones refusing to answer,
+// flagged in Response.Refusal and in the refusal attribute of the span: they are not errors, but
+// they are not useful answers either.
+func WithRefusalDetector(detect func(content string) bool) ClientOption {
+	return func(c *Client) {
+		c.refusalDetector = detect
+	}
 }
 
 // Response contains the LLM response and metadata
 type Response struct {
 	Content          string
+	Refusal          bool // Empty, or refusing to answer, see WithRefusalDetector
 	PromptTokens     int
 	CompletionTokens int
 	TotalTokens      int
 	Latency          time.Duration
-	PromptEvalTime   time.Duration // Time to evaluate prompt (from model metadata if available)
-	TTFT             time.Duration // Time To First Token (actual measured via streaming)
+	PromptEvalTime   time.Duration   // Time to evaluate prompt (from model metadata if available)
+	TTFT             time.Duration   // Time To First Token (actual measured via streaming)
+	InterTokenP50    time.Duration   // Median time between consecutive streaming chunks
+	InterTokenP95    time.Duration   // 95th percentile time between consecutive streaming chunks
+	InterTokenGaps   []time.Duration // Times between consecutive streaming chunks, in order, to pool them across requests
 }
 
 // NewClient creates a new LLM client
-func NewClient(endpoint, model string) (*Client, error) {
+func NewClient(endpoint, model string, opts ...ClientOption) (*Client, error) {
 	// Determine if this is an external OpenAI API or local Docker Model Runner
 	apiKey := "foo" // Default for Docker Model Runner
 	if strings.Contains(endpoint, "api.openai.com") {
@@ -51,64 +141,239 @@ func NewClient(endpoint, model string) (*Client, error) {
 		}
 	}
 
-	opts := []openai.Option{
+	c := &Client{
+		model:      model,
+		endpoint:   endpoint,
+		tracer:     otel.Tracer("llmclient"),
+		httpClient: defaultHTTPClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = defaultHTTPClient
+	}
+	if c.debugLogger == nil && debugEnabled() {
+		c.debugLogger = logDebug
+	}
+
+	httpClient := c.httpClient
+	if c.debugLogger != nil {
+		httpClient = withDebugTransport(httpClient, c.debugLogger)
+	}
+
+	openaiOpts := []openai.Option{
 		openai.WithBaseURL(endpoint),
 		openai.WithModel(model),
 		openai.WithToken(apiKey),
 		openai.WithCallback(callbacks.NewOTelCallbackHandler()),
+		openai.WithHTTPClient(httpClient),
 	}
 
-	llm, err := openai.New(opts...)
+	llm, err := openai.New(openaiOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create openai client: %w", err)
 	}
+	c.llm = llm
 
-	return &Client{
-		llm:    llm,
-		model:  model,
-		tracer: otel.Tracer("llmclient"),
-	}, nil
+	return c, nil
+}
+
+// checkContextSize verifies that the prompts fit in the context window of the model, if configured
+func (c *Client) checkContextSize(systemPrompt, userPrompt string) error {
+	if c.contextSize <= 0 {
+		return nil
+	}
+
+	inputTokens := CountTokens(c.model, systemPrompt) + CountTokens(c.model, userPrompt)
+	if inputTokens > c.contextSize {
+		return fmt.Errorf("%w: the prompt has %d tokens, but the context of %s allows %d", ErrContextExceeded, inputTokens, c.model, c.contextSize)
+	}
+
+	return nil
+}
+
+// messages returns the initial messages of a request: the system and the user messages, or only the
+// user message, starting with the system prompt, when the model doesn't support the system role
+func (c *Client) messages(systemPrompt, userPrompt string) []llms.MessageContent {
+	if !c.noSystem {
+		return []llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeSystem, systemPrompt),
+			llms.TextParts(llms.ChatMessageTypeHuman, userPrompt),
+		}
+	}
+
+	if systemPrompt != "" {
+		userPrompt = systemPrompt + "\n\n" + userPrompt
+	}
+
+	return []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, userPrompt),
+	}
+}
+
+// GenerateOptions configures a generation request
+type GenerateOptions struct {
+	Temperature float64
+	// Seed makes the sampling reproducible on deterministic backends. Nil means no seed is sent.
+	Seed *int
 }
 
 // GenerateWithTemp sends a prompt to the LLM with a specific temperature and returns the response with metadata
 func (c *Client) GenerateWithTemp(ctx context.Context, testCase string, systemPrompt, userPrompt string, temperature float64) (*Response, error) {
+	return c.GenerateWithOptions(ctx, testCase, systemPrompt, userPrompt, GenerateOptions{Temperature: temperature})
+}
+
+// GenerateWithOptions sends a prompt to the LLM with the given options and returns the response with metadata
+func (c *Client) GenerateWithOptions(ctx context.Context, testCase string, systemPrompt, userPrompt string, opts GenerateOptions) (*Response, error) {
+	return c.generate(ctx, testCase, systemPrompt, userPrompt, opts, !c.noStream)
+}
+
+// GenerateWithImage sends a multimodal prompt to a vision model, attaching the image to the user
+// message as a base64 data URL, and returns the response with metadata. The mime type must be one of
+// image/png, image/jpeg, image/gif or image/webp, matching the content of the image.
+func (c *Client) GenerateWithImage(ctx context.Context, systemPrompt, userPrompt string, imageData []byte, mimeType string) (*Response, error) {
+	if err := validateImage(imageData, mimeType); err != nil {
+		return nil, err
+	}
+
+	image := llms.ImageURLPart("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(imageData))
+
+	return c.generate(ctx, "", systemPrompt, userPrompt, GenerateOptions{}, !c.noStream, imagePart{
+		part:     image,
+		mimeType: mimeType,
+		size:     len(imageData),
+	})
+}
+
+// imagePart is an image attached to the user message of a prompt
+type imagePart struct {
+	part     llms.ContentPart
+	mimeType string
+	size     int
+}
+
+// validateImage returns ErrUnsupportedImage if the image is empty, or its mime type is not supported
+// or doesn't match the content sniffed from its first bytes
+func validateImage(imageData []byte, mimeType string) error {
+	if len(imageData) == 0 {
+		return fmt.Errorf("%w: empty image", ErrUnsupportedImage)
+	}
+	if !slices.Contains(supportedImageMimeTypes, mimeType) {
+		return fmt.Errorf("%w: mime type %q, expected one of %s", ErrUnsupportedImage, mimeType, strings.Join(supportedImageMimeTypes, ", "))
+	}
+	if detected := http.DetectContentType(imageData); detected != mimeType {
+		return fmt.Errorf("%w: the content is %s, not %s", ErrUnsupportedImage, detected, mimeType)
+	}
+
+	return nil
+}
+
+// StreamingComparison contains the responses to the same prompt with and without streaming
+type StreamingComparison struct {
+	Streaming    *Response
+	NonStreaming *Response
+	// Overhead is the streaming latency minus the non-streaming one, negative when streaming is faster
+	Overhead time.Duration
+	// StreamingFirst is true when the streaming request was sent first
+	StreamingFirst bool
+}
+
+// CompareStreaming sends the prompt twice, streaming the response and without streaming it,
+// to measure whether streaming adds overhead to the total latency. The second request may
+// reuse the KV cache of the first one, so callers alternate streamingFirst across the
+// iterations to spread that advantage evenly between both modes.
+func (c *Client) CompareStreaming(ctx context.Context, testCase string, systemPrompt, userPrompt string, opts GenerateOptions, streamingFirst bool) (*StreamingComparison, error) {
+	cmp := &StreamingComparison{StreamingFirst: streamingFirst}
+
+	for _, stream := range []bool{streamingFirst, !streamingFirst} {
+		resp, err := c.generate(ctx, testCase, systemPrompt, userPrompt, opts, stream)
+		if err != nil {
+			if stream {
+				return nil, fmt.Errorf("streaming: %w", err)
+			}
+			return nil, fmt.Errorf("non-streaming: %w", err)
+		}
+
+		if stream {
+			cmp.Streaming = resp
+		} else {
+			cmp.NonStreaming = resp
+		}
+	}
+
+	cmp.Overhead = cmp.Streaming.Latency - cmp.NonStreaming.Latency
+
+	return cmp, nil
+}
+
+// generate sends a prompt to the LLM, streaming the response if stream is set. The images are
+// attached to the user message, after its text.
+func (c *Client) generate(ctx context.Context, testCase string, systemPrompt, userPrompt string, opts GenerateOptions, stream bool, images ...imagePart) (*Response, error) {
+	if err := c.checkContextSize(systemPrompt, userPrompt); err != nil {
+		return nil, err
+	}
+
+	temperature := opts.Temperature
+
 	spanAttrs := []attribute.KeyValue{
 		attribute.String(semconv.AttrModel, c.model),
 		attribute.String(semconv.AttrSystemPrompt, systemPrompt),
 		attribute.String(semconv.AttrUserPrompt, userPrompt),
 		attribute.Float64(semconv.AttrTemperature, temperature),
+		attribute.Bool(semconv.AttrStream, stream),
 	}
 	if testCase != "" {
 		spanAttrs = append(spanAttrs, attribute.String(semconv.AttrCase, testCase))
 	}
+	if opts.Seed != nil {
+		spanAttrs = append(spanAttrs, attribute.Int(semconv.AttrSeed, *opts.Seed))
+	}
+	for _, image := range images {
+		spanAttrs = append(spanAttrs,
+			attribute.String(semconv.AttrImageMimeType, image.mimeType),
+			attribute.Int(semconv.AttrImageBytes, image.size),
+		)
+	}
 
 	ctx, span := c.tracer.Start(ctx, "llm.generate",
 		trace.WithAttributes(spanAttrs...),
 	)
 	defer span.End()
 
-	content := []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeSystem, systemPrompt),
-		llms.TextParts(llms.ChatMessageTypeHuman, userPrompt),
+	content := c.messages(systemPrompt, userPrompt)
+	userMessage := &content[len(content)-1]
+	for _, image := range images {
+		userMessage.Parts = append(userMessage.Parts, image.part)
 	}
 
 	start := time.Now()
 	var ttft time.Duration
 	firstTokenReceived := false
 	var fullContent strings.Builder
+	var chunkTimes []time.Time
 
-	// Use streaming to capture real TTFT
-	completion, err := c.llm.GenerateContent(ctx, content,
+	callOpts := []llms.CallOption{
 		llms.WithTemperature(temperature),
-		llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+	}
+	if stream {
+		// Use streaming to capture real TTFT
+		callOpts = append(callOpts, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
 			if !firstTokenReceived {
 				ttft = time.Since(start)
 				firstTokenReceived = true
 			}
+			chunkTimes = append(chunkTimes, time.Now())
 			fullContent.Write(chunk)
 			return nil
-		}),
-	)
+		}))
+	}
+	if opts.Seed != nil {
+		callOpts = append(callOpts, llms.WithSeed(*opts.Seed))
+	}
+
+	completion, err := c.llm.GenerateContent(ctx, content, callOpts...)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("generate content: %w", err)
@@ -135,19 +400,12 @@ func (c *Client) GenerateWithTemp(ctx context.Context, testCase string, systemPr
 
 	if len(completion.Choices) > 0 && completion.Choices[0].GenerationInfo != nil {
 		genInfo := completion.Choices[0].GenerationInfo
-		if pt, ok := genInfo["PromptTokens"].(int); ok {
-			promptTokens = pt
-		} else {
-			promptTokens = llms.CountTokens(c.model, systemPrompt+userPrompt)
-		}
-		if ct, ok := genInfo["CompletionTokens"].(int); ok {
-			completionTokens = ct
-		} else {
-			completionTokens = llms.CountTokens(c.model, responseContent)
-		}
-		if tt, ok := genInfo["TotalTokens"].(int); ok {
-			totalTokens = tt
-		} else {
+
+		var ok bool
+		promptTokens, completionTokens, totalTokens, ok = callbacks.ExtractTokenUsage(genInfo)
+		if !ok {
+			promptTokens = CountTokens(c.model, systemPrompt+userPrompt)
+			completionTokens = CountTokens(c.model, responseContent)
 			totalTokens = promptTokens + completionTokens
 		}
 
@@ -180,6 +438,9 @@ func (c *Client) GenerateWithTemp(ctx context.Context, testCase string, systemPr
 		ttft = latency
 	}
 
+	interTokenGaps := chunkGaps(chunkTimes)
+	interTokenP50, interTokenP95 := interTokenStats(interTokenGaps)
+
 	resp := &Response{
 		Content:          responseContent,
 		PromptTokens:     promptTokens,
@@ -188,6 +449,9 @@ func (c *Client) GenerateWithTemp(ctx context.Context, testCase string, systemPr
 		Latency:          latency,
 		PromptEvalTime:   promptEvalTime,
 		TTFT:             ttft,
+		InterTokenP50:    interTokenP50,
+		InterTokenP95:    interTokenP95,
+		InterTokenGaps:   interTokenGaps,
 	}
 
 	// Add response metadata to span
@@ -198,7 +462,10 @@ func (c *Client) GenerateWithTemp(ctx context.Context, testCase string, systemPr
 		attribute.Int64(semconv.AttrLatencyMs, latency.Milliseconds()),
 		attribute.Int64(semconv.AttrPromptEvalTimeMs, promptEvalTime.Milliseconds()),
 		attribute.Int64(semconv.AttrTTFTMs, ttft.Milliseconds()),
+		attribute.Float64(semconv.AttrInterTokenP50Ms, durationToMs(interTokenP50)),
+		attribute.Float64(semconv.AttrInterTokenP95Ms, durationToMs(interTokenP95)),
 	)
+	c.detectRefusal(span, resp)
 
 	// Log the model response
 	logger := global.GetLoggerProvider().Logger("llmclient")
@@ -248,12 +515,54 @@ func sanitizeUTF8(s string) string {
 	return strings.ToValidUTF8(s, "�")
 }
 
-// estimateTokens provides a rough estimate of token count based on character count.
-// This is used as a last-resort fallback when neither GenerationInfo nor llms.CountTokens provides token counts.
-// Preference order: 1) GenerationInfo, 2) llms.CountTokens, 3) estimateTokens
-func estimateTokens(text string) int {
-	// Rough approximation: 1 token ≈ 4 characters for English text
-	return len(text) / 4
+// interTokenStats calculates the median and 95th percentile of the gaps between consecutive
+// streaming chunks, see chunkGaps. The gaps are left unsorted, in the order of the chunks.
+func interTokenStats(gaps []time.Duration) (p50, p95 time.Duration) {
+	if len(gaps) == 0 {
+		return 0, 0
+	}
+
+	gaps = slices.Sorted(slices.Values(gaps))
+
+	return durationPercentile(gaps, 50), durationPercentile(gaps, 95)
+}
+
+// chunkGaps returns the time elapsed between consecutive streaming chunks, in order,
+// nil with less than two chunks. The time to the first chunk is not included, as it's
+// already measured as TTFT.
+func chunkGaps(chunkTimes []time.Time) []time.Duration {
+	if len(chunkTimes) < 2 {
+		return nil
+	}
+
+	gaps := make([]time.Duration, 0, len(chunkTimes)-1)
+	for i := 1; i < len(chunkTimes); i++ {
+		gaps = append(gaps, chunkTimes[i].Sub(chunkTimes[i-1]))
+	}
+
+	return gaps
+}
+
+// durationPercentile calculates the nth percentile of a sorted slice of durations,
+// interpolating linearly between the closest ranks
+func durationPercentile(sorted []time.Duration, p int) time.Duration {
+	index := (float64(p) / 100.0) * float64(len(sorted)-1)
+	lower := int(index)
+	upper := lower + 1
+
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	weight := index - float64(lower)
+	// Round to the nanosecond, so the floating point error of the weight does not truncate the result
+	return time.Duration(math.Round(float64(sorted[lower])*(1-weight) + float64(sorted[upper])*weight))
+}
+
+// durationToMs converts a duration to fractional milliseconds, as inter-token
+// latencies are usually below one millisecond apart on fast backends
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
 }
 
 // ToolResult contains information about a tool call execution
@@ -279,6 +588,10 @@ type ResponseWithTools struct {
 // GenerateWithTools sends a prompt to the LLM with tools and iteratively executes tool calls
 // until the model provides a final answer or reaches maxIterations
 func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemPrompt, userPrompt string, temperature float64, tools []llms.Tool, maxIterations int) (*ResponseWithTools, error) {
+	if err := c.checkContextSize(systemPrompt, userPrompt); err != nil {
+		return nil, err
+	}
+
 	spanAttrs := []attribute.KeyValue{
 		attribute.String(semconv.AttrModel, c.model),
 		attribute.String(semconv.AttrSystemPrompt, systemPrompt),
@@ -300,10 +613,7 @@ func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemP
 	iterations := 0
 
 	// Build initial message history
-	messages := []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeSystem, systemPrompt),
-		llms.TextParts(llms.ChatMessageTypeHuman, userPrompt),
-	}
+	messages := c.messages(systemPrompt, userPrompt)
 
 	// Iterative loop for tool calling
 	for iterations < maxIterations {
@@ -337,22 +647,8 @@ func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemP
 			// Build response with tool metadata
 			totalLatency := time.Since(totalStart)
 
-			// Get token usage from first LLM call (approximation)
-			promptTokens := 0
-			completionTokens := 0
-			totalTokens := 0
-
-			if choice.GenerationInfo != nil {
-				if pt, ok := choice.GenerationInfo["PromptTokens"].(int); ok {
-					promptTokens = pt
-				}
-				if ct, ok := choice.GenerationInfo["CompletionTokens"].(int); ok {
-					completionTokens = ct
-				}
-				if tt, ok := choice.GenerationInfo["TotalTokens"].(int); ok {
-					totalTokens = tt
-				}
-			}
+			// Get token usage from the final LLM call (approximation)
+			promptTokens, completionTokens, totalTokens, _ := callbacks.ExtractTokenUsage(choice.GenerationInfo)
 
 			if totalTokens == 0 {
 				promptTokens = estimateTokens(systemPrompt + userPrompt)
@@ -370,14 +666,17 @@ func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemP
 				attribute.Int("iterations", iterations),
 			)
 
+			resp := &Response{
+				Content:          finalContent,
+				PromptTokens:     promptTokens,
+				CompletionTokens: completionTokens,
+				TotalTokens:      totalTokens,
+				Latency:          totalLatency,
+			}
+			c.detectRefusal(span, resp)
+
 			return &ResponseWithTools{
-				Response: &Response{
-					Content:          finalContent,
-					PromptTokens:     promptTokens,
-					CompletionTokens: completionTokens,
-					TotalTokens:      totalTokens,
-					Latency:          totalLatency,
-				},
+				Response:     resp,
 				ToolCalls:    toolResults,
 				Iterations:   iterations,
 				TotalLatency: totalLatency,
@@ -392,7 +691,7 @@ func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemP
 			toolStart := time.Now()
 
 			// Execute the tool
-			output, err := executeToolCall(ctx, toolCall)
+			output, err := c.executeToolCall(ctx, toolCall)
 
 			toolDuration := time.Since(toolStart)
 			toolLatency += toolDuration
@@ -443,8 +742,21 @@ func (c *Client) GenerateWithTools(ctx context.Context, testCase string, systemP
 	}, fmt.Errorf("maximum iterations (%d) reached without final answer", maxIterations)
 }
 
+// detectRefusal flags the response as a refusal, if the client classifies them, see WithRefusalDetector
+func (c *Client) detectRefusal(span trace.Span, resp *Response) {
+	if c.refusalDetector == nil {
+		return
+	}
+
+	resp.Refusal = c.refusalDetector(resp.Content)
+	span.SetAttributes(attribute.Bool(semconv.AttrRefusal, resp.Refusal))
+}
+
+// currencyConverter converts currencies for all the clients, caching the exchange rates
+var currencyConverter = tools.NewCurrencyConverter()
+
 // executeToolCall routes a tool call to the appropriate tool implementation
-func executeToolCall(ctx context.Context, toolCall llms.ToolCall) (string, error) {
+func (c *Client) executeToolCall(ctx context.Context, toolCall llms.ToolCall) (string, error) {
 	switch toolCall.FunctionCall.Name {
 	case "calculator":
 		calc := tools.NewCalculator()
@@ -458,6 +770,19 @@ func executeToolCall(ctx context.Context, toolCall llms.ToolCall) (string, error
 		httpClient := tools.NewHTTPClient()
 		return httpClient.Execute(toolCall.FunctionCall.Arguments)
 
+	case "currency":
+		// Shared, so the cached rates are reused across the calls
+		return currencyConverter.Execute(toolCall.FunctionCall.Arguments)
+
+	case "wikipedia":
+		wikipedia := tools.NewWikipedia()
+		return wikipedia.Execute(toolCall.FunctionCall.Arguments)
+
+	case "model_info":
+		// The metadata is served by the Docker Model Runner API, next to the OpenAI-compatible one
+		modelInfo := tools.NewModelInfoTool(strings.TrimSuffix(strings.TrimSuffix(c.endpoint, "/"), "/engines/v1"), c.model)
+		return modelInfo.Execute(toolCall.FunctionCall.Arguments)
+
 	default:
 		return "", fmt.Errorf("unknown tool: %s", toolCall.FunctionCall.Name)
 	}
@@ -480,3 +805,20 @@ func GetHTTPClientTool() llms.Tool {
 	httpClient := tools.NewHTTPClient()
 	return httpClient.GetToolDefinition()
 }
+
+// GetCurrencyTool returns the currency converter tool definition
+func GetCurrencyTool() llms.Tool {
+	return currencyConverter.GetToolDefinition()
+}
+
+// GetWikipediaTool returns the Wikipedia tool definition
+func GetWikipediaTool() llms.Tool {
+	wikipedia := tools.NewWikipedia()
+	return wikipedia.GetToolDefinition()
+}
+
+// GetModelInfoTool returns the model metadata tool definition
+func GetModelInfoTool() llms.Tool {
+	modelInfo := tools.NewModelInfoTool("", "")
+	return modelInfo.GetToolDefinition()
+}
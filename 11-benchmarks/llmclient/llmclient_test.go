@@ -0,0 +1,523 @@
+package llmclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeStreamingModel streams its chunks, waiting the given interval between them
+type fakeStreamingModel struct {
+	chunks   []string
+	interval time.Duration
+	// streamed records whether each call streamed the response
+	streamed []bool
+}
+
+func (f *fakeStreamingModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	f.streamed = append(f.streamed, opts.StreamingFunc != nil)
+
+	content := ""
+	for i, chunk := range f.chunks {
+		if i > 0 {
+			time.Sleep(f.interval)
+		}
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return nil, err
+			}
+		}
+		content += chunk
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: content}},
+	}, nil
+}
+
+func (f *fakeStreamingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func newFakeClient(model llms.Model) *Client {
+	return &Client{
+		llm:    model,
+		model:  "fake-model",
+		tracer: otel.Tracer("llmclient-test"),
+	}
+}
+
+func TestGenerateWithTemp_interTokenLatency(t *testing.T) {
+	interval := 20 * time.Millisecond
+	client := newFakeClient(&fakeStreamingModel{
+		chunks:   []string{"The", " answer", " is", " 42", "."},
+		interval: interval,
+	})
+
+	resp, err := client.GenerateWithTemp(context.Background(), "test-case", "system", "user", 0.1)
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if resp.Content != "The answer is 42." {
+		t.Fatalf("unexpected content: %q", resp.Content)
+	}
+
+	// Sleeping guarantees a lower bound, the upper bound is generous to absorb scheduler noise
+	if resp.InterTokenP50 < interval || resp.InterTokenP50 > 5*interval {
+		t.Errorf("expected inter-token p50 around %s, got %s", interval, resp.InterTokenP50)
+	}
+	if resp.InterTokenP95 < resp.InterTokenP50 {
+		t.Errorf("expected inter-token p95 (%s) to be >= p50 (%s)", resp.InterTokenP95, resp.InterTokenP50)
+	} // The gaps between the 5 chunks, to pool them across requests
+	if len(resp.InterTokenGaps) != 4 {
+		t.Errorf("expected 4 inter-token gaps, got %d", len(resp.InterTokenGaps))
+	}
+}
+
+func TestInterTokenStats(t *testing.T) {
+	start := time.Now()
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	t.Run("gaps", func(t *testing.T) {
+		// gaps: 10, 10, 10, 10, 50
+		p50, p95 := interTokenStats(chunkGaps([]time.Time{at(0), at(10), at(20), at(30), at(40), at(90)}))
+
+		if p50 != 10*time.Millisecond {
+			t.Errorf("expected p50 of 10ms, got %s", p50)
+		}
+		if p95 != 42*time.Millisecond {
+			t.Errorf("expected p95 of 42ms, got %s", p95)
+		}
+	})
+
+	t.Run("single-chunk", func(t *testing.T) {
+		p50, p95 := interTokenStats(chunkGaps([]time.Time{at(0)}))
+		if p50 != 0 || p95 != 0 {
+			t.Errorf("expected zero stats for a single chunk, got p50=%s p95=%s", p50, p95)
+		}
+		if gaps := chunkGaps([]time.Time{at(0)}); gaps != nil {
+			t.Errorf("expected no gaps for a single chunk, got %v", gaps)
+		}
+	})
+
+	t.Run("gaps-in-order", func(t *testing.T) {
+		gaps := chunkGaps([]time.Time{at(0), at(50), at(60)})
+
+		expected := []time.Duration{50 * time.Millisecond, 10 * time.Millisecond}
+		if !slices.Equal(gaps, expected) {
+			t.Errorf("expected the gaps %v, got %v", expected, gaps)
+		}
+
+		// the stats do not sort the gaps of the response
+		interTokenStats(gaps)
+		if !slices.Equal(gaps, expected) {
+			t.Errorf("expected the gaps to keep their order, got %v", gaps)
+		}
+	})
+}
+
+// recordingModel records the call options of the last request
+type recordingModel struct {
+	opts     llms.CallOptions
+	messages []llms.MessageContent
+	calls    int
+}
+
+func (r *recordingModel) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	r.calls++
+	r.messages = messages
+	r.opts = llms.CallOptions{}
+	for _, opt := range options {
+		opt(&r.opts)
+	}
+
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "ok"}},
+	}, nil
+}
+
+func (r *recordingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+func TestGenerateWithOptions_seed(t *testing.T) {
+	t.Run("with-seed", func(t *testing.T) {
+		model := &recordingModel{}
+		client := newFakeClient(model)
+
+		seed := 42
+		_, err := client.GenerateWithOptions(context.Background(), "test-case", "system", "user", GenerateOptions{Temperature: 0.3, Seed: &seed})
+		if err != nil {
+			t.Fatalf("generate: %s", err)
+		}
+
+		if model.opts.Seed != 42 {
+			t.Errorf("expected seed 42 to be forwarded, got %d", model.opts.Seed)
+		}
+		if model.opts.Temperature != 0.3 {
+			t.Errorf("expected temperature 0.3 to be forwarded, got %.1f", model.opts.Temperature)
+		}
+	})
+
+	t.Run("without-seed", func(t *testing.T) {
+		model := &recordingModel{}
+		client := newFakeClient(model)
+
+		_, err := client.GenerateWithTemp(context.Background(), "test-case", "system", "user", 0.3)
+		if err != nil {
+			t.Fatalf("generate: %s", err)
+		}
+
+		if model.opts.Seed != 0 {
+			t.Errorf("expected no seed to be forwarded, got %d", model.opts.Seed)
+		}
+	})
+}
+
+func TestGenerateWithTemp_contextSize(t *testing.T) {
+	t.Run("exceeded", func(t *testing.T) {
+		model := &recordingModel{}
+		client := newFakeClient(model)
+		WithContextSize(16)(client)
+
+		userPrompt := strings.Repeat("Summarize this very long document. ", 100)
+
+		_, err := client.GenerateWithTemp(context.Background(), "test-case", "system", userPrompt, 0.1)
+		if !errors.Is(err, ErrContextExceeded) {
+			t.Fatalf("expected a context exceeded error, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "allows 16") {
+			t.Errorf("expected the allowed tokens in the error, got %q", err)
+		}
+
+		if model.calls != 0 {
+			t.Errorf("expected no request to be sent, got %d", model.calls)
+		}
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		model := &recordingModel{}
+		client := newFakeClient(model)
+		WithContextSize(4096)(client)
+
+		if _, err := client.GenerateWithTemp(context.Background(), "test-case", "system", "user", 0.1); err != nil {
+			t.Fatalf("generate: %s", err)
+		}
+
+		if model.calls != 1 {
+			t.Errorf("expected the request to be sent, got %d calls", model.calls)
+		}
+	})
+}
+
+func TestCompareStreaming(t *testing.T) {
+	model := &fakeStreamingModel{
+		chunks:   []string{"The", " answer", " is", " 42", "."},
+		interval: 5 * time.Millisecond,
+	}
+	client := newFakeClient(model)
+
+	cmp, err := client.CompareStreaming(context.Background(), "test-case", "system", "user", GenerateOptions{Temperature: 0.1}, true)
+	if err != nil {
+		t.Fatalf("compare streaming: %s", err)
+	}
+
+	for name, resp := range map[string]*Response{"streaming": cmp.Streaming, "non-streaming": cmp.NonStreaming} {
+		if resp.Content != "The answer is 42." {
+			t.Errorf("unexpected %s content: %q", name, resp.Content)
+		}
+	}
+
+	// Only the streamed response measures the gaps between the chunks
+	if cmp.Streaming.InterTokenP50 == 0 {
+		t.Error("expected the streaming inter-token latency to be measured")
+	}
+	if cmp.NonStreaming.InterTokenP50 != 0 || cmp.NonStreaming.TTFT != cmp.NonStreaming.Latency {
+		t.Errorf("expected the non-streaming TTFT to be the latency, got %s and %s", cmp.NonStreaming.TTFT, cmp.NonStreaming.Latency)
+	}
+
+	if expected := cmp.Streaming.Latency - cmp.NonStreaming.Latency; cmp.Overhead != expected {
+		t.Errorf("expected overhead %s, got %s", expected, cmp.Overhead)
+	}
+
+	// The order is reversed on demand, so the warm cache doesn't always favor the same mode
+	cmp, err = client.CompareStreaming(context.Background(), "test-case", "system", "user", GenerateOptions{Temperature: 0.1}, false)
+	if err != nil {
+		t.Fatalf("compare streaming: %s", err)
+	}
+	if cmp.StreamingFirst || cmp.Streaming == nil || cmp.NonStreaming == nil {
+		t.Errorf("expected both responses with the non-streaming one first, got %+v", cmp)
+	}
+	if expected := []bool{true, false, false, true}; !slices.Equal(model.streamed, expected) {
+		t.Errorf("expected the requests streamed as %v, got %v", expected, model.streamed)
+	}
+}
+
+func TestWithStream(t *testing.T) {
+	model := &recordingModel{}
+	client := newFakeClient(model)
+	WithStream(false)(client)
+
+	if _, err := client.GenerateWithTemp(context.Background(), "test-case", "system", "user", 0.1); err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if model.opts.StreamingFunc != nil {
+		t.Error("expected no streaming function to be sent")
+	}
+}
+
+func TestWithSystemPrompt(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported bool
+		expected  []llms.MessageContent
+	}{
+		{
+			name:      "supported",
+			supported: true,
+			expected: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeSystem, "You are a Go expert."),
+				llms.TextParts(llms.ChatMessageTypeHuman, "Explain goroutines."),
+			},
+		},
+		{
+			name:      "unsupported",
+			supported: false,
+			expected: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeHuman, "You are a Go expert.\n\nExplain goroutines."),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &recordingModel{}
+			client := newFakeClient(model)
+			WithSystemPrompt(tt.supported)(client)
+
+			if _, err := client.GenerateWithTemp(context.Background(), "test-case", "You are a Go expert.", "Explain goroutines.", 0.1); err != nil {
+				t.Fatalf("generate: %s", err)
+			}
+
+			if !reflect.DeepEqual(model.messages, tt.expected) {
+				t.Errorf("unexpected messages:\n got: %+v\nwant: %+v", model.messages, tt.expected)
+			}
+		})
+	}
+
+	t.Run("unsupported-without-system-prompt", func(t *testing.T) {
+		model := &recordingModel{}
+		client := newFakeClient(model)
+		WithSystemPrompt(false)(client)
+
+		if _, err := client.GenerateWithTemp(context.Background(), "test-case", "", "Explain goroutines.", 0.1); err != nil {
+			t.Fatalf("generate: %s", err)
+		}
+
+		expected := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Explain goroutines.")}
+		if !reflect.DeepEqual(model.messages, expected) {
+			t.Errorf("unexpected messages:\n got: %+v\nwant: %+v", model.messages, expected)
+		}
+	})
+}
+
+// recordingTransport answers the chat completions with a canned response, recording the requests
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+
+	body := `{"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}],` +
+		`"usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}}`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	transport := &recordingTransport{}
+
+	client, err := NewClient("http://model-runner.test/engines/v1", "ai/smollm2", WithHTTPClient(&http.Client{Transport: transport}), WithStream(false))
+	if err != nil {
+		t.Fatalf("new client: %s", err)
+	}
+
+	resp, err := client.GenerateWithTemp(context.Background(), "test-case", "system", "user", 0.1)
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("expected the request sent by the injected client, got %d requests", len(transport.requests))
+	}
+	if host := transport.requests[0].URL.Host; host != "model-runner.test" {
+		t.Errorf("expected the request to the model runner, got %s", host)
+	}
+	if resp.Content != "ok" || resp.TotalTokens != 6 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestNewClient_defaultHTTPClient(t *testing.T) {
+	first, err := NewClient("http://model-runner.test/engines/v1", "ai/smollm2")
+	if err != nil {
+		t.Fatalf("new client: %s", err)
+	}
+	second, err := NewClient("http://model-runner.test/engines/v1", "ai/qwen3")
+	if err != nil {
+		t.Fatalf("new client: %s", err)
+	}
+
+	// The clients share the pooled transport, to reuse the connections
+	if first.httpClient != defaultHTTPClient || second.httpClient != defaultHTTPClient {
+		t.Error("expected the clients to share the default HTTP client")
+	}
+	if transport, ok := defaultHTTPClient.Transport.(*http.Transport); !ok || transport.MaxIdleConnsPerHost <= 2 {
+		t.Errorf("expected a pooled transport, got %+v", defaultHTTPClient.Transport)
+	}
+}
+
+// fakeRecordingModel answers with its content, recording the messages it receives
+type fakeRecordingModel struct {
+	content  string
+	messages []llms.MessageContent
+}
+
+func (f *fakeRecordingModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	f.messages = messages
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: f.content}},
+	}, nil
+}
+
+func (f *fakeRecordingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func TestGenerateWithImage(t *testing.T) {
+	// The PNG signature is enough to sniff the content type
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	model := &fakeRecordingModel{content: "A cat."}
+	client := newFakeClient(model)
+
+	resp, err := client.GenerateWithImage(context.Background(), "You describe images.", "What is in the picture?", png, "image/png")
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+	if resp.Content != "A cat." {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+
+	if len(model.messages) != 2 {
+		t.Fatalf("expected the system and user messages, got %d", len(model.messages))
+	}
+	user := model.messages[1]
+	if user.Role != llms.ChatMessageTypeHuman || len(user.Parts) != 2 {
+		t.Fatalf("expected the user message with the text and the image, got %+v", user)
+	}
+	if text, ok := user.Parts[0].(llms.TextContent); !ok || text.Text != "What is in the picture?" {
+		t.Errorf("expected the prompt first, got %+v", user.Parts[0])
+	}
+	image, ok := user.Parts[1].(llms.ImageURLContent)
+	if !ok {
+		t.Fatalf("expected an image part, got %T", user.Parts[1])
+	}
+	if expected := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png); image.URL != expected {
+		t.Errorf("expected the image as a data URL %q, got %q", expected, image.URL)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			data     []byte
+			mimeType string
+		}{
+			{name: "empty", data: nil, mimeType: "image/png"},
+			{name: "unsupported", data: png, mimeType: "image/tiff"},
+			{name: "not-an-image", data: png, mimeType: "text/plain"},
+			{name: "mismatch", data: png, mimeType: "image/jpeg"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.GenerateWithImage(context.Background(), "system", "user", tt.data, tt.mimeType)
+				if !errors.Is(err, ErrUnsupportedImage) {
+					t.Errorf("expected ErrUnsupportedImage, got %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestWithRefusalDetector(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	isRefusal := func(content string) bool { return strings.HasPrefix(content, "I cannot") }
+
+	for content, refusal := range map[string]bool{"I cannot help with that.": true, "The answer is 42.": false} {
+		client := newFakeClient(&fakeRecordingModel{content: content})
+		client.tracer = provider.Tracer("llmclient-test")
+		WithRefusalDetector(isRefusal)(client)
+
+		resp, err := client.GenerateWithTemp(context.Background(), "factual-question", "You are helpful.", "What is the answer?", 0.1)
+		if err != nil {
+			t.Fatalf("generate: %s", err)
+		}
+		if resp.Refusal != refusal {
+			t.Errorf("expected refusal %t for %q, got %t", refusal, content, resp.Refusal)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		found := false
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.AttrRefusal {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected the %s attribute in the span", semconv.AttrRefusal)
+		}
+	}
+
+	// Without a detector, the responses are not classified
+	resp, err := newFakeClient(&fakeRecordingModel{content: "I cannot help with that."}).GenerateWithTemp(context.Background(), "", "You are helpful.", "What is the answer?", 0.1)
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+	if resp.Refusal {
+		t.Error("expected no refusal without a detector")
+	}
+}
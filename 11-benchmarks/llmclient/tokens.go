@@ -0,0 +1,96 @@
+package llmclient
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/modelname"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// tokenizerFamilies maps the families of models to the tiktoken encoding of their tokenizer, or the
+// closest one, by the prefix of the model name without its namespace nor its tag, lowercased: e.g.
+// llama3.2 for ai/llama3.2:1B-Q4_0, or llama-3.2-1b-instruct-gguf for hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF.
+//
+// llms.CountTokens only knows the names of the OpenAI models, so it falls back to a rough estimate for
+// the namespaced names of Docker Model Runner. The families with SentencePiece tokenizers, e.g. Gemma,
+// Mistral or Phi, have no tiktoken equivalent and use the estimate of estimateTokens.
+var tokenizerFamilies = []struct {
+	prefix   string
+	encoding string
+}{
+	// OpenAI models: the o200k_base encoding of the newest ones is not supported by tiktoken-go,
+	// cl100k_base counts a few more tokens for the same text
+	{prefix: "gpt-", encoding: tiktoken.MODEL_CL100K_BASE},
+	{prefix: "o1", encoding: tiktoken.MODEL_CL100K_BASE},
+	{prefix: "o3", encoding: tiktoken.MODEL_CL100K_BASE},
+	{prefix: "o4", encoding: tiktoken.MODEL_CL100K_BASE},
+	// Llama 3 extends the 100k tokens of cl100k_base with 28k tokens for other languages
+	{prefix: "llama3", encoding: tiktoken.MODEL_CL100K_BASE},
+	{prefix: "llama-3", encoding: tiktoken.MODEL_CL100K_BASE},
+	// Qwen uses a byte-level BPE tokenizer built on cl100k_base
+	{prefix: "qwen", encoding: tiktoken.MODEL_CL100K_BASE},
+}
+
+// TokenizerEncoding returns the tiktoken encoding of the tokenizer family of the model, empty when
+// the family is unknown, see tokenizerFamilies
+func TokenizerEncoding(model string) string {
+	parsed, err := modelname.Parse(model)
+	if err != nil {
+		return ""
+	}
+	name := strings.ToLower(parsed.Name)
+
+	for _, family := range tokenizerFamilies {
+		if strings.HasPrefix(name, family.prefix) {
+			return family.encoding
+		}
+	}
+
+	return ""
+}
+
+// CountTokens counts the tokens of the text with the tokenizer family of the model, falling back to
+// estimateTokens when the family is unknown or its encoding can't be loaded, e.g. offline, as the
+// encodings are downloaded on first use
+func CountTokens(model, text string) int {
+	if enc := loadEncoding(TokenizerEncoding(model)); enc != nil {
+		return len(enc.Encode(text, nil, nil))
+	}
+
+	return estimateTokens(text)
+}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = map[string]*tiktoken.Tiktoken{} // nil for the encodings that failed to load
+)
+
+// loadEncoding returns the encoding, loaded once as building it is expensive, nil when the name is
+// empty or the encoding can't be loaded
+func loadEncoding(name string) *tiktoken.Tiktoken {
+	if name == "" {
+		return nil
+	}
+
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+
+	enc, ok := encodings[name]
+	if !ok {
+		enc, _ = tiktoken.GetEncoding(name) // A failure falls back to the estimate, don't retry it
+		encodings[name] = enc
+	}
+
+	return enc
+}
+
+// estimateTokens provides a rough estimate of token count based on character count.
+// This is used as a last-resort fallback when neither GenerationInfo nor CountTokens provides token counts.
+// Preference order: 1) GenerationInfo, 2) CountTokens, 3) estimateTokens
+func estimateTokens(text string) int {
+	// Rough approximation: 1 token ≈ 4 characters for English text, rounded up so a short text
+	// doesn't count as zero tokens
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
@@ -0,0 +1,83 @@
+package llmclient
+
+import (
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+func TestTokenizerEncoding(t *testing.T) {
+	tests := []struct {
+		model    string
+		expected string
+	}{
+		{model: "ai/llama3.2:1B-Q4_0", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "ai/llama3.1:8B-Q4_K_M", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "ai/qwen3:0.6B-Q4_0", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "ai/qwen2.5", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "gpt-4o-mini", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "gpt-5.1", expected: tiktoken.MODEL_CL100K_BASE},
+		{model: "o3-mini", expected: tiktoken.MODEL_CL100K_BASE},
+		// SentencePiece tokenizers have no tiktoken equivalent
+		{model: "ai/gemma3:4B-Q4_K_M", expected: ""},
+		{model: "ai/mistral:7B-Q4_0", expected: ""},
+		{model: "ai/smollm2", expected: ""},
+		{model: "ai/llama2:7B", expected: ""},
+		{model: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := TokenizerEncoding(tt.model); got != tt.expected {
+				t.Errorf("expected encoding %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCountTokens(t *testing.T) {
+	text := "Testcontainers for Go makes integration testing with real dependencies easy."
+
+	t.Run("unknown-family", func(t *testing.T) {
+		if got, expected := CountTokens("ai/gemma3:4B-Q4_K_M", text), estimateTokens(text); got != expected {
+			t.Errorf("expected the estimate of %d tokens, got %d", expected, got)
+		}
+	})
+
+	t.Run("known-family", func(t *testing.T) {
+		// The encoding is downloaded on first use: offline, the count falls back to the estimate
+		if got := CountTokens("ai/llama3.2:1B-Q4_0", text); got <= 0 {
+			t.Errorf("expected a positive count, got %d", got)
+		}
+	})
+
+	t.Run("short-text", func(t *testing.T) {
+		if got := CountTokens("ai/gemma3", "Hi"); got != 1 {
+			t.Errorf("expected a short text to count as 1 token, got %d", got)
+		}
+		if got := CountTokens("ai/gemma3", ""); got != 0 {
+			t.Errorf("expected an empty text to count as 0 tokens, got %d", got)
+		}
+	})
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{text: "", expected: 0},
+		{text: "abc", expected: 1},
+		{text: "abcd", expected: 1},
+		{text: "abcde", expected: 2},
+		// Characters, not bytes
+		{text: "日本語です", expected: 2},
+	}
+
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.expected {
+			t.Errorf("expected %d tokens for %q, got %d", tt.expected, tt.text, got)
+		}
+	}
+}
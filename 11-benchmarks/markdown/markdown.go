@@ -0,0 +1,81 @@
+// Package markdown extracts the structured parts of the model responses, which are usually
+// written in markdown, such as the fenced code blocks to execute or evaluate.
+package markdown
+
+import "strings"
+
+// CodeBlock is a fenced code block of a markdown text
+type CodeBlock struct {
+	// Language is the first word of the info string of the opening fence, empty without it
+	Language string
+	// Code is the content between the fences, without the trailing newline
+	Code string
+}
+
+// fence is the opening fence of a code block
+type fence struct {
+	char   byte // '`' or '~'
+	length int  // At least 3
+}
+
+// ExtractCodeBlocks returns the code blocks fenced with three or more backticks or tildes, in order.
+// As in CommonMark, a block is closed by a fence of the same character at least as long as the
+// opening one, so a block fenced with four backticks can contain lines with three of them, and an
+// unclosed block runs until the end of the text, as in truncated responses. Indented fences are
+// accepted, as the models indent the code blocks in lists. Inline code spans, with backticks in the
+// info string, are not fences.
+func ExtractCodeBlocks(text string) []CodeBlock {
+	var blocks []CodeBlock
+
+	var open *fence
+	var language string
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		if open == nil {
+			if f, info, ok := parseFence(line); ok {
+				open = &f
+				language, _, _ = strings.Cut(info, " ")
+				lines = nil
+			}
+			continue
+		}
+
+		if f, info, ok := parseFence(line); ok && f.char == open.char && f.length >= open.length && info == "" {
+			blocks = append(blocks, CodeBlock{Language: language, Code: strings.Join(lines, "\n")})
+			open = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if open != nil {
+		blocks = append(blocks, CodeBlock{Language: language, Code: strings.Join(lines, "\n")})
+	}
+
+	return blocks
+}
+
+// parseFence parses a line as a code fence, returning it along with its trimmed info string
+func parseFence(line string) (fence, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return fence{}, "", false
+	}
+
+	f := fence{char: trimmed[0]}
+	for f.length < len(trimmed) && trimmed[f.length] == f.char {
+		f.length++
+	}
+	if f.length < 3 {
+		return fence{}, "", false
+	}
+
+	info := strings.TrimSpace(trimmed[f.length:])
+	if f.char == '`' && strings.ContainsRune(info, '`') {
+		return fence{}, "", false
+	}
+
+	return f, info, true
+}
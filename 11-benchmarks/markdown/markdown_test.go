@@ -0,0 +1,124 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []CodeBlock
+	}{
+		{
+			name:     "no-blocks",
+			text:     "The answer is 5050, using `n * (n + 1) / 2`.",
+			expected: nil,
+		},
+		{
+			name: "single-with-language",
+			text: "Here is the function:\n\n```go\nfunc fib(n int) int {\n\treturn n\n}\n```\n\nIt is recursive.",
+			expected: []CodeBlock{
+				{Language: "go", Code: "func fib(n int) int {\n\treturn n\n}"},
+			},
+		},
+		{
+			name: "multiple-blocks",
+			text: "First:\n```python\nprint(1)\n```\nThen:\n```bash\npython main.py\n```\n",
+			expected: []CodeBlock{
+				{Language: "python", Code: "print(1)"},
+				{Language: "bash", Code: "python main.py"},
+			},
+		},
+		{
+			name: "no-language",
+			text: "```\nprint(1)\n```",
+			expected: []CodeBlock{
+				{Code: "print(1)"},
+			},
+		},
+		{
+			name: "info-string-attributes",
+			text: "```python title=\"fib.py\"\nprint(1)\n```",
+			expected: []CodeBlock{
+				{Language: "python", Code: "print(1)"},
+			},
+		},
+		{
+			name: "nested-backticks",
+			text: "````markdown\nUse a code block:\n```go\nfmt.Println(1)\n```\n````",
+			expected: []CodeBlock{
+				{Language: "markdown", Code: "Use a code block:\n```go\nfmt.Println(1)\n```"},
+			},
+		},
+		{
+			name: "tildes",
+			text: "~~~python\nprint(\"```\")\n~~~",
+			expected: []CodeBlock{
+				{Language: "python", Code: "print(\"```\")"},
+			},
+		},
+		{
+			name: "indented-in-list",
+			text: "1. Run:\n   ```sh\n   go test ./...\n   ```",
+			expected: []CodeBlock{
+				{Language: "sh", Code: "   go test ./..."},
+			},
+		},
+		{
+			name: "crlf",
+			text: "```go\r\nx := 1\r\n```\r\n",
+			expected: []CodeBlock{
+				{Language: "go", Code: "x := 1"},
+			},
+		},
+		{
+			name: "empty-block",
+			text: "```go\n```",
+			expected: []CodeBlock{
+				{Language: "go", Code: ""},
+			},
+		},
+		{
+			name: "unclosed-runs-to-the-end",
+			text: "```python\ndef fib(n):\n    return n",
+			expected: []CodeBlock{
+				{Language: "python", Code: "def fib(n):\n    return n"},
+			},
+		},
+		{
+			name: "shorter-closing-fence-does-not-close",
+			text: "````go\nx := 1\n```\ny := 2\n````",
+			expected: []CodeBlock{
+				{Language: "go", Code: "x := 1\n```\ny := 2"},
+			},
+		},
+		{
+			name: "closing-fence-with-info-does-not-close",
+			text: "```go\nx := 1\n```python\n```",
+			expected: []CodeBlock{
+				{Language: "go", Code: "x := 1\n```python"},
+			},
+		},
+		{
+			name:     "inline-triple-backticks-are-not-fences",
+			text:     "Run ```go test``` to check it.",
+			expected: nil,
+		},
+		{
+			name:     "two-backticks-are-not-fences",
+			text:     "``\ncode\n``",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractCodeBlocks(tt.text)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
 	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -26,13 +33,19 @@ type AggregateMetrics struct {
 	TTFTP95            float64
 	PromptEvalTimeP50  float64
 	PromptEvalTimeP95  float64
+	InterTokenP50      float64 // Median of the inter-token latencies pooled across the requests
+	InterTokenP95      float64 // 95th percentile of the inter-token latencies pooled across the requests
+	LatencySamples     int     // Number of successful results the percentiles are computed from
 	SuccessRate        float64
+	RefusalRate        float64 // Rate of the successful responses that are empty or refusals
 	TokensPerOp        float64
-	EvalScore          float64 // Average evaluator score (0.0-1.0)
-	EvalPassRate       float64 // Percentage of "yes" responses from evaluator
-	TokensPerSec       float64 // Total TPS: (input + output) / TAT
-	OutputTokensPerSec float64 // Output TPS: output tokens / generation time
-	NsPerOp            float64 // Nanoseconds per operation (Go benchmark metric)
+	EvalScore          float64                        // Average evaluator score (0.0-1.0)
+	EvalPassRate       float64                        // Percentage of "yes" responses from evaluator
+	CategoryRates      map[evaluator.Category]float64 // Rate of each category of the evaluated responses, see categoryRates
+	TokensPerSec       float64                        // Total TPS: (input + output) / TAT
+	OutputTokensPerSec float64                        // Output TPS: output tokens / generation time
+	TokenEfficiency    float64                        // Average length of the responses against the expected one (0.0-1.0)
+	NsPerOp            float64                        // Nanoseconds per operation (Go benchmark metric)
 	// Tool calling metrics
 	ToolCallCount         float64 // Average tool calls per operation
 	ToolIterationCount    float64 // Average LLM-tool iterations per operation
@@ -50,27 +63,142 @@ type MetricsCollector struct {
 	meter metric.Meter
 
 	// Histograms
-	latencyHistogram         metric.Float64Histogram
-	ttftHistogram            metric.Float64Histogram
-	promptEvalTimeHistogram  metric.Float64Histogram
-	toolCallLatencyHistogram metric.Float64Histogram
+	latencyHistogram          metric.Float64Histogram
+	ttftHistogram             metric.Float64Histogram
+	promptEvalTimeHistogram   metric.Float64Histogram
+	toolCallLatencyHistogram  metric.Float64Histogram
+	interTokenHistogram       metric.Float64Histogram
+	ragLatencyHistogram       metric.Float64Histogram
+	completionTokensHistogram metric.Float64Histogram
 
 	// Store aggregate metrics per model/case/temp combination
 	aggregates   map[string]*AggregateMetrics
-	aggregatesMu sync.RWMutex // Protects aggregates map for concurrent access
+	aggregatesMu sync.RWMutex // Protects aggregates, coldStarts, modelMemory, promptEvals and throughputSweeps maps for concurrent access
+
+	// Latency in milliseconds of the very first request per model
+	coldStarts map[string]float64
+
+	// Host memory in MB attributable to loading each model
+	modelMemory map[string]float64
+
+	// Prompt-eval times of the requests per model and system prompt, in order, see RecordPromptEval
+	promptEvals map[promptCacheKey]*promptEvalSeries
+
+	// Capacity curve per model and test case, see RecordThroughputSweep
+	throughputSweeps map[throughputKey]ThroughputSweep
+
+	// Attributes added to all the measurements, e.g. the inference backend
+	baseAttrs []attribute.KeyValue
+
+	// Bucket boundaries in milliseconds of the latency, TTFT, prompt-eval time and RAG latency histograms
+	latencyBuckets []float64
 
 	// Counters
 	totalRequests      int64
 	successfulRequests int64
 }
 
+// MetricsCollectorOption configures the metrics collector
+type MetricsCollectorOption func(*MetricsCollector)
+
+// WithBaseAttributes adds the attributes to all the measurements of the collector, so the
+// Grafana panels can tell apart the runs, e.g. on the GPU and on the CPU
+func WithBaseAttributes(attrs ...attribute.KeyValue) MetricsCollectorOption {
+	return func(mc *MetricsCollector) {
+		mc.baseAttrs = append(mc.baseAttrs, attrs...)
+	}
+}
+
+// WithLatencyBuckets replaces the bucket boundaries, in milliseconds, of the latency, TTFT,
+// prompt-eval time and RAG latency histograms. The default ones top out at 30s, so the slow
+// models on the CPU, or the long code generations, end up in the overflow bucket.
+func WithLatencyBuckets(buckets ...float64) MetricsCollectorOption {
+	return func(mc *MetricsCollector) {
+		mc.latencyBuckets = slices.Clone(buckets)
+	}
+}
+
+// DefaultLatencyBuckets returns the bucket boundaries in milliseconds of the latency histograms:
+// 10ms, 50ms, 100ms, 250ms, 500ms, 1s, 2.5s, 5s, 10s, 30s
+func DefaultLatencyBuckets() []float64 {
+	return []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+}
+
+// ParseLatencyBuckets parses a comma-separated list of bucket boundaries in milliseconds,
+// e.g. "100,1000,10000,60000,120000", which must be positive and strictly increasing
+func ParseLatencyBuckets(s string) ([]float64, error) {
+	var buckets []float64
+	for _, field := range strings.Split(s, ",") {
+		bucket, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse bucket %q: %w", field, err)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := validateLatencyBuckets(buckets); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// getLatencyBuckets returns the bucket boundaries defined by the BENCH_LATENCY_BUCKETS environment
+// variable, or DefaultLatencyBuckets if it's not set
+func getLatencyBuckets() ([]float64, error) {
+	value := os.Getenv("BENCH_LATENCY_BUCKETS")
+	if value == "" {
+		return DefaultLatencyBuckets(), nil
+	}
+
+	buckets, err := ParseLatencyBuckets(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BENCH_LATENCY_BUCKETS %q: %w", value, err)
+	}
+
+	return buckets, nil
+}
+
+// validateLatencyBuckets checks the boundaries are positive and strictly increasing, as
+// OpenTelemetry silently drops the histograms with invalid boundaries
+func validateLatencyBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		return errors.New("no latency buckets")
+	}
+
+	for i, bucket := range buckets {
+		if math.IsNaN(bucket) || math.IsInf(bucket, 0) || bucket <= 0 {
+			return fmt.Errorf("latency bucket %v must be a positive number", bucket)
+		}
+		if i > 0 && bucket <= buckets[i-1] {
+			return fmt.Errorf("latency buckets must be strictly increasing, got %v after %v", bucket, buckets[i-1])
+		}
+	}
+
+	return nil
+}
+
 // NewMetricsCollector creates a new metrics collector
-func NewMetricsCollector() (*MetricsCollector, error) {
+func NewMetricsCollector(opts ...MetricsCollectorOption) (*MetricsCollector, error) {
 	meter := otel.Meter("llm-benchmark")
 
-	// Define histogram buckets for millisecond-scale latencies
-	// Buckets: 10ms, 50ms, 100ms, 250ms, 500ms, 1s, 2.5s, 5s, 10s, 30s
-	latencyBuckets := []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+	mc := &MetricsCollector{
+		meter:            meter,
+		latencyBuckets:   DefaultLatencyBuckets(),
+		aggregates:       make(map[string]*AggregateMetrics),
+		coldStarts:       make(map[string]float64),
+		modelMemory:      make(map[string]float64),
+		promptEvals:      make(map[promptCacheKey]*promptEvalSeries),
+		throughputSweeps: make(map[throughputKey]ThroughputSweep),
+	}
+	for _, opt := range opts {
+		opt(mc)
+	}
+
+	if err := validateLatencyBuckets(mc.latencyBuckets); err != nil {
+		return nil, err
+	}
+	latencyBuckets := mc.latencyBuckets
 
 	latencyHistogram, err := meter.Float64Histogram(
 		semconv.MetricLLMLatency,
@@ -111,15 +239,45 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 		return nil, fmt.Errorf("failed to create tool call latency histogram: %w", err)
 	}
 
-	mc := &MetricsCollector{
-		meter:                    meter,
-		latencyHistogram:         latencyHistogram,
-		ttftHistogram:            ttftHistogram,
-		promptEvalTimeHistogram:  promptEvalTimeHistogram,
-		toolCallLatencyHistogram: toolCallLatencyHistogram,
-		aggregates:               make(map[string]*AggregateMetrics),
+	// Inter-token latency histogram (streamed tokens are usually a few milliseconds apart)
+	interTokenBuckets := []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000}
+	interTokenHistogram, err := meter.Float64Histogram(
+		semconv.MetricLLMInterTokenLatency,
+		metric.WithDescription(semconv.DescLLMInterTokenLatency),
+		metric.WithExplicitBucketBoundaries(interTokenBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inter-token latency histogram: %w", err)
+	}
+
+	ragLatencyHistogram, err := meter.Float64Histogram(
+		semconv.MetricRAGLatency,
+		metric.WithDescription(semconv.DescRAGLatency),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rag latency histogram: %w", err)
+	}
+
+	// Completion tokens histogram (powers of two, from one-liners to long code generations)
+	completionTokensBuckets := []float64{16, 32, 64, 128, 256, 512, 1024, 2048, 4096}
+	completionTokensHistogram, err := meter.Float64Histogram(
+		semconv.MetricLLMCompletionTokens,
+		metric.WithDescription(semconv.DescLLMCompletionTokens),
+		metric.WithExplicitBucketBoundaries(completionTokensBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create completion tokens histogram: %w", err)
 	}
 
+	mc.latencyHistogram = latencyHistogram
+	mc.ttftHistogram = ttftHistogram
+	mc.promptEvalTimeHistogram = promptEvalTimeHistogram
+	mc.toolCallLatencyHistogram = toolCallLatencyHistogram
+	mc.interTokenHistogram = interTokenHistogram
+	mc.ragLatencyHistogram = ragLatencyHistogram
+	mc.completionTokensHistogram = completionTokensHistogram
+
 	// Register observable gauges with callbacks that emit metrics with labels
 	if _, err := meter.Float64ObservableGauge(
 		semconv.MetricLLMLatencyP50,
@@ -133,7 +291,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.LatencyP50, metric.WithAttributes(attrs...))
+				o.Observe(agg.LatencyP50, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -153,7 +311,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.LatencyP95, metric.WithAttributes(attrs...))
+				o.Observe(agg.LatencyP95, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -173,7 +331,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.TTFTP50, metric.WithAttributes(attrs...))
+				o.Observe(agg.TTFTP50, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -193,7 +351,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.TTFTP95, metric.WithAttributes(attrs...))
+				o.Observe(agg.TTFTP95, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -213,7 +371,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.PromptEvalTimeP50, metric.WithAttributes(attrs...))
+				o.Observe(agg.PromptEvalTimeP50, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -233,7 +391,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.PromptEvalTimeP95, metric.WithAttributes(attrs...))
+				o.Observe(agg.PromptEvalTimeP95, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -253,7 +411,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.SuccessRate, metric.WithAttributes(attrs...))
+				o.Observe(agg.SuccessRate, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -261,6 +419,26 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 		return nil, fmt.Errorf("failed to create success rate gauge: %w", err)
 	}
 
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMRefusalRate,
+		metric.WithDescription(semconv.DescLLMRefusalRate),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for _, agg := range mc.aggregates {
+				attrs := []attribute.KeyValue{
+					attribute.String(semconv.AttrModel, agg.Model),
+					attribute.String(semconv.AttrCase, agg.TestCase),
+					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
+				}
+				o.Observe(agg.RefusalRate, mc.withAttributes(attrs...))
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create refusal rate gauge: %w", err)
+	}
+
 	if _, err := meter.Float64ObservableGauge(
 		semconv.MetricLLMTokensPerOp,
 		metric.WithDescription(semconv.DescLLMTokensPerOp),
@@ -273,7 +451,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.TokensPerOp, metric.WithAttributes(attrs...))
+				o.Observe(agg.TokensPerOp, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -293,7 +471,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.EvalScore, metric.WithAttributes(attrs...))
+				o.Observe(agg.EvalScore, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -301,6 +479,32 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 		return nil, fmt.Errorf("failed to create eval score gauge: %w", err)
 	}
 
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMEvalCategoryRate,
+		metric.WithDescription(semconv.DescLLMEvalCategoryRate),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for _, agg := range mc.aggregates {
+				if agg.CategoryRates == nil {
+					continue
+				}
+				for _, category := range evaluator.Categories {
+					attrs := []attribute.KeyValue{
+						attribute.String(semconv.AttrModel, agg.Model),
+						attribute.String(semconv.AttrCase, agg.TestCase),
+						attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
+						attribute.String(semconv.AttrCategory, string(category)),
+					}
+					o.Observe(agg.CategoryRates[category], mc.withAttributes(attrs...))
+				}
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create eval category rate gauge: %w", err)
+	}
+
 	if _, err := meter.Float64ObservableGauge(
 		semconv.MetricLLMEvalPassRate,
 		metric.WithDescription(semconv.DescLLMEvalPassRate),
@@ -313,7 +517,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.EvalPassRate, metric.WithAttributes(attrs...))
+				o.Observe(agg.EvalPassRate, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -333,7 +537,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.TokensPerSec, metric.WithAttributes(attrs...))
+				o.Observe(agg.TokensPerSec, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -353,7 +557,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.OutputTokensPerSec, metric.WithAttributes(attrs...))
+				o.Observe(agg.OutputTokensPerSec, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -373,7 +577,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.NsPerOp, metric.WithAttributes(attrs...))
+				o.Observe(agg.NsPerOp, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -381,6 +585,118 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 		return nil, fmt.Errorf("failed to create ns per op gauge: %w", err)
 	}
 
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMColdStart,
+		metric.WithDescription(semconv.DescLLMColdStart),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for model, coldStartMs := range mc.coldStarts {
+				o.Observe(coldStartMs, mc.withAttributes(attribute.String(semconv.AttrModel, model)))
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create cold start gauge: %w", err)
+	}
+
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMModelMemory,
+		metric.WithDescription(semconv.DescLLMModelMemory),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for model, memoryMB := range mc.modelMemory {
+				o.Observe(memoryMB, mc.withAttributes(attribute.String(semconv.AttrModel, model)))
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create model memory gauge: %w", err)
+	}
+
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMPromptCacheSpeedup,
+		metric.WithDescription(semconv.DescLLMPromptCacheSpeedup),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for key, series := range mc.promptEvals {
+				speedup, ok := PromptCacheSpeedup(series.evalTimes)
+				if !ok {
+					continue
+				}
+				o.Observe(speedup, mc.withAttributes(
+					attribute.String(semconv.AttrModel, key.model),
+					attribute.String(semconv.AttrCase, series.testCase),
+				))
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create prompt cache speedup gauge: %w", err)
+	}
+
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMThroughputLatencyP95,
+		metric.WithDescription(semconv.DescLLMThroughputLatencyP95),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for key, sweep := range mc.throughputSweeps {
+				for _, level := range sweep.Levels {
+					o.Observe(float64(level.LatencyP95)/float64(time.Millisecond), mc.withAttributes(
+						attribute.String(semconv.AttrModel, key.model),
+						attribute.String(semconv.AttrCase, key.testCase),
+						attribute.Int(semconv.AttrConcurrency, level.Concurrency),
+					))
+				}
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create throughput latency gauge: %w", err)
+	}
+
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMThroughputRPS,
+		metric.WithDescription(semconv.DescLLMThroughputRPS),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for key, sweep := range mc.throughputSweeps {
+				for _, level := range sweep.Levels {
+					o.Observe(level.RPS, mc.withAttributes(
+						attribute.String(semconv.AttrModel, key.model),
+						attribute.String(semconv.AttrCase, key.testCase),
+						attribute.Int(semconv.AttrConcurrency, level.Concurrency),
+					))
+				}
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create throughput gauge: %w", err)
+	}
+
+	if _, err := meter.Float64ObservableGauge(
+		semconv.MetricLLMMaxSustainableRPS,
+		metric.WithDescription(semconv.DescLLMMaxSustainableRPS),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			mc.aggregatesMu.RLock()
+			defer mc.aggregatesMu.RUnlock()
+			for key, sweep := range mc.throughputSweeps {
+				o.Observe(sweep.MaxSustainableRPS, mc.withAttributes(
+					attribute.String(semconv.AttrModel, key.model),
+					attribute.String(semconv.AttrCase, key.testCase),
+				))
+			}
+			return nil
+		}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create max sustainable rps gauge: %w", err)
+	}
+
 	if _, err := meter.Float64ObservableGauge(
 		semconv.MetricGPUUtilization,
 		metric.WithDescription(semconv.DescGPUUtilization),
@@ -394,7 +710,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.GPUUtilization, metric.WithAttributes(attrs...))
+				o.Observe(agg.GPUUtilization, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -415,7 +731,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.GPUMemory, metric.WithAttributes(attrs...))
+				o.Observe(agg.GPUMemory, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -436,7 +752,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolCallCount, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolCallCount, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -456,7 +772,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolIterationCount, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolIterationCount, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -476,7 +792,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolSuccessRate, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolSuccessRate, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -496,7 +812,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolParamAccuracy, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolParamAccuracy, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -516,7 +832,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolSelectionAccuracy, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolSelectionAccuracy, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -536,7 +852,7 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 					attribute.String(semconv.AttrCase, agg.TestCase),
 					attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", agg.Temp)),
 				}
-				o.Observe(agg.ToolConvergence, metric.WithAttributes(attrs...))
+				o.Observe(agg.ToolConvergence, mc.withAttributes(attrs...))
 			}
 			return nil
 		}),
@@ -547,6 +863,28 @@ func NewMetricsCollector() (*MetricsCollector, error) {
 	return mc, nil
 }
 
+// withAttributes returns the measurement option with the attributes and the base attributes of the collector.
+// The model attribute is completed with the parameter count and quantization parsed from the model name,
+// when the name tells them.
+func (mc *MetricsCollector) withAttributes(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	all := make([]attribute.KeyValue, 0, len(attrs)+len(mc.baseAttrs)+2)
+	all = append(all, attrs...)
+	for _, attr := range attrs {
+		if attr.Key != semconv.AttrModel {
+			continue
+		}
+		params, quant := ParseModelName(attr.Value.AsString())
+		if params != "" {
+			all = append(all, attribute.String(semconv.AttrModelParams, params))
+		}
+		if quant != "" {
+			all = append(all, attribute.String(semconv.AttrModelQuant, quant))
+		}
+	}
+	all = append(all, mc.baseAttrs...)
+	return metric.WithAttributes(all...)
+}
+
 // RecordLatency records a latency measurement with exemplar support
 func (mc *MetricsCollector) RecordLatency(ctx context.Context, latency time.Duration, model, testCase string, temp float64) {
 	span := trace.SpanFromContext(ctx)
@@ -564,7 +902,7 @@ func (mc *MetricsCollector) RecordLatency(ctx context.Context, latency time.Dura
 		attribute.String(semconv.AttrSpanID, spanID),
 	}
 
-	mc.latencyHistogram.Record(ctx, latencyMs, metric.WithAttributes(attrs...))
+	mc.latencyHistogram.Record(ctx, latencyMs, mc.withAttributes(attrs...))
 	mc.totalRequests++
 }
 
@@ -585,7 +923,7 @@ func (mc *MetricsCollector) RecordTTFT(ctx context.Context, ttft time.Duration,
 		attribute.String(semconv.AttrSpanID, spanID),
 	}
 
-	mc.ttftHistogram.Record(ctx, ttftMs, metric.WithAttributes(attrs...))
+	mc.ttftHistogram.Record(ctx, ttftMs, mc.withAttributes(attrs...))
 }
 
 // RecordPromptEvalTime records a prompt evaluation time measurement with exemplar support
@@ -605,7 +943,146 @@ func (mc *MetricsCollector) RecordPromptEvalTime(ctx context.Context, promptEval
 		attribute.String(semconv.AttrSpanID, spanID),
 	}
 
-	mc.promptEvalTimeHistogram.Record(ctx, promptEvalTimeMs, metric.WithAttributes(attrs...))
+	mc.promptEvalTimeHistogram.Record(ctx, promptEvalTimeMs, mc.withAttributes(attrs...))
+}
+
+// RecordColdStart records the latency of the first request to a model, which includes
+// loading its weights into memory. Only the first measurement per model is kept.
+// It returns the recorded cold start latency in milliseconds.
+func (mc *MetricsCollector) RecordColdStart(model string, latency time.Duration) float64 {
+	mc.aggregatesMu.Lock()
+	defer mc.aggregatesMu.Unlock()
+
+	if coldStartMs, ok := mc.coldStarts[model]; ok {
+		return coldStartMs
+	}
+
+	coldStartMs := float64(latency.Milliseconds())
+	mc.coldStarts[model] = coldStartMs
+	return coldStartMs
+}
+
+// ColdStart returns the cold start latency in milliseconds recorded for a model
+func (mc *MetricsCollector) ColdStart(model string) (float64, bool) {
+	mc.aggregatesMu.RLock()
+	defer mc.aggregatesMu.RUnlock()
+
+	coldStartMs, ok := mc.coldStarts[model]
+	return coldStartMs, ok
+}
+
+// RecordModelMemory records the host memory in MB attributable to loading a model, sampled
+// around its first request
+func (mc *MetricsCollector) RecordModelMemory(model string, memoryMB float64) {
+	mc.aggregatesMu.Lock()
+	defer mc.aggregatesMu.Unlock()
+
+	mc.modelMemory[model] = memoryMB
+}
+
+// ModelMemory returns the host memory in MB attributable to loading the model, if it was sampled
+func (mc *MetricsCollector) ModelMemory(model string) (float64, bool) {
+	mc.aggregatesMu.RLock()
+	defer mc.aggregatesMu.RUnlock()
+
+	memoryMB, ok := mc.modelMemory[model]
+	return memoryMB, ok
+}
+
+// throughputKey identifies the capacity curve of a model for a test case
+type throughputKey struct {
+	model    string
+	testCase string
+}
+
+// RecordThroughputSweep records the capacity curve of a model for a test case, replacing the
+// previous one, see MeasureThroughput
+func (mc *MetricsCollector) RecordThroughputSweep(model, testCase string, sweep ThroughputSweep) {
+	mc.aggregatesMu.Lock()
+	defer mc.aggregatesMu.Unlock()
+
+	mc.throughputSweeps[throughputKey{model: model, testCase: testCase}] = sweep
+}
+
+// promptCacheKey identifies the requests sharing a prompt prefix, cached by the inference engine
+type promptCacheKey struct {
+	model        string
+	systemPrompt string
+}
+
+// promptEvalSeries contains the prompt-eval times of the requests sharing a prompt prefix
+type promptEvalSeries struct {
+	testCase  string // test case of the first request, to label the speedup
+	evalTimes []time.Duration
+}
+
+// RecordPromptEval records the prompt-eval time of a request to a model, to measure the speedup of
+// the requests sharing its system prompt once it's in the KV cache, see PromptCacheSpeedup
+func (mc *MetricsCollector) RecordPromptEval(model, testCase, systemPrompt string, evalTime time.Duration) {
+	mc.aggregatesMu.Lock()
+	defer mc.aggregatesMu.Unlock()
+
+	key := promptCacheKey{model: model, systemPrompt: systemPrompt}
+	series, ok := mc.promptEvals[key]
+	if !ok {
+		series = &promptEvalSeries{testCase: testCase}
+		mc.promptEvals[key] = series
+	}
+	series.evalTimes = append(series.evalTimes, evalTime)
+}
+
+// PromptCacheSpeedup returns the prompt cache speedup of the requests to a model sharing the system
+// prompt, or false if fewer than two were recorded
+func (mc *MetricsCollector) PromptCacheSpeedup(model, systemPrompt string) (float64, bool) {
+	mc.aggregatesMu.RLock()
+	defer mc.aggregatesMu.RUnlock()
+
+	series, ok := mc.promptEvals[promptCacheKey{model: model, systemPrompt: systemPrompt}]
+	if !ok {
+		return 0, false
+	}
+
+	return PromptCacheSpeedup(series.evalTimes)
+}
+
+// RecordInterTokenLatency records the p50 and p95 inter-token latencies of a single request,
+// distinguished by the stat attribute, with exemplar support
+func (mc *MetricsCollector) RecordInterTokenLatency(ctx context.Context, p50, p95 time.Duration, model, testCase string, temp float64) {
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+
+	for stat, value := range map[string]time.Duration{"p50": p50, "p95": p95} {
+		attrs := []attribute.KeyValue{
+			attribute.String(semconv.AttrModel, model),
+			attribute.String(semconv.AttrCase, testCase),
+			attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", temp)),
+			attribute.String(semconv.AttrStat, stat),
+			attribute.String(semconv.AttrTraceID, traceID),
+			attribute.String(semconv.AttrSpanID, spanID),
+		}
+
+		// Record in fractional milliseconds, as tokens can be streamed less than 1ms apart
+		mc.interTokenHistogram.Record(ctx, float64(value)/float64(time.Millisecond), mc.withAttributes(attrs...))
+	}
+}
+
+// RecordCompletionTokens records the output tokens of a request, to show the variance of the output
+// lengths that the average tokens per operation hides, e.g. at high temperatures
+func (mc *MetricsCollector) RecordCompletionTokens(ctx context.Context, completionTokens int, model, testCase string, temp float64) {
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+
+	attrs := []attribute.KeyValue{
+		attribute.String(semconv.AttrModel, model),
+		attribute.String(semconv.AttrCase, testCase),
+		attribute.String(semconv.AttrTemp, fmt.Sprintf("%.1f", temp)),
+		attribute.String(semconv.AttrTraceID, traceID),
+		attribute.String(semconv.AttrSpanID, spanID),
+	}
+
+	mc.completionTokensHistogram.Record(ctx, float64(completionTokens), mc.withAttributes(attrs...))
 }
 
 // RecordToolCallLatency records a tool call latency measurement with exemplar support
@@ -626,25 +1103,47 @@ func (mc *MetricsCollector) RecordToolCallLatency(ctx context.Context, latency t
 		attribute.String(semconv.AttrSpanID, spanID),
 	}
 
-	mc.toolCallLatencyHistogram.Record(ctx, latencyMs, metric.WithAttributes(attrs...))
+	mc.toolCallLatencyHistogram.Record(ctx, latencyMs, mc.withAttributes(attrs...))
 }
 
-// UpdateAggregates updates the aggregate metrics (percentiles, success rate, etc.) for a specific model/case/temp combination
-func (mc *MetricsCollector) UpdateAggregates(model, testCase string, temp, p50, p95, ttftP50, ttftP95, promptEvalP50, promptEvalP95, successRate, tokensPerOp, evalScore, evalPassRate, tokensPerSec, outputTokensPerSec, nsPerOp float64) {
-	key := fmt.Sprintf("%s|%s|%.1f", model, testCase, temp)
+// RecordRAGLatency records the end-to-end latency of a RAG query with exemplar support
+func (mc *MetricsCollector) RecordRAGLatency(ctx context.Context, latency time.Duration, model, store string) {
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+
+	attrs := []attribute.KeyValue{
+		attribute.String(semconv.AttrModel, model),
+		attribute.String(semconv.AttrStore, store),
+		attribute.String(semconv.AttrTraceID, traceID),
+		attribute.String(semconv.AttrSpanID, spanID),
+	}
+
+	mc.ragLatencyHistogram.Record(ctx, float64(latency.Milliseconds()), mc.withAttributes(attrs...))
+}
+
+// SetAggregates stores the aggregate metrics of a model/case/temp combination, exported by the
+// gauges. The GPU metrics sampled during the benchmark are preserved.
+func (mc *MetricsCollector) SetAggregates(agg AggregateMetrics) {
+	key := fmt.Sprintf("%s|%s|%.1f", agg.Model, agg.TestCase, agg.Temp)
 
 	mc.aggregatesMu.Lock()
 	defer mc.aggregatesMu.Unlock()
 
-	// Preserve existing GPU metrics if they exist
-	existingGPUUtil := 0.0
-	existingGPUMem := 0.0
+	// Preserve GPU metrics from previous sampling
+	agg.GPUUtilization = 0
+	agg.GPUMemory = 0
 	if existing, ok := mc.aggregates[key]; ok {
-		existingGPUUtil = existing.GPUUtilization
-		existingGPUMem = existing.GPUMemory
+		agg.GPUUtilization = existing.GPUUtilization
+		agg.GPUMemory = existing.GPUMemory
 	}
 
-	mc.aggregates[key] = &AggregateMetrics{
+	mc.aggregates[key] = &agg
+}
+
+// UpdateAggregates updates the aggregate metrics (percentiles, success rate, etc.) for a specific model/case/temp combination
+func (mc *MetricsCollector) UpdateAggregates(model, testCase string, temp, p50, p95, ttftP50, ttftP95, promptEvalP50, promptEvalP95, successRate, tokensPerOp, evalScore, evalPassRate, tokensPerSec, outputTokensPerSec, nsPerOp float64) {
+	mc.SetAggregates(AggregateMetrics{
 		Model:              model,
 		TestCase:           testCase,
 		Temp:               temp,
@@ -661,60 +1160,7 @@ func (mc *MetricsCollector) UpdateAggregates(model, testCase string, temp, p50,
 		TokensPerSec:       tokensPerSec,
 		OutputTokensPerSec: outputTokensPerSec,
 		NsPerOp:            nsPerOp,
-		// Tool metrics (will be set to 0 for non-tool cases)
-		ToolCallCount:         0,
-		ToolIterationCount:    0,
-		ToolSuccessRate:       0,
-		ToolParamAccuracy:     0,
-		ToolSelectionAccuracy: 0,
-		// Preserve GPU metrics from previous sampling
-		GPUUtilization: existingGPUUtil,
-		GPUMemory:      existingGPUMem,
-	}
-}
-
-// UpdateAggregatesWithToolMetrics updates the aggregate metrics including tool-specific metrics
-func (mc *MetricsCollector) UpdateAggregatesWithToolMetrics(model, testCase string, temp, p50, p95, ttftP50, ttftP95, promptEvalP50, promptEvalP95, successRate, tokensPerOp, evalScore, evalPassRate, tokensPerSec, outputTokensPerSec, nsPerOp, toolCallCount, toolIterationCount, toolSuccessRate, toolParamAccuracy, toolSelectionAccuracy, toolConvergence float64) {
-	key := fmt.Sprintf("%s|%s|%.1f", model, testCase, temp)
-
-	mc.aggregatesMu.Lock()
-	defer mc.aggregatesMu.Unlock()
-
-	// Preserve existing GPU metrics if they exist
-	existingGPUUtil := 0.0
-	existingGPUMem := 0.0
-	if existing, ok := mc.aggregates[key]; ok {
-		existingGPUUtil = existing.GPUUtilization
-		existingGPUMem = existing.GPUMemory
-	}
-
-	mc.aggregates[key] = &AggregateMetrics{
-		Model:                 model,
-		TestCase:              testCase,
-		Temp:                  temp,
-		LatencyP50:            p50,
-		LatencyP95:            p95,
-		TTFTP50:               ttftP50,
-		TTFTP95:               ttftP95,
-		PromptEvalTimeP50:     promptEvalP50,
-		PromptEvalTimeP95:     promptEvalP95,
-		SuccessRate:           successRate,
-		TokensPerOp:           tokensPerOp,
-		EvalScore:             evalScore,
-		EvalPassRate:          evalPassRate,
-		TokensPerSec:          tokensPerSec,
-		OutputTokensPerSec:    outputTokensPerSec,
-		NsPerOp:               nsPerOp,
-		ToolCallCount:         toolCallCount,
-		ToolIterationCount:    toolIterationCount,
-		ToolSuccessRate:       toolSuccessRate,
-		ToolParamAccuracy:     toolParamAccuracy,
-		ToolSelectionAccuracy: toolSelectionAccuracy,
-		ToolConvergence:       toolConvergence,
-		// Preserve GPU metrics from previous sampling
-		GPUUtilization: existingGPUUtil,
-		GPUMemory:      existingGPUMem,
-	}
+	})
 }
 
 // UpdateGPUMetrics updates GPU utilization and memory metrics for a specific model/case/temp
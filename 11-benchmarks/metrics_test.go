@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRecordColdStart(t *testing.T) {
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	if _, ok := mc.ColdStart("ai/llama3.2:1B-Q4_0"); ok {
+		t.Fatal("expected no cold start before the first request")
+	}
+
+	// The first request loads the model, the following ones are warm
+	if got := mc.RecordColdStart("ai/llama3.2:1B-Q4_0", 4500*time.Millisecond); got != 4500 {
+		t.Errorf("expected cold start of 4500ms, got %.0f", got)
+	}
+	mc.RecordColdStart("ai/llama3.2:1B-Q4_0", 300*time.Millisecond)
+
+	coldStartMs, ok := mc.ColdStart("ai/llama3.2:1B-Q4_0")
+	if !ok {
+		t.Fatal("expected cold start to be recorded")
+	}
+	if coldStartMs != 4500 {
+		t.Errorf("expected the first latency (4500ms) under the cold start metric, got %.0f", coldStartMs)
+	}
+
+	if _, ok := mc.ColdStart("ai/qwen3:0.6B-Q4_0"); ok {
+		t.Error("expected cold starts to be tracked per model")
+	}
+}
+
+func TestComputeAggregates(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		if agg := computeAggregates(nil); !reflect.DeepEqual(agg, AggregateMetrics{}) {
+			t.Errorf("expected zero aggregates, got %+v", agg)
+		}
+	})
+
+	t.Run("all-failures", func(t *testing.T) {
+		agg := computeAggregates([]BenchmarkResult{{Success: false}, {Success: false, Latency: time.Second}})
+
+		if !reflect.DeepEqual(agg, AggregateMetrics{}) {
+			t.Errorf("expected zero aggregates with a zero success rate, got %+v", agg)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		results := []BenchmarkResult{
+			{
+				Success: true, Latency: 1000 * time.Millisecond, TTFT: 200 * time.Millisecond, PromptEvalTime: 100 * time.Millisecond,
+				InterTokenGaps: slices.Repeat([]time.Duration{10 * time.Millisecond}, 10),
+				PromptTokens:   50, CompletionTokens: 150, EvalResponse: "yes", EvalScore: 1, EvalCategory: evaluator.CategoryCorrect, TokenEfficiency: 1,
+			},
+			{
+				Success: true, Latency: 3000 * time.Millisecond, TTFT: 1000 * time.Millisecond, PromptEvalTime: 300 * time.Millisecond,
+				InterTokenGaps: append(slices.Repeat([]time.Duration{10 * time.Millisecond}, 8), 50*time.Millisecond, 50*time.Millisecond),
+				PromptTokens:   50, CompletionTokens: 250, EvalResponse: "no", EvalScore: 0.5, EvalCategory: evaluator.CategoryRefusal, TokenEfficiency: 0.5, Refusal: true,
+			},
+			// Failures only count in the success rate
+			{Success: false, Latency: 9000 * time.Millisecond, PromptTokens: 500},
+			{Success: false},
+		}
+
+		agg := computeAggregates(results)
+
+		expected := AggregateMetrics{
+			LatencyP50:         2000,
+			LatencyP95:         3000, // the max, too few samples for the p95
+			TTFTP50:            600,
+			TTFTP95:            1000,
+			PromptEvalTimeP50:  200,
+			PromptEvalTimeP95:  300,
+			InterTokenP50:      10,
+			InterTokenP95:      50, // of the 20 gaps pooled across the requests
+			LatencySamples:     2,
+			SuccessRate:        0.5,
+			RefusalRate:        0.5, // of the successful results
+			TokensPerOp:        250,
+			EvalScore:          0.75,
+			EvalPassRate:       0.5,
+			CategoryRates:      map[evaluator.Category]float64{evaluator.CategoryCorrect: 0.5, evaluator.CategoryRefusal: 0.5},
+			TokensPerSec:       125,       // 250 tokens in 2s on average
+			OutputTokensPerSec: 200 / 1.4, // 200 tokens in (800ms + 2000ms) / 2 of generation
+			TokenEfficiency:    0.75,
+		}
+		if math.Abs(agg.OutputTokensPerSec-expected.OutputTokensPerSec) > 1e-9 {
+			t.Errorf("expected %.1f output tokens/s, got %.1f", expected.OutputTokensPerSec, agg.OutputTokensPerSec)
+		}
+		agg.OutputTokensPerSec = expected.OutputTokensPerSec
+		if !reflect.DeepEqual(agg, expected) {
+			t.Errorf("expected:\n%+v\ngot:\n%+v", expected, agg)
+		}
+	})
+
+	t.Run("tools", func(t *testing.T) {
+		results := []BenchmarkResult{
+			{Success: true, Latency: time.Second, ToolCallCount: 2, ToolIterationCount: 2, ToolParamAccuracy: 1, ToolSelectionAccuracy: 1},
+			{Success: true, Latency: time.Second, ToolCallCount: 4, ToolIterationCount: 3, ToolParamAccuracy: 0.5, ToolSelectionAccuracy: 1},
+			{Success: true, Latency: time.Second},
+			{Success: false},
+		}
+
+		agg := computeAggregates(results)
+
+		if agg.ToolCallCount != 3 || agg.ToolIterationCount != 2.5 || agg.ToolParamAccuracy != 0.75 || agg.ToolSelectionAccuracy != 1 {
+			t.Errorf("unexpected tool averages: %+v", agg)
+		}
+		if math.Abs(agg.ToolSuccessRate-2.0/3) > 1e-9 {
+			t.Errorf("expected a tool success rate of 2/3, got %.3f", agg.ToolSuccessRate)
+		}
+		// The optimal path takes 2 calls: (1 + 2/4) / 2
+		if agg.ToolConvergence != 0.75 {
+			t.Errorf("expected a convergence of 0.75, got %.3f", agg.ToolConvergence)
+		}
+	})
+}
+
+func TestCategoryRates(t *testing.T) {
+	results := []BenchmarkResult{
+		{Success: true, EvalResponse: "yes", EvalCategory: evaluator.CategoryCorrect},
+		{Success: true, EvalResponse: "no", EvalCategory: evaluator.CategoryHallucination},
+		{Success: true, EvalResponse: "no", EvalCategory: evaluator.CategoryHallucination},
+		// evaluated, but not classified by the judge
+		{Success: true, EvalResponse: "no"},
+		// not evaluated, or failed: ignored
+		{Success: true},
+		{Success: false, EvalResponse: "no", EvalCategory: evaluator.CategoryOffTopic},
+	}
+
+	expected := map[evaluator.Category]float64{
+		evaluator.CategoryCorrect:       0.25,
+		evaluator.CategoryHallucination: 0.5,
+	}
+	if rates := categoryRates(results); !reflect.DeepEqual(rates, expected) {
+		t.Errorf("expected the rates %v, got %v", expected, rates)
+	}
+
+	if rates := categoryRates(nil); rates != nil {
+		t.Errorf("expected no rates without evaluations, got %v", rates)
+	}
+
+	if unit := categoryRateUnit(evaluator.CategoryOffTopic); unit != "eval_off_topic_rate" {
+		t.Errorf("expected the unit eval_off_topic_rate, got %s", unit)
+	}
+}
+
+func TestEvalStats(t *testing.T) {
+	results := []BenchmarkResult{
+		{Success: true, EvalResponse: "yes", EvalScore: 1.0},
+		{Success: true, EvalResponse: "no", EvalScore: 0.6},
+		{Success: true, EvalResponse: " Yes ", EvalScore: 0.9},
+		{Success: true, EvalResponse: "unsure", EvalScore: 0.5},
+		// not evaluated, or failed: ignored
+		{Success: true},
+		{Success: false, EvalResponse: "yes", EvalScore: 1.0},
+	}
+
+	avgScore, passRate := evalStats(results)
+
+	if passRate != 0.5 {
+		t.Errorf("expected a pass rate of 0.5, got %.2f", passRate)
+	}
+	if want := (1.0 + 0.6 + 0.9 + 0.5) / 4; math.Abs(avgScore-want) > 1e-9 {
+		t.Errorf("expected an average score of %.3f, got %.3f", want, avgScore)
+	}
+
+	// a high average score does not mean a high pass rate
+	avgScore, passRate = evalStats([]BenchmarkResult{
+		{Success: true, EvalResponse: "no", EvalScore: 0.8},
+		{Success: true, EvalResponse: "no", EvalScore: 0.8},
+	})
+	if avgScore != 0.8 || passRate != 0 {
+		t.Errorf("expected an average score of 0.8 and no passes, got %.2f and %.2f", avgScore, passRate)
+	}
+
+	if avgScore, passRate := evalStats(nil); avgScore != 0 || passRate != 0 {
+		t.Errorf("expected zeros without evaluations, got %.2f and %.2f", avgScore, passRate)
+	}
+}
+
+func TestRecordCompletionTokens(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	ctx := context.Background()
+	for _, tokens := range []int{10, 40, 50, 300, 5000} {
+		mc.RecordCompletionTokens(ctx, tokens, "ai/llama3.2:1B-Q4_0", "code-generation", 0.9)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	var histogram *metricdata.Histogram[float64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == semconv.MetricLLMCompletionTokens {
+				h, ok := m.Data.(metricdata.Histogram[float64])
+				if !ok {
+					t.Fatalf("expected a float64 histogram, got %T", m.Data)
+				}
+				histogram = &h
+			}
+		}
+	}
+	if histogram == nil {
+		t.Fatalf("metric %s not collected", semconv.MetricLLMCompletionTokens)
+	}
+	if len(histogram.DataPoints) != 1 {
+		t.Fatalf("expected 1 data point, got %d", len(histogram.DataPoints))
+	}
+
+	dp := histogram.DataPoints[0]
+	if dp.Count != 5 || dp.Sum != 5400 {
+		t.Errorf("expected 5 records summing 5400 tokens, got %d summing %.0f", dp.Count, dp.Sum)
+	}
+
+	// Buckets: (-inf,16], (16,32], (32,64], (64,128], (128,256], (256,512], (512,1024], (1024,2048], (2048,4096], (4096,+inf)
+	expectedBounds := []float64{16, 32, 64, 128, 256, 512, 1024, 2048, 4096}
+	expectedCounts := []uint64{1, 0, 2, 0, 0, 1, 0, 0, 0, 1}
+	if !slices.Equal(dp.Bounds, expectedBounds) {
+		t.Errorf("expected bounds %v, got %v", expectedBounds, dp.Bounds)
+	}
+	if !slices.Equal(dp.BucketCounts, expectedCounts) {
+		t.Errorf("expected bucket counts %v, got %v", expectedCounts, dp.BucketCounts)
+	}
+
+	// The model attributes parsed from the name label the measurements
+	for key, expected := range map[string]string{semconv.AttrModelParams: "1B", semconv.AttrModelQuant: "Q4_0"} {
+		if v, ok := dp.Attributes.Value(attribute.Key(key)); !ok || v.AsString() != expected {
+			t.Errorf("expected attribute %s=%s, got %q", key, expected, v.AsString())
+		}
+	}
+}
+
+func TestWithLatencyBuckets(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	buckets := []float64{1000, 5000, 15000, 30000, 60000, 120000}
+	mc, err := NewMetricsCollector(WithLatencyBuckets(buckets...))
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	ctx := context.Background()
+	mc.RecordLatency(ctx, 90*time.Second, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+	mc.RecordTTFT(ctx, 20*time.Second, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+	mc.RecordPromptEvalTime(ctx, 2*time.Second, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+	mc.RecordRAGLatency(ctx, 45*time.Second, "ai/llama3.2:1B-Q4_0", "memory")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	bounds := make(map[string][]float64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if h, ok := m.Data.(metricdata.Histogram[float64]); ok && len(h.DataPoints) > 0 {
+				bounds[m.Name] = h.DataPoints[0].Bounds
+			}
+		}
+	}
+
+	for _, name := range []string{semconv.MetricLLMLatency, semconv.MetricLLMTTFT, semconv.MetricLLMPromptEvalTime, semconv.MetricRAGLatency} {
+		got, ok := bounds[name]
+		if !ok {
+			t.Errorf("metric %s not collected", name)
+			continue
+		}
+		if !slices.Equal(got, buckets) {
+			t.Errorf("expected %s bounds %v, got %v", name, buckets, got)
+		}
+	}
+}
+
+func TestParseLatencyBuckets(t *testing.T) {
+	buckets, err := ParseLatencyBuckets("100, 1000,60000")
+	if err != nil {
+		t.Fatalf("parse latency buckets: %s", err)
+	}
+	if expected := []float64{100, 1000, 60000}; !slices.Equal(buckets, expected) {
+		t.Errorf("expected %v, got %v", expected, buckets)
+	}
+
+	for _, invalid := range []string{"", "100,abc", "1000,100", "100,100", "0,100", "-5,100"} {
+		if _, err := ParseLatencyBuckets(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+
+	if _, err := NewMetricsCollector(WithLatencyBuckets(500, 100)); err == nil {
+		t.Error("expected an error creating the collector with decreasing buckets")
+	}
+}
+
+func TestRecordThroughputSweep(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	mc.RecordThroughputSweep("ai/llama3.2:1B-Q4_0", "code-generation", ThroughputSweep{
+		Levels: []ThroughputLevel{
+			{Concurrency: 1, Requests: 2, LatencyP95: 500 * time.Millisecond, RPS: 2},
+			{Concurrency: 2, Requests: 4, LatencyP95: 600 * time.Millisecond, RPS: 3.5},
+			{Concurrency: 4, Requests: 8, LatencyP95: 2 * time.Second, RPS: 3.8},
+		},
+		MaxSustainableRPS:         3.5,
+		MaxSustainableConcurrency: 2,
+	})
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	gauges := make(map[string][]metricdata.DataPoint[float64])
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if g, ok := m.Data.(metricdata.Gauge[float64]); ok {
+				gauges[m.Name] = g.DataPoints
+			}
+		}
+	}
+
+	if dps := gauges[semconv.MetricLLMMaxSustainableRPS]; len(dps) != 1 || dps[0].Value != 3.5 {
+		t.Errorf("expected the max sustainable rps of 3.5, got %+v", dps)
+	}
+
+	// The capacity curve: the p95 latency in milliseconds per concurrency
+	latencies := make(map[int64]float64)
+	for _, dp := range gauges[semconv.MetricLLMThroughputLatencyP95] {
+		concurrency, _ := dp.Attributes.Value(attribute.Key(semconv.AttrConcurrency))
+		latencies[concurrency.AsInt64()] = dp.Value
+	}
+	expected := map[int64]float64{1: 500, 2: 600, 4: 2000}
+	if len(latencies) != len(expected) {
+		t.Fatalf("expected the latency of %d levels, got %v", len(expected), latencies)
+	}
+	for concurrency, latency := range expected {
+		if latencies[concurrency] != latency {
+			t.Errorf("expected the p95 latency of %.0fms at concurrency %d, got %.0f", latency, concurrency, latencies[concurrency])
+		}
+	}
+
+	if dps := gauges[semconv.MetricLLMThroughputRPS]; len(dps) != 3 {
+		t.Errorf("expected the throughput of 3 levels, got %d", len(dps))
+	}
+}
+
+func TestEvalCategoryRateGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	mc.SetAggregates(AggregateMetrics{
+		Model: "ai/llama3.2:1B-Q4_0", TestCase: "factual-question", Temp: 0.1,
+		CategoryRates: map[evaluator.Category]float64{evaluator.CategoryCorrect: 0.75, evaluator.CategoryHallucination: 0.25},
+	})
+	// Not evaluated: no rates
+	mc.SetAggregates(AggregateMetrics{Model: "ai/llama3.2:1B-Q4_0", TestCase: "code-generation", Temp: 0.1})
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %s", err)
+	}
+
+	rates := make(map[string]float64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != semconv.MetricLLMEvalCategoryRate {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Gauge[float64]).DataPoints {
+				testCase, _ := dp.Attributes.Value(attribute.Key(semconv.AttrCase))
+				if testCase.AsString() != "factual-question" {
+					t.Errorf("expected no rates of the case %s", testCase.AsString())
+				}
+				category, _ := dp.Attributes.Value(attribute.Key(semconv.AttrCategory))
+				rates[category.AsString()] = dp.Value
+			}
+		}
+	}
+
+	// Every category is observed, so the panels show the zero rates too
+	if len(rates) != len(evaluator.Categories) {
+		t.Fatalf("expected the rates of %d categories, got %v", len(evaluator.Categories), rates)
+	}
+	if rates["correct"] != 0.75 || rates["hallucination"] != 0.25 || rates["off-topic"] != 0 {
+		t.Errorf("unexpected category rates: %v", rates)
+	}
+}
@@ -0,0 +1,141 @@
+// Package modelname parses the references of the models pulled into Docker Model Runner, e.g.
+// ai/llama3.2:1B-Q4_0 or hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M, into their registry,
+// namespace, name and tag, so they are parsed the same way across the benchmarks.
+package modelname
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTag is the tag of the models referenced without one
+const DefaultTag = "latest"
+
+// huggingFaceRegistries are the hosts of the Hugging Face registry, whose models are stored
+// lowercased by Docker Model Runner
+var huggingFaceRegistries = []string{"hf.co", "huggingface.co"}
+
+// ErrInvalid is returned, wrapped, when a model reference can't be parsed
+var ErrInvalid = errors.New("invalid model name")
+
+var (
+	// registryPattern matches a registry host, with an optional port, e.g. hf.co or localhost:5000
+	registryPattern = regexp.MustCompile(`^[A-Za-z0-9]+([.-][A-Za-z0-9]+)*(:[0-9]+)?$`)
+	// componentPattern matches a namespace or a name, e.g. ai, llama3.2 or Llama-3.2-1B-Instruct-GGUF.
+	// Unlike the image references, the uppercase letters of the Hugging Face names are allowed.
+	componentPattern = regexp.MustCompile(`^[A-Za-z0-9]+([._-]+[A-Za-z0-9]+)*$`)
+	// tagPattern matches a tag, e.g. 1B-Q4_0 or latest, with the limits of the image tags
+	tagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+)
+
+// ModelName is a parsed model reference: [registry/][namespace/]name[:tag]
+type ModelName struct {
+	// Registry is the host of the registry, e.g. hf.co, empty for Docker Hub
+	Registry string
+	// Namespace is the organization or the user publishing the model, e.g. ai or bartowski, with
+	// slashes when it has several components. It's empty for the bare names, e.g. gpt-5.1.
+	Namespace string
+	// Name is the name of the model, e.g. llama3.2
+	Name string
+	// Tag is the variant of the model, e.g. 1B-Q4_0, empty when the reference has none
+	Tag string
+}
+
+// Parse parses a model reference, e.g. ai/llama3.2:1B-Q4_0, hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF,
+// localhost:5000/ai/smollm2 or gpt-5.1. The first component is the registry when it has a dot or a
+// port, or it's localhost, and it's followed by other components.
+func Parse(fq string) (ModelName, error) {
+	if fq == "" {
+		return ModelName{}, fmt.Errorf("%w: empty reference", ErrInvalid)
+	}
+
+	var m ModelName
+	path := fq
+	// The tag follows the last colon, unless it belongs to the registry host, e.g. localhost:5000/ai/model
+	if i := strings.LastIndex(fq, ":"); i >= 0 && !strings.Contains(fq[i+1:], "/") {
+		path, m.Tag = fq[:i], fq[i+1:]
+		if !tagPattern.MatchString(m.Tag) {
+			return ModelName{}, fmt.Errorf("%w %q: invalid tag %q", ErrInvalid, fq, m.Tag)
+		}
+	}
+
+	components := strings.Split(path, "/")
+	if len(components) > 1 && isRegistry(components[0]) {
+		m.Registry, components = components[0], components[1:]
+		if !registryPattern.MatchString(m.Registry) {
+			return ModelName{}, fmt.Errorf("%w %q: invalid registry %q", ErrInvalid, fq, m.Registry)
+		}
+	}
+
+	for _, component := range components {
+		if !componentPattern.MatchString(component) {
+			return ModelName{}, fmt.Errorf("%w %q: invalid path component %q", ErrInvalid, fq, component)
+		}
+	}
+
+	m.Name = components[len(components)-1]
+	m.Namespace = strings.Join(components[:len(components)-1], "/")
+
+	return m, nil
+}
+
+// isRegistry tells whether the first component of a reference is a registry host
+func isRegistry(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+// String returns the reference of the model, the same one it was parsed from
+func (m ModelName) String() string {
+	var b strings.Builder
+	for _, part := range []string{m.Registry, m.Namespace} {
+		if part != "" {
+			b.WriteString(part)
+			b.WriteString("/")
+		}
+	}
+	b.WriteString(m.Name)
+	if m.Tag != "" {
+		b.WriteString(":")
+		b.WriteString(m.Tag)
+	}
+
+	return b.String()
+}
+
+// IsHuggingFace tells whether the model is pulled from the Hugging Face registry
+func (m ModelName) IsHuggingFace() bool {
+	for _, registry := range huggingFaceRegistries {
+		if strings.EqualFold(m.Registry, registry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Normalize returns the model as Docker Model Runner references it: the Hugging Face models are
+// lowercased, as their registry requires, while the other ones are kept as they are, e.g. the
+// uppercase quantization of ai/llama3.2:1B-Q4_0.
+func (m ModelName) Normalize() ModelName {
+	if !m.IsHuggingFace() {
+		return m
+	}
+
+	return ModelName{
+		Registry:  strings.ToLower(m.Registry),
+		Namespace: strings.ToLower(m.Namespace),
+		Name:      strings.ToLower(m.Name),
+		Tag:       strings.ToLower(m.Tag),
+	}
+}
+
+// WithDefaultTag returns the model with the "latest" tag if it has none, as it's pulled
+func (m ModelName) WithDefaultTag() ModelName {
+	if m.Tag == "" {
+		m.Tag = DefaultTag
+	}
+
+	return m
+}
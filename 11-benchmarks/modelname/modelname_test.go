@@ -0,0 +1,136 @@
+package modelname
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fq       string
+		expected ModelName
+	}{
+		{fq: "ai/llama3.2:1B-Q4_0", expected: ModelName{Namespace: "ai", Name: "llama3.2", Tag: "1B-Q4_0"}},
+		{fq: "ai/llama3.2:3B-Q4_K_M", expected: ModelName{Namespace: "ai", Name: "llama3.2", Tag: "3B-Q4_K_M"}},
+		{fq: "ai/qwen3:0.6B-Q4_0", expected: ModelName{Namespace: "ai", Name: "qwen3", Tag: "0.6B-Q4_0"}},
+		{fq: "ai/mxbai-embed-large:335M-F16", expected: ModelName{Namespace: "ai", Name: "mxbai-embed-large", Tag: "335M-F16"}},
+		{fq: "ai/gemma3:latest", expected: ModelName{Namespace: "ai", Name: "gemma3", Tag: "latest"}},
+		{fq: "ai/smollm2", expected: ModelName{Namespace: "ai", Name: "smollm2"}},
+		{
+			fq:       "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M",
+			expected: ModelName{Registry: "hf.co", Namespace: "bartowski", Name: "Llama-3.2-1B-Instruct-GGUF", Tag: "Q4_K_M"},
+		},
+		{
+			fq:       "hf.co/bartowski/llama-3.2-1b-instruct-gguf:q4_k_m",
+			expected: ModelName{Registry: "hf.co", Namespace: "bartowski", Name: "llama-3.2-1b-instruct-gguf", Tag: "q4_k_m"},
+		},
+		{
+			fq:       "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF",
+			expected: ModelName{Registry: "hf.co", Namespace: "bartowski", Name: "Llama-3.2-1B-Instruct-GGUF"},
+		},
+		{
+			fq:       "localhost:5000/ai/smollm2:360M-Q4_K_M",
+			expected: ModelName{Registry: "localhost:5000", Namespace: "ai", Name: "smollm2", Tag: "360M-Q4_K_M"},
+		},
+		{fq: "localhost/ai/smollm2", expected: ModelName{Registry: "localhost", Namespace: "ai", Name: "smollm2"}},
+		{fq: "gpt-5.1", expected: ModelName{Name: "gpt-5.1"}},
+		{fq: "o3-mini:2025-01-31", expected: ModelName{Name: "o3-mini", Tag: "2025-01-31"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fq, func(t *testing.T) {
+			m, err := Parse(tt.fq)
+			if err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+			if m != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, m)
+			}
+			if got := m.String(); got != tt.fq {
+				t.Errorf("expected %q to round-trip, got %q", tt.fq, got)
+			}
+		})
+	}
+}
+
+func TestParse_malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		fq   string
+	}{
+		{name: "empty", fq: ""},
+		{name: "blank", fq: " "},
+		{name: "surrounding-spaces", fq: " ai/llama3.2:1B-Q4_0 "},
+		{name: "empty-tag", fq: "ai/llama3.2:"},
+		{name: "empty-name", fq: "ai/"},
+		{name: "empty-namespace", fq: "/llama3.2"},
+		{name: "empty-component", fq: "hf.co//Llama-3.2-1B-Instruct-GGUF"},
+		{name: "only-tag", fq: ":1B-Q4_0"},
+		{name: "only-registry", fq: "hf.co/"},
+		{name: "invalid-tag", fq: "ai/llama3.2:1B Q4_0"},
+		{name: "tag-with-leading-dot", fq: "ai/llama3.2:.1B"},
+		{name: "invalid-character", fq: "ai/llama@3.2"},
+		{name: "digest", fq: "ai/llama3.2@sha256:abc"},
+		{name: "invalid-registry-port", fq: "localhost:port/ai/smollm2"},
+		{name: "leading-separator", fq: "ai/-llama3.2"},
+		{name: "trailing-separator", fq: "ai/llama3.2-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.fq)
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected ErrInvalid, got %+v, %v", m, err)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		fq       string
+		expected string
+	}{
+		{fq: "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M", expected: "hf.co/bartowski/llama-3.2-1b-instruct-gguf:q4_k_m"},
+		{fq: "HF.co/Bartowski/Llama-3.2-1B-Instruct-GGUF", expected: "hf.co/bartowski/llama-3.2-1b-instruct-gguf"},
+		{fq: "huggingface.co/bartowski/Llama-3.2-1B-Instruct-GGUF", expected: "huggingface.co/bartowski/llama-3.2-1b-instruct-gguf"},
+		// The quantization of the other registries is uppercase
+		{fq: "ai/llama3.2:1B-Q4_0", expected: "ai/llama3.2:1B-Q4_0"},
+		{fq: "localhost:5000/ai/smollm2:360M-Q4_K_M", expected: "localhost:5000/ai/smollm2:360M-Q4_K_M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fq, func(t *testing.T) {
+			m, err := Parse(tt.fq)
+			if err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+			if got := m.Normalize().String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithDefaultTag(t *testing.T) {
+	tests := []struct {
+		fq       string
+		expected string
+	}{
+		{fq: "ai/smollm2", expected: "ai/smollm2:latest"},
+		{fq: "localhost:5000/ai/smollm2", expected: "localhost:5000/ai/smollm2:latest"},
+		{fq: "ai/llama3.2:1B-Q4_0", expected: "ai/llama3.2:1B-Q4_0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fq, func(t *testing.T) {
+			m, err := Parse(tt.fq)
+			if err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+			if got := m.WithDefaultTag().String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
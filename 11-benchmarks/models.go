@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/modelname"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelConfig defines a model to benchmark
+type ModelConfig struct {
+	Namespace   string  `json:"namespace" yaml:"namespace"`
+	Name        string  `json:"name" yaml:"name"`
+	Tag         string  `json:"tag" yaml:"tag"`
+	FQName      string  `json:"fq_name" yaml:"fq_name"`
+	IsExternal  bool    `json:"external" yaml:"external"`         // True if using external API (not Docker Model Runner)
+	ExternalURL string  `json:"external_url" yaml:"external_url"` // External API endpoint (e.g., https://api.openai.com/v1)
+	SizeMB      float64 `json:"size_mb" yaml:"size_mb"`           // Download size, to check the disk space before pulling (estimated if zero)
+	// SupportsSystemPrompt tells whether the model handles a separate system message, or needs the
+	// system prompt in the user message. Nil means detected by the capabilities probe.
+	SupportsSystemPrompt *bool `json:"supports_system_prompt,omitempty" yaml:"supports_system_prompt,omitempty"`
+}
+
+var (
+	// modelParamsPattern matches the parameter count in a model tag, e.g. 1B, 0.6B or 360M
+	modelParamsPattern = regexp.MustCompile(`^\d+(\.\d+)?[KMBT]$`)
+	// modelQuantPattern matches the quantization in a model tag, e.g. Q4_0, Q4_K_M, IQ2_XS or F16
+	modelQuantPattern = regexp.MustCompile(`^(I?Q\d+(_[A-Z0-9]+)*|F16|BF16|F32)$`)
+)
+
+// ParseModelName extracts the parameter count and the quantization from the tag of a fully
+// qualified model name, e.g. "3B" and "Q4_K_M" for ai/llama3.2:3B-Q4_K_M. The components
+// without a recognizable value are returned empty, e.g. both for gpt-5.1 or ai/gemma3:latest.
+func ParseModelName(fqName string) (params, quant string) {
+	name, err := modelname.Parse(fqName)
+	if err != nil || name.Tag == "" {
+		return "", ""
+	}
+
+	for _, part := range strings.Split(strings.ToUpper(name.Tag), "-") {
+		switch {
+		case params == "" && modelParamsPattern.MatchString(part):
+			params = part
+		case quant == "" && modelQuantPattern.MatchString(part):
+			quant = part
+		}
+	}
+
+	return params, quant
+}
+
+// Params returns the parameter count of the model, e.g. 3B, or empty when the name doesn't tell it
+func (m ModelConfig) Params() string {
+	params, _ := ParseModelName(m.FQName)
+	return params
+}
+
+// Quant returns the quantization of the model, e.g. Q4_K_M, or empty when the name doesn't tell it
+func (m ModelConfig) Quant() string {
+	_, quant := ParseModelName(m.FQName)
+	return quant
+}
+
+// SystemPromptSupported tells whether the system prompts are sent in a separate system message: as
+// set in SupportsSystemPrompt, or as detected by the capabilities probe when it's not set
+func (m ModelConfig) SystemPromptSupported(detected bool) bool {
+	if m.SupportsSystemPrompt != nil {
+		return *m.SupportsSystemPrompt
+	}
+
+	return detected
+}
+
+// modelsManifest is the content of a models manifest file
+type modelsManifest struct {
+	Models []ModelConfig `json:"models" yaml:"models"`
+}
+
+// LoadModelsManifest reads the models to benchmark from a manifest file, in YAML (.yaml, .yml)
+// or JSON (.json) format, with the models under the "models" key:
+//
+//	models:
+//	  - namespace: ai
+//	    name: llama3.2
+//	    tag: 1B-Q4_0
+//	  - namespace: ai
+//	    name: smollm2
+//	    supports_system_prompt: false
+//	  - name: gpt-5.1
+//	    external: true
+//	    external_url: https://api.openai.com/v1
+//
+// The fully qualified name of the models defaults to namespace/name:tag for the local models,
+// and to the name for the external ones. Every model is validated, see ModelConfig.validate.
+func LoadModelsManifest(path string) ([]ModelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read models manifest: %w", err)
+	}
+
+	var manifest modelsManifest
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("parse models manifest %s: %w", path, err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("parse models manifest %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported models manifest format %q: use .yaml, .yml or .json", ext)
+	}
+
+	if len(manifest.Models) == 0 {
+		return nil, fmt.Errorf("models manifest %s: no models defined", path)
+	}
+
+	var errs []error
+	for i := range manifest.Models {
+		model := &manifest.Models[i]
+		model.setDefaults()
+		if err := model.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("model %d: %w", i+1, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("models manifest %s: %w", path, errors.Join(errs...))
+	}
+
+	return manifest.Models, nil
+}
+
+// setDefaults derives the fully qualified name of the model when it's not set
+func (m *ModelConfig) setDefaults() {
+	if m.FQName != "" {
+		return
+	}
+
+	if m.IsExternal {
+		m.FQName = m.Name
+		return
+	}
+
+	if m.Namespace == "" || m.Name == "" {
+		// Invalid, reported by validate
+		return
+	}
+
+	m.FQName = m.Namespace + "/" + m.Name
+	if m.Tag != "" {
+		m.FQName += ":" + m.Tag
+	}
+}
+
+// validate checks the fields required by the backend of the model: the local models, pulled into
+// Docker Model Runner, need a namespace and a name forming a valid model reference, while the
+// external ones need a name and the URL of their OpenAI-compatible API
+func (m *ModelConfig) validate() error {
+	if m.Name == "" {
+		return errors.New("name is required")
+	}
+	if m.SizeMB < 0 {
+		return errors.New("size_mb can't be negative")
+	}
+
+	if !m.IsExternal {
+		if m.Namespace == "" {
+			return errors.New("namespace is required for local models")
+		}
+		if m.ExternalURL != "" {
+			return errors.New("external_url is only supported for external models")
+		}
+		if _, err := modelname.Parse(m.FQName); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if m.ExternalURL == "" {
+		return errors.New("external_url is required for external models")
+	}
+	u, err := url.Parse(m.ExternalURL)
+	if err != nil {
+		return fmt.Errorf("invalid external_url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid external_url %q: must be an http(s) URL", m.ExternalURL)
+	}
+
+	return nil
+}
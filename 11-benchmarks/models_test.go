@@ -0,0 +1,181 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadModelsManifest(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		models, err := LoadModelsManifest(filepath.Join("testdata", "models", "models.yaml"))
+		if err != nil {
+			t.Fatalf("load manifest: %s", err)
+		}
+
+		expected := []ModelConfig{
+			{Namespace: "ai", Name: "llama3.2", Tag: "1B-Q4_0", FQName: "ai/llama3.2:1B-Q4_0"},
+			{Namespace: "ai", Name: "qwen3", Tag: "0.6B-Q4_0", FQName: "ai/qwen3:0.6B-Q4_0"},
+			{Namespace: "hf.co/bartowski", Name: "Llama-3.2-1B-Instruct-GGUF", FQName: "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF", SizeMB: 1300},
+			{Namespace: "openai", Name: "gpt-5.1", FQName: "gpt-5.1", IsExternal: true, ExternalURL: "https://api.openai.com/v1"},
+		}
+		if !reflect.DeepEqual(models, expected) {
+			t.Errorf("unexpected models:\n got: %+v\nwant: %+v", models, expected)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		models, err := LoadModelsManifest(filepath.Join("testdata", "models", "models.json"))
+		if err != nil {
+			t.Fatalf("load manifest: %s", err)
+		}
+
+		expected := []ModelConfig{
+			{Namespace: "ai", Name: "llama3.2", Tag: "3B-Q4_K_M", FQName: "ai/llama3.2:3B-Q4_K_M"},
+			{Name: "gpt-5.1", FQName: "gpt-5.1", IsExternal: true, ExternalURL: "https://api.openai.com/v1"},
+		}
+		if !reflect.DeepEqual(models, expected) {
+			t.Errorf("unexpected models:\n got: %+v\nwant: %+v", models, expected)
+		}
+	})
+
+	invalid := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "local-without-namespace",
+			file:     "models.yaml",
+			content:  "models:\n  - name: llama3.2\n    tag: 1B-Q4_0\n",
+			expected: "namespace is required for local models",
+		},
+		{
+			name:     "local-invalid-name",
+			file:     "models.yaml",
+			content:  "models:\n  - namespace: ai\n    name: llama 3.2\n    tag: 1B-Q4_0\n",
+			expected: `invalid model name "ai/llama 3.2:1B-Q4_0"`,
+		},
+		{
+			name:     "external-without-url",
+			file:     "models.yaml",
+			content:  "models:\n  - name: gpt-5.1\n    external: true\n",
+			expected: "external_url is required for external models",
+		},
+		{
+			name:     "external-invalid-url",
+			file:     "models.json",
+			content:  `{"models": [{"name": "gpt-5.1", "external": true, "external_url": "api.openai.com"}]}`,
+			expected: "must be an http(s) URL",
+		},
+		{
+			name:     "local-with-url",
+			file:     "models.yaml",
+			content:  "models:\n  - namespace: ai\n    name: qwen3\n    external_url: http://localhost:12434\n",
+			expected: "external_url is only supported for external models",
+		},
+		{
+			name:     "unknown-field",
+			file:     "models.yaml",
+			content:  "models:\n  - namespace: ai\n    name: qwen3\n    quantization: Q4_0\n",
+			expected: "field quantization not found",
+		},
+		{
+			name:     "empty",
+			file:     "models.json",
+			content:  `{"models": []}`,
+			expected: "no models defined",
+		},
+		{
+			name:     "unsupported-format",
+			file:     "models.toml",
+			content:  "",
+			expected: "unsupported models manifest format",
+		},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("write manifest: %s", err)
+			}
+
+			_, err := LoadModelsManifest(path)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseModelName(t *testing.T) {
+	tests := []struct {
+		fqName string
+		params string
+		quant  string
+	}{
+		{fqName: "ai/llama3.2:3B-Q4_K_M", params: "3B", quant: "Q4_K_M"},
+		{fqName: "ai/llama3.2:1B-Q4_0", params: "1B", quant: "Q4_0"},
+		{fqName: "ai/qwen3:0.6B-Q4_0", params: "0.6B", quant: "Q4_0"},
+		{fqName: "ai/smollm2:360M-F16", params: "360M", quant: "F16"},
+		{fqName: "ai/gemma3:q8_0", quant: "Q8_0"},
+		// No recognizable suffix
+		{fqName: "ai/gemma3:latest"},
+		{fqName: "ai/gemma3"},
+		{fqName: "gpt-5.1"},
+		{fqName: "localhost:5000/ai/gemma3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fqName, func(t *testing.T) {
+			params, quant := ParseModelName(tt.fqName)
+			if params != tt.params || quant != tt.quant {
+				t.Errorf("expected params %q and quant %q, got %q and %q", tt.params, tt.quant, params, quant)
+			}
+
+			model := ModelConfig{FQName: tt.fqName}
+			if model.Params() != tt.params || model.Quant() != tt.quant {
+				t.Errorf("expected the model config to report params %q and quant %q, got %q and %q", tt.params, tt.quant, model.Params(), model.Quant())
+			}
+		})
+	}
+}
+
+func TestModelConfig_SystemPromptSupported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	content := "models:\n" +
+		"  - namespace: ai\n    name: llama3.2\n" +
+		"  - namespace: ai\n    name: smollm2\n    supports_system_prompt: false\n" +
+		"  - namespace: ai\n    name: qwen3\n    supports_system_prompt: true\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %s", err)
+	}
+
+	models, err := LoadModelsManifest(path)
+	if err != nil {
+		t.Fatalf("load manifest: %s", err)
+	}
+
+	tests := []struct {
+		model    ModelConfig
+		detected bool
+		expected bool
+	}{
+		// Not set: the capabilities probe decides
+		{model: models[0], detected: true, expected: true},
+		{model: models[0], detected: false, expected: false},
+		// Set in the manifest: it overrides the probe
+		{model: models[1], detected: true, expected: false},
+		{model: models[2], detected: false, expected: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.model.SystemPromptSupported(tt.detected); got != tt.expected {
+			t.Errorf("%s, detected %t: expected %t, got %t", tt.model.FQName, tt.detected, tt.expected, got)
+		}
+	}
+}
@@ -66,8 +66,9 @@ func getCPUModel() string {
 	return runtime.GOARCH
 }
 
-// InitOTel initializes OpenTelemetry with OTLP exporters for traces and metrics
-func InitOTel(ctx context.Context, otlpEndpoint string) (*OtelSetup, error) {
+// newBenchmarkResource creates the resource describing the benchmark service and the runtime it runs on,
+// with the extra attributes, e.g. the inference backend
+func newBenchmarkResource(extraAttrs ...attribute.KeyValue) (*resource.Resource, error) {
 	// Get CPU model info
 	cpuModel := getCPUModel()
 
@@ -86,6 +87,18 @@ func InitOTel(ctx context.Context, otlpEndpoint string) (*OtelSetup, error) {
 			attribute.String("cpu.model", cpuModel),           // cpu: Apple M4 Max
 		),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource.Merge(res, resource.NewSchemaless(extraAttrs...))
+}
+
+// InitOTel initializes OpenTelemetry with OTLP exporters for traces and metrics, registering the
+// extra metric readers alongside the OTLP one, e.g. the Prometheus exporter, and adding the extra
+// attributes to the resource
+func InitOTel(ctx context.Context, otlpEndpoint string, extraReaders []metric.Reader, extraAttrs ...attribute.KeyValue) (*OtelSetup, error) {
+	res, err := newBenchmarkResource(extraAttrs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
@@ -116,15 +129,19 @@ func InitOTel(ctx context.Context, otlpEndpoint string) (*OtelSetup, error) {
 		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
 	}
 
-	// Setup metric provider with periodic reader
-	meterProvider := metric.NewMeterProvider(
+	// Setup metric provider with periodic reader, and the extra ones
+	meterOpts := []metric.Option{
 		metric.WithReader(
 			metric.NewPeriodicReader(metricExporter,
 				metric.WithInterval(5*time.Second),
 			),
 		),
 		metric.WithResource(res),
-	)
+	}
+	for _, reader := range extraReaders {
+		meterOpts = append(meterOpts, metric.WithReader(reader))
+	}
+	meterProvider := metric.NewMeterProvider(meterOpts...)
 
 	// Setup log exporter
 	logExporter, err := otlploghttp.New(ctx,
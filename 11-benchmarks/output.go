@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Default names of the artifacts in the run directory, see RunOutput
+const (
+	reportFileName    = "report.md"
+	dashboardFileName = "dashboard.json"
+	summaryFileName   = "summary.txt"
+	resultsFileName   = "results.json"
+)
+
+// RunOutput is the directory the artifacts of a benchmark run are written to, e.g. the markdown
+// report or the dashboard JSON, so the runs are self-contained and can be compared side by side
+type RunOutput struct {
+	// RunID identifies the run, e.g. 20261015-142530-3f9a2c1
+	RunID string
+	// Dir is the run directory, <BENCH_OUTPUT_DIR>/<RunID>, empty when the artifacts are not collected
+	Dir string
+}
+
+// NewRunID returns the identifier of a run started at the time: the UTC timestamp, sortable, and a
+// short hash telling apart the runs started at the same second, e.g. on several machines
+func NewRunID(now time.Time) string {
+	host, _ := os.Hostname()
+	sum := sha256.Sum256(fmt.Appendf(nil, "%d-%s-%d", now.UnixNano(), host, os.Getpid()))
+
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(sum[:])[:7]
+}
+
+// NewRunOutput creates the directory of the run under root, empty to not collect the artifacts
+func NewRunOutput(root, runID string) (RunOutput, error) {
+	out := RunOutput{RunID: runID}
+	if root == "" {
+		return out, nil
+	}
+
+	out.Dir = filepath.Join(root, runID)
+	if err := os.MkdirAll(out.Dir, 0o755); err != nil {
+		return RunOutput{}, fmt.Errorf("create run directory: %w", err)
+	}
+
+	return out, nil
+}
+
+// Path returns the file an artifact is written to. Without run directory, it's the configured path,
+// empty to skip the artifact. With it, the artifacts are always written: the relative paths are
+// resolved in the run directory, and the default name is used when no path is configured.
+func (o RunOutput) Path(path, defaultName string) string {
+	switch {
+	case o.Dir == "":
+		return path
+	case path == "":
+		return filepath.Join(o.Dir, defaultName)
+	case filepath.IsAbs(path):
+		return path
+	default:
+		return filepath.Join(o.Dir, path)
+	}
+}
+
+// getRunOutput creates the directory of the run in the one of the BENCH_OUTPUT_DIR environment
+// variable, if it's set
+func getRunOutput(now time.Time) (RunOutput, error) {
+	return NewRunOutput(strings.TrimSpace(os.Getenv("BENCH_OUTPUT_DIR")), NewRunID(now))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewRunID(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 25, 30, 0, time.FixedZone("CEST", 2*60*60))
+
+	runID := NewRunID(now)
+	if !regexp.MustCompile(`^20261015-122530-[0-9a-f]{7}$`).MatchString(runID) {
+		t.Errorf("expected the UTC timestamp and a short hash, got %q", runID)
+	}
+
+	if other := NewRunID(now.Add(time.Nanosecond)); other == runID {
+		t.Errorf("expected different run IDs for runs started at the same second, got %q twice", runID)
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	t.Run("artifacts-in-run-dir", func(t *testing.T) {
+		root := filepath.Join(t.TempDir(), "results")
+		t.Setenv("BENCH_OUTPUT_DIR", root)
+
+		out, err := getRunOutput(time.Now())
+		if err != nil {
+			t.Fatalf("get run output: %s", err)
+		}
+
+		if out.Dir != filepath.Join(root, out.RunID) {
+			t.Fatalf("expected the run directory under the output one, got %q", out.Dir)
+		}
+		if info, err := os.Stat(out.Dir); err != nil || !info.IsDir() {
+			t.Fatalf("expected the run directory to be created: %v", err)
+		}
+
+		dashboardPath := out.Path("", dashboardFileName)
+		if err := WriteDashboardJSON(dashboardPath, "LLM Bench"); err != nil {
+			t.Fatalf("write dashboard: %s", err)
+		}
+		reportPath := out.Path("custom-report.md", reportFileName)
+		if err := os.WriteFile(reportPath, []byte("# Report\n"), 0o644); err != nil {
+			t.Fatalf("write report: %s", err)
+		}
+
+		entries, err := os.ReadDir(out.Dir)
+		if err != nil {
+			t.Fatalf("read run directory: %s", err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if len(names) != 2 || names[0] != "custom-report.md" || names[1] != dashboardFileName {
+			t.Errorf("expected the artifacts in the run directory, got %v", names)
+		}
+
+		absolute := filepath.Join(t.TempDir(), "report.md")
+		if got := out.Path(absolute, reportFileName); got != absolute {
+			t.Errorf("expected the absolute paths unchanged, got %q", got)
+		}
+	})
+
+	t.Run("without-output-dir", func(t *testing.T) {
+		t.Setenv("BENCH_OUTPUT_DIR", "")
+
+		out, err := getRunOutput(time.Now())
+		if err != nil {
+			t.Fatalf("get run output: %s", err)
+		}
+		if out.Dir != "" {
+			t.Errorf("expected no run directory, got %q", out.Dir)
+		}
+
+		if got := out.Path("", reportFileName); got != "" {
+			t.Errorf("expected the unconfigured artifacts to be skipped, got %q", got)
+		}
+		if got := out.Path("report.md", reportFileName); got != "report.md" {
+			t.Errorf("expected the configured path unchanged, got %q", got)
+		}
+	})
+}
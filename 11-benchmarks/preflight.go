@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+)
+
+const (
+	// DefaultModelSizeMB is the conservative size estimate of the models without a known size
+	DefaultModelSizeMB = 4096
+
+	// diskSpaceMargin is the extra space required over the size of the model, as the estimates are
+	// approximate and the pull unpacks the layers
+	diskSpaceMargin = 1.2
+
+	// dockerRootDir is the default data directory of Docker Engine on Linux
+	dockerRootDir = "/var/lib/docker"
+)
+
+// ErrInsufficientDiskSpace is returned when there is no room on disk to pull a model
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+// EstimatedSizeMB returns the size of the model in MB: the size_mb of the manifest if set, or the
+// parameter count times the bits per weight of the quantization if the name tells them, or
+// DefaultModelSizeMB otherwise
+func (m ModelConfig) EstimatedSizeMB() float64 {
+	if m.SizeMB > 0 {
+		return m.SizeMB
+	}
+
+	params, quant := ParseModelName(m.FQName)
+	count, ok := parseParamCount(params)
+	if !ok {
+		return DefaultModelSizeMB
+	}
+	bits, ok := quantBitsPerWeight(quant)
+	if !ok {
+		return DefaultModelSizeMB
+	}
+
+	return count * bits / 8 / (1024 * 1024)
+}
+
+// parseParamCount returns the number of parameters of a parameter count, e.g. 1.5e9 for 1.5B
+func parseParamCount(params string) (float64, bool) {
+	if params == "" {
+		return 0, false
+	}
+
+	multipliers := map[byte]float64{'K': 1e3, 'M': 1e6, 'B': 1e9, 'T': 1e12}
+	multiplier, ok := multipliers[params[len(params)-1]]
+	if !ok {
+		return 0, false
+	}
+	count, err := strconv.ParseFloat(params[:len(params)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return count * multiplier, true
+}
+
+// quantBitsPerWeight returns an upper bound of the bits per weight of a quantization, as the
+// quantized formats store the scales of each block besides the weights, e.g. 5 for Q4_K_M
+func quantBitsPerWeight(quant string) (float64, bool) {
+	switch quant {
+	case "F32":
+		return 32, true
+	case "F16", "BF16":
+		return 16, true
+	}
+
+	digits := strings.TrimLeft(quant, "IQ")
+	if i := strings.Index(digits, "_"); i >= 0 {
+		digits = digits[:i]
+	}
+	bits, err := strconv.Atoi(digits)
+	if err != nil || digits == "" {
+		return 0, false
+	}
+
+	return float64(bits + 1), true
+}
+
+// modelsDiskPath returns the path of the filesystem the models are pulled into, defined by the
+// BENCH_MODELS_DISK_PATH environment variable. It defaults to the data directory of Docker Engine
+// on Linux, and to the home directory, where Docker Desktop stores its disk image, elsewhere.
+func modelsDiskPath() string {
+	if path := os.Getenv("BENCH_MODELS_DISK_PATH"); path != "" {
+		return path
+	}
+
+	if _, err := os.Stat(dockerRootDir); err == nil {
+		return dockerRootDir
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+
+	return string(os.PathSeparator)
+}
+
+// checkDiskSpace returns ErrInsufficientDiskSpace if the available bytes can't hold the model,
+// with the margin of diskSpaceMargin
+func checkDiskSpace(model string, sizeMB float64, availableBytes uint64, path string) error {
+	requiredMB := sizeMB * diskSpaceMargin
+	availableMB := float64(availableBytes) / (1024 * 1024)
+
+	if availableMB < requiredMB {
+		return fmt.Errorf("%w: pulling %s needs about %.0f MB, but only %.0f MB are available in %s. Free some space, e.g. with `docker model rm`, or set size_mb in the models manifest if the estimate is wrong",
+			ErrInsufficientDiskSpace, model, requiredMB, availableMB, path)
+	}
+
+	return nil
+}
+
+// PreflightPull checks that there is room on disk to pull the model, before starting a pull that
+// would otherwise fill the disk mid-run with a confusing Docker error. The models already listed by
+// the OpenAI-compatible endpoint are not checked, and the check is skipped with a warning when the
+// available space can't be read, e.g. on unsupported platforms.
+func PreflightPull(ctx context.Context, endpoint string, model ModelConfig, diskPath string) error {
+	listed, err := listModels(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("list models: %w", err)
+	}
+	if isModelListed(listed, model.FQName) {
+		return nil
+	}
+
+	available, err := availableDiskBytes(diskPath)
+	if err != nil {
+		logging.Default().Warn("Skipping the disk space preflight", "model", model.FQName, "error", err)
+		return nil
+	}
+
+	return checkDiskSpace(model.FQName, model.EstimatedSizeMB(), available, diskPath)
+}
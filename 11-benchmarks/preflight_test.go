@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEstimatedSizeMB(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    ModelConfig
+		expected float64
+	}{
+		{name: "manifest", model: ModelConfig{FQName: "ai/llama3.2:3B-Q4_K_M", SizeMB: 2020}, expected: 2020},
+		// 3B parameters of 5 bits
+		{name: "q4_k_m", model: ModelConfig{FQName: "ai/llama3.2:3B-Q4_K_M"}, expected: 3e9 * 5 / 8 / (1024 * 1024)},
+		// 335M parameters of 16 bits
+		{name: "f16", model: ModelConfig{FQName: "ai/mxbai-embed-large:335M-F16"}, expected: 335e6 * 16 / 8 / (1024 * 1024)},
+		{name: "no-quant", model: ModelConfig{FQName: "ai/llama3.2:3B"}, expected: DefaultModelSizeMB},
+		{name: "no-tag", model: ModelConfig{FQName: "ai/smollm2"}, expected: DefaultModelSizeMB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.EstimatedSizeMB(); math.Abs(got-tt.expected) > 0.01 {
+				t.Errorf("expected %.2f MB, got %.2f MB", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	const mb = 1024 * 1024
+
+	t.Run("enough", func(t *testing.T) {
+		// 1000 MB plus the 20% margin
+		if err := checkDiskSpace("ai/llama3.2:1B-Q4_0", 1000, 1200*mb, "/var/lib/docker"); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("insufficient", func(t *testing.T) {
+		err := checkDiskSpace("ai/llama3.2:1B-Q4_0", 1000, 1199*mb, "/var/lib/docker")
+		if !errors.Is(err, ErrInsufficientDiskSpace) {
+			t.Fatalf("expected an insufficient disk space error, got %v", err)
+		}
+		for _, expected := range []string{"ai/llama3.2:1B-Q4_0", "1200 MB", "1199 MB", "/var/lib/docker"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected the error to contain %q, got %q", expected, err)
+			}
+		}
+	})
+}
+
+func TestPreflightPull(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"id": "ai/llama3.2:1B-Q4_0"}]}`))
+	}))
+	defer srv.Close()
+
+	// A pulled model is not checked, even if it couldn't fit in the disk
+	pulled := ModelConfig{FQName: "ai/llama3.2:1B-Q4_0", SizeMB: math.MaxInt32}
+	if err := PreflightPull(context.Background(), srv.URL, pulled, t.TempDir()); err != nil {
+		t.Errorf("unexpected error for a pulled model: %s", err)
+	}
+
+	huge := ModelConfig{FQName: "ai/huge:1T-F32", SizeMB: math.MaxInt32}
+	if err := PreflightPull(context.Background(), srv.URL, huge, t.TempDir()); !errors.Is(err, ErrInsufficientDiskSpace) {
+		t.Errorf("expected an insufficient disk space error, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// EnvProgress is the environment variable enabling the progress lines, printed as each
+// model/case/temp combination completes, so the long matrix runs don't look stuck
+const EnvProgress = "BENCH_PROGRESS"
+
+// ProgressReporter prints a one-line summary of each completed benchmark. A nil reporter is
+// disabled, so the benchmarks can report unconditionally.
+type ProgressReporter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	completed int
+}
+
+// NewProgressReporter creates a progress reporter writing to w
+func NewProgressReporter(w io.Writer) *ProgressReporter {
+	return &ProgressReporter{w: w}
+}
+
+// Report prints the summary of the completed benchmark, numbered in completion order
+func (p *ProgressReporter) Report(agg AggregateMetrics) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.completed++
+	fmt.Fprintf(p.w, "[%d] %s\n", p.completed, FormatProgress(agg))
+}
+
+// FormatProgress formats the aggregate metrics of a benchmark as a progress line, e.g.
+// "ai/llama3.2:1B-Q4_0 code-generation temp0.1 → p50 1234ms, 42.5 tok/s, 100% success (10 samples)"
+func FormatProgress(agg AggregateMetrics) string {
+	return fmt.Sprintf("%s %s temp%.1f → p50 %.0fms, %.1f tok/s, %.0f%% success (%d samples)",
+		agg.Model, agg.TestCase, agg.Temp,
+		agg.LatencyP50,
+		agg.TokensPerSec,
+		agg.SuccessRate*100,
+		agg.LatencySamples,
+	)
+}
+
+// getProgressReporter returns the reporter printing the progress lines to the standard error, so
+// they don't mix with the benchmark results of the standard output, if the BENCH_PROGRESS
+// environment variable is set. It returns nil otherwise.
+func getProgressReporter() (*ProgressReporter, error) {
+	enabled, err := getEnvBool(EnvProgress)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	return NewProgressReporter(os.Stderr), nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		agg      AggregateMetrics
+		expected string
+	}{
+		{
+			name: "successful",
+			agg: AggregateMetrics{
+				Model: "ai/llama3.2:1B-Q4_0", TestCase: "code-generation", Temp: 0.1,
+				LatencyP50: 1234.4, TokensPerSec: 42.46, SuccessRate: 1, LatencySamples: 10,
+			},
+			expected: "ai/llama3.2:1B-Q4_0 code-generation temp0.1 → p50 1234ms, 42.5 tok/s, 100% success (10 samples)",
+		},
+		{
+			name: "partially-failed",
+			agg: AggregateMetrics{
+				Model: "ai/qwen3:0.6B-Q4_0", TestCase: "factual-question", Temp: 0.7,
+				LatencyP50: 850, TokensPerSec: 61, SuccessRate: 2.0 / 3, LatencySamples: 2,
+			},
+			expected: "ai/qwen3:0.6B-Q4_0 factual-question temp0.7 → p50 850ms, 61.0 tok/s, 67% success (2 samples)",
+		},
+		{
+			name:     "all-failed",
+			agg:      AggregateMetrics{Model: "gpt-5.1", TestCase: "code-explanation", Temp: 1},
+			expected: "gpt-5.1 code-explanation temp1.0 → p50 0ms, 0.0 tok/s, 0% success (0 samples)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatProgress(tt.agg); got != tt.expected {
+				t.Errorf("unexpected progress line:\n got: %s\nwant: %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Run("numbered", func(t *testing.T) {
+		var sb strings.Builder
+		progress := NewProgressReporter(&sb)
+
+		progress.Report(AggregateMetrics{Model: "ai/llama3.2:1B-Q4_0", TestCase: "code-generation", Temp: 0.1})
+		progress.Report(AggregateMetrics{Model: "ai/llama3.2:1B-Q4_0", TestCase: "code-generation", Temp: 0.7})
+
+		lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 progress lines, got %q", sb.String())
+		}
+		if !strings.HasPrefix(lines[0], "[1] ai/llama3.2:1B-Q4_0 code-generation temp0.1 ") ||
+			!strings.HasPrefix(lines[1], "[2] ai/llama3.2:1B-Q4_0 code-generation temp0.7 ") {
+			t.Errorf("expected the lines numbered in completion order, got %q", lines)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(EnvProgress, "")
+
+		progress, err := getProgressReporter()
+		if err != nil {
+			t.Fatalf("get progress reporter: %s", err)
+		}
+		if progress != nil {
+			t.Fatal("expected no progress reporter when the variable is not set")
+		}
+
+		// A disabled reporter ignores the results
+		progress.Report(AggregateMetrics{Model: "ai/llama3.2:1B-Q4_0"})
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv(EnvProgress, "true")
+
+		progress, err := getProgressReporter()
+		if err != nil {
+			t.Fatalf("get progress reporter: %s", err)
+		}
+		if progress == nil {
+			t.Fatal("expected a progress reporter")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv(EnvProgress, "loud")
+
+		if _, err := getProgressReporter(); err == nil {
+			t.Fatal("expected an error for an invalid value")
+		}
+	})
+}
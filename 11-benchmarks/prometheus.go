@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// EnvPrometheusPort is the environment variable with the port of the Prometheus scrape endpoint,
+// e.g. 9464, to monitor the long benchmark runs live from an existing Prometheus. It's disabled
+// when the variable is not set.
+const EnvPrometheusPort = "BENCH_PROMETHEUS_PORT"
+
+// prometheusMetricsPath is the path of the scrape endpoint
+const prometheusMetricsPath = "/metrics"
+
+// PrometheusServer exposes the benchmark metrics in the Prometheus text format, read from the
+// meter provider alongside the OTLP export to the LGTM stack
+type PrometheusServer struct {
+	exporter *prometheus.Exporter
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewPrometheusServer creates the Prometheus exporter and listens on the address, e.g. :9464, without
+// serving yet. Its reader must be registered in the meter provider, see Reader.
+func NewPrometheusServer(addr string) (*PrometheusServer, error) {
+	// A registry of its own, so only the benchmark metrics are exposed, not the ones of the Go runtime
+	registry := promclient.NewRegistry()
+
+	exporter, err := prometheus.New(prometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("create prometheus exporter: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(prometheusMetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	return &PrometheusServer{
+		exporter: exporter,
+		listener: listener,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Reader returns the metric reader to register in the meter provider
+func (p *PrometheusServer) Reader() metric.Reader {
+	return p.exporter
+}
+
+// URL returns the URL of the scrape endpoint
+func (p *PrometheusServer) URL() string {
+	return "http://" + p.listener.Addr().String() + prometheusMetricsPath
+}
+
+// Start serves the scrape endpoint in the background, until Shutdown is called
+func (p *PrometheusServer) Start() {
+	go func() {
+		if err := p.server.Serve(p.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Default().Warn("Prometheus scrape endpoint stopped", "error", err)
+		}
+	}()
+}
+
+// Shutdown stops serving the scrape endpoint, waiting for the in-flight scrapes
+func (p *PrometheusServer) Shutdown(ctx context.Context) error {
+	if err := p.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown prometheus server: %w", err)
+	}
+
+	return nil
+}
+
+// getPrometheusAddr returns the address of the scrape endpoint defined by the BENCH_PROMETHEUS_PORT
+// environment variable, empty when it's not set
+func getPrometheusAddr() (string, error) {
+	value := strings.TrimSpace(os.Getenv(EnvPrometheusPort))
+	if value == "" {
+		return "", nil
+	}
+
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return "", fmt.Errorf("invalid %s %q: must be a port between 1 and 65535", EnvPrometheusPort, value)
+	}
+
+	return ":" + strconv.Itoa(port), nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusServer(t *testing.T) {
+	server, err := NewPrometheusServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("new prometheus server: %s", err)
+	}
+	server.Start()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(server.Reader()))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	ctx := context.Background()
+	mc.RecordLatency(ctx, 1200*time.Millisecond, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+	mc.RecordTTFT(ctx, 300*time.Millisecond, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+	mc.RecordCompletionTokens(ctx, 120, "ai/llama3.2:1B-Q4_0", "code-generation", 0.1)
+
+	resp, err := http.Get(server.URL())
+	if err != nil {
+		t.Fatalf("scrape: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read scrape: %s", err)
+	}
+
+	for _, expected := range []string{"llm_latency_bucket", "llm_ttft_bucket", "llm_completion_tokens_bucket", "target_info"} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected %s in the scrape, got:\n%s", expected, body)
+		}
+	}
+}
+
+func TestGetPrometheusAddr(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{value: "", expected: ""},
+		{value: "9464", expected: ":9464"},
+		{value: " 9090 ", expected: ":9090"},
+		{value: "0", wantErr: true},
+		{value: "70000", wantErr: true},
+		{value: "metrics", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(EnvPrometheusPort, tt.value)
+
+			addr, err := getPrometheusAddr()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", addr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("get prometheus addr: %s", err)
+			}
+			if addr != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, addr)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package prompt renders the prompt templates with {{name}} placeholders, so the prompts can contain
+// percent signs and braces, e.g. code or JSON examples, unlike the fmt verbs.
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// placeholder matches a variable of a template, e.g. {{question}} or {{ question }}. The braces not
+// enclosing a variable name, e.g. the ones of a JSON example, are kept literally.
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Render replaces the {{name}} placeholders of the template with the values of the variables. The
+// values are inserted as they are, without looking for placeholders in them, so the answers of a
+// model can't inject variables. It fails listing the variables of the template that are not provided.
+func Render(template string, vars map[string]string) (string, error) {
+	if missing := Missing(template, vars); len(missing) > 0 {
+		return "", fmt.Errorf("missing prompt variables: %s", strings.Join(missing, ", "))
+	}
+
+	return placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		return vars[placeholder.FindStringSubmatch(match)[1]]
+	}), nil
+}
+
+// Variables returns the names of the variables of the template, sorted and without duplicates
+func Variables(template string) []string {
+	var names []string
+	for _, match := range placeholder.FindAllStringSubmatch(template, -1) {
+		names = append(names, match[1])
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// Missing returns the names of the variables of the template that are not provided, sorted
+func Missing(template string, vars map[string]string) []string {
+	var missing []string
+	for _, name := range Variables(template) {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,88 @@
+package prompt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]string
+		expected string
+	}{
+		{
+			name:     "multi-variable",
+			template: "Question: {{question}}\nAnswer: {{answer}}\nReference: {{reference}}",
+			vars:     map[string]string{"question": "2+2?", "answer": "4", "reference": "4"},
+			expected: "Question: 2+2?\nAnswer: 4\nReference: 4",
+		},
+		{
+			name:     "repeated-variable",
+			template: "{{word}}, {{ word }}!",
+			vars:     map[string]string{"word": "hello"},
+			expected: "hello, hello!",
+		},
+		{
+			name:     "literal-braces",
+			template: `Respond with {"response": "yes"} or {{}} or {{ not a var }} for {{name}}`,
+			vars:     map[string]string{"name": "the judge"},
+			expected: `Respond with {"response": "yes"} or {{}} or {{ not a var }} for the judge`,
+		},
+		{
+			name:     "percent-signs",
+			template: "Score {{score}}% of 100%",
+			vars:     map[string]string{"score": "%d"},
+			expected: "Score %d% of 100%",
+		},
+		{
+			name:     "values-not-rendered",
+			template: "Answer: {{answer}}",
+			vars:     map[string]string{"answer": "{{reference}}", "reference": "secret"},
+			expected: "Answer: {{reference}}",
+		},
+		{
+			name:     "unused-variables",
+			template: "no variables",
+			vars:     map[string]string{"question": "ignored"},
+			expected: "no variables",
+		},
+		{
+			name:     "empty-value",
+			template: "[{{answer}}]",
+			vars:     map[string]string{"answer": ""},
+			expected: "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.template, tt.vars)
+			if err != nil {
+				t.Fatalf("render: %s", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRender_missingVariables(t *testing.T) {
+	_, err := Render("{{question}} {{answer}} {{reference}} {{answer}}", map[string]string{"question": "q"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "answer, reference") {
+		t.Errorf("expected the missing variables listed once, got %s", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	got := Variables("{{b}} {{ a }} {{b}} {c} {{}}")
+	if expected := []string{"a", "b"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"slices"
+	"time"
+)
+
+// PromptCacheSpeedup returns how many times faster the prompt evaluation of the requests sharing a
+// system prompt gets once the inference engine caches it: the prompt-eval time of the first request,
+// which fills the KV cache, divided by the median of the following ones. A speedup close to 1 means
+// the prefix is not reused. It returns false with fewer than two prompt-eval times.
+func PromptCacheSpeedup(evalTimes []time.Duration) (float64, bool) {
+	if len(evalTimes) < 2 || evalTimes[0] <= 0 {
+		return 0, false
+	}
+
+	following := slices.Clone(evalTimes[1:])
+	slices.Sort(following)
+
+	var median time.Duration
+	if n := len(following); n%2 == 1 {
+		median = following[n/2]
+	} else {
+		median = (following[n/2-1] + following[n/2]) / 2
+	}
+	if median <= 0 {
+		return 0, false
+	}
+
+	return float64(evalTimes[0]) / float64(median), true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPromptCacheSpeedup(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name      string
+		evalTimes []time.Duration
+		expected  float64
+		ok        bool
+	}{
+		{name: "cached", evalTimes: []time.Duration{400 * ms, 50 * ms, 40 * ms, 60 * ms}, expected: 8, ok: true},
+		// The median ignores the outlier of a request evicting the prefix
+		{name: "outlier", evalTimes: []time.Duration{400 * ms, 50 * ms, 390 * ms, 50 * ms}, expected: 8, ok: true},
+		{name: "even-following", evalTimes: []time.Duration{300 * ms, 40 * ms, 60 * ms}, expected: 6, ok: true},
+		{name: "not-cached", evalTimes: []time.Duration{200 * ms, 200 * ms, 200 * ms}, expected: 1, ok: true},
+		{name: "single-request", evalTimes: []time.Duration{400 * ms}},
+		{name: "no-requests"},
+		{name: "zero-first", evalTimes: []time.Duration{0, 50 * ms}},
+		{name: "zero-following", evalTimes: []time.Duration{400 * ms, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			speedup, ok := PromptCacheSpeedup(tt.evalTimes)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%t, got %t", tt.ok, ok)
+			}
+			if math.Abs(speedup-tt.expected) > 1e-9 {
+				t.Errorf("expected a speedup of %.2f, got %.2f", tt.expected, speedup)
+			}
+		})
+	}
+}
+
+func TestRecordPromptEval(t *testing.T) {
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	const model = "ai/llama3.2:1B-Q4_0"
+	mc.RecordPromptEval(model, "code-explanation", "You are a coding assistant.", 500*time.Millisecond)
+	if _, ok := mc.PromptCacheSpeedup(model, "You are a coding assistant."); ok {
+		t.Fatal("expected no speedup after the first request")
+	}
+
+	mc.RecordPromptEval(model, "code-explanation", "You are a coding assistant.", 100*time.Millisecond)
+	// Another system prompt is not cached yet
+	mc.RecordPromptEval(model, "factual-question", "You are a knowledgeable assistant.", 450*time.Millisecond)
+
+	speedup, ok := mc.PromptCacheSpeedup(model, "You are a coding assistant.")
+	if !ok || speedup != 5 {
+		t.Errorf("expected a speedup of 5, got %.2f (%t)", speedup, ok)
+	}
+	if _, ok := mc.PromptCacheSpeedup(model, "You are a knowledgeable assistant."); ok {
+		t.Error("expected the speedup to be tracked per system prompt")
+	}
+	if _, ok := mc.PromptCacheSpeedup("ai/qwen3:0.6B-Q4_0", "You are a coding assistant."); ok {
+		t.Error("expected the speedup to be tracked per model")
+	}
+}
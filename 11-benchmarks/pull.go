@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/retry"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+)
+
+// ModelPuller pulls models into the Model Runner, e.g. the DMR container
+type ModelPuller interface {
+	PullModel(ctx context.Context, model string) error
+}
+
+// PullRetryConfig configures the retries of PullModelVerified
+type PullRetryConfig struct {
+	Attempts int           // Maximum number of pull attempts
+	Backoff  time.Duration // Wait before the first retry, doubled on each retry
+}
+
+// DefaultPullRetryConfig retries a failed pull twice, waiting 5s and 10s
+var DefaultPullRetryConfig = PullRetryConfig{Attempts: 3, Backoff: 5 * time.Second}
+
+// PullModelVerified pulls the model, retrying the failed pulls with exponential backoff.
+// After a successful pull, it verifies that the model is listed by the OpenAI-compatible endpoint
+// with the expected tag, as an interrupted or corrupted pull would otherwise surface as confusing
+// inference errors downstream. A failed verification is retried as a failed pull.
+func PullModelVerified(ctx context.Context, puller ModelPuller, endpoint, model string, cfg PullRetryConfig) error {
+	policy := retry.Policy{
+		MaxAttempts:    cfg.Attempts,
+		InitialBackoff: cfg.Backoff,
+		OnRetry: func(attempt int, wait time.Duration, err error) {
+			logging.Default().Warn("Failed to pull model, retrying", "model", model, "attempt", attempt, "backoff", wait, "error", err)
+		},
+	}
+
+	if err := retry.Do(ctx, policy, func() error { return pullAndVerify(ctx, puller, endpoint, model) }); err != nil {
+		return fmt.Errorf("pull model %s: %w", model, err)
+	}
+
+	return nil
+}
+
+func pullAndVerify(ctx context.Context, puller ModelPuller, endpoint, model string) error {
+	if err := puller.PullModel(ctx, model); err != nil {
+		return fmt.Errorf("pull: %w", err)
+	}
+
+	listed, err := listModels(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	if isModelListed(listed, model) {
+		return nil
+	}
+
+	return fmt.Errorf("verify: model %s not listed after the pull (listed: %s)", withDefaultTag(model), strings.Join(listed, ", "))
+}
+
+// isModelListed tells whether the model is among the listed IDs, with the default tag if it has none
+func isModelListed(listed []string, model string) bool {
+	expected := withDefaultTag(model)
+	for _, id := range listed {
+		// Hugging Face models are stored lowercased by the Model Runner
+		if strings.EqualFold(withDefaultTag(id), expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listModels returns the IDs of the models listed by the OpenAI-compatible endpoint
+func listModels(ctx context.Context, endpoint string) ([]string, error) {
+	url := strings.TrimSuffix(endpoint, "/") + "/models"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list models returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &models); err != nil {
+		return nil, fmt.Errorf("unmarshal models: %w", err)
+	}
+
+	ids := make([]string, len(models.Data))
+	for i, m := range models.Data {
+		ids[i] = m.ID
+	}
+
+	return ids, nil
+}
+
+// withDefaultTag adds the "latest" tag to the model reference, if it has none
+func withDefaultTag(model string) string {
+	name := model[strings.LastIndex(model, "/")+1:]
+	if strings.Contains(name, ":") {
+		return model
+	}
+
+	return model + ":latest"
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// flakyPuller fails the first pulls, then "pulls" the model, so the stub endpoint lists it
+type flakyPuller struct {
+	failures int
+	calls    int
+	pulled   *[]string
+}
+
+func (p *flakyPuller) PullModel(_ context.Context, model string) error {
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("unexpected EOF")
+	}
+
+	*p.pulled = append(*p.pulled, model)
+	return nil
+}
+
+func newModelsServer(t *testing.T, pulled *[]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/engines/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		body := `{"object":"list","data":[`
+		for i, m := range *pulled {
+			if i > 0 {
+				body += ","
+			}
+			body += `{"id":"` + m + `"}`
+		}
+		body += `]}`
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestPullModelVerified(t *testing.T) {
+	cfg := PullRetryConfig{Attempts: 3, Backoff: time.Millisecond}
+
+	t.Run("retry", func(t *testing.T) {
+		var pulled []string
+		srv := newModelsServer(t, &pulled)
+		puller := &flakyPuller{failures: 1, pulled: &pulled}
+
+		if err := PullModelVerified(context.Background(), puller, srv.URL+"/engines/v1", "ai/llama3.2:1B-Q4_0", cfg); err != nil {
+			t.Fatalf("pull model: %s", err)
+		}
+
+		if puller.calls != 2 {
+			t.Fatalf("expected 2 pull attempts, got %d", puller.calls)
+		}
+	})
+
+	t.Run("default-tag", func(t *testing.T) {
+		pulled := []string{"ai/smollm2:latest"}
+		srv := newModelsServer(t, &pulled)
+
+		// the model is already listed, so the pull does not need to add it
+		if err := PullModelVerified(context.Background(), &flakyPuller{pulled: new([]string)}, srv.URL+"/engines/v1", "ai/smollm2", cfg); err != nil {
+			t.Fatalf("pull model: %s", err)
+		}
+	})
+
+	t.Run("verification-failed", func(t *testing.T) {
+		var listed []string
+		srv := newModelsServer(t, &listed)
+		// the pulls succeed, but the model never shows up in the listing
+		puller := &flakyPuller{pulled: new([]string)}
+
+		err := PullModelVerified(context.Background(), puller, srv.URL+"/engines/v1", "ai/llama3.2:1B-Q4_0", cfg)
+		if err == nil {
+			t.Fatal("expected a verification error")
+		}
+
+		if puller.calls != 3 {
+			t.Fatalf("expected 3 pull attempts, got %d", puller.calls)
+		}
+	})
+}
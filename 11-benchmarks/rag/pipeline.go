@@ -0,0 +1,169 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/semconv"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SystemPrompt is the system prompt of the generation, answering from the retrieved passages
+const SystemPrompt = "You are a helpful assistant. Answer the question using only the provided context. If the context does not contain the answer, say that you don't know."
+
+// Generator generates the answer from the augmented prompt, like the llmclient.Client
+type Generator interface {
+	GenerateWithOptions(ctx context.Context, testCase string, systemPrompt, userPrompt string, opts llmclient.GenerateOptions) (*llmclient.Response, error)
+}
+
+// Pipeline answers questions embedding them, searching the store for the most similar documents
+// and generating the answer from them, timing each phase in its own span
+type Pipeline struct {
+	embedder  embeddings.Embedder
+	store     Store
+	generator Generator
+	numDocs   int
+	tracer    trace.Tracer
+}
+
+// Result contains the answer of a query, with the latency of each phase
+type Result struct {
+	Response        *llmclient.Response
+	Docs            []schema.Document
+	EmbedLatency    time.Duration // Time to embed the query
+	SearchLatency   time.Duration // Time to search the store with the query vector
+	GenerateLatency time.Duration // Time to generate the answer from the retrieved documents
+	Latency         time.Duration // End-to-end time of the query
+}
+
+// NewPipeline creates a RAG pipeline retrieving numDocs documents per query
+func NewPipeline(embedder embeddings.Embedder, store Store, generator Generator, numDocs int) *Pipeline {
+	return &Pipeline{
+		embedder:  embedder,
+		store:     store,
+		generator: generator,
+		numDocs:   numDocs,
+		tracer:    otel.Tracer("rag"),
+	}
+}
+
+// Index embeds the texts and adds them to the store
+func (p *Pipeline) Index(ctx context.Context, texts []string) error {
+	vectors, err := p.embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("embed documents: %w", err)
+	}
+
+	docs := make([]schema.Document, len(texts))
+	for i, text := range texts {
+		docs[i] = schema.Document{PageContent: text}
+	}
+
+	if err := p.store.AddDocuments(ctx, docs, vectors); err != nil {
+		return fmt.Errorf("add documents: %w", err)
+	}
+
+	return nil
+}
+
+// Query answers the question from the documents of the store. The embedding of the question,
+// the search and the generation are traced as the rag.embed_query, rag.search and rag.generate
+// spans, children of the rag.query span.
+func (p *Pipeline) Query(ctx context.Context, testCase string, question string, opts llmclient.GenerateOptions) (*Result, error) {
+	ctx, span := p.tracer.Start(ctx, "rag.query", trace.WithAttributes(
+		attribute.String(semconv.AttrCase, testCase),
+		attribute.String(semconv.AttrUserPrompt, question),
+	))
+	defer span.End()
+
+	start := time.Now()
+	result := &Result{}
+
+	var vector []float32
+	err := p.phase(ctx, "rag.embed_query", &result.EmbedLatency, func(ctx context.Context) error {
+		var err error
+		vector, err = p.embedder.EmbedQuery(ctx, question)
+		if err != nil {
+			return fmt.Errorf("embed query: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = p.phase(ctx, "rag.search", &result.SearchLatency, func(ctx context.Context) error {
+		docs, err := p.store.Search(ctx, vector, p.numDocs)
+		if err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+		result.Docs = docs
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = p.phase(ctx, "rag.generate", &result.GenerateLatency, func(ctx context.Context) error {
+		resp, err := p.generator.GenerateWithOptions(ctx, testCase, SystemPrompt, buildPrompt(question, result.Docs), opts)
+		if err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+		result.Response = resp
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result.Latency = time.Since(start)
+
+	span.SetAttributes(
+		attribute.Int("rag.docs.count", len(result.Docs)),
+		attribute.Int64(semconv.AttrLatencyMs, result.Latency.Milliseconds()),
+	)
+
+	return result, nil
+}
+
+// phase runs fn in a span with the given name, storing its duration in latency
+func (p *Pipeline) phase(ctx context.Context, name string, latency *time.Duration, fn func(ctx context.Context) error) error {
+	ctx, span := p.tracer.Start(ctx, name)
+	defer span.End()
+
+	start := time.Now()
+	err := fn(ctx)
+	*latency = time.Since(start)
+
+	span.SetAttributes(attribute.Int64(semconv.AttrLatencyMs, latency.Milliseconds()))
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// buildPrompt returns the user prompt with the numbered contents of the documents as context
+func buildPrompt(question string, docs []schema.Document) string {
+	var sb strings.Builder
+
+	sb.WriteString("Context:\n")
+	for i, doc := range docs {
+		fmt.Fprintf(&sb, "[%d] %s\n", i+1, doc.PageContent)
+	}
+
+	sb.WriteString("\nQuestion: ")
+	sb.WriteString(question)
+
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+//go:build integration
+
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/llmclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	embedDelay    = 20 * time.Millisecond
+	searchDelay   = 40 * time.Millisecond
+	generateDelay = 60 * time.Millisecond
+)
+
+// slowEmbedder embeds each text as a vector of its vowel counts, after a delay per call
+type slowEmbedder struct{}
+
+func (slowEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vecs[i] = vowels(text)
+	}
+	return vecs, nil
+}
+
+func (slowEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	time.Sleep(embedDelay)
+	return vowels(text), nil
+}
+
+func vowels(text string) []float32 {
+	vec := make([]float32, 5)
+	for _, r := range strings.ToLower(text) {
+		if i := strings.IndexRune("aeiou", r); i != -1 {
+			vec[i]++
+		}
+	}
+	return vec
+}
+
+// slowStore is a MemoryStore searching after a delay
+type slowStore struct {
+	*MemoryStore
+}
+
+func (s slowStore) Search(ctx context.Context, vector []float32, numDocs int) ([]schema.Document, error) {
+	time.Sleep(searchDelay)
+	return s.MemoryStore.Search(ctx, vector, numDocs)
+}
+
+// slowGenerator answers with the user prompt, after a delay
+type slowGenerator struct {
+	userPrompt string
+}
+
+func (g *slowGenerator) GenerateWithOptions(_ context.Context, _ string, _ string, userPrompt string, _ llmclient.GenerateOptions) (*llmclient.Response, error) {
+	time.Sleep(generateDelay)
+	g.userPrompt = userPrompt
+	return &llmclient.Response{Content: "answer", Latency: generateDelay}, nil
+}
+
+func TestPipeline_Query(t *testing.T) {
+	ctx := context.Background()
+	generator := &slowGenerator{}
+	pipeline := NewPipeline(slowEmbedder{}, slowStore{NewMemoryStore()}, generator, 1)
+
+	if err := pipeline.Index(ctx, []string{"aaaa", "eeee", "iiii"}); err != nil {
+		t.Fatalf("index: %s", err)
+	}
+
+	result, err := pipeline.Query(ctx, "rag", "eee?", llmclient.GenerateOptions{})
+	if err != nil {
+		t.Fatalf("query: %s", err)
+	}
+
+	if len(result.Docs) != 1 || result.Docs[0].PageContent != "eeee" {
+		t.Fatalf("expected the most similar document, got %v", result.Docs)
+	}
+	if !strings.Contains(generator.userPrompt, "[1] eeee") || !strings.Contains(generator.userPrompt, "eee?") {
+		t.Errorf("expected the prompt to contain the document and the question, got %q", generator.userPrompt)
+	}
+	if result.Response.Content != "answer" {
+		t.Errorf("expected the generated answer, got %q", result.Response.Content)
+	}
+
+	// Each phase takes at least its delay, but less than the next phase's one: they don't overlap
+	assertPhase(t, "embed", result.EmbedLatency, embedDelay, searchDelay)
+	assertPhase(t, "search", result.SearchLatency, searchDelay, generateDelay)
+	assertPhase(t, "generate", result.GenerateLatency, generateDelay, generateDelay+embedDelay)
+
+	phases := result.EmbedLatency + result.SearchLatency + result.GenerateLatency
+	if result.Latency < phases {
+		t.Errorf("expected the end-to-end latency %s to include the phases, %s", result.Latency, phases)
+	}
+	if result.Latency > phases+embedDelay {
+		t.Errorf("expected the end-to-end latency %s to be close to the phases, %s", result.Latency, phases)
+	}
+}
+
+func assertPhase(t *testing.T, name string, got, minimum, maximum time.Duration) {
+	t.Helper()
+
+	if got < minimum || got >= maximum {
+		t.Errorf("expected the %s latency between %s and %s, got %s", name, minimum, maximum, got)
+	}
+}
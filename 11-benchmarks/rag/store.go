@@ -0,0 +1,93 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Store is a vector store searched by the embedding of the query, instead of by the query text as
+// langchaingo's vectorstores.VectorStore, so the embedding and the search can be timed apart
+type Store interface {
+	// AddDocuments adds the documents to the store, with the vectors of their contents
+	AddDocuments(ctx context.Context, docs []schema.Document, vectors [][]float32) error
+	// Search returns the numDocs documents most similar to the vector, the most similar first
+	Search(ctx context.Context, vector []float32, numDocs int) ([]schema.Document, error)
+}
+
+// MemoryStore is an in-memory Store, comparing the vectors by cosine similarity.
+// It's safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	docs    []schema.Document
+	vectors [][]float32
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// AddDocuments adds the documents to the store, with the vectors of their contents
+func (s *MemoryStore) AddDocuments(_ context.Context, docs []schema.Document, vectors [][]float32) error {
+	if len(docs) != len(vectors) {
+		return fmt.Errorf("got %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.docs = append(s.docs, docs...)
+	s.vectors = append(s.vectors, vectors...)
+
+	return nil
+}
+
+// Search returns the numDocs documents most similar to the vector, with their similarity as score
+func (s *MemoryStore) Search(_ context.Context, vector []float32, numDocs int) ([]schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found := make([]schema.Document, len(s.docs))
+	for i, doc := range s.docs {
+		if len(s.vectors[i]) != len(vector) {
+			return nil, fmt.Errorf("the query vector has %d dimensions, but the documents have %d", len(vector), len(s.vectors[i]))
+		}
+
+		doc.Score = cosineSimilarity(vector, s.vectors[i])
+		found[i] = doc
+	}
+
+	slices.SortStableFunc(found, func(a, b schema.Document) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return found[:min(numDocs, len(found))], nil
+}
+
+// cosineSimilarity returns the cosine of the angle between the vectors, zero if any of them is null
+func cosineSimilarity(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
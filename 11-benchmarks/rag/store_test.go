@@ -0,0 +1,53 @@
+package rag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	docs := []schema.Document{{PageContent: "x"}, {PageContent: "y"}, {PageContent: "xy"}}
+	vectors := [][]float32{{1, 0}, {0, 1}, {1, 1}}
+	if err := store.AddDocuments(ctx, docs, vectors); err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+
+	found, err := store.Search(ctx, []float32{1, 0.1}, 2)
+	if err != nil {
+		t.Fatalf("search: %s", err)
+	}
+
+	if len(found) != 2 || found[0].PageContent != "x" || found[1].PageContent != "xy" {
+		t.Fatalf("expected the two most similar documents, got %v", found)
+	}
+	if found[0].Score <= found[1].Score {
+		t.Errorf("expected decreasing scores, got %f and %f", found[0].Score, found[1].Score)
+	}
+
+	t.Run("more-than-stored", func(t *testing.T) {
+		found, err := store.Search(ctx, []float32{1, 0}, 10)
+		if err != nil {
+			t.Fatalf("search: %s", err)
+		}
+		if len(found) != 3 {
+			t.Errorf("expected all the documents, got %d", len(found))
+		}
+	})
+
+	t.Run("dimension-mismatch", func(t *testing.T) {
+		if _, err := store.Search(ctx, []float32{1, 0, 0}, 1); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("vector-count-mismatch", func(t *testing.T) {
+		if err := store.AddDocuments(ctx, docs, vectors[:1]); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// VectorStoreFactory creates a langchaingo vector store embedding the documents and the queries with the embedder
+type VectorStoreFactory func(ctx context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error)
+
+// VectorStore adapts a langchaingo vector store, like the stores of the examples, to a Store.
+// The vector store embeds the texts itself, so it's created with an embedder returning the vectors
+// already computed by the pipeline: the search phase doesn't call the embedding model again.
+// The calls are serialized, as the vectors are handed to the embedder before each of them.
+type VectorStore struct {
+	mu       sync.Mutex
+	store    vectorstores.VectorStore
+	embedder *vectorEmbedder
+	options  []vectorstores.Option
+}
+
+// NewVectorStore creates a Store from the vector store created by newStore. The options are passed
+// to every call of the vector store, e.g. the namespace isolating the documents of the benchmark.
+func NewVectorStore(ctx context.Context, newStore VectorStoreFactory, options ...vectorstores.Option) (*VectorStore, error) {
+	embedder := &vectorEmbedder{}
+
+	store, err := newStore(ctx, embedder)
+	if err != nil {
+		return nil, fmt.Errorf("new vector store: %w", err)
+	}
+
+	return &VectorStore{store: store, embedder: embedder, options: options}, nil
+}
+
+// AddDocuments adds the documents to the vector store, with the vectors of their contents
+func (s *VectorStore) AddDocuments(ctx context.Context, docs []schema.Document, vectors [][]float32) error {
+	if len(docs) != len(vectors) {
+		return fmt.Errorf("got %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.embedder.documents = vectors
+	defer func() { s.embedder.documents = nil }()
+
+	if _, err := s.store.AddDocuments(ctx, docs, s.options...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Search returns the numDocs documents most similar to the vector, as scored by the vector store
+func (s *VectorStore) Search(ctx context.Context, vector []float32, numDocs int) ([]schema.Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.embedder.query = vector
+	defer func() { s.embedder.query = nil }()
+
+	// the query text is ignored, as the embedder returns the vector
+	return s.store.SimilaritySearch(ctx, "", numDocs, s.options...)
+}
+
+// Close releases the resources of the vector store, if it has any
+func (s *VectorStore) Close() error {
+	if closer, ok := s.store.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// vectorEmbedder returns the vectors computed by the pipeline instead of embedding the texts
+type vectorEmbedder struct {
+	documents [][]float32
+	query     []float32
+}
+
+func (e *vectorEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	if len(texts) != len(e.documents) {
+		return nil, fmt.Errorf("got %d texts to embed, but %d vectors", len(texts), len(e.documents))
+	}
+
+	return e.documents, nil
+}
+
+func (e *vectorEmbedder) EmbedQuery(_ context.Context, _ string) ([]float32, error) {
+	if e.query == nil {
+		return nil, errors.New("no query vector")
+	}
+
+	return e.query, nil
+}
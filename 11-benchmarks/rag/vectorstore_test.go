@@ -0,0 +1,109 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// embeddingVectorStore is a langchaingo vector store embedding the texts with its embedder, like
+// the stores of the examples, keeping the documents in a MemoryStore
+type embeddingVectorStore struct {
+	embedder   embeddings.Embedder
+	memory     *MemoryStore
+	namespaces []string
+	closed     bool
+}
+
+func (s *embeddingVectorStore) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) {
+	s.recordNamespace(options)
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+
+	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.memory.AddDocuments(ctx, docs, vectors)
+}
+
+func (s *embeddingVectorStore) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) {
+	s.recordNamespace(options)
+
+	vector, err := s.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memory.Search(ctx, vector, numDocuments)
+}
+
+func (s *embeddingVectorStore) recordNamespace(options []vectorstores.Option) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	s.namespaces = append(s.namespaces, opts.NameSpace)
+}
+
+func (s *embeddingVectorStore) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestVectorStore(t *testing.T) {
+	ctx := context.Background()
+
+	var backend *embeddingVectorStore
+	store, err := NewVectorStore(ctx, func(_ context.Context, embedder embeddings.Embedder) (vectorstores.VectorStore, error) {
+		backend = &embeddingVectorStore{embedder: embedder, memory: NewMemoryStore()}
+		return backend, nil
+	}, vectorstores.WithNameSpace("benchmarks"))
+	if err != nil {
+		t.Fatalf("new vector store: %s", err)
+	}
+
+	docs := []schema.Document{{PageContent: "x"}, {PageContent: "y"}, {PageContent: "xy"}}
+	vectors := [][]float32{{1, 0}, {0, 1}, {1, 1}}
+	if err := store.AddDocuments(ctx, docs, vectors); err != nil {
+		t.Fatalf("add documents: %s", err)
+	}
+
+	// The vector store searches by the vector of the pipeline, not by embedding the query again
+	found, err := store.Search(ctx, []float32{1, 0.1}, 2)
+	if err != nil {
+		t.Fatalf("search: %s", err)
+	}
+	if len(found) != 2 || found[0].PageContent != "x" || found[1].PageContent != "xy" {
+		t.Fatalf("expected the two most similar documents, got %v", found)
+	}
+
+	if len(backend.namespaces) != 2 || backend.namespaces[0] != "benchmarks" || backend.namespaces[1] != "benchmarks" {
+		t.Errorf("expected the options in every call, got namespaces %v", backend.namespaces)
+	}
+
+	if err := store.AddDocuments(ctx, docs, vectors[:1]); err == nil {
+		t.Error("expected an error for a vector count mismatch")
+	}
+
+	if err := store.Close(); err != nil || !backend.closed {
+		t.Errorf("expected the vector store to be closed, got closed %t and error %v", backend.closed, err)
+	}
+
+	t.Run("factory-error", func(t *testing.T) {
+		_, err := NewVectorStore(ctx, func(context.Context, embeddings.Embedder) (vectorstores.VectorStore, error) {
+			return nil, errors.New("container not running")
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
+)
+
+// ErrPanic is returned when a benchmark operation panics, e.g. on a nil pointer handling a response
+var ErrPanic = errors.New("panic")
+
+// runRecovered runs the operation, returning its panic as an error wrapping ErrPanic, with the
+// stack trace, so a crash in a single iteration is counted as a failure instead of aborting the
+// whole run and losing the results of the other models and cases
+func runRecovered[T any](op func() T) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrPanic, r, debug.Stack())
+		}
+	}()
+
+	return op(), nil
+}
+
+// errorReporter marks a benchmark as failed, implemented by *testing.B
+type errorReporter interface {
+	Name() string
+	Errorf(format string, args ...any)
+}
+
+// recoverBenchmark recovers the panic of a sub-benchmark, logging it and marking the sub-benchmark as
+// failed, so the run continues with the next one. Defer it first in the functions passed to b.Run.
+func recoverBenchmark(b errorReporter) {
+	if r := recover(); r != nil {
+		logging.Default().Error("💥 Benchmark panicked, continuing with the next one", "benchmark", b.Name(), "panic", r, "stack", string(debug.Stack()))
+		b.Errorf("%s: %v", ErrPanic, r)
+	}
+}
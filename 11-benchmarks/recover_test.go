@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// panickingClient crashes on the requests listed in panicOn, like a nil response being dereferenced
+type panickingClient struct {
+	panicOn map[int]bool
+	calls   int
+}
+
+func (c *panickingClient) Generate() string {
+	call := c.calls
+	c.calls++
+	if c.panicOn[call] {
+		var resp *struct{ Content string }
+		return resp.Content
+	}
+	return fmt.Sprintf("response %d", call)
+}
+
+func TestRunRecovered(t *testing.T) {
+	client := &panickingClient{panicOn: map[int]bool{1: true}}
+	var responses []string
+	failures := 0
+
+	timer := &fakeBenchTimer{}
+	timer.StartTimer()
+	runTimedIterations(timer, 4, func(i int) string {
+		response, err := runRecovered(client.Generate)
+		if err != nil {
+			if !errors.Is(err, ErrPanic) || !strings.Contains(err.Error(), "nil pointer") {
+				t.Errorf("expected the panic as an error, got %v", err)
+			}
+			failures++
+			return ""
+		}
+		return response
+	}, func(i int, response string) {
+		responses = append(responses, response)
+	})
+	timer.StopTimer()
+
+	// The run continues after the panic, which counts as a failure
+	if client.calls != 4 || len(responses) != 4 {
+		t.Fatalf("expected the 4 iterations to run, got %d calls and %d responses", client.calls, len(responses))
+	}
+	if failures != 1 || responses[1] != "" || responses[3] != "response 3" {
+		t.Errorf("expected only the second iteration to fail, got %d failures and %q", failures, responses)
+	}
+}
+
+// fakeErrorReporter records the errors of a benchmark
+type fakeErrorReporter struct {
+	errors []string
+}
+
+func (f *fakeErrorReporter) Name() string {
+	return "BenchmarkLLMs/llama3.2/code-explanation/temp0.1"
+}
+
+func (f *fakeErrorReporter) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecoverBenchmark(t *testing.T) {
+	b := &fakeErrorReporter{}
+
+	ran := []string{}
+	for _, name := range []string{"crashing", "next"} {
+		func() {
+			defer recoverBenchmark(b)
+
+			if name == "crashing" {
+				panic("unexpected response")
+			}
+			ran = append(ran, name)
+		}()
+	}
+
+	if len(ran) != 1 || ran[0] != "next" {
+		t.Errorf("expected the next benchmark to run after the panic, got %v", ran)
+	}
+	if len(b.errors) != 1 || !strings.Contains(b.errors[0], "unexpected response") {
+		t.Errorf("expected the panic to fail the benchmark, got %v", b.errors)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRefusalPatterns are the case-insensitive patterns of the usual refusals of the models
+var DefaultRefusalPatterns = []string{
+	`(?i)\bI (cannot|can't|can not|won't|am unable to|am not able to)\b`,
+	`(?i)\bI'm (unable|not able) to\b`,
+	`(?i)\bas an AI\b`,
+	`(?i)\b(I'm|I am) sorry, but\b`,
+	`(?i)\bI (must|have to) decline\b`,
+}
+
+// refusalPrefixLen is the length in bytes of the beginning of the response the patterns are matched
+// against: the models refuse upfront, while a useful answer may say "I cannot stress this enough".
+const refusalPrefixLen = 200
+
+// RefusalDetector classifies the successful responses that are not useful answers: the empty
+// ones and the ones refusing to answer, which would otherwise inflate the success rate
+type RefusalDetector struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRefusalDetector returns a detector of the responses starting with one of the patterns
+func NewRefusalDetector(patterns []string) (*RefusalDetector, error) {
+	d := &RefusalDetector{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile refusal pattern %q: %w", pattern, err)
+		}
+		d.patterns = append(d.patterns, re)
+	}
+
+	return d, nil
+}
+
+// IsRefusal reports whether the response is empty, or refuses to answer in its beginning
+func (d *RefusalDetector) IsRefusal(response string) bool {
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return true
+	}
+
+	prefix := response
+	if len(prefix) > refusalPrefixLen {
+		prefix = prefix[:refusalPrefixLen]
+		// Don't cut a multi-byte character in half
+		for !utf8.ValidString(prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	for _, re := range d.patterns {
+		if re.MatchString(prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRefusalDetector returns the detector of the patterns in the file of the BENCH_REFUSAL_PATTERNS_FILE
+// environment variable, one regular expression per line, or of DefaultRefusalPatterns if it's not set.
+// Empty lines and lines starting with # are ignored.
+func getRefusalDetector() (*RefusalDetector, error) {
+	path := os.Getenv("BENCH_REFUSAL_PATTERNS_FILE")
+	if path == "" {
+		return NewRefusalDetector(DefaultRefusalPatterns)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open refusal patterns: %w", err)
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read refusal patterns: %w", err)
+	}
+
+	return NewRefusalDetector(patterns)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRefusalDetector(t *testing.T) {
+	detector, err := NewRefusalDetector(DefaultRefusalPatterns)
+	if err != nil {
+		t.Fatalf("new refusal detector: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		refusal  bool
+	}{
+		{name: "empty", response: "", refusal: true},
+		{name: "whitespace", response: " \n\t", refusal: true},
+		{name: "cannot", response: "I cannot help with writing that code.", refusal: true},
+		{name: "cant", response: "I can't provide information about that.", refusal: true},
+		{name: "as-an-ai", response: "As an AI language model, I don't have opinions on history.", refusal: true},
+		{name: "sorry-but", response: "I'm sorry, but I am unable to assist with this request.", refusal: true},
+		{name: "unable", response: "I'm unable to calculate that.", refusal: true},
+		{name: "answer", response: "The sum of all numbers between 1 and 100 is 5050.", refusal: false},
+		{name: "code", response: "```go\nfunc fib(n int) int {\n\tif n < 2 {\n\t\treturn n\n\t}\n\treturn fib(n-1) + fib(n-2)\n}\n```", refusal: false},
+		{
+			// The pattern deep in a useful answer is not a refusal
+			name:     "pattern-late-in-the-answer",
+			response: "Toledo was a center of translation in the 12th century. " + strings.Repeat("Scholars translated Arabic texts into Latin. ", 10) + "I cannot overstate its importance.",
+			refusal:  false,
+		},
+		{name: "multi-byte-prefix", response: strings.Repeat("ñ", 150), refusal: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detector.IsRefusal(tt.response); got != tt.refusal {
+				t.Errorf("expected refusal %t, got %t", tt.refusal, got)
+			}
+		})
+	}
+
+	if _, err := NewRefusalDetector([]string{"(unclosed"}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestGetRefusalDetector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "refusals.txt")
+	if err := os.WriteFile(path, []byte("# Refusals of our models\n\n(?i)^no comment\n"), 0o644); err != nil {
+		t.Fatalf("write patterns: %s", err)
+	}
+	t.Setenv("BENCH_REFUSAL_PATTERNS_FILE", path)
+
+	detector, err := getRefusalDetector()
+	if err != nil {
+		t.Fatalf("get refusal detector: %s", err)
+	}
+
+	if !detector.IsRefusal("No comment.") {
+		t.Error("expected the custom pattern to match")
+	}
+	if detector.IsRefusal("I cannot help with that.") {
+		t.Error("expected the custom patterns to replace the default ones")
+	}
+}
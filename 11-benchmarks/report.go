@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GenerateMarkdownReport writes the aggregate metrics as a markdown report, to share the results
+// in pull requests or docs: a header with the CPU model and Go version of the benchmark resource,
+// followed by a table with the key metrics of each model/case/temp combination, sorted by model,
+// test case and temperature.
+func GenerateMarkdownReport(aggregates map[string]*AggregateMetrics, w io.Writer) error {
+	res, err := newBenchmarkResource()
+	if err != nil {
+		return fmt.Errorf("benchmark resource: %w", err)
+	}
+
+	attrs := res.Set()
+	resourceValue := func(key string) string {
+		if v, ok := attrs.Value(attribute.Key(key)); ok {
+			return v.Emit()
+		}
+		return "unknown"
+	}
+
+	rows := make([]AggregateMetrics, 0, len(aggregates))
+	for _, agg := range aggregates {
+		rows = append(rows, *agg)
+	}
+	sortAggregates(rows)
+
+	var sb strings.Builder
+	sb.WriteString("# LLM Benchmark Report\n\n")
+	fmt.Fprintf(&sb, "- **CPU**: %s\n", resourceValue("cpu.model"))
+	fmt.Fprintf(&sb, "- **Go**: %s (%s/%s)\n", resourceValue("go.version"), resourceValue("go.os"), resourceValue("go.arch"))
+	sb.WriteString("\n")
+
+	if len(rows) == 0 {
+		sb.WriteString("No benchmark results.\n")
+	} else {
+		sb.WriteString("| Model | Case | Temp | Latency P50 (ms) | Latency P95 (ms) | TTFT P50 (ms) | TPS | Success Rate | Eval Score | Eval Categories |\n")
+		sb.WriteString("|---|---|---:|---:|---:|---:|---:|---:|---:|---|\n")
+		for _, agg := range rows {
+			fmt.Fprintf(&sb, "| %s | %s | %.1f | %.0f | %.0f | %.0f | %.1f | %.0f%% | %.2f | %s |\n",
+				markdownEscape(agg.Model), markdownEscape(agg.TestCase), agg.Temp,
+				agg.LatencyP50, agg.LatencyP95, agg.TTFTP50,
+				agg.TokensPerSec,
+				agg.SuccessRate*100,
+				agg.EvalScore,
+				formatCategoryRates(agg.CategoryRates),
+			)
+		}
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// formatCategoryRates lists the categories of the evaluated responses with their rates, e.g.
+// "correct 75%, hallucination 25%", or "-" if the evaluator did not classify any
+func formatCategoryRates(rates map[evaluator.Category]float64) string {
+	var parts []string
+	for _, category := range evaluator.Categories {
+		if rate := rates[category]; rate > 0 {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", category, rate*100))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// markdownEscape escapes the pipes of a table cell, e.g. in model names
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/evaluator"
+)
+
+func TestGenerateMarkdownReport(t *testing.T) {
+	aggregates := map[string]*AggregateMetrics{
+		"ai/qwen3:0.6B-Q4_0|code-generation|0.1": {
+			Model: "ai/qwen3:0.6B-Q4_0", TestCase: "code-generation", Temp: 0.1,
+			LatencyP50: 900, LatencyP95: 1500, TTFTP50: 120, TokensPerSec: 85.25, SuccessRate: 0.5, EvalScore: 0.4,
+			CategoryRates: map[evaluator.Category]float64{evaluator.CategoryCorrect: 0.25, evaluator.CategoryHallucination: 0.75},
+		},
+		"ai/llama3.2:1B-Q4_0|code-generation|0.1": {
+			Model: "ai/llama3.2:1B-Q4_0", TestCase: "code-generation", Temp: 0.1,
+			LatencyP50: 1100, LatencyP95: 2000, TTFTP50: 150, TokensPerSec: 58, SuccessRate: 1, EvalScore: 0.8,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateMarkdownReport(aggregates, &buf); err != nil {
+		t.Fatalf("generate report: %s", err)
+	}
+	report := buf.String()
+
+	for _, expected := range []string{
+		"- **Go**: " + runtime.Version(),
+		"- **CPU**: ",
+		"| Model | Case | Temp | Latency P50 (ms) | Latency P95 (ms) | TTFT P50 (ms) | TPS | Success Rate | Eval Score | Eval Categories |",
+		"| ai/llama3.2:1B-Q4_0 | code-generation | 0.1 | 1100 | 2000 | 150 | 58.0 | 100% | 0.80 | - |",
+		"| ai/qwen3:0.6B-Q4_0 | code-generation | 0.1 | 900 | 1500 | 120 | 85.2 | 50% | 0.40 | correct 25%, hallucination 75% |",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("expected the report to contain %q:\n%s", expected, report)
+		}
+	}
+
+	// Sorted by model
+	if strings.Index(report, "ai/llama3.2") > strings.Index(report, "ai/qwen3") {
+		t.Errorf("expected the rows sorted by model:\n%s", report)
+	}
+}
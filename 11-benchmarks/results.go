@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+)
+
+// ResultsRecorder keeps the results of each benchmark to export them once the run completes. The
+// benchmark function runs again with a growing b.N until the timing is stable: only the results of
+// its last run, the reported one, are kept.
+type ResultsRecorder struct {
+	mu          sync.Mutex
+	benchmarks  []string // Names of the benchmarks, in the order they first ran
+	byBenchmark map[string][]benchresult.Result
+}
+
+// NewResultsRecorder returns an empty ResultsRecorder
+func NewResultsRecorder() *ResultsRecorder {
+	return &ResultsRecorder{byBenchmark: make(map[string][]benchresult.Result)}
+}
+
+// Record replaces the results of the benchmark
+func (r *ResultsRecorder) Record(benchmark string, results []benchresult.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byBenchmark[benchmark]; !ok {
+		r.benchmarks = append(r.benchmarks, benchmark)
+	}
+	r.byBenchmark[benchmark] = results
+}
+
+// Results returns the recorded results of all the benchmarks, in the order they first ran
+func (r *ResultsRecorder) Results() []benchresult.Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var all []benchresult.Result
+	for _, benchmark := range r.benchmarks {
+		all = append(all, r.byBenchmark[benchmark]...)
+	}
+
+	return all
+}
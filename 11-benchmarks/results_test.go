@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/benchresult"
+)
+
+func TestResultsRecorder(t *testing.T) {
+	recorder := NewResultsRecorder()
+
+	if got := recorder.Results(); len(got) != 0 {
+		t.Fatalf("expected no results, got %+v", got)
+	}
+
+	// The benchmark runs again with a growing b.N: the last run replaces the previous ones
+	recorder.Record("BenchmarkLLM/llama/factual-question/temp0.1", []benchresult.Result{{TestCase: "factual-question", Success: false}})
+	recorder.Record("BenchmarkLLM/llama/code-generation/temp0.1", []benchresult.Result{{TestCase: "code-generation", Success: true}})
+	recorder.Record("BenchmarkLLM/llama/factual-question/temp0.1", []benchresult.Result{
+		{TestCase: "factual-question", Success: true},
+		{TestCase: "factual-question", Success: true},
+	})
+
+	expected := []benchresult.Result{
+		{TestCase: "factual-question", Success: true},
+		{TestCase: "factual-question", Success: true},
+		{TestCase: "code-generation", Success: true},
+	}
+	if got := recorder.Results(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
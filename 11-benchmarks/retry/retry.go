@@ -0,0 +1,89 @@
+// Package retry retries the operations failing for transient reasons, such as the model pulls or
+// the requests of the tools to external APIs, with exponential backoff and jitter.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy configures the retries of Do
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Values below 1 mean 1.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled on each retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between the attempts, zero for no cap
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the wait randomized, from 0 to 1: 0.2 waits between 80% and 120%
+	// of the backoff, so the clients failing at the same time don't retry at the same time
+	Jitter float64
+	// Retryable reports whether the error is worth retrying, nil to retry all of them
+	Retryable func(error) bool
+	// OnRetry is called before waiting to retry the failed attempt, e.g. to log it, if not nil
+	OnRetry func(attempt int, wait time.Duration, err error)
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable, or the attempts are
+// exhausted, waiting between the attempts with exponential backoff. The errors of all the attempts
+// are returned together, with the error of the context when it's done while waiting.
+func Do(ctx context.Context, policy Policy, fn func() error) error {
+	attempts := max(policy.MaxAttempts, 1)
+	backoff := policy.InitialBackoff
+
+	var errs []error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return errors.Join(errs...)
+		}
+		if attempt == attempts {
+			break
+		}
+
+		wait := policy.wait(backoff)
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(append(errs, ctx.Err())...)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 {
+			backoff = min(backoff, policy.MaxBackoff)
+		}
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", attempts, errors.Join(errs...))
+}
+
+// wait returns the backoff, capped and randomized by the jitter of the policy
+func (p Policy) wait(backoff time.Duration) time.Duration {
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, p.MaxBackoff)
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter == 0 || backoff <= 0 {
+		return backoff
+	}
+
+	// Uniformly distributed in [1-jitter, 1+jitter)
+	factor := 1 - jitter + 2*jitter*rand.Float64()
+
+	return time.Duration(float64(backoff) * factor)
+}
@@ -0,0 +1,152 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+// failing returns an operation failing with the errors in order, then succeeding, counting its calls
+func failing(calls *int, errs ...error) func() error {
+	return func() error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("success-after-retry", func(t *testing.T) {
+		var calls int
+		var retries []int
+		p := policy
+		p.OnRetry = func(attempt int, _ time.Duration, err error) {
+			if !errors.Is(err, errTransient) {
+				t.Errorf("expected the error of the attempt, got %v", err)
+			}
+			retries = append(retries, attempt)
+		}
+
+		if err := Do(context.Background(), p, failing(&calls, errTransient, errTransient)); err != nil {
+			t.Fatalf("expected the third attempt to succeed, got %s", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 attempts, got %d", calls)
+		}
+		if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+			t.Errorf("expected retries after the attempts 1 and 2, got %v", retries)
+		}
+	})
+
+	t.Run("exhaustion", func(t *testing.T) {
+		var calls int
+		err := Do(context.Background(), policy, failing(&calls, errTransient, errTransient, errTransient, errTransient))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 attempts, got %d", calls)
+		}
+		if !errors.Is(err, errTransient) {
+			t.Errorf("expected the errors of the attempts, got %s", err)
+		}
+		for _, expected := range []string{"failed after 3 attempts", "attempt 1: transient", "attempt 3: transient"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected %q in the error, got %s", expected, err)
+			}
+		}
+	})
+
+	t.Run("context-cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := Policy{MaxAttempts: 5, InitialBackoff: time.Hour, OnRetry: func(int, time.Duration, error) { cancel() }}
+
+		var calls int
+		start := time.Now()
+		err := Do(ctx, p, failing(&calls, errTransient, errTransient))
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, errTransient) {
+			t.Fatalf("expected the cancellation and the error of the attempt, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected no attempt after the cancellation, got %d", calls)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected the wait to stop on cancellation, took %s", elapsed)
+		}
+	})
+
+	t.Run("retryable-predicate", func(t *testing.T) {
+		errPermanent := errors.New("permanent")
+		p := policy
+		p.Retryable = func(err error) bool { return errors.Is(err, errTransient) }
+
+		var calls int
+		err := Do(context.Background(), p, failing(&calls, errTransient, errPermanent, errTransient))
+		if !errors.Is(err, errPermanent) {
+			t.Fatalf("expected the permanent error, got %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected to stop at the permanent error, got %d attempts", calls)
+		}
+	})
+
+	t.Run("single-attempt", func(t *testing.T) {
+		var calls int
+		if err := Do(context.Background(), Policy{}, failing(&calls, errTransient)); err == nil {
+			t.Fatal("expected an error")
+		}
+		if calls != 1 {
+			t.Errorf("expected a single attempt without max attempts, got %d", calls)
+		}
+	})
+}
+
+func TestPolicyWait(t *testing.T) {
+	t.Run("capped", func(t *testing.T) {
+		p := Policy{MaxBackoff: time.Second}
+		if wait := p.wait(time.Minute); wait != time.Second {
+			t.Errorf("expected the wait capped to 1s, got %s", wait)
+		}
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		p := Policy{Jitter: 0.2}
+		for range 100 {
+			if wait := p.wait(time.Second); wait < 800*time.Millisecond || wait >= 1200*time.Millisecond {
+				t.Fatalf("expected the wait within 20%% of 1s, got %s", wait)
+			}
+		}
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		var waits []time.Duration
+		p := Policy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     4 * time.Millisecond,
+			OnRetry:        func(_ int, wait time.Duration, _ error) { waits = append(waits, wait) },
+		}
+
+		var calls int
+		_ = Do(context.Background(), p, failing(&calls, errTransient, errTransient, errTransient, errTransient, errTransient))
+
+		expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}
+		if len(waits) != len(expected) {
+			t.Fatalf("expected %d waits, got %v", len(expected), waits)
+		}
+		for i := range expected {
+			if waits[i] != expected[i] {
+				t.Errorf("expected the waits %v, got %v", expected, waits)
+				break
+			}
+		}
+	})
+}
@@ -16,33 +16,62 @@ const (
 	MetricLLMPromptEvalTime        = "llm.prompt_eval_time"
 	MetricLLMPromptEvalTimeP50     = "llm.prompt_eval_time.p50"
 	MetricLLMPromptEvalTimeP95     = "llm.prompt_eval_time.p95"
+	MetricLLMInterTokenLatency     = "llm.inter_token_latency"
+	MetricLLMCompletionTokens      = "llm.completion_tokens"
 	MetricLLMSuccessRate           = "llm.success_rate"
 	MetricLLMTokensPerOp           = "llm.tokens_per_op"
 	MetricLLMEvalScore             = "llm.eval_score"
 	MetricLLMEvalPassRate          = "llm.eval_pass_rate"
+	MetricLLMEvalCategoryRate      = "llm.eval_category_rate"
 	MetricLLMTokensPerSecond       = "llm.tokens_per_second"
 	MetricLLMOutputTokensPerSecond = "llm.output_tokens_per_second"
 	MetricLLMNsPerOp               = "llm.ns_per_op"
+	MetricLLMColdStart             = "llm.cold_start"
+	MetricLLMModelMemory           = "llm.model_memory_mb"
+	MetricLLMPromptCacheSpeedup    = "llm.prompt_cache_speedup"
+	MetricLLMRefusalRate           = "llm.refusal_rate"
+	MetricLLMThroughputLatencyP95  = "llm.throughput.latency.p95"
+	MetricLLMThroughputRPS         = "llm.throughput.rps"
+	MetricLLMMaxSustainableRPS     = "llm.max_sustainable_rps"
+	MetricRAGLatency               = "rag.latency_ms"
 	MetricGPUUtilization           = "gpu.utilization"
 	MetricGPUMemory                = "gpu.memory"
 
 	// Attribute keys - Metrics
-	AttrModel   = "model"
-	AttrCase    = "case"
-	AttrTemp    = "temp"
-	AttrTraceID = "trace_id"
-	AttrSpanID  = "span_id"
+	AttrModel       = "model"
+	AttrCase        = "case"
+	AttrTemp        = "temp"
+	AttrStore       = "store"
+	AttrConcurrency = "concurrency"
+	AttrCategory    = "category"
+	AttrTraceID     = "trace_id"
+	AttrSpanID      = "span_id"
+
+	// Model attributes parsed from the model tag, e.g. ai/llama3.2:3B-Q4_K_M, to group the panels by size or quantization
+	AttrModelParams = "params"
+	AttrModelQuant  = "quant"
+
+	// AttrInferenceBackend tells if the models run on the GPU or the CPU, set on the resource and all the metrics
+	AttrInferenceBackend = "inference.backend"
 
 	// Attribute keys - Spans (OpenTelemetry tracing)
 	AttrSystemPrompt     = "system_prompt"
 	AttrUserPrompt       = "user_prompt"
 	AttrTemperature      = "temperature"
+	AttrSeed             = "seed"
+	AttrStream           = "stream"
+	AttrImageMimeType    = "image_mime_type"
+	AttrImageBytes       = "image_bytes"
 	AttrPromptTokens     = "prompt_tokens"
 	AttrCompletionTokens = "completion_tokens"
 	AttrTotalTokens      = "total_tokens"
 	AttrLatencyMs        = "latency_ms"
 	AttrTTFTMs           = "ttft_ms"
 	AttrPromptEvalTimeMs = "prompt_eval_time_ms"
+	AttrInterTokenP50Ms  = "inter_token_p50_ms"
+	AttrInterTokenP95Ms  = "inter_token_p95_ms"
+	AttrRefusal          = "refusal"
+	AttrStat             = "stat"
 
 	// Metric units
 	UnitMilliseconds = "ms"
@@ -59,13 +88,24 @@ const (
 	DescLLMPromptEvalTime        = "Prompt evaluation time from model metadata in seconds"
 	DescLLMPromptEvalTimeP50     = "50th percentile prompt evaluation time in seconds"
 	DescLLMPromptEvalTimeP95     = "95th percentile prompt evaluation time in seconds"
+	DescLLMInterTokenLatency     = "Time between consecutive streamed tokens per request (p50/p95) in milliseconds"
+	DescLLMCompletionTokens      = "Output tokens generated per request"
 	DescLLMSuccessRate           = "Success rate of LLM requests"
 	DescLLMTokensPerOp           = "Total tokens per operation"
 	DescLLMEvalScore             = "Average evaluator score (0.0-1.0) per operation"
 	DescLLMEvalPassRate          = "Percentage of responses marked as 'yes' by evaluator"
+	DescLLMEvalCategoryRate      = "Rate of the evaluated responses classified in each category by the evaluator, e.g. hallucination"
 	DescLLMTokensPerSecond       = "Total tokens per second (input + output / TAT)"
 	DescLLMOutputTokensPerSecond = "Output tokens per second (generation speed only)"
 	DescLLMNsPerOp               = "Nanoseconds per operation (Go benchmark metric)"
+	DescLLMColdStart             = "Latency of the first request to a model, including model loading, in milliseconds"
+	DescLLMModelMemory           = "Host memory growth attributable to loading a model, in MB"
+	DescLLMPromptCacheSpeedup    = "Prompt-eval time of the first request with a system prompt divided by the median of the following ones"
+	DescLLMRefusalRate           = "Rate of the successful requests answered with an empty response or a refusal"
+	DescLLMThroughputLatencyP95  = "95th percentile latency of the requests at each concurrency of the throughput sweep, in milliseconds"
+	DescLLMThroughputRPS         = "Requests per second completed at each concurrency of the throughput sweep"
+	DescLLMMaxSustainableRPS     = "Highest requests per second of the throughput sweep with the p95 latency under the SLO"
+	DescRAGLatency               = "End-to-end latency of RAG queries (query embedding + search + generation) in milliseconds"
 	DescGPUUtilization           = "GPU utilization percentage"
 	DescGPUMemory                = "GPU memory usage in MB"
 )
@@ -0,0 +1,45 @@
+// Package stats computes the percentiles of the benchmark samples, shared by the benchmarks and the
+// commands comparing their results.
+package stats
+
+import "math"
+
+// PercentileMinSamples returns the number of samples needed for at least one of them to lie above
+// the nth percentile, e.g. 2 for the p50 and 20 for the p95. With fewer samples, the percentile is
+// not distinguishable from the maximum, and interpolating between the top samples understates it.
+func PercentileMinSamples(p int) int {
+	if p >= 100 {
+		return 1
+	}
+
+	return int(math.Ceil(100 / float64(100-p)))
+}
+
+// LowConfidencePercentile tells whether there are too few samples to estimate the nth percentile
+func LowConfidencePercentile(n, p int) bool {
+	return n < PercentileMinSamples(p)
+}
+
+// Percentile calculates the nth percentile of a sorted slice, interpolating linearly between the
+// closest ranks. With fewer samples than PercentileMinSamples, it returns the maximum, as the
+// samples can't tell the percentile apart from it, e.g. the p95 of 2 samples.
+func Percentile(sorted []float64, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	if LowConfidencePercentile(len(sorted), p) {
+		return sorted[len(sorted)-1]
+	}
+
+	index := (float64(p) / 100.0) * float64(len(sorted)-1)
+	lower := int(index)
+	upper := lower + 1
+
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	weight := index - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
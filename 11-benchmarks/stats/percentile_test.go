@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	ramp := make([]float64, 100)
+	for i := range ramp {
+		ramp[i] = float64(i + 1)
+	}
+
+	tests := []struct {
+		name          string
+		sorted        []float64
+		p             int
+		expected      float64
+		lowConfidence bool
+	}{
+		{name: "empty", sorted: nil, p: 50, expected: 0, lowConfidence: true},
+		{name: "n=1/p50", sorted: []float64{120}, p: 50, expected: 120, lowConfidence: true},
+		{name: "n=1/p95", sorted: []float64{120}, p: 95, expected: 120, lowConfidence: true},
+		{name: "n=2/p50", sorted: []float64{100, 200}, p: 50, expected: 150},
+		// The top sample is the only evidence of the tail: the max is reported instead of interpolating
+		{name: "n=2/p95", sorted: []float64{100, 200}, p: 95, expected: 200, lowConfidence: true},
+		{name: "n=19/p95", sorted: ramp[:19], p: 95, expected: 19, lowConfidence: true},
+		{name: "n=20/p95", sorted: ramp[:20], p: 95, expected: 19.05},
+		{name: "n=100/p50", sorted: ramp, p: 50, expected: 50.5},
+		{name: "n=100/p95", sorted: ramp, p: 95, expected: 95.05},
+		{name: "n=100/p100", sorted: ramp, p: 100, expected: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.sorted, tt.p); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected the p%d to be %f, got %f", tt.p, tt.expected, got)
+			}
+			if got := LowConfidencePercentile(len(tt.sorted), tt.p); got != tt.lowConfidence {
+				t.Errorf("expected low confidence %t, got %t", tt.lowConfidence, got)
+			}
+		})
+	}
+}
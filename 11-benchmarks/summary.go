@@ -0,0 +1,59 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+// Aggregates returns a copy of the aggregate metrics, sorted by model, test case and temperature
+func (mc *MetricsCollector) Aggregates() []AggregateMetrics {
+	mc.aggregatesMu.RLock()
+	defer mc.aggregatesMu.RUnlock()
+
+	aggregates := make([]AggregateMetrics, 0, len(mc.aggregates))
+	for _, agg := range mc.aggregates {
+		aggregates = append(aggregates, *agg)
+	}
+
+	sortAggregates(aggregates)
+
+	return aggregates
+}
+
+// sortAggregates sorts the aggregate metrics by model, test case and temperature
+func sortAggregates(aggregates []AggregateMetrics) {
+	slices.SortFunc(aggregates, func(a, b AggregateMetrics) int {
+		return cmp.Or(
+			cmp.Compare(a.Model, b.Model),
+			cmp.Compare(a.TestCase, b.TestCase),
+			cmp.Compare(a.Temp, b.Temp),
+		)
+	})
+}
+
+// WriteSummary writes the aggregate metrics as a table, one row per model/case/temp combination,
+// so the results of a run can be checked in the terminal or the CI logs without opening Grafana.
+// The aggregates are written in the given order, see MetricsCollector.Aggregates.
+func WriteSummary(w io.Writer, aggregates []AggregateMetrics) error {
+	if len(aggregates) == 0 {
+		_, err := fmt.Fprintln(w, "No benchmark results to summarize")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tCASE\tTEMP\tP50 (ms)\tP95 (ms)\tTPS\tSUCCESS\tSCORE")
+	for _, agg := range aggregates {
+		fmt.Fprintf(tw, "%s\t%s\t%.1f\t%.0f\t%.0f\t%.1f\t%.0f%%\t%.2f\n",
+			agg.Model, agg.TestCase, agg.Temp,
+			agg.LatencyP50, agg.LatencyP95,
+			agg.TokensPerSec,
+			agg.SuccessRate*100,
+			agg.EvalScore,
+		)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSummary(t *testing.T) {
+	mc, err := NewMetricsCollector()
+	if err != nil {
+		t.Fatalf("new metrics collector: %s", err)
+	}
+
+	// Updated out of order, the summary sorts them by model, case and temperature
+	mc.UpdateAggregates("ai/qwen3:0.6B-Q4_0", "code-generation", 0.1, 900, 1500, 0, 0, 0, 0, 0.5, 0, 0.4, 0, 85.25, 0, 0)
+	mc.UpdateAggregates("ai/llama3.2:1B-Q4_0", "factual-question", 0.5, 1200, 2100, 0, 0, 0, 0, 1.0, 0, 0.9, 0, 60, 0, 0)
+	mc.UpdateAggregates("ai/llama3.2:1B-Q4_0", "code-generation", 0.9, 1300, 2400, 0, 0, 0, 0, 0.75, 0, 0.7, 0, 55.5, 0, 0)
+	mc.UpdateAggregates("ai/llama3.2:1B-Q4_0", "code-generation", 0.1, 1100, 2000, 0, 0, 0, 0, 1.0, 0, 0.8, 0, 58, 0, 0)
+
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, mc.Aggregates()); err != nil {
+		t.Fatalf("write summary: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a header and 4 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+
+	expected := [][]string{
+		{"MODEL", "CASE", "TEMP", "P50", "(ms)", "P95", "(ms)", "TPS", "SUCCESS", "SCORE"},
+		{"ai/llama3.2:1B-Q4_0", "code-generation", "0.1", "1100", "2000", "58.0", "100%", "0.80"},
+		{"ai/llama3.2:1B-Q4_0", "code-generation", "0.9", "1300", "2400", "55.5", "75%", "0.70"},
+		{"ai/llama3.2:1B-Q4_0", "factual-question", "0.5", "1200", "2100", "60.0", "100%", "0.90"},
+		{"ai/qwen3:0.6B-Q4_0", "code-generation", "0.1", "900", "1500", "85.2", "50%", "0.40"},
+	}
+	for i, line := range lines {
+		if got := strings.Fields(line); strings.Join(got, " ") != strings.Join(expected[i], " ") {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], got)
+		}
+	}
+
+	// The columns are aligned
+	if col := strings.Index(lines[0], "CASE"); strings.Index(lines[1], "code-generation") != col {
+		t.Errorf("expected the CASE column at %d:\n%s", col, buf.String())
+	}
+}
+
+func TestWriteSummaryEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, nil); err != nil {
+		t.Fatalf("write summary: %s", err)
+	}
+
+	if !strings.Contains(buf.String(), "No benchmark results") {
+		t.Errorf("unexpected summary: %q", buf.String())
+	}
+}
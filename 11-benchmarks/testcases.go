@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TestCase defines a prompt evaluation test case
+type TestCase struct {
+	Name         string
+	SystemPrompt string
+	UserPrompt   string
+	// Tool call expected for tool-assisted test cases, scored by the evaluator's EvaluateToolUse
+	ExpectedTool string
+	ExpectedArgs map[string]any
+	// Completion tokens expected for the responses, see TokenEfficiency. Zero uses a wide default.
+	ExpectedTokens TokenRange
+	// Language of the code blocks of the responses run by the evaluator's EvaluateCodeExecution, empty
+	// to not run them, and the output expected from them, empty to only check they run successfully
+	CodeLanguage   string
+	ExpectedOutput string
+}
+
+// LoadTestCasesFromDir reads the test cases from a directory with one folder per test case, named
+// after it, defining the prompts in the system_prompt.txt and user_prompt.txt files:
+//
+//	testcases/
+//	  sql-generation/
+//	    system_prompt.txt
+//	    user_prompt.txt
+//
+// Both prompts are required and can't be empty. The files outside the folders are ignored.
+func LoadTestCasesFromDir(path string) ([]TestCase, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("read test cases dir: %w", err)
+	}
+
+	var (
+		cases []TestCase
+		errs  []error
+	)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		tc := TestCase{Name: entry.Name()}
+		dir := filepath.Join(path, entry.Name())
+
+		systemPrompt, err := readPromptFile(filepath.Join(dir, "system_prompt.txt"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("test case %s: %w", tc.Name, err))
+			continue
+		}
+		userPrompt, err := readPromptFile(filepath.Join(dir, "user_prompt.txt"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("test case %s: %w", tc.Name, err))
+			continue
+		}
+
+		tc.SystemPrompt = systemPrompt
+		tc.UserPrompt = userPrompt
+		cases = append(cases, tc)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no test cases defined in %s", path)
+	}
+
+	return cases, nil
+}
+
+// readPromptFile returns the trimmed content of a prompt file, which must exist and not be empty
+func readPromptFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read prompt file: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" {
+		return "", fmt.Errorf("prompt file %s is empty", path)
+	}
+
+	return trimmed, nil
+}
+
+// mergeTestCases adds the custom test cases to the built-in ones: a custom test case replaces the
+// built-in one with the same name, keeping its position, and the rest are appended in order
+func mergeTestCases(builtin, custom []TestCase) []TestCase {
+	merged := make([]TestCase, len(builtin), len(builtin)+len(custom))
+	copy(merged, builtin)
+
+	index := make(map[string]int, len(merged))
+	for i, tc := range merged {
+		index[tc.Name] = i
+	}
+
+	for _, tc := range custom {
+		if i, ok := index[tc.Name]; ok {
+			merged[i] = tc
+			continue
+		}
+		index[tc.Name] = len(merged)
+		merged = append(merged, tc)
+	}
+
+	return merged
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestCase writes the prompt files of a test case, skipping the empty ones
+func writeTestCase(t *testing.T, dir, name, systemPrompt, userPrompt string) {
+	t.Helper()
+
+	caseDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		t.Fatalf("create test case dir: %s", err)
+	}
+	for file, content := range map[string]string{"system_prompt.txt": systemPrompt, "user_prompt.txt": userPrompt} {
+		if content == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(caseDir, file), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %s", file, err)
+		}
+	}
+}
+
+func TestLoadTestCasesFromDir(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCase(t, dir, "sql-generation", "You are a SQL expert.\n", "Write a query returning the 10 most recent orders.\n")
+		writeTestCase(t, dir, "haiku", "You are a poet.", "Write a haiku about containers.")
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644); err != nil {
+			t.Fatalf("write readme: %s", err)
+		}
+
+		cases, err := LoadTestCasesFromDir(dir)
+		if err != nil {
+			t.Fatalf("load test cases: %s", err)
+		}
+
+		expected := []TestCase{
+			{Name: "haiku", SystemPrompt: "You are a poet.", UserPrompt: "Write a haiku about containers."},
+			{Name: "sql-generation", SystemPrompt: "You are a SQL expert.", UserPrompt: "Write a query returning the 10 most recent orders."},
+		}
+		if !reflect.DeepEqual(cases, expected) {
+			t.Errorf("expected %+v, got %+v", expected, cases)
+		}
+	})
+
+	t.Run("missing-prompt", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCase(t, dir, "haiku", "You are a poet.", "")
+
+		if _, err := LoadTestCasesFromDir(dir); err == nil {
+			t.Fatal("expected an error for the missing user prompt")
+		}
+	})
+
+	t.Run("empty-prompt", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCase(t, dir, "haiku", "  \n", "Write a haiku about containers.")
+
+		if _, err := LoadTestCasesFromDir(dir); err == nil {
+			t.Fatal("expected an error for the empty system prompt")
+		}
+	})
+
+	t.Run("no-test-cases", func(t *testing.T) {
+		if _, err := LoadTestCasesFromDir(t.TempDir()); err == nil {
+			t.Fatal("expected an error for the empty directory")
+		}
+	})
+}
+
+func TestMergeTestCases(t *testing.T) {
+	builtin := []TestCase{
+		{Name: "code-explanation", SystemPrompt: "built-in", UserPrompt: "built-in"},
+		{Name: "code-generation", SystemPrompt: "built-in", UserPrompt: "built-in"},
+	}
+	custom := []TestCase{
+		{Name: "haiku", SystemPrompt: "custom", UserPrompt: "custom"},
+		{Name: "code-generation", SystemPrompt: "custom", UserPrompt: "custom"},
+	}
+
+	merged := mergeTestCases(builtin, custom)
+
+	expected := []TestCase{
+		{Name: "code-explanation", SystemPrompt: "built-in", UserPrompt: "built-in"},
+		{Name: "code-generation", SystemPrompt: "custom", UserPrompt: "custom"},
+		{Name: "haiku", SystemPrompt: "custom", UserPrompt: "custom"},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+
+	// The built-in test cases are left untouched
+	if builtin[1].SystemPrompt != "built-in" {
+		t.Error("expected the built-in test cases unchanged")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/stats"
+)
+
+// Defaults of the throughput sweep, see ThroughputConfig
+const (
+	defaultThroughputMaxConcurrency    = 8
+	defaultThroughputRequestsPerWorker = 2
+	defaultThroughputSLO               = 10 * time.Second
+)
+
+// ThroughputConfig configures the throughput sweep of MeasureThroughput
+type ThroughputConfig struct {
+	// MaxConcurrency is the highest number of concurrent requests of the sweep, which doubles it from 1
+	MaxConcurrency int
+	// RequestsPerWorker is the number of requests sent by each concurrent worker at each level
+	RequestsPerWorker int
+	// SLO is the p95 latency a level must stay under to be sustainable
+	SLO time.Duration
+}
+
+// ThroughputLevel contains the latency and the throughput of the requests at a concurrency
+type ThroughputLevel struct {
+	Concurrency int
+	Requests    int // Number of successful requests
+	Failed      int
+	LatencyP50  time.Duration
+	LatencyP95  time.Duration
+	RPS         float64 // Successful requests per second of wall-clock time
+}
+
+// Sustainable tells whether all the requests of the level succeeded with the p95 latency under the SLO
+func (l ThroughputLevel) Sustainable(slo time.Duration) bool {
+	return l.Requests > 0 && l.Failed == 0 && l.LatencyP95 <= slo
+}
+
+// ThroughputSweep is the capacity curve of a model: the latency and the throughput at each concurrency
+type ThroughputSweep struct {
+	Levels []ThroughputLevel
+	// MaxSustainableRPS is the highest throughput of the sustainable levels, zero if none is
+	MaxSustainableRPS float64
+	// MaxSustainableConcurrency is the concurrency of MaxSustainableRPS, zero if no level is sustainable
+	MaxSustainableConcurrency int
+}
+
+// ConcurrencyLevels returns the concurrencies of the sweep, doubling from 1 up to the maximum,
+// which is always included, e.g. 1, 2, 4, 6 for 6
+func ConcurrencyLevels(maxConcurrency int) []int {
+	var levels []int
+	for c := 1; c < maxConcurrency; c *= 2 {
+		levels = append(levels, c)
+	}
+
+	return append(levels, max(maxConcurrency, 1))
+}
+
+// RunConcurrentLoad sends the requests with the given number of concurrent workers, each one sending
+// its next request as soon as the previous one completes, and measures their latency and throughput.
+// The errors of the failed requests are returned joined, with the level of the successful ones.
+func RunConcurrentLoad(ctx context.Context, concurrency, requests int, generate func(ctx context.Context) error) (ThroughputLevel, error) {
+	concurrency = max(concurrency, 1)
+
+	jobs := make(chan int, requests)
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var errs []error
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+
+				requestStart := time.Now()
+				err := generate(ctx)
+				latency := time.Since(requestStart)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("request %d: %w", i+1, err))
+				} else {
+					latencies = append(latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	level := ThroughputLevel{
+		Concurrency: concurrency,
+		Requests:    len(latencies),
+		Failed:      len(errs),
+	}
+	if len(latencies) > 0 {
+		ms := make([]float64, len(latencies))
+		for i, latency := range latencies {
+			ms[i] = float64(latency) / float64(time.Millisecond)
+		}
+		slices.Sort(ms)
+
+		level.LatencyP50 = time.Duration(stats.Percentile(ms, 50) * float64(time.Millisecond))
+		level.LatencyP95 = time.Duration(stats.Percentile(ms, 95) * float64(time.Millisecond))
+		level.RPS = float64(len(latencies)) / elapsed.Seconds()
+	}
+
+	return level, errors.Join(errs...)
+}
+
+// MeasureThroughput ramps the concurrency of the requests and returns the capacity curve, with the
+// highest throughput the model sustains with the p95 latency under the SLO. The sweep stops at the
+// first level that is not sustainable, as the latency only degrades further with more concurrency.
+// It fails if all the requests of a level fail.
+func MeasureThroughput(ctx context.Context, cfg ThroughputConfig, generate func(ctx context.Context) error) (ThroughputSweep, error) {
+	if cfg.SLO <= 0 {
+		return ThroughputSweep{}, fmt.Errorf("the latency SLO must be positive, got %s", cfg.SLO)
+	}
+	requestsPerWorker := max(cfg.RequestsPerWorker, 1)
+
+	var sweep ThroughputSweep
+	for _, concurrency := range ConcurrencyLevels(cfg.MaxConcurrency) {
+		level, err := RunConcurrentLoad(ctx, concurrency, concurrency*requestsPerWorker, generate)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return sweep, fmt.Errorf("concurrency %d: %w", concurrency, ctxErr)
+		}
+		if level.Requests == 0 {
+			return sweep, fmt.Errorf("concurrency %d: all the requests failed: %w", concurrency, err)
+		}
+		sweep.Levels = append(sweep.Levels, level)
+
+		if !level.Sustainable(cfg.SLO) {
+			break
+		}
+		if level.RPS > sweep.MaxSustainableRPS {
+			sweep.MaxSustainableRPS = level.RPS
+			sweep.MaxSustainableConcurrency = level.Concurrency
+		}
+	}
+
+	return sweep, nil
+}
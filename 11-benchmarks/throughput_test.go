@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeServer serves the requests with a fixed latency, up to the given ones in parallel, queueing
+// the others, like an inference engine with a fixed number of slots
+func fakeServer(slots int, latency time.Duration) func(ctx context.Context) error {
+	sem := make(chan struct{}, slots)
+	return func(ctx context.Context) error {
+		sem <- struct{}{}
+		defer func() { <-sem }()
+
+		time.Sleep(latency)
+		return nil
+	}
+}
+
+func TestConcurrencyLevels(t *testing.T) {
+	tests := []struct {
+		max      int
+		expected []int
+	}{
+		{max: 0, expected: []int{1}},
+		{max: 1, expected: []int{1}},
+		{max: 8, expected: []int{1, 2, 4, 8}},
+		{max: 6, expected: []int{1, 2, 4, 6}},
+	}
+
+	for _, tt := range tests {
+		if got := ConcurrencyLevels(tt.max); !slices.Equal(got, tt.expected) {
+			t.Errorf("ConcurrencyLevels(%d): expected %v, got %v", tt.max, tt.expected, got)
+		}
+	}
+}
+
+func TestRunConcurrentLoad(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	errFailed := errors.New("failed")
+
+	var calls atomic.Int32
+	level, err := RunConcurrentLoad(context.Background(), 3, 9, func(ctx context.Context) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		if calls.Add(1) == 1 {
+			return errFailed
+		}
+		return nil
+	})
+
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected the error of the failed request, got %v", err)
+	}
+	if level.Concurrency != 3 || level.Requests != 8 || level.Failed != 1 {
+		t.Errorf("expected 8 successful and 1 failed requests at concurrency 3, got %+v", level)
+	}
+	if got := maxInFlight.Load(); got != 3 {
+		t.Errorf("expected 3 requests in flight at most, got %d", got)
+	}
+	if level.LatencyP95 < 10*time.Millisecond || level.RPS <= 0 {
+		t.Errorf("expected the latency and the throughput measured, got %+v", level)
+	}
+}
+
+func TestMeasureThroughput(t *testing.T) {
+	t.Run("max-sustainable", func(t *testing.T) {
+		// Two slots of 50ms: the latency holds up to 2 concurrent requests, then they queue
+		cfg := ThroughputConfig{MaxConcurrency: 8, RequestsPerWorker: 2, SLO: 75 * time.Millisecond}
+
+		sweep, err := MeasureThroughput(context.Background(), cfg, fakeServer(2, 50*time.Millisecond))
+		if err != nil {
+			t.Fatalf("measure throughput: %s", err)
+		}
+
+		if sweep.MaxSustainableConcurrency != 2 {
+			t.Errorf("expected the max sustainable concurrency of 2, got %d: %+v", sweep.MaxSustainableConcurrency, sweep.Levels)
+		}
+		// 2 requests every 50ms
+		if sweep.MaxSustainableRPS < 30 || sweep.MaxSustainableRPS > 40 {
+			t.Errorf("expected about 40 requests per second, got %.2f", sweep.MaxSustainableRPS)
+		}
+
+		// The sweep stops at the first level over the SLO
+		var concurrencies []int
+		for _, level := range sweep.Levels {
+			concurrencies = append(concurrencies, level.Concurrency)
+		}
+		if !slices.Equal(concurrencies, []int{1, 2, 4}) {
+			t.Errorf("expected the levels 1, 2 and 4, got %v", concurrencies)
+		}
+		if last := sweep.Levels[len(sweep.Levels)-1]; last.Sustainable(cfg.SLO) {
+			t.Errorf("expected the last level over the SLO, got %+v", last)
+		}
+	})
+
+	t.Run("none-sustainable", func(t *testing.T) {
+		cfg := ThroughputConfig{MaxConcurrency: 4, RequestsPerWorker: 1, SLO: 10 * time.Millisecond}
+
+		sweep, err := MeasureThroughput(context.Background(), cfg, fakeServer(1, 30*time.Millisecond))
+		if err != nil {
+			t.Fatalf("measure throughput: %s", err)
+		}
+		if sweep.MaxSustainableRPS != 0 || sweep.MaxSustainableConcurrency != 0 || len(sweep.Levels) != 1 {
+			t.Errorf("expected no sustainable level, got %+v", sweep)
+		}
+	})
+
+	t.Run("all-failed", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		cfg := ThroughputConfig{MaxConcurrency: 4, RequestsPerWorker: 1, SLO: time.Second}
+
+		_, err := MeasureThroughput(context.Background(), cfg, func(ctx context.Context) error { return errFailed })
+		if !errors.Is(err, errFailed) {
+			t.Errorf("expected the errors of the requests, got %v", err)
+		}
+	})
+
+	t.Run("invalid-slo", func(t *testing.T) {
+		if _, err := MeasureThroughput(context.Background(), ThroughputConfig{MaxConcurrency: 1}, fakeServer(1, 0)); err == nil {
+			t.Error("expected an error without SLO")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// DefaultTokenEfficiencyWeight is the weight of the token efficiency in the composite score, the
+// evaluator score weighing the rest
+const DefaultTokenEfficiencyWeight = 0.2
+
+// defaultExpectedTokens is the expected length of the responses of the test cases without their own,
+// e.g. the custom ones: wide, to only penalize the degenerate responses
+var defaultExpectedTokens = TokenRange{Min: 20, Max: 800}
+
+// TokenRange is the range of completion tokens expected for the responses to a test case
+type TokenRange struct {
+	Min int
+	Max int
+}
+
+// ExpectedTokenRange returns the completion tokens expected for the responses to the test case
+func (tc TestCase) ExpectedTokenRange() TokenRange {
+	if tc.ExpectedTokens == (TokenRange{}) {
+		return defaultExpectedTokens
+	}
+
+	return tc.ExpectedTokens
+}
+
+// TokenEfficiency scores the length of a response against the expected range, from 0 to 1. Responses
+// within the range score 1, shorter ones, likely truncated or incomplete, score proportionally to
+// their length, and longer ones, needlessly verbose, inversely to it, so each extra token costs.
+func TokenEfficiency(completionTokens int, expected TokenRange) float64 {
+	switch {
+	case completionTokens <= 0:
+		return 0
+	case completionTokens < expected.Min:
+		return float64(completionTokens) / float64(expected.Min)
+	case expected.Max > 0 && completionTokens > expected.Max:
+		return float64(expected.Max) / float64(completionTokens)
+	default:
+		return 1
+	}
+}
+
+// CompositeScore combines the evaluator score, for the quality of the responses, with their token
+// efficiency, for their cost, giving the efficiency the weight and the evaluator score the rest
+func CompositeScore(evalScore, tokenEfficiency, weight float64) float64 {
+	return (1-weight)*evalScore + weight*tokenEfficiency
+}
+
+// getTokenEfficiencyWeight returns the weight defined by the BENCH_TOKEN_EFFICIENCY_WEIGHT environment
+// variable, between 0 and 1, or DefaultTokenEfficiencyWeight if it's not set
+func getTokenEfficiencyWeight() (float64, error) {
+	value := os.Getenv("BENCH_TOKEN_EFFICIENCY_WEIGHT")
+	if value == "" {
+		return DefaultTokenEfficiencyWeight, nil
+	}
+
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 0 || weight > 1 {
+		return 0, fmt.Errorf("invalid BENCH_TOKEN_EFFICIENCY_WEIGHT %q: must be a number between 0 and 1", value)
+	}
+
+	return weight, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTokenEfficiency(t *testing.T) {
+	expected := TokenRange{Min: 100, Max: 400}
+
+	tests := []struct {
+		name     string
+		tokens   int
+		expected float64
+	}{
+		{name: "empty", tokens: 0, expected: 0},
+		{name: "truncated", tokens: 25, expected: 0.25},
+		{name: "terse", tokens: 50, expected: 0.5},
+		{name: "min", tokens: 100, expected: 1},
+		{name: "in-range", tokens: 250, expected: 1},
+		{name: "max", tokens: 400, expected: 1},
+		{name: "verbose", tokens: 800, expected: 0.5},
+		{name: "rambling", tokens: 1600, expected: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenEfficiency(tt.tokens, expected); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %.2f, got %.2f", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("monotonic", func(t *testing.T) {
+		// Non-decreasing up to the range, non-increasing past it
+		prev := TokenEfficiency(0, expected)
+		for tokens := 1; tokens <= expected.Max; tokens++ {
+			got := TokenEfficiency(tokens, expected)
+			if got < prev {
+				t.Fatalf("expected the efficiency to grow up to the range, got %.3f at %d tokens after %.3f", got, tokens, prev)
+			}
+			prev = got
+		}
+		for tokens := expected.Max + 1; tokens <= 10*expected.Max; tokens++ {
+			got := TokenEfficiency(tokens, expected)
+			if got > prev {
+				t.Fatalf("expected the efficiency to decay past the range, got %.3f at %d tokens after %.3f", got, tokens, prev)
+			}
+			prev = got
+		}
+		if prev <= 0 {
+			t.Errorf("expected long responses to keep a positive efficiency, got %.3f", prev)
+		}
+	})
+}
+
+func TestExpectedTokenRange(t *testing.T) {
+	if got := (TestCase{Name: "custom"}).ExpectedTokenRange(); got != defaultExpectedTokens {
+		t.Errorf("expected the default range for test cases without one, got %+v", got)
+	}
+
+	tc := TestCase{Name: "math", ExpectedTokens: TokenRange{Min: 20, Max: 250}}
+	if got := tc.ExpectedTokenRange(); got != tc.ExpectedTokens {
+		t.Errorf("expected the range of the test case, got %+v", got)
+	}
+}
+
+func TestCompositeScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		evalScore  float64
+		efficiency float64
+		weight     float64
+		expected   float64
+	}{
+		{name: "default-weight", evalScore: 0.9, efficiency: 0.4, weight: DefaultTokenEfficiencyWeight, expected: 0.8},
+		{name: "quality-only", evalScore: 0.9, efficiency: 0.4, weight: 0, expected: 0.9},
+		{name: "efficiency-only", evalScore: 0.9, efficiency: 0.4, weight: 1, expected: 0.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompositeScore(tt.evalScore, tt.efficiency, tt.weight); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %.2f, got %.2f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetTokenEfficiencyWeight(t *testing.T) {
+	t.Setenv("BENCH_TOKEN_EFFICIENCY_WEIGHT", "")
+	if weight, err := getTokenEfficiencyWeight(); err != nil || weight != DefaultTokenEfficiencyWeight {
+		t.Errorf("expected the default weight, got %.2f (%v)", weight, err)
+	}
+
+	t.Setenv("BENCH_TOKEN_EFFICIENCY_WEIGHT", "0.5")
+	if weight, err := getTokenEfficiencyWeight(); err != nil || weight != 0.5 {
+		t.Errorf("expected a weight of 0.5, got %.2f (%v)", weight, err)
+	}
+
+	for _, value := range []string{"-0.1", "1.5", "half"} {
+		t.Setenv("BENCH_TOKEN_EFFICIENCY_WEIGHT", value)
+		if _, err := getTokenEfficiencyWeight(); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
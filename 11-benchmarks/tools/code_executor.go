@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/markdown"
+	"github.com/mdelapenya/genai-testcontainers-go/testing/logging"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/tmc/langchaingo/llms"
@@ -14,8 +16,8 @@ import (
 
 // CodeExecutorInput represents the input parameters for code execution
 type CodeExecutorInput struct {
-	Code     string `json:"code"`               // Python code to execute
-	Language string `json:"language,omitempty"` // Programming language (default: python)
+	Code     string `json:"code"`               // Code to execute
+	Language string `json:"language,omitempty"` // Programming language: python (default) or go
 }
 
 // CodeExecutorResult represents the result of code execution
@@ -45,33 +47,36 @@ func NewCodeExecutorWithTimeout(timeout time.Duration) *CodeExecutor {
 	}
 }
 
-// Execute runs the provided code in an isolated Python container
+// Execute runs the provided code in an isolated container of its language: Python runs the code as a
+// script, and Go runs it as the main package of a program
 func (c *CodeExecutor) Execute(inputJSON string) (string, error) {
 	var input CodeExecutorInput
 	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
 		return "", fmt.Errorf("failed to parse code executor input: %w", err)
 	}
 
+	// The models often send the code fenced as in their markdown responses
+	input.Code = unfence(input.Code)
+
 	// Default to Python if language not specified
 	if input.Language == "" {
 		input.Language = "python"
 	}
 
-	// Only support Python for now
-	if input.Language != "python" {
+	runtime, ok := runtimes[NormalizeLanguage(input.Language)]
+	if !ok {
 		result := CodeExecutorResult{
-			Error:    fmt.Sprintf("unsupported language: %s (only python is supported)", input.Language),
+			Error:    fmt.Sprintf("unsupported language: %s (only python and go are supported)", input.Language),
 			ExitCode: 1,
 		}
 		resultJSON, _ := json.Marshal(result)
 		return string(resultJSON), fmt.Errorf("unsupported language: %s", input.Language)
 	}
 
-	// Execute Python code
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	stdout, stderr, exitCode, err := c.executePythonCode(ctx, input.Code)
+	stdout, stderr, exitCode, err := c.executeCode(ctx, runtime, input.Code)
 
 	result := CodeExecutorResult{
 		Stdout:   stdout,
@@ -90,12 +95,62 @@ func (c *CodeExecutor) Execute(inputJSON string) (string, error) {
 	return string(resultJSON), err
 }
 
-// executePythonCode runs Python code in a container and returns stdout, stderr, and exit code
-func (c *CodeExecutor) executePythonCode(ctx context.Context, code string) (string, string, int, error) {
-	// Create a Python container with the code as a command
+// unfence returns the content of the code if it's a fenced code block, or the code as is
+func unfence(code string) string {
+	trimmed := strings.TrimSpace(code)
+	if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+		return code
+	}
+
+	blocks := markdown.ExtractCodeBlocks(trimmed)
+	if len(blocks) == 0 {
+		return code
+	}
+
+	return blocks[0].Code
+}
+
+// codeRuntime is the container running the code of a language
+type codeRuntime struct {
+	image string
+	cmd   func(code string) []string
+}
+
+// runtimes are the containers of the supported languages. Go compiles and runs the code as a single
+// file program, so it must be a complete main package.
+var runtimes = map[string]codeRuntime{
+	"python": {
+		image: "python:3.12-alpine",
+		cmd: func(code string) []string {
+			return []string{"python", "-c", code}
+		},
+	},
+	"go": {
+		image: "golang:1.25-alpine",
+		cmd: func(code string) []string {
+			return []string{"sh", "-c", `cd /tmp && printf '%s' "$0" > main.go && go run main.go`, code}
+		},
+	},
+}
+
+// NormalizeLanguage returns the language of the code executor for the name or alias of a language,
+// e.g. py or golang, as found in the info string of the markdown code blocks
+func NormalizeLanguage(language string) string {
+	switch language = strings.ToLower(language); language {
+	case "py", "python3":
+		return "python"
+	case "golang":
+		return "go"
+	default:
+		return language
+	}
+}
+
+// executeCode runs the code in a container of its runtime and returns stdout, stderr, and exit code
+func (c *CodeExecutor) executeCode(ctx context.Context, runtime codeRuntime, code string) (string, string, int, error) {
 	req := testcontainers.ContainerRequest{
-		Image: "python:3.12-alpine",
-		Cmd:   []string{"python", "-c", code},
+		Image: runtime.image,
+		Cmd:   runtime.cmd(code),
 		WaitingFor: wait.ForExit().
 			WithExitTimeout(c.timeout),
 	}
@@ -106,13 +161,13 @@ func (c *CodeExecutor) executePythonCode(ctx context.Context, code string) (stri
 		Started:          true,
 	})
 	if err != nil {
-		return "", "", 1, fmt.Errorf("failed to start Python container: %w", err)
+		return "", "", 1, fmt.Errorf("failed to start %s container: %w", runtime.image, err)
 	}
 	defer func() {
 		// Terminate the container
 		if termErr := container.Terminate(ctx); termErr != nil {
 			// Log but don't fail on cleanup errors
-			fmt.Printf("Warning: failed to terminate container: %v\n", termErr)
+			logging.Default().Warn("Failed to terminate container", "error", termErr)
 		}
 	}()
 
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnfence(t *testing.T) {
+	tests := map[string]string{
+		"print(1)":                         "print(1)",
+		"```python\nprint(1)\n```":         "print(1)",
+		"\n```\nprint(1)\nprint(2)\n```\n": "print(1)\nprint(2)",
+		"print('```')":                     "print('```')",
+		"```python\nprint(1)":              "print(1)",
+		"```python print(1)```":            "```python print(1)```",
+	}
+
+	for code, expected := range tests {
+		if got := unfence(code); got != expected {
+			t.Errorf("unfence(%q): expected %q, got %q", code, expected, got)
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"python":  "python",
+		"py":      "python",
+		"Python3": "python",
+		"go":      "go",
+		"golang":  "go",
+		"rust":    "rust",
+	}
+
+	for language, expected := range tests {
+		if got := NormalizeLanguage(language); got != expected {
+			t.Errorf("NormalizeLanguage(%q): expected %q, got %q", language, expected, got)
+		}
+		if _, ok := runtimes[NormalizeLanguage(language)]; ok != (expected != "rust") {
+			t.Errorf("unexpected runtime support for %q: %t", language, ok)
+		}
+	}
+}
+
+func TestCodeExecutor_unsupportedLanguage(t *testing.T) {
+	output, err := NewCodeExecutor().Execute(`{"code": "fn main() {}", "language": "rust"}`)
+	if err == nil {
+		t.Fatal("expected an error for an unsupported language")
+	}
+	if !strings.Contains(output, "only python and go are supported") {
+		t.Errorf("expected the supported languages in the result, got %s", output)
+	}
+}
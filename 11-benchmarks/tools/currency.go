@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// DefaultRatesURL is the free Frankfurter API, publishing the reference rates of the European Central Bank
+	DefaultRatesURL = "https://api.frankfurter.app/latest"
+
+	// DefaultRatesTTL is how long the fetched rates are reused, as the reference rates are updated once a day
+	DefaultRatesTTL = time.Hour
+)
+
+// CurrencyInput represents the input parameters for currency conversions
+type CurrencyInput struct {
+	Amount float64 `json:"amount"`
+	From   string  `json:"from"` // ISO 4217 code, e.g. USD
+	To     string  `json:"to"`   // ISO 4217 code, e.g. EUR
+}
+
+// CurrencyResult represents the result of a currency conversion
+type CurrencyResult struct {
+	Amount float64 `json:"amount"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Rate   float64 `json:"rate"`
+	Result float64 `json:"result"`
+	Date   string  `json:"date,omitempty"` // Date of the rates
+	Error  string  `json:"error,omitempty"`
+}
+
+// ratesResponse is the response of the rates API for a base currency
+type ratesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// cachedRates are the rates of a base currency, with the time they were fetched
+type cachedRates struct {
+	rates     ratesResponse
+	fetchedAt time.Time
+}
+
+// CurrencyConverter converts amounts between currencies, caching the rates of each base currency
+// for a TTL, so repeated conversions don't hit the rates API. It's safe for concurrent use.
+type CurrencyConverter struct {
+	client   *http.Client
+	ratesURL string
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedRates
+}
+
+// NewCurrencyConverter creates a new currency converter tool using the Frankfurter API
+func NewCurrencyConverter() *CurrencyConverter {
+	return NewCurrencyConverterWithURL(DefaultRatesURL, DefaultRatesTTL)
+}
+
+// NewCurrencyConverterWithURL creates a new currency converter tool using the rates API at ratesURL,
+// which must answer like the Frankfurter API, and caching the rates for ttl
+func NewCurrencyConverterWithURL(ratesURL string, ttl time.Duration) *CurrencyConverter {
+	return &CurrencyConverter{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		ratesURL: ratesURL,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cachedRates),
+	}
+}
+
+// Execute converts the amount between the currencies of the input
+func (c *CurrencyConverter) Execute(inputJSON string) (string, error) {
+	var input CurrencyInput
+	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+		return "", fmt.Errorf("failed to parse currency input: %w", err)
+	}
+
+	result := CurrencyResult{
+		Amount: input.Amount,
+		From:   strings.ToUpper(strings.TrimSpace(input.From)),
+		To:     strings.ToUpper(strings.TrimSpace(input.To)),
+	}
+
+	rate, date, err := c.rate(result.From, result.To)
+	if err == nil {
+		result.Rate = rate
+		result.Result = input.Amount * rate
+		result.Date = date
+	} else {
+		result.Error = err.Error()
+	}
+
+	resultJSON, jsonErr := json.Marshal(result)
+	if jsonErr != nil {
+		return "", fmt.Errorf("failed to marshal currency result: %w", jsonErr)
+	}
+
+	return string(resultJSON), err
+}
+
+// rate returns the exchange rate between the currencies, and the date of the rates
+func (c *CurrencyConverter) rate(from, to string) (float64, string, error) {
+	if from == "" || to == "" {
+		return 0, "", fmt.Errorf("both from and to currencies are required")
+	}
+	if from == to {
+		return 1, "", nil
+	}
+
+	rates, err := c.ratesFor(from)
+	if err != nil {
+		return 0, "", err
+	}
+
+	rate, ok := rates.Rates[to]
+	if !ok {
+		return 0, "", fmt.Errorf("unknown currency: %s", to)
+	}
+
+	return rate, rates.Date, nil
+}
+
+// ratesFor returns the rates of the base currency, fetching them if they are not cached or expired
+func (c *CurrencyConverter) ratesFor(base string) (ratesResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.cache[base]; ok && c.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.rates, nil
+	}
+
+	rates, err := c.fetchRates(base)
+	if err != nil {
+		return ratesResponse{}, err
+	}
+
+	c.cache[base] = cachedRates{rates: rates, fetchedAt: c.now()}
+	return rates, nil
+}
+
+// fetchRates fetches the rates of the base currency from the rates API
+func (c *CurrencyConverter) fetchRates(base string) (ratesResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, c.ratesURL+"?"+url.Values{"from": {base}}.Encode(), nil)
+	if err != nil {
+		return ratesResponse{}, fmt.Errorf("failed to create rates request: %w", err)
+	}
+	req.Header.Set("User-Agent", "LLM-Benchmark-Tool/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ratesResponse{}, fmt.Errorf("rates request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ratesResponse{}, fmt.Errorf("failed to read rates response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ratesResponse{}, fmt.Errorf("rates request returned error status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var rates ratesResponse
+	if err := json.Unmarshal(body, &rates); err != nil {
+		return ratesResponse{}, fmt.Errorf("failed to parse rates response: %w", err)
+	}
+
+	return rates, nil
+}
+
+// GetToolDefinition returns the langchaingo tool definition for the currency converter
+func (c *CurrencyConverter) GetToolDefinition() llms.Tool {
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "currency",
+			Description: "Converts an amount of money from one currency to another using the latest exchange rates. Use this tool when you need to convert between currencies, then use the calculator to compare or combine the converted amounts.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"amount": map[string]any{
+						"type":        "number",
+						"description": "The amount of money to convert",
+					},
+					"from": map[string]any{
+						"type":        "string",
+						"description": "The ISO 4217 code of the currency of the amount, e.g. USD",
+					},
+					"to": map[string]any{
+						"type":        "string",
+						"description": "The ISO 4217 code of the currency to convert to, e.g. EUR",
+					},
+				},
+				"required": []string{"amount", "from", "to"},
+			},
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCurrencyConverter(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		switch r.URL.Query().Get("from") {
+		case "USD":
+			w.Write([]byte(`{"amount": 1.0, "base": "USD", "date": "2025-06-26", "rates": {"EUR": 0.92, "GBP": 0.79}}`))
+		default:
+			http.Error(w, `{"message": "not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 6, 26, 12, 0, 0, 0, time.UTC)
+	converter := NewCurrencyConverterWithURL(srv.URL, time.Hour)
+	converter.now = func() time.Time { return now }
+
+	convert := func(t *testing.T, input string) (CurrencyResult, error) {
+		t.Helper()
+
+		output, err := converter.Execute(input)
+
+		var result CurrencyResult
+		if jsonErr := json.Unmarshal([]byte(output), &result); jsonErr != nil {
+			t.Fatalf("unmarshal result %q: %s", output, jsonErr)
+		}
+
+		return result, err
+	}
+
+	t.Run("conversion", func(t *testing.T) {
+		result, err := convert(t, `{"amount": 100, "from": "usd", "to": "EUR"}`)
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if math.Abs(result.Result-92) > 1e-9 || result.Rate != 0.92 || result.From != "USD" || result.Date != "2025-06-26" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		before := requests.Load()
+
+		now = now.Add(59 * time.Minute)
+		result, err := convert(t, `{"amount": 10, "from": "USD", "to": "GBP"}`)
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if math.Abs(result.Result-7.9) > 1e-9 {
+			t.Errorf("expected 7.9 GBP, got %+v", result)
+		}
+		if requests.Load() != before {
+			t.Errorf("expected the cached rates to be used, got %d new requests", requests.Load()-before)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		before := requests.Load()
+
+		now = now.Add(time.Hour)
+		if _, err := convert(t, `{"amount": 10, "from": "USD", "to": "EUR"}`); err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if requests.Load() != before+1 {
+			t.Errorf("expected the expired rates to be fetched again, got %d new requests", requests.Load()-before)
+		}
+	})
+
+	t.Run("same-currency", func(t *testing.T) {
+		result, err := convert(t, `{"amount": 10, "from": "EUR", "to": "eur"}`)
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if result.Result != 10 || result.Rate != 1 {
+			t.Errorf("expected the same amount, got %+v", result)
+		}
+	})
+
+	t.Run("unknown-currency", func(t *testing.T) {
+		result, err := convert(t, `{"amount": 10, "from": "USD", "to": "XYZ"}`)
+		if err == nil || result.Error != "unknown currency: XYZ" {
+			t.Errorf("expected an unknown currency error, got %v and %+v", err, result)
+		}
+	})
+
+	t.Run("rates-error", func(t *testing.T) {
+		result, err := convert(t, `{"amount": 10, "from": "ABC", "to": "EUR"}`)
+		if err == nil || result.Error == "" {
+			t.Errorf("expected an error, got %+v", result)
+		}
+	})
+}
@@ -2,12 +2,15 @@ package tools
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/retry"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -27,20 +30,25 @@ type HTTPClientResult struct {
 	Error      string            `json:"error,omitempty"`
 }
 
+// defaultRetryPolicy retries the GET requests failing for transient reasons twice, waiting ~500ms and ~1s
+var defaultRetryPolicy = retry.Policy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+	Retryable:      isTransient,
+}
+
 // HTTPClient makes HTTP requests to external APIs
 type HTTPClient struct {
-	client  *http.Client
-	timeout time.Duration
+	client      *http.Client
+	timeout     time.Duration
+	retryPolicy retry.Policy
 }
 
 // NewHTTPClient creates a new HTTP client tool with default timeout
 func NewHTTPClient() *HTTPClient {
-	return &HTTPClient{
-		client: &http.Client{
-			Timeout: 30 * time.Second, // Default 30 second timeout
-		},
-		timeout: 30 * time.Second,
-	}
+	return NewHTTPClientWithTimeout(30 * time.Second) // Default 30 second timeout
 }
 
 // NewHTTPClientWithTimeout creates a new HTTP client with a custom timeout
@@ -49,10 +57,43 @@ func NewHTTPClientWithTimeout(timeout time.Duration) *HTTPClient {
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		timeout: timeout,
+		timeout:     timeout,
+		retryPolicy: defaultRetryPolicy,
 	}
 }
 
+// transientStatusError is a response worth retrying: the rate limits and the server errors
+type transientStatusError struct {
+	statusCode int
+}
+
+func (e *transientStatusError) Error() string {
+	return fmt.Sprintf("HTTP request returned transient status: %d", e.statusCode)
+}
+
+// isTransient reports whether the request failed for a reason that may go away on its own: a
+// transient status, a network error, a timeout, or a connection closed while reading the response
+func isTransient(err error) bool {
+	var statusErr *transientStatusError
+	var opErr *net.OpError
+	var netErr net.Error
+
+	return errors.As(err, &statusErr) ||
+		errors.As(err, &opErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryPolicyFor returns the retry policy of the method: only the idempotent GET requests are retried
+func (h *HTTPClient) retryPolicyFor(method string) retry.Policy {
+	policy := h.retryPolicy
+	if method != http.MethodGet {
+		policy.MaxAttempts = 1
+	}
+
+	return policy
+}
+
 // Execute performs an HTTP request based on the input
 func (h *HTTPClient) Execute(inputJSON string) (string, error) {
 	var input HTTPClientInput
@@ -115,9 +156,31 @@ func (h *HTTPClient) Execute(inputJSON string) (string, error) {
 		req.Header.Set("User-Agent", "LLM-Benchmark-Tool/1.0")
 	}
 
-	// Execute request
-	resp, err := h.client.Do(req)
-	if err != nil {
+	// Execute request, retrying the transient failures. The response of the last attempt is kept,
+	// so an exhausted transient status is reported like any other error status.
+	var resp *http.Response
+	var bodyBytes []byte
+	var readErr error
+	err = retry.Do(req.Context(), h.retryPolicyFor(input.Method), func() error {
+		resp, bodyBytes, readErr = nil, nil, nil
+
+		r, err := h.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer r.Body.Close()
+
+		resp = r
+		if bodyBytes, readErr = io.ReadAll(r.Body); readErr != nil {
+			return readErr
+		}
+		if r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500 {
+			return &transientStatusError{statusCode: r.StatusCode}
+		}
+
+		return nil
+	})
+	if resp == nil {
 		result := HTTPClientResult{
 			Error:      fmt.Sprintf("HTTP request failed: %v", err),
 			StatusCode: 0,
@@ -125,11 +188,9 @@ func (h *HTTPClient) Execute(inputJSON string) (string, error) {
 		resultJSON, _ := json.Marshal(result)
 		return string(resultJSON), fmt.Errorf("HTTP request failed: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// The response body could not be read
+	if err := readErr; err != nil {
 		result := HTTPClientResult{
 			Error:      fmt.Sprintf("failed to read response body: %v", err),
 			StatusCode: resp.StatusCode,
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first requests with the status, then answers OK, counting the requests
+func flakyServer(t *testing.T, failures int, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if int(requests.Add(1)) <= failures {
+			http.Error(w, "try again later", status)
+			return
+		}
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func newTestHTTPClient() *HTTPClient {
+	h := NewHTTPClientWithTimeout(5 * time.Second)
+	h.retryPolicy.InitialBackoff = time.Millisecond
+	return h
+}
+
+func TestHTTPClientRetries(t *testing.T) {
+	t.Run("transient-status", func(t *testing.T) {
+		srv, requests := flakyServer(t, 2, http.StatusServiceUnavailable)
+
+		out, err := newTestHTTPClient().Execute(fmt.Sprintf(`{"url": %q}`, srv.URL))
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		var result HTTPClientResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("unmarshal result: %s", err)
+		}
+		if result.StatusCode != http.StatusOK || result.Body != `{"ok": true}` {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("expected 3 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		srv, requests := flakyServer(t, 5, http.StatusTooManyRequests)
+
+		out, err := newTestHTTPClient().Execute(fmt.Sprintf(`{"url": %q}`, srv.URL))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		// The last response is reported like any other error status
+		var result HTTPClientResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("unmarshal result: %s", err)
+		}
+		if result.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("expected the status of the last response, got %d", result.StatusCode)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("expected 3 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("client-error", func(t *testing.T) {
+		srv, requests := flakyServer(t, 1, http.StatusNotFound)
+
+		if _, err := newTestHTTPClient().Execute(fmt.Sprintf(`{"url": %q}`, srv.URL)); err == nil {
+			t.Fatal("expected an error")
+		}
+		if requests.Load() != 1 {
+			t.Errorf("expected no retry of a client error, got %d requests", requests.Load())
+		}
+	})
+
+	t.Run("post", func(t *testing.T) {
+		srv, requests := flakyServer(t, 1, http.StatusBadGateway)
+
+		if _, err := newTestHTTPClient().Execute(fmt.Sprintf(`{"url": %q, "method": "POST", "body": "{}"}`, srv.URL)); err == nil {
+			t.Fatal("expected an error")
+		}
+		if requests.Load() != 1 {
+			t.Errorf("expected no retry of a POST request, got %d requests", requests.Load())
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdelapenya/genai-testcontainers-go/benchmarks/modelname"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ModelInfoInput represents the input parameters for model metadata lookups
+type ModelInfoInput struct {
+	Model string `json:"model,omitempty"` // Model reference, e.g. ai/llama3.2:1B-Q4_0, defaults to the model calling the tool
+}
+
+// ModelInfoResult represents the metadata of a model
+type ModelInfoResult struct {
+	Model         string `json:"model"`
+	ContextWindow int    `json:"context_window,omitempty"` // Maximum number of tokens of the context
+	Parameters    string `json:"parameters,omitempty"`     // e.g. 1.24 B
+	Quantization  string `json:"quantization,omitempty"`   // e.g. Q4_0
+	Architecture  string `json:"architecture,omitempty"`   // e.g. llama
+	Size          string `json:"size,omitempty"`           // e.g. 727.75 MiB
+	Error         string `json:"error,omitempty"`
+}
+
+// dmrModel is a model stored by the Docker Model Runner, as listed by its /models endpoint
+type dmrModel struct {
+	ID     string   `json:"id"`
+	Tags   []string `json:"tags"`
+	Config struct {
+		Parameters   string         `json:"parameters"`
+		Quantization string         `json:"quantization"`
+		Architecture string         `json:"architecture"`
+		Size         string         `json:"size"`
+		ContextSize  int            `json:"context_size"` // Set when the context was configured
+		GGUF         map[string]any `json:"gguf"`         // Metadata of the GGUF file, e.g. llama.context_length
+	} `json:"config"`
+}
+
+// ModelInfoTool reports the context window, parameter count and quantization of the models of a
+// Docker Model Runner, so a model can reason about its own limits, e.g. declining an over-long task
+type ModelInfoTool struct {
+	client  *http.Client
+	baseURL string
+	model   string
+}
+
+// NewModelInfoTool creates a new model metadata tool querying the Docker Model Runner at baseURL,
+// e.g. http://localhost:12434, and describing the model when the input names none
+func NewModelInfoTool(baseURL, model string) *ModelInfoTool {
+	return &ModelInfoTool{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+	}
+}
+
+// Execute looks up the metadata of the model of the input
+func (m *ModelInfoTool) Execute(inputJSON string) (string, error) {
+	var input ModelInfoInput
+	if strings.TrimSpace(inputJSON) != "" {
+		if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+			return "", fmt.Errorf("failed to parse model info input: %w", err)
+		}
+	}
+
+	result := ModelInfoResult{Model: strings.TrimSpace(input.Model)}
+	if result.Model == "" {
+		result.Model = m.model
+	}
+
+	model, err := m.lookup(result.Model)
+	if err == nil {
+		result.ContextWindow = model.contextWindow()
+		result.Parameters = model.Config.Parameters
+		result.Quantization = model.Config.Quantization
+		result.Architecture = model.Config.Architecture
+		result.Size = model.Config.Size
+	} else {
+		result.Error = err.Error()
+	}
+
+	resultJSON, jsonErr := json.Marshal(result)
+	if jsonErr != nil {
+		return "", fmt.Errorf("failed to marshal model info result: %w", jsonErr)
+	}
+
+	return string(resultJSON), err
+}
+
+// lookup finds the model among the ones listed by the Docker Model Runner
+func (m *ModelInfoTool) lookup(model string) (dmrModel, error) {
+	if model == "" {
+		return dmrModel{}, fmt.Errorf("model is required")
+	}
+
+	resp, err := m.client.Get(m.baseURL + "/models")
+	if err != nil {
+		return dmrModel{}, fmt.Errorf("list models request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return dmrModel{}, fmt.Errorf("failed to read models response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return dmrModel{}, fmt.Errorf("list models request returned error status %d", resp.StatusCode)
+	}
+
+	var models []dmrModel
+	if err := json.Unmarshal(body, &models); err != nil {
+		return dmrModel{}, fmt.Errorf("failed to parse models response: %w", err)
+	}
+
+	expected := withLatestTag(model)
+	for _, candidate := range models {
+		for _, tag := range candidate.Tags {
+			// Hugging Face models are stored lowercased by the Model Runner
+			if strings.EqualFold(withLatestTag(tag), expected) {
+				return candidate, nil
+			}
+		}
+	}
+
+	return dmrModel{}, fmt.Errorf("model not found: %s", model)
+}
+
+// contextWindow returns the configured context size of the model, or the context length it was
+// trained with, zero if it's unknown
+func (d dmrModel) contextWindow() int {
+	if d.Config.ContextSize > 0 {
+		return d.Config.ContextSize
+	}
+
+	// The key of the architecture first, then any other one, in a stable order
+	keys := []string{d.Config.Architecture + ".context_length"}
+	for _, key := range slices.Sorted(maps.Keys(d.Config.GGUF)) {
+		if strings.HasSuffix(key, ".context_length") {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		if v, ok := d.Config.GGUF[key]; ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(v))); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+
+	return 0
+}
+
+// withLatestTag adds the "latest" tag to the model reference, if it has none. The references that
+// can't be parsed are returned as they are, so they only match the same reference.
+func withLatestTag(model string) string {
+	name, err := modelname.Parse(model)
+	if err != nil {
+		return model
+	}
+
+	return name.WithDefaultTag().String()
+}
+
+// GetToolDefinition returns the langchaingo tool definition for the model metadata tool
+func (m *ModelInfoTool) GetToolDefinition() llms.Tool {
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "model_info",
+			Description: "Reports the context window in tokens, the parameter count and the quantization of a model. Use this tool to check your own limits before a long task, e.g. whether a document fits in your context.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"model": map[string]any{
+						"type":        "string",
+						"description": "The model reference, e.g. ai/llama3.2:1B-Q4_0 (default: your own model)",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newStubModelRunner serves canned metadata of the models on the /models endpoint of the Model Runner
+func newStubModelRunner(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /models", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`[
+			{
+				"id": "sha256:1a2b",
+				"tags": ["ai/llama3.2:1B-Q4_0"],
+				"config": {
+					"format": "gguf",
+					"quantization": "Q4_0",
+					"parameters": "1.24 B",
+					"architecture": "llama",
+					"size": "727.75 MiB",
+					"gguf": {"general.name": "Llama 3.2 1B Instruct", "llama.context_length": "131072"}
+				}
+			},
+			{
+				"id": "sha256:3c4d",
+				"tags": ["ai/smollm2"],
+				"config": {
+					"quantization": "IQ2_XXS/Q4_K_M",
+					"parameters": "361.82 M",
+					"architecture": "llama",
+					"context_size": 8192,
+					"gguf": {"llama.context_length": "8192"}
+				}
+			}
+		]`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestModelInfoTool(t *testing.T) {
+	srv := newStubModelRunner(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected ModelInfoResult
+		wantErr  bool
+	}{
+		{
+			name:  "own-model",
+			input: `{}`,
+			expected: ModelInfoResult{
+				Model: "ai/llama3.2:1B-Q4_0", ContextWindow: 131072, Parameters: "1.24 B",
+				Quantization: "Q4_0", Architecture: "llama", Size: "727.75 MiB",
+			},
+		},
+		{
+			name:  "configured-context",
+			input: `{"model": "ai/smollm2:latest"}`,
+			expected: ModelInfoResult{
+				Model: "ai/smollm2:latest", ContextWindow: 8192, Parameters: "361.82 M",
+				Quantization: "IQ2_XXS/Q4_K_M", Architecture: "llama",
+			},
+		},
+		{
+			name:     "not-found",
+			input:    `{"model": "ai/missing"}`,
+			expected: ModelInfoResult{Model: "ai/missing", Error: "model not found: ai/missing"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := NewModelInfoTool(srv.URL+"/", "ai/llama3.2:1B-Q4_0")
+
+			out, err := tool.Execute(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+
+			var result ModelInfoResult
+			if err := json.Unmarshal([]byte(out), &result); err != nil {
+				t.Fatalf("unmarshal result: %s", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		if _, err := NewModelInfoTool("http://127.0.0.1:1", "ai/llama3.2:1B-Q4_0").Execute(`{}`); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// DefaultWikipediaURL is the REST summary API of the English Wikipedia, the title is appended to it
+	DefaultWikipediaURL = "https://en.wikipedia.org/api/rest_v1/page/summary"
+
+	// DefaultMaxExtractChars caps the extract returned to the model, so a long summary doesn't fill the context
+	DefaultMaxExtractChars = 2000
+)
+
+// WikipediaInput represents the input parameters for Wikipedia lookups
+type WikipediaInput struct {
+	Title string `json:"title"` // Title of the article, e.g. Alan Turing
+}
+
+// WikipediaResult represents the summary of a Wikipedia article
+type WikipediaResult struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Extract     string `json:"extract"`
+	URL         string `json:"url,omitempty"`
+	Truncated   bool   `json:"truncated,omitempty"` // Whether the extract was cut to the size cap
+	Error       string `json:"error,omitempty"`
+}
+
+// summaryResponse is the response of the Wikipedia REST summary API
+type summaryResponse struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Extract     string `json:"extract"`
+	ContentURLs struct {
+		Desktop struct {
+			Page string `json:"page"`
+		} `json:"desktop"`
+	} `json:"content_urls"`
+}
+
+// Wikipedia looks up the summary of Wikipedia articles, grounding the answers to factual questions
+type Wikipedia struct {
+	client          *http.Client
+	baseURL         string
+	maxExtractChars int
+}
+
+// NewWikipedia creates a new Wikipedia tool using the English Wikipedia
+func NewWikipedia() *Wikipedia {
+	return NewWikipediaWithURL(DefaultWikipediaURL, DefaultMaxExtractChars)
+}
+
+// NewWikipediaWithURL creates a new Wikipedia tool using the summary API at baseURL, which must
+// answer like the Wikipedia REST summary API, and capping the extracts to maxExtractChars characters
+func NewWikipediaWithURL(baseURL string, maxExtractChars int) *Wikipedia {
+	return &Wikipedia{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		maxExtractChars: maxExtractChars,
+	}
+}
+
+// Execute looks up the summary of the article of the input
+func (w *Wikipedia) Execute(inputJSON string) (string, error) {
+	var input WikipediaInput
+	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+		return "", fmt.Errorf("failed to parse wikipedia input: %w", err)
+	}
+
+	result := WikipediaResult{
+		Title: strings.TrimSpace(input.Title),
+	}
+
+	summary, err := w.summary(result.Title)
+	if err == nil {
+		result.Title = summary.Title
+		result.Description = summary.Description
+		result.Extract, result.Truncated = truncate(summary.Extract, w.maxExtractChars)
+		result.URL = summary.ContentURLs.Desktop.Page
+	} else {
+		result.Error = err.Error()
+	}
+
+	resultJSON, jsonErr := json.Marshal(result)
+	if jsonErr != nil {
+		return "", fmt.Errorf("failed to marshal wikipedia result: %w", jsonErr)
+	}
+
+	return string(resultJSON), err
+}
+
+// summary fetches the summary of the article from the summary API
+func (w *Wikipedia) summary(title string) (summaryResponse, error) {
+	if title == "" {
+		return summaryResponse{}, fmt.Errorf("title is required")
+	}
+
+	// Wikipedia titles use underscores instead of spaces
+	req, err := http.NewRequest(http.MethodGet, w.baseURL+"/"+url.PathEscape(strings.ReplaceAll(title, " ", "_")), nil)
+	if err != nil {
+		return summaryResponse{}, fmt.Errorf("failed to create wikipedia request: %w", err)
+	}
+	// Wikimedia requires a User-Agent identifying the client
+	req.Header.Set("User-Agent", "LLM-Benchmark-Tool/1.0")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return summaryResponse{}, fmt.Errorf("wikipedia request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return summaryResponse{}, fmt.Errorf("failed to read wikipedia response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return summaryResponse{}, fmt.Errorf("article not found: %s", title)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return summaryResponse{}, fmt.Errorf("wikipedia request returned error status %d", resp.StatusCode)
+	}
+
+	var summary summaryResponse
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return summaryResponse{}, fmt.Errorf("failed to parse wikipedia response: %w", err)
+	}
+
+	if summary.Type == "disambiguation" {
+		return summaryResponse{}, fmt.Errorf("%s is ambiguous, use a more specific title", title)
+	}
+
+	return summary, nil
+}
+
+// truncate cuts the text to maxChars characters, reporting whether it was cut.
+// A non-positive maxChars disables the cap.
+func truncate(text string, maxChars int) (string, bool) {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return text, false
+	}
+
+	return string(runes[:maxChars]) + "...", true
+}
+
+// GetToolDefinition returns the langchaingo tool definition for the Wikipedia tool
+func (w *Wikipedia) GetToolDefinition() llms.Tool {
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "wikipedia",
+			Description: "Looks up the summary of a Wikipedia article by its title. Use this tool to check facts about people, places, events or concepts instead of answering from memory.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"title": map[string]any{
+						"type":        "string",
+						"description": "The title of the Wikipedia article, e.g. Alan Turing",
+					},
+				},
+				"required": []string{"title"},
+			},
+		},
+	}
+}
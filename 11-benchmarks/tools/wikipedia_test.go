@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWikipedia(t *testing.T) {
+	const extract = "Alan Mathison Turing was an English mathematician, computer scientist, logician, cryptanalyst, philosopher and theoretical biologist."
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Alan_Turing":
+			w.Write([]byte(`{
+				"type": "standard",
+				"title": "Alan Turing",
+				"description": "English computer scientist (1912–1954)",
+				"extract": "` + extract + `",
+				"content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Alan_Turing"}}
+			}`))
+		case "/Mercury":
+			w.Write([]byte(`{"type": "disambiguation", "title": "Mercury", "extract": "Mercury may refer to:"}`))
+		default:
+			http.Error(w, `{"title": "Not found."}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	lookup := func(t *testing.T, tool *Wikipedia, input string) (WikipediaResult, error) {
+		t.Helper()
+
+		output, err := tool.Execute(input)
+
+		var result WikipediaResult
+		if jsonErr := json.Unmarshal([]byte(output), &result); jsonErr != nil {
+			t.Fatalf("unmarshal result %q: %s", output, jsonErr)
+		}
+
+		return result, err
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		result, err := lookup(t, NewWikipediaWithURL(srv.URL, DefaultMaxExtractChars), `{"title": "Alan Turing"}`)
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if result.Extract != extract || result.Truncated {
+			t.Errorf("unexpected extract: %+v", result)
+		}
+		if result.URL != "https://en.wikipedia.org/wiki/Alan_Turing" {
+			t.Errorf("unexpected url: %s", result.URL)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		result, err := lookup(t, NewWikipediaWithURL(srv.URL, 20), `{"title": "Alan Turing"}`)
+		if err != nil {
+			t.Fatalf("execute: %s", err)
+		}
+
+		if !result.Truncated || result.Extract != "Alan Mathison Turing..." {
+			t.Errorf("expected the extract to be truncated to 20 characters, got %+v", result)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		result, err := lookup(t, NewWikipediaWithURL(srv.URL, DefaultMaxExtractChars), `{"title": "Alan Turingg"}`)
+		if err == nil || !strings.Contains(result.Error, "article not found") {
+			t.Errorf("expected a not found error, got %v (%+v)", err, result)
+		}
+	})
+
+	t.Run("disambiguation", func(t *testing.T) {
+		result, err := lookup(t, NewWikipediaWithURL(srv.URL, DefaultMaxExtractChars), `{"title": "Mercury"}`)
+		if err == nil || !strings.Contains(result.Error, "ambiguous") {
+			t.Errorf("expected an ambiguous title error, got %v (%+v)", err, result)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package main
+
+// warmupTestCase labels the measurements of the warmup request, so the Grafana panels can filter it out
+const warmupTestCase = "warmup"
+
+// warmupModel returns the model to warm up before creating the Grafana dashboard, so it has a data
+// point when the user opens it: the first one to benchmark. It returns false when the warmup is
+// skipped, see BENCH_SKIP_WARMUP, or there are no models.
+func warmupModel(models []ModelConfig, skip bool) (ModelConfig, bool) {
+	if skip || len(models) == 0 {
+		return ModelConfig{}, false
+	}
+
+	return models[0], true
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestWarmupModel(t *testing.T) {
+	llama := ModelConfig{Name: "llama3.2", FQName: "ai/llama3.2:1B-Q4_0"}
+	qwen := ModelConfig{Name: "qwen3", FQName: "ai/qwen3:0.6B-Q4_0"}
+
+	tests := []struct {
+		name     string
+		models   []ModelConfig
+		skip     bool
+		expected ModelConfig
+		warmup   bool
+	}{
+		{name: "first-model", models: []ModelConfig{llama, qwen}, expected: llama, warmup: true},
+		{name: "skipped", models: []ModelConfig{llama, qwen}, skip: true},
+		{name: "no-models", models: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, warmup := warmupModel(tt.models, tt.skip)
+			if warmup != tt.warmup {
+				t.Fatalf("expected warmup %t, got %t", tt.warmup, warmup)
+			}
+			if model != tt.expected {
+				t.Errorf("expected model %+v, got %+v", tt.expected, model)
+			}
+		})
+	}
+}